@@ -0,0 +1,45 @@
+// Package agent shares one authenticated vault client between many processes
+// on a host.
+//
+// A Server holds a vault.Vault (usually a 1Password provider), caches the
+// secrets it reads, and answers get and resolve requests over a unix domain
+// socket. On Linux each connection's peer credentials are checked against
+// the allowed user IDs; elsewhere access is limited by the socket's file
+// permissions. Short-lived processes use a Client instead of authenticating
+// to 1Password themselves, which keeps them within the service account's
+// rate limits.
+//
+//	provider, _ := onepassword.NewFromEnv()
+//	server := agent.NewServer(provider, agent.Config{CacheTTL: 5 * time.Minute})
+//	err := server.ListenAndServe(ctx)
+//
+//	client := agent.NewClient(agent.DefaultSocketPath())
+//	secret, err := client.Get(ctx, "Private/API Keys/github-token")
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// EnvSocketPath is the environment variable overriding DefaultSocketPath.
+const EnvSocketPath = "OMNIVAULT_OP_AGENT_SOCK"
+
+// DefaultSocketPath returns the socket path used when none is configured:
+// $OMNIVAULT_OP_AGENT_SOCK if set, otherwise omnivault-op.sock in
+// $XDG_RUNTIME_DIR or, failing that, a per-user name in the temp directory.
+func DefaultSocketPath() string {
+	if path := os.Getenv(EnvSocketPath); path != "" {
+		return path
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "omnivault-op.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("omnivault-op-%d.sock", os.Getuid()))
+}
+
+// resolveResponse is the body of a successful resolve request.
+type resolveResponse struct {
+	Value string `json:"value"`
+}
@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/omnivault/providers/memory"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// startServer runs a server over v on a temporary socket until the test ends.
+func startServer(t *testing.T, v vault.Vault, config Config) (*Server, *Client) {
+	t.Helper()
+
+	config.SocketPath = filepath.Join(t.TempDir(), "agent.sock")
+	server := NewServer(v, config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.ListenAndServe(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("ListenAndServe() error = %v", err)
+		}
+	})
+
+	// Wait for the socket to appear
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(config.SocketPath); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	client := NewClient(config.SocketPath)
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestAgent_GetAndResolve(t *testing.T) {
+	mem := memory.NewWithSecrets(map[string]string{"Work/API/token": "s3cret"})
+	_, client := startServer(t, mem, Config{})
+	ctx := context.Background()
+
+	secret, err := client.Get(ctx, "Work/API/token")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if secret.Value != "s3cret" {
+		t.Errorf("Get() value = %q, want 's3cret'", secret.Value)
+	}
+
+	value, err := client.Resolve(ctx, "Work/API/token")
+	if err != nil || value != "s3cret" {
+		t.Errorf("Resolve() = %q, %v; want 's3cret'", value, err)
+	}
+
+	_, err = client.Get(ctx, "Work/API/missing")
+	if !errors.Is(err, vault.ErrSecretNotFound) {
+		t.Errorf("Get() missing error = %v, want ErrSecretNotFound", err)
+	}
+
+	exists, err := client.Exists(ctx, "Work/API/missing")
+	if err != nil || exists {
+		t.Errorf("Exists() = %v, %v; want false, nil", exists, err)
+	}
+
+	if err := client.Set(ctx, "Work/API/token", &vault.Secret{}); !errors.Is(err, vault.ErrReadOnly) {
+		t.Errorf("Set() error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestAgent_Cache(t *testing.T) {
+	mem := memory.NewWithSecrets(map[string]string{"V/I/f": "v1"})
+	_, client := startServer(t, mem, Config{CacheTTL: time.Hour})
+	ctx := context.Background()
+
+	if v, _ := client.Resolve(ctx, "V/I/f"); v != "v1" {
+		t.Fatalf("Resolve() = %q, want 'v1'", v)
+	}
+
+	_ = mem.Set(ctx, "V/I/f", &vault.Secret{Value: "v2"})
+	if v, _ := client.Resolve(ctx, "V/I/f"); v != "v1" {
+		t.Errorf("Resolve() = %q, want cached 'v1'", v)
+	}
+
+	if err := client.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if v, _ := client.Resolve(ctx, "V/I/f"); v != "v2" {
+		t.Errorf("Resolve() after Flush = %q, want 'v2'", v)
+	}
+}
+
+func TestAgent_RejectsDisallowedPeer(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("peer credentials are only checked on Linux")
+	}
+
+	mem := memory.NewWithSecrets(map[string]string{"V/I/f": "v"})
+	_, client := startServer(t, mem, Config{AllowedUIDs: []int{os.Getuid() + 1}})
+
+	_, err := client.Get(context.Background(), "V/I/f")
+	if !errors.Is(err, vault.ErrConnectionFailed) {
+		t.Errorf("Get() error = %v, want ErrConnectionFailed", err)
+	}
+}
+
+func TestDefaultSocketPath(t *testing.T) {
+	t.Setenv(EnvSocketPath, "/run/custom.sock")
+	if got := DefaultSocketPath(); got != "/run/custom.sock" {
+		t.Errorf("DefaultSocketPath() = %q, want override", got)
+	}
+
+	t.Setenv(EnvSocketPath, "")
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	if got := DefaultSocketPath(); got != "/run/user/1000/omnivault-op.sock" {
+		t.Errorf("DefaultSocketPath() = %q", got)
+	}
+}
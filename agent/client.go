@@ -0,0 +1,133 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/agentplexus/omnivault-onepassword/internal/wire"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ProviderName is the name returned by Client.Name().
+const ProviderName = "onepassword-agent"
+
+// Client reads secrets from an agent. It implements vault.Vault as a
+// read-only vault, so it can be registered with an omnivault resolver in
+// place of the provider itself.
+type Client struct {
+	http *http.Client
+}
+
+// NewClient returns a client for the agent listening on socketPath. No
+// connection is made until the first request.
+func NewClient(socketPath string) *Client {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socketPath)
+		},
+	}
+	return &Client{http: &http.Client{Transport: transport}}
+}
+
+// Get returns the secret at path.
+func (c *Client) Get(ctx context.Context, path string) (*vault.Secret, error) {
+	var secret vault.Secret
+	if err := c.do(ctx, http.MethodGet, "/v1/secret?path="+url.QueryEscape(path), &secret); err != nil {
+		return nil, vault.NewVaultError("Get", path, ProviderName, err)
+	}
+	return &secret, nil
+}
+
+// Resolve returns the value of a secret reference.
+func (c *Client) Resolve(ctx context.Context, ref string) (string, error) {
+	var resp resolveResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/resolve?ref="+url.QueryEscape(ref), &resp); err != nil {
+		return "", vault.NewVaultError("Resolve", ref, ProviderName, err)
+	}
+	return resp.Value, nil
+}
+
+// Flush asks the agent to drop its cached secrets.
+func (c *Client) Flush(ctx context.Context) error {
+	if err := c.do(ctx, http.MethodPost, "/v1/flush", nil); err != nil {
+		return vault.NewVaultError("Flush", "", ProviderName, err)
+	}
+	return nil
+}
+
+// Exists reports whether the secret at path exists.
+func (c *Client) Exists(ctx context.Context, path string) (bool, error) {
+	if _, err := c.Get(ctx, path); err != nil {
+		if errors.Is(err, vault.ErrSecretNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Set is not supported; the agent is read-only.
+func (c *Client) Set(_ context.Context, path string, _ *vault.Secret) error {
+	return vault.NewVaultError("Set", path, ProviderName, vault.ErrReadOnly)
+}
+
+// Delete is not supported; the agent is read-only.
+func (c *Client) Delete(_ context.Context, path string) error {
+	return vault.NewVaultError("Delete", path, ProviderName, vault.ErrReadOnly)
+}
+
+// List is not supported by the agent.
+func (c *Client) List(_ context.Context, prefix string) ([]string, error) {
+	return nil, vault.NewVaultError("List", prefix, ProviderName, vault.ErrNotSupported)
+}
+
+// Name returns the provider name.
+func (c *Client) Name() string {
+	return ProviderName
+}
+
+// Capabilities returns the client capabilities.
+func (c *Client) Capabilities() vault.Capabilities {
+	return vault.Capabilities{
+		Read:       true,
+		MultiField: true,
+	}
+}
+
+// Close releases idle connections to the agent.
+func (c *Client) Close() error {
+	c.http.CloseIdleConnections()
+	return nil
+}
+
+// do sends a request to the agent and decodes a successful response into out.
+func (c *Client) do(ctx context.Context, method, target string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, "http://agent"+target, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", vault.ErrConnectionFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return wire.ReadError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Ensure Client implements vault.Vault.
+var _ vault.Vault = (*Client)(nil)
@@ -0,0 +1,40 @@
+//go:build linux
+
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// errPeerCredUnsupported is returned by peerUID where peer credentials can't
+// be read.
+var errPeerCredUnsupported = errors.New("peer credentials not supported")
+
+// peerUID returns the user ID of the process on the other end of conn.
+func peerUID(conn net.Conn) (int, error) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return 0, errPeerCredUnsupported
+	}
+
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var cred *syscall.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, fmt.Errorf("failed to read peer credentials: %w", credErr)
+	}
+	return int(cred.Uid), nil
+}
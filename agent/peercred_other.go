@@ -0,0 +1,18 @@
+//go:build !linux
+
+package agent
+
+import (
+	"errors"
+	"net"
+)
+
+// errPeerCredUnsupported is returned by peerUID where peer credentials can't
+// be read.
+var errPeerCredUnsupported = errors.New("peer credentials not supported")
+
+// peerUID is not implemented on this platform; access is limited by the
+// socket file's permissions instead.
+func peerUID(net.Conn) (int, error) {
+	return 0, errPeerCredUnsupported
+}
@@ -0,0 +1,249 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/agentplexus/omnivault-onepassword/internal/wire"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// DefaultCacheTTL is how long the server reuses a secret it has read.
+const DefaultCacheTTL = 5 * time.Minute
+
+// Config configures a Server.
+type Config struct {
+	// SocketPath is where ListenAndServe creates the socket.
+	// Default: DefaultSocketPath()
+	SocketPath string
+
+	// AllowedUIDs lists the user IDs allowed to connect. Connections from
+	// other users are closed before any request is read. Only enforced
+	// where peer credentials are available (Linux).
+	// Default: the user running the server
+	AllowedUIDs []int
+
+	// CacheTTL is how long secrets are cached. Negative disables caching.
+	// Default: 5 minutes
+	CacheTTL time.Duration
+
+	// Logger for request and connection logging. Optional.
+	Logger *slog.Logger
+}
+
+// withDefaults returns a copy of the config with default values applied.
+func (c Config) withDefaults() Config {
+	if c.SocketPath == "" {
+		c.SocketPath = DefaultSocketPath()
+	}
+	if len(c.AllowedUIDs) == 0 {
+		c.AllowedUIDs = []int{os.Getuid()}
+	}
+	if c.CacheTTL == 0 {
+		c.CacheTTL = DefaultCacheTTL
+	}
+	if c.Logger == nil {
+		c.Logger = slog.New(slog.DiscardHandler)
+	}
+	return c
+}
+
+// Server serves secrets from a vault over a unix domain socket.
+type Server struct {
+	vault  vault.Vault
+	config Config
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// cacheEntry is a cached secret and when it expires.
+type cacheEntry struct {
+	secret    *vault.Secret
+	expiresAt time.Time
+}
+
+// NewServer returns a server reading secrets from v.
+func NewServer(v vault.Vault, config Config) *Server {
+	return &Server{
+		vault:  v,
+		config: config.withDefaults(),
+		now:    time.Now,
+		cache:  make(map[string]cacheEntry),
+	}
+}
+
+// ListenAndServe creates the socket with owner-only permissions, replacing a
+// stale socket file, and serves requests until ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	path := s.config.SocketPath
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	s.config.Logger.Info("agent listening", "socket", path)
+	err = srv.Serve(s.peerCheckListener(l))
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Handler returns the HTTP handler serving the agent API:
+//
+//	GET /v1/secret?path=PATH   the secret as JSON (vault.Secret)
+//	GET /v1/resolve?ref=REF    {"value": "..."}
+//	POST /v1/flush             drop all cached secrets
+//
+// It performs no authentication; ListenAndServe checks peer credentials
+// before handing connections to it.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/secret", func(w http.ResponseWriter, r *http.Request) {
+		secret, err := s.get(r.Context(), r.URL.Query().Get("path"))
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		s.writeJSON(w, http.StatusOK, secret)
+	})
+	mux.HandleFunc("GET /v1/resolve", func(w http.ResponseWriter, r *http.Request) {
+		secret, err := s.get(r.Context(), r.URL.Query().Get("ref"))
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		s.writeJSON(w, http.StatusOK, resolveResponse{Value: secret.Value})
+	})
+	mux.HandleFunc("POST /v1/flush", func(w http.ResponseWriter, _ *http.Request) {
+		s.Flush()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+// Flush drops all cached secrets.
+func (s *Server) Flush() {
+	s.mu.Lock()
+	s.cache = make(map[string]cacheEntry)
+	s.mu.Unlock()
+}
+
+// get returns the secret at path, from the cache when fresh.
+func (s *Server) get(ctx context.Context, path string) (*vault.Secret, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: path is required", vault.ErrInvalidPath)
+	}
+
+	if s.config.CacheTTL > 0 {
+		s.mu.Lock()
+		entry, ok := s.cache[path]
+		s.mu.Unlock()
+		if ok && s.now().Before(entry.expiresAt) {
+			return entry.secret, nil
+		}
+	}
+
+	secret, err := s.vault.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.CacheTTL > 0 {
+		s.mu.Lock()
+		s.cache[path] = cacheEntry{secret: secret, expiresAt: s.now().Add(s.config.CacheTTL)}
+		s.mu.Unlock()
+	}
+	return secret, nil
+}
+
+// writeJSON writes v as a JSON response.
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	if err := wire.WriteJSON(w, status, v); err != nil {
+		s.config.Logger.Debug("failed to write response", "error", err)
+	}
+}
+
+// writeError writes err as an error response.
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	if err := wire.WriteError(w, err); err != nil {
+		s.config.Logger.Debug("failed to write response", "error", err)
+	}
+}
+
+// peerCheckListener wraps l so that connections from disallowed users are
+// closed on accept.
+func (s *Server) peerCheckListener(l net.Listener) net.Listener {
+	return &peerListener{Listener: l, allowed: s.config.AllowedUIDs, logger: s.config.Logger}
+}
+
+// peerListener closes accepted connections whose peer is not allowed.
+type peerListener struct {
+	net.Listener
+	allowed []int
+	logger  *slog.Logger
+}
+
+// Accept returns the next connection from an allowed peer.
+func (l *peerListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		uid, err := peerUID(conn)
+		if errors.Is(err, errPeerCredUnsupported) {
+			return conn, nil
+		}
+		if err != nil {
+			l.logger.Warn("rejecting connection", "error", err)
+			conn.Close()
+			continue
+		}
+		if !containsUID(l.allowed, uid) {
+			l.logger.Warn("rejecting connection from disallowed user", "uid", uid)
+			conn.Close()
+			continue
+		}
+		return conn, nil
+	}
+}
+
+// containsUID reports whether uid is in uids.
+func containsUID(uids []int, uid int) bool {
+	for _, u := range uids {
+		if u == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package onepassword
+
+import (
+	"context"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+// The subsets of the 1Password SDK APIs used by Provider. The SDK's
+// SecretsAPI, ItemsAPI, and VaultsAPI satisfy them; tests substitute mocks.
+
+// secretsAPI resolves secret references.
+type secretsAPI interface {
+	Resolve(ctx context.Context, secretReference string) (string, error)
+}
+
+// itemsAPI manages the items of a vault.
+type itemsAPI interface {
+	Create(ctx context.Context, params op.ItemCreateParams) (op.Item, error)
+	Get(ctx context.Context, vaultID, itemID string) (op.Item, error)
+	Put(ctx context.Context, item op.Item) (op.Item, error)
+	Delete(ctx context.Context, vaultID, itemID string) error
+	ListAll(ctx context.Context, vaultID string) (*op.Iterator[op.ItemOverview], error)
+}
+
+// vaultsAPI lists vaults.
+type vaultsAPI interface {
+	ListAll(ctx context.Context) (*op.Iterator[op.VaultOverview], error)
+}
@@ -0,0 +1,316 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// mockAPI implements secretsAPI, itemsAPI, and vaultsAPI over fixed data,
+// which Put updates, and records the calls made to it.
+type mockAPI struct {
+	vaults   []op.VaultOverview
+	items    map[string][]op.Item // by vault ID
+	resolved map[string]string    // secret reference -> value
+	err      error                // returned by every call when set
+
+	mu         sync.Mutex
+	calls      []string
+	vaultLists int
+	itemLists  int
+	created    []op.ItemCreateParams
+	put        []op.Item
+	deleted    []string
+}
+
+func newMockProvider(m *mockAPI, config Config) *Provider {
+	return newWithAPIs(m, m, mockVaults{m}, config)
+}
+
+func (m *mockAPI) Resolve(_ context.Context, ref string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, "Resolve "+ref)
+	if m.err != nil {
+		return "", m.err
+	}
+	value, ok := m.resolved[ref]
+	if !ok {
+		return "", errors.New("fieldNotFound")
+	}
+	return value, nil
+}
+
+func (m *mockAPI) Create(_ context.Context, params op.ItemCreateParams) (op.Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, "Create")
+	if m.err != nil {
+		return op.Item{}, m.err
+	}
+	m.created = append(m.created, params)
+	return op.Item{
+		Title:    params.Title,
+		VaultID:  params.VaultID,
+		Category: params.Category,
+		Fields:   params.Fields,
+		Sections: params.Sections,
+		Tags:     params.Tags,
+	}, nil
+}
+
+func (m *mockAPI) Get(_ context.Context, vaultID, itemID string) (op.Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, "Get "+itemID)
+	if m.err != nil {
+		return op.Item{}, m.err
+	}
+	for _, item := range m.items[vaultID] {
+		if item.ID == itemID {
+			return item, nil
+		}
+	}
+	return op.Item{}, errors.New("itemNotFound")
+}
+
+func (m *mockAPI) Put(_ context.Context, item op.Item) (op.Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, "Put "+item.ID)
+	if m.err != nil {
+		return op.Item{}, m.err
+	}
+	m.put = append(m.put, item)
+	for i, stored := range m.items[item.VaultID] {
+		if stored.ID == item.ID {
+			m.items[item.VaultID][i] = item
+		}
+	}
+	return item, nil
+}
+
+func (m *mockAPI) Delete(_ context.Context, _, itemID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, "Delete "+itemID)
+	if m.err != nil {
+		return m.err
+	}
+	m.deleted = append(m.deleted, itemID)
+	return nil
+}
+
+func (m *mockAPI) ListAll(_ context.Context, vaultID string) (*op.Iterator[op.ItemOverview], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.itemLists++
+	if m.err != nil {
+		return nil, m.err
+	}
+	var overviews []op.ItemOverview
+	for _, item := range m.items[vaultID] {
+		overviews = append(overviews, op.ItemOverview{ID: item.ID, Title: item.Title, Category: item.Category, VaultID: vaultID})
+	}
+	return op.NewIterator(overviews), nil
+}
+
+// mockVaults adapts mockAPI to vaultsAPI, whose ListAll signature differs
+// from itemsAPI's.
+type mockVaults struct{ *mockAPI }
+
+func (m mockVaults) ListAll(context.Context) (*op.Iterator[op.VaultOverview], error) {
+	m.mu.Lock()
+	m.vaultLists++
+	m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	return op.NewIterator(m.vaults), nil
+}
+
+func testMockAPI() *mockAPI {
+	return &mockAPI{
+		vaults: []op.VaultOverview{{ID: "v1", Title: "Private"}, {ID: "v2", Title: "Work"}},
+		items: map[string][]op.Item{
+			"v1": {{
+				ID:      "i1",
+				Title:   "Database",
+				VaultID: "v1",
+				Version: 3,
+				Tags:    []string{"env:prod"},
+				Fields: []op.ItemField{
+					{ID: "username", Title: "username", FieldType: op.ItemFieldTypeText, Value: "admin"},
+					{ID: "password", Title: "password", FieldType: op.ItemFieldTypeConcealed, Value: "hunter2"},
+				},
+			}},
+			"v2": {{ID: "i2", Title: "API", VaultID: "v2"}},
+		},
+		resolved: map[string]string{"op://Private/Database/username": "admin"},
+	}
+}
+
+func TestProvider_Get_Mock(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	secret, err := p.Get(ctx, "Private/Database")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if secret.Value != "hunter2" || secret.Fields["username"] != "admin" {
+		t.Errorf("Get() = %+v", secret)
+	}
+	if secret.Metadata.Version != "3" || secret.Metadata.Tags["env"] != "prod" {
+		t.Errorf("Metadata = %+v", secret.Metadata)
+	}
+
+	field, err := p.Get(ctx, "Private/Database/username")
+	if err != nil || field.Value != "admin" {
+		t.Fatalf("Get(field) = %+v, %v", field, err)
+	}
+	if last := m.calls[len(m.calls)-1]; last != "Resolve op://Private/Database/username" {
+		t.Errorf("last call = %q, want secret reference resolution", last)
+	}
+
+	for _, path := range []string{"Private/Missing", "Nowhere/Database", "Private/Database/missing"} {
+		if _, err := p.Get(ctx, path); !errors.Is(err, vault.ErrSecretNotFound) {
+			t.Errorf("Get(%q) error = %v, want ErrSecretNotFound", path, err)
+		}
+	}
+
+	m.err = errors.New("unauthorized")
+	if _, err := p.Get(ctx, "Work/API"); !errors.Is(err, vault.ErrAccessDenied) {
+		t.Errorf("Get() error = %v, want ErrAccessDenied", err)
+	}
+}
+
+func TestProvider_Set_Mock(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{DefaultCategory: CategoryPassword})
+
+	err := p.Set(ctx, "Work/New", &vault.Secret{
+		Value:    "s3cret",
+		Metadata: vault.Metadata{Tags: map[string]string{"team": "infra"}},
+	})
+	if err != nil {
+		t.Fatalf("Set(new) error = %v", err)
+	}
+	if len(m.created) != 1 {
+		t.Fatalf("created %d items, want 1", len(m.created))
+	}
+	created := m.created[0]
+	if created.VaultID != "v2" || created.Title != "New" || created.Category != CategoryPassword {
+		t.Errorf("created = %+v", created)
+	}
+	if !reflect.DeepEqual(created.Tags, []string{"team:infra"}) {
+		t.Errorf("created tags = %v", created.Tags)
+	}
+
+	if err := p.Set(ctx, "Private/Database/password", &vault.Secret{Value: "rotated"}); err != nil {
+		t.Fatalf("Set(existing) error = %v", err)
+	}
+	if len(m.put) != 1 {
+		t.Fatalf("put %d items, want 1", len(m.put))
+	}
+	values := make(map[string]string)
+	for _, f := range m.put[0].Fields {
+		values[f.Title] = f.Value
+	}
+	if !reflect.DeepEqual(values, map[string]string{"username": "admin", "password": "rotated"}) {
+		t.Errorf("updated fields = %v", values)
+	}
+
+	stale := &vault.Secret{Value: "x", Metadata: vault.Metadata{Version: "1"}}
+	if err := p.Set(ctx, "Private/Database", stale); !errors.Is(err, ErrConflict) {
+		t.Errorf("Set(stale version) error = %v, want ErrConflict", err)
+	}
+}
+
+func TestProvider_Delete_Mock(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	if err := p.Delete(ctx, "Private/Database"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if !reflect.DeepEqual(m.deleted, []string{"i1"}) {
+		t.Errorf("deleted = %v", m.deleted)
+	}
+
+	// Missing vaults and items are not an error
+	for _, path := range []string{"Private/Missing", "Nowhere/Item"} {
+		if err := p.Delete(ctx, path); err != nil {
+			t.Errorf("Delete(%q) error = %v, want nil", path, err)
+		}
+	}
+	if len(m.deleted) != 1 {
+		t.Errorf("deleted = %v, want only i1", m.deleted)
+	}
+}
+
+func TestProvider_Exists_Mock(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(sectionedMockAPI(), Config{})
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"Private/Database", true},
+		{"Private/Missing", false},
+		{"Nowhere/Database", false},
+		{"Private/Database/username", true},
+		{"Private/Database/nonexistent-field", false},
+		{"Private/Database/Production/host", true},
+		{"Private/Database/prod/password", true},
+		{"Private/Database/Production/username", false},
+		{"Private/Database/Staging/host", false},
+		{"op://Private/Database/host", true},
+	}
+
+	for _, tt := range tests {
+		got, err := p.Exists(ctx, tt.path)
+		if err != nil {
+			t.Fatalf("Exists(%q) error = %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("Exists(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestProvider_List_Mock(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(testMockAPI(), Config{})
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"", []string{"Private/Database", "Work/API"}},
+		{"Work/", []string{"Work/API"}},
+		{"Private/Data", []string{"Private/Database"}},
+		{"Nowhere/", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := p.List(ctx, tt.prefix)
+		if err != nil {
+			t.Fatalf("List(%q) error = %v", tt.prefix, err)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("List(%q) = %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+}
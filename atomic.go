@@ -0,0 +1,262 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// AtomicBatchError is returned by SetBatchAtomic when a write fails. It
+// reports what state the batch left behind.
+type AtomicBatchError struct {
+	// Path is the path whose write failed. If it failed only in
+	// Config.MirrorTo, its write to 1Password is undone too, and Path is
+	// listed in RolledBack or Remaining like the earlier writes.
+	Path string
+
+	// Err is the error of the failed write.
+	Err error
+
+	// RolledBack lists the paths whose earlier writes were undone.
+	RolledBack []string
+
+	// Remaining maps the paths whose writes could not be undone to the
+	// rollback error. Their new values are still in 1Password or, if the
+	// error wraps ErrMirror, in Config.MirrorTo.
+	Remaining map[string]error
+}
+
+// Error implements the error interface.
+func (e *AtomicBatchError) Error() string {
+	msg := fmt.Sprintf("batch write of %s failed: %v; %d write(s) rolled back", e.Path, e.Err, len(e.RolledBack))
+	if len(e.Remaining) > 0 {
+		paths := make([]string, 0, len(e.Remaining))
+		for path := range e.Remaining {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		msg += ", rollback failed for " + strings.Join(paths, ", ")
+	}
+	return msg
+}
+
+// Unwrap returns the error of the failed write.
+func (e *AtomicBatchError) Unwrap() error {
+	return e.Err
+}
+
+// batchSnapshot is the state of an item before a batch wrote to it.
+type batchSnapshot struct {
+	path    *ParsedPath
+	vaultID string
+	title   string
+	item    *op.Item // nil if the batch creates the item
+}
+
+// SetBatchAtomic stores several secrets like SetBatch, but all or nothing
+// as far as possible: the items written are snapshotted first, and when a
+// write fails the writes already applied are undone in reverse order,
+// restoring updated items and deleting created ones. Paths are written in
+// sorted order.
+//
+// 1Password has no transactions, so this is compensation, not isolation:
+// other writers may see the intermediate state, and an undo can itself
+// fail. The returned *AtomicBatchError reports exactly which writes were
+// rolled back and which remain. Invalid paths and missing vaults are
+// reported before anything is written. Undone writes are undone in
+// Config.MirrorTo as well.
+//
+// In a dry run nothing needs undoing: the batch is planned in full, or not
+// at all if a write fails, and the paths planned before the failure are
+// reported as rolled back.
+func (p *Provider) SetBatchAtomic(ctx context.Context, secrets map[string]*vault.Secret) error {
+	if p.closed.Load() {
+		return vault.NewVaultError("SetBatchAtomic", "", ProviderName, vault.ErrClosed)
+	}
+
+	if plan := p.planFor(ctx); plan != nil {
+		return p.planBatchAtomic(ctx, plan, secrets)
+	}
+
+	paths := make([]string, 0, len(secrets))
+	for path := range secrets {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// Snapshot every item before writing any
+	snapshots := make(map[string]*batchSnapshot)
+	keys := make(map[string]string, len(paths))
+	for _, path := range paths {
+		snap, key, err := p.snapshotItem(ctx, path, snapshots)
+		if err != nil {
+			return err
+		}
+		snapshots[key] = snap
+		keys[path] = key
+	}
+
+	var applied []string
+	for _, path := range paths {
+		err := p.Set(ctx, path, secrets[path])
+		if err == nil {
+			applied = append(applied, path)
+			continue
+		}
+		// A write that failed only in the mirror reached 1Password, so it
+		// is undone with the others
+		if errors.Is(err, ErrMirror) {
+			applied = append(applied, path)
+		}
+
+		batchErr := &AtomicBatchError{Path: path, Err: err}
+		restored := make(map[string]error)
+		for i := len(applied) - 1; i >= 0; i-- {
+			key := keys[applied[i]]
+			// Rollback runs even if ctx was cancelled
+			rollbackCtx := context.WithoutCancel(ctx)
+			if _, done := restored[key]; !done {
+				restored[key] = p.restoreItem(rollbackCtx, snapshots[key])
+			}
+			rerr := restored[key]
+			if rerr == nil {
+				rerr = p.mirrorRestore(rollbackCtx, applied[i], snapshots[key])
+			}
+			if rerr != nil {
+				if batchErr.Remaining == nil {
+					batchErr.Remaining = make(map[string]error)
+				}
+				batchErr.Remaining[applied[i]] = rerr
+			} else {
+				batchErr.RolledBack = append(batchErr.RolledBack, applied[i])
+			}
+		}
+		return batchErr
+	}
+	return nil
+}
+
+// planBatchAtomic plans the writes of SetBatchAtomic into plan, all of
+// them or, if one fails, none.
+func (p *Provider) planBatchAtomic(ctx context.Context, plan *ChangeSet, secrets map[string]*vault.Secret) error {
+	paths := make([]string, 0, len(secrets))
+	for path := range secrets {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// Plan into a change set of the batch's own, added to plan on success
+	var batch ChangeSet
+	batchCtx := WithDryRun(ctx, &batch)
+	for i, path := range paths {
+		if err := p.Set(batchCtx, path, secrets[path]); err != nil {
+			batchErr := &AtomicBatchError{Path: path, Err: err}
+			for j := i - 1; j >= 0; j-- {
+				batchErr.RolledBack = append(batchErr.RolledBack, paths[j])
+			}
+			return batchErr
+		}
+	}
+	for _, change := range batch.Changes() {
+		plan.add(change)
+	}
+	return nil
+}
+
+// mirrorRestore applies the rollback of a write to path to
+// Config.MirrorTo: it mirrors the restored item, sets the snapshotted
+// field back or, if the path had none, deletes it.
+func (p *Provider) mirrorRestore(ctx context.Context, path string, snap *batchSnapshot) error {
+	if p.mirror == nil {
+		return nil
+	}
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return vault.NewVaultError("SetBatchAtomic", path, ProviderName, err)
+	}
+
+	if snap.item == nil {
+		return p.mirrorDeleteItem(ctx, "SetBatchAtomic", path, parsed)
+	}
+	if parsed.Field == "" {
+		return p.mirrorItem(ctx, "SetBatchAtomic", path, parsed)
+	}
+	field, ok := findField(*snap.item, parsed.Section, parsed.Field)
+	if !ok {
+		return p.mirrorDelete(ctx, "SetBatchAtomic", path)
+	}
+	return p.mirrorSet(ctx, "SetBatchAtomic", path, &vault.Secret{Value: fieldValue(field)})
+}
+
+// snapshotItem captures the item a path writes to, reusing a snapshot
+// already taken for another path of the same item. It returns the snapshot
+// and its key.
+func (p *Provider) snapshotItem(ctx context.Context, path string, snapshots map[string]*batchSnapshot) (*batchSnapshot, string, error) {
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return nil, "", vault.NewVaultError("SetBatchAtomic", path, ProviderName, err)
+	}
+
+	vaultID, err := p.resolveVaultID(ctx, parsed.Vault)
+	if err != nil {
+		return nil, "", mapError("SetBatchAtomic", path, err)
+	}
+	key := vaultID + "\x00" + parsed.Item
+	if snap, ok := snapshots[key]; ok {
+		return snap, key, nil
+	}
+
+	snap := &batchSnapshot{path: parsed, vaultID: vaultID, title: parsed.Item}
+	itemID, err := p.resolveItemID(ctx, vaultID, parsed.Item)
+	if isNotFoundError(err) {
+		return snap, key, nil
+	}
+	if err != nil {
+		return nil, "", mapError("SetBatchAtomic", path, err)
+	}
+	item, err := p.items.Get(ctx, vaultID, itemID)
+	if err != nil {
+		return nil, "", mapError("SetBatchAtomic", path, err)
+	}
+	snap.item = &item
+	return snap, key, nil
+}
+
+// restoreItem undoes a batch's writes to an item: it puts back the
+// snapshotted content, or deletes the item if the batch created it.
+func (p *Provider) restoreItem(ctx context.Context, snap *batchSnapshot) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	p.invalidateDiskCache(ctx, snap.path)
+
+	if snap.item == nil {
+		itemID, err := p.resolveItemID(ctx, snap.vaultID, snap.title)
+		if isNotFoundError(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := p.items.Delete(ctx, snap.vaultID, itemID); err != nil && !isNotFoundError(err) {
+			return err
+		}
+		return nil
+	}
+
+	current, err := p.items.Get(ctx, snap.vaultID, snap.item.ID)
+	if err != nil {
+		return err
+	}
+	restored := *snap.item
+	restored.Version = current.Version
+	if _, err := p.items.Put(ctx, restored); err != nil {
+		return err
+	}
+	return nil
+}
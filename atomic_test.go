@@ -0,0 +1,254 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/providers/memory"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// storingItems is an itemsAPI that stores created items, fails writes to
+// the item titled fail, and fails rollback puts when failPut is set.
+type storingItems struct {
+	*mockAPI
+	fail    string
+	failPut *bool
+}
+
+func (s storingItems) Create(ctx context.Context, params op.ItemCreateParams) (op.Item, error) {
+	if params.Title == s.fail {
+		return op.Item{}, errors.New("write rejected")
+	}
+	item, err := s.mockAPI.Create(ctx, params)
+	if err != nil {
+		return item, err
+	}
+	item.ID = "new-" + params.Title
+	s.mu.Lock()
+	s.items[params.VaultID] = append(s.items[params.VaultID], item)
+	s.mu.Unlock()
+	return item, nil
+}
+
+func (s storingItems) Put(ctx context.Context, item op.Item) (op.Item, error) {
+	if item.Title == s.fail || (s.failPut != nil && *s.failPut) {
+		return op.Item{}, errors.New("write rejected")
+	}
+	return s.mockAPI.Put(ctx, item)
+}
+
+// failingMirror fails Set for the path fail.
+type failingMirror struct {
+	vault.Vault
+	fail string
+}
+
+func (f failingMirror) Set(ctx context.Context, path string, secret *vault.Secret) error {
+	if path == f.fail {
+		return errors.New("mirror rejected")
+	}
+	return f.Vault.Set(ctx, path, secret)
+}
+
+func TestProvider_SetBatchAtomic(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("success", func(t *testing.T) {
+		m := testMockAPI()
+		p := newWithAPIs(m, storingItems{mockAPI: m}, mockVaults{m}, Config{})
+		err := p.SetBatchAtomic(ctx, map[string]*vault.Secret{
+			"Private/Database/password": {Value: "new"},
+			"Private/Cache/password":    {Value: "x"},
+		})
+		if err != nil {
+			t.Fatalf("SetBatchAtomic() error = %v", err)
+		}
+		if len(m.created) != 1 || len(m.put) != 1 || len(m.deleted) != 0 {
+			t.Errorf("created %d, put %d, deleted %d", len(m.created), len(m.put), len(m.deleted))
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		m := testMockAPI()
+		p := newWithAPIs(m, storingItems{mockAPI: m, fail: "Zebra"}, mockVaults{m}, Config{})
+		err := p.SetBatchAtomic(ctx, map[string]*vault.Secret{
+			"Private/Database/password": {Value: "new"},
+			"Private/Cache/password":    {Value: "x"},
+			"Private/Zebra/password":    {Value: "y"},
+		})
+
+		var batchErr *AtomicBatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("SetBatchAtomic() error = %v, want *AtomicBatchError", err)
+		}
+		if batchErr.Path != "Private/Zebra/password" {
+			t.Errorf("Path = %q", batchErr.Path)
+		}
+		if want := []string{"Private/Database/password", "Private/Cache/password"}; !reflect.DeepEqual(batchErr.RolledBack, want) {
+			t.Errorf("RolledBack = %v, want %v", batchErr.RolledBack, want)
+		}
+		if len(batchErr.Remaining) != 0 {
+			t.Errorf("Remaining = %v", batchErr.Remaining)
+		}
+
+		// The created item is deleted and the updated one restored
+		if !reflect.DeepEqual(m.deleted, []string{"new-Cache"}) {
+			t.Errorf("deleted = %v, want [new-Cache]", m.deleted)
+		}
+		restored := m.put[len(m.put)-1]
+		if restored.ID != "i1" || findValue(restored, "password") != "hunter2" {
+			t.Errorf("restored item = %+v", restored)
+		}
+	})
+
+	t.Run("rollback fails", func(t *testing.T) {
+		m := testMockAPI()
+		failPut := false
+		items := storingItems{mockAPI: m, fail: "Zebra", failPut: &failPut}
+		p := newWithAPIs(m, items, mockVaults{m}, Config{})
+
+		// Fail puts once the first write is done
+		p.items = hookedItems{itemsAPI: p.items, afterPut: func() { failPut = true }}
+		err := p.SetBatchAtomic(ctx, map[string]*vault.Secret{
+			"Private/Database/password": {Value: "new"},
+			"Private/Zebra/password":    {Value: "y"},
+		})
+
+		var batchErr *AtomicBatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("SetBatchAtomic() error = %v, want *AtomicBatchError", err)
+		}
+		if _, ok := batchErr.Remaining["Private/Database/password"]; !ok || len(batchErr.RolledBack) != 0 {
+			t.Errorf("RolledBack = %v, Remaining = %v", batchErr.RolledBack, batchErr.Remaining)
+		}
+	})
+
+	t.Run("rollback mirrored", func(t *testing.T) {
+		m := testMockAPI()
+		secondary := memory.New()
+		p := newWithAPIs(m, storingItems{mockAPI: m, fail: "Zebra"}, mockVaults{m}, Config{MirrorTo: secondary})
+		err := p.SetBatchAtomic(ctx, map[string]*vault.Secret{
+			"Private/Database/password": {Value: "new"},
+			"Private/Cache/password":    {Value: "x"},
+			"Private/Zebra/password":    {Value: "y"},
+		})
+		var batchErr *AtomicBatchError
+		if !errors.As(err, &batchErr) || len(batchErr.Remaining) != 0 {
+			t.Fatalf("SetBatchAtomic() error = %v, want a complete rollback", err)
+		}
+
+		if secret, err := secondary.Get(ctx, "Private/Database/password"); err != nil || secret.Value != "hunter2" {
+			t.Errorf("mirror Get() = %+v, %v; want the restored value", secret, err)
+		}
+		if ok, _ := secondary.Exists(ctx, "Private/Cache/password"); ok {
+			t.Error("mirror kept the write to the deleted item")
+		}
+	})
+
+	t.Run("mirror fails", func(t *testing.T) {
+		m := testMockAPI()
+		secondary := failingMirror{Vault: memory.New(), fail: "Private/Zebra/password"}
+		p := newWithAPIs(m, storingItems{mockAPI: m}, mockVaults{m}, Config{MirrorTo: secondary})
+		err := p.SetBatchAtomic(ctx, map[string]*vault.Secret{
+			"Private/Database/password": {Value: "new"},
+			"Private/Zebra/password":    {Value: "y"},
+		})
+
+		var batchErr *AtomicBatchError
+		if !errors.As(err, &batchErr) || !errors.Is(err, ErrMirror) {
+			t.Fatalf("SetBatchAtomic() error = %v, want *AtomicBatchError wrapping ErrMirror", err)
+		}
+		if want := []string{"Private/Zebra/password", "Private/Database/password"}; !reflect.DeepEqual(batchErr.RolledBack, want) || len(batchErr.Remaining) != 0 {
+			t.Errorf("RolledBack = %v, Remaining = %v; want %v rolled back", batchErr.RolledBack, batchErr.Remaining, want)
+		}
+		if !reflect.DeepEqual(m.deleted, []string{"new-Zebra"}) {
+			t.Errorf("deleted = %v, want the item written to 1Password", m.deleted)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		m := testMockAPI()
+		p := newWithAPIs(m, storingItems{mockAPI: m}, mockVaults{m}, Config{DryRun: true})
+		p.Use(func(next OpFunc) OpFunc {
+			return func(ctx context.Context, req *OpRequest) (*OpResponse, error) {
+				if req.Path == "Private/Zebra/password" {
+					return nil, errors.New("write rejected")
+				}
+				return next(ctx, req)
+			}
+		})
+
+		err := p.SetBatchAtomic(ctx, map[string]*vault.Secret{
+			"Private/Database/password": {Value: "new"},
+			"Private/Cache/password":    {Value: "x"},
+			"Private/Zebra/password":    {Value: "y"},
+		})
+		var batchErr *AtomicBatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("SetBatchAtomic() error = %v, want *AtomicBatchError", err)
+		}
+		if want := []string{"Private/Database/password", "Private/Cache/password"}; !reflect.DeepEqual(batchErr.RolledBack, want) {
+			t.Errorf("RolledBack = %v, want %v", batchErr.RolledBack, want)
+		}
+		if n := p.PlannedChanges().Len(); n != 0 {
+			t.Errorf("planned %d changes of a failed batch", n)
+		}
+
+		err = p.SetBatchAtomic(ctx, map[string]*vault.Secret{
+			"Private/Database/password": {Value: "new"},
+			"Private/Cache/password":    {Value: "x"},
+		})
+		if err != nil {
+			t.Fatalf("SetBatchAtomic() error = %v", err)
+		}
+		if n := p.PlannedChanges().Len(); n != 2 {
+			t.Errorf("planned %d changes, want 2", n)
+		}
+		if len(m.created) != 0 || len(m.put) != 0 || len(m.deleted) != 0 {
+			t.Errorf("dry run created %d, put %d, deleted %d", len(m.created), len(m.put), len(m.deleted))
+		}
+	})
+
+	t.Run("invalid before writing", func(t *testing.T) {
+		m := testMockAPI()
+		p := newMockProvider(m, Config{})
+		err := p.SetBatchAtomic(ctx, map[string]*vault.Secret{
+			"Private/Database/password": {Value: "new"},
+			"Missing/Item/password":     {Value: "x"},
+		})
+		if !errors.Is(err, vault.ErrSecretNotFound) {
+			t.Errorf("SetBatchAtomic() error = %v, want not found", err)
+		}
+		if len(m.put) != 0 || len(m.created) != 0 {
+			t.Error("SetBatchAtomic() wrote before validating")
+		}
+	})
+}
+
+// hookedItems calls afterPut after each successful Put.
+type hookedItems struct {
+	itemsAPI
+	afterPut func()
+}
+
+func (h hookedItems) Put(ctx context.Context, item op.Item) (op.Item, error) {
+	updated, err := h.itemsAPI.Put(ctx, item)
+	if err == nil {
+		h.afterPut()
+	}
+	return updated, err
+}
+
+// findValue returns the value of the field titled name.
+func findValue(item op.Item, name string) string {
+	for _, field := range item.Fields {
+		if field.Title == name {
+			return field.Value
+		}
+	}
+	return ""
+}
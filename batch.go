@@ -2,6 +2,12 @@ package onepassword
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/agentplexus/omnivault/vault"
 )
@@ -12,10 +18,7 @@ import (
 // Note: The 1Password SDK v0.1.x doesn't support batch resolution,
 // so this is implemented as sequential Resolve calls.
 func (p *Provider) GetBatch(ctx context.Context, paths []string) (map[string]*vault.Secret, error) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-
-	if p.closed {
+	if p.closed.Load() {
 		return nil, vault.NewVaultError("GetBatch", "", ProviderName, vault.ErrClosed)
 	}
 
@@ -26,10 +29,6 @@ func (p *Provider) GetBatch(ctx context.Context, paths []string) (map[string]*va
 	results := make(map[string]*vault.Secret)
 
 	// Process each path individually
-	// Note: We release the read lock for each Get call since Get acquires its own lock
-	p.mu.RUnlock()
-	defer p.mu.RLock()
-
 	for _, path := range paths {
 		secret, err := p.Get(ctx, path)
 		if err == nil {
@@ -41,54 +40,172 @@ func (p *Provider) GetBatch(ctx context.Context, paths []string) (map[string]*va
 	return results, nil
 }
 
-// SetBatch stores multiple secrets in a single operation.
-// Note: 1Password SDK doesn't support batch writes, so this is implemented
-// as sequential operations.
-func (p *Provider) SetBatch(ctx context.Context, secrets map[string]*vault.Secret) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+// DefaultBatchConcurrency is how many writes SetBatch and DeleteBatch run
+// at once.
+const DefaultBatchConcurrency = 4
 
-	if p.closed {
-		return vault.NewVaultError("SetBatch", "", ProviderName, vault.ErrClosed)
+// batchRetries is how often a batch write failing with a rate limit is
+// retried before its error is reported.
+const batchRetries = 3
+
+// batchBackoff is the first pause after a rate limit that didn't say how
+// long to wait. It doubles with each retry of the same secret.
+var batchBackoff = time.Second
+
+// BatchError reports the secrets a batch operation failed on. The batch
+// still runs to the end; secrets not listed succeeded.
+//
+//	var batchErr *onepassword.BatchError
+//	if errors.As(err, &batchErr) {
+//	    for path, err := range batchErr.Errors {
+//	        log.Printf("%s: %v", path, err)
+//	    }
+//	}
+type BatchError struct {
+	// Errors maps each failed path to its error.
+	Errors map[string]error
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	paths := slices.Sorted(maps.Keys(e.Errors))
+	if len(paths) == 1 {
+		return e.Errors[paths[0]].Error()
 	}
+	return fmt.Sprintf("%d secrets failed, first %s: %v", len(paths), paths[0], e.Errors[paths[0]])
+}
 
-	// Unlock for individual operations (they acquire their own locks)
-	p.mu.Unlock()
-	defer p.mu.Lock()
+// Unwrap returns the errors of the failed secrets.
+func (e *BatchError) Unwrap() []error {
+	paths := slices.Sorted(maps.Keys(e.Errors))
+	errs := make([]error, len(paths))
+	for i, path := range paths {
+		errs[i] = e.Errors[path]
+	}
+	return errs
+}
 
-	var lastErr error
-	for path, secret := range secrets {
-		if err := p.Set(ctx, path, secret); err != nil {
-			lastErr = err
-		}
+// SetBatch stores multiple secrets in a single operation.
+// Note: 1Password SDK doesn't support batch writes, so the secrets are
+// written by up to Config.BatchConcurrency concurrent Set calls. Secrets
+// of the same item are written one after another.
+//
+// A write rejected with a rate limit pauses the whole batch for the
+// requested time, or an increasing backoff, and is retried. Failures are
+// returned together as a *BatchError.
+func (p *Provider) SetBatch(ctx context.Context, secrets map[string]*vault.Secret) error {
+	if p.closed.Load() {
+		return vault.NewVaultError("SetBatch", "", ProviderName, vault.ErrClosed)
 	}
 
-	return lastErr
+	return p.runBatch(ctx, slices.Sorted(maps.Keys(secrets)), func(ctx context.Context, path string) error {
+		return p.Set(ctx, path, secrets[path])
+	})
 }
 
 // DeleteBatch removes multiple secrets in a single operation.
-// Note: 1Password SDK doesn't support batch deletes, so this is implemented
-// as sequential operations.
+// Note: 1Password SDK doesn't support batch deletes, so the secrets are
+// deleted by concurrent Delete calls, throttled and reported as SetBatch
+// does.
 func (p *Provider) DeleteBatch(ctx context.Context, paths []string) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	if p.closed {
+	if p.closed.Load() {
 		return vault.NewVaultError("DeleteBatch", "", ProviderName, vault.ErrClosed)
 	}
 
-	// Unlock for individual operations (they acquire their own locks)
-	p.mu.Unlock()
-	defer p.mu.Lock()
+	return p.runBatch(ctx, paths, p.Delete)
+}
 
-	var lastErr error
+// runBatch calls fn for each path, at most Config.BatchConcurrency at a
+// time. A rate limit pauses all calls not yet started, and the rate-limited
+// call is retried up to batchRetries times. It returns a *BatchError of the
+// paths fn failed for, or nil.
+func (p *Provider) runBatch(ctx context.Context, paths []string, fn func(context.Context, string) error) error {
+	var (
+		throttle batchThrottle
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		errs     = make(map[string]error)
+		sem      = make(chan struct{}, max(p.config.BatchConcurrency, 1))
+	)
 	for _, path := range paths {
-		if err := p.Delete(ctx, path); err != nil {
-			lastErr = err
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+
+			err := throttle.do(ctx, func() error { return fn(ctx, path) })
+			if err != nil {
+				mu.Lock()
+				errs[path] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &BatchError{Errors: errs}
+}
+
+// batchThrottle pauses a batch after a rate limit.
+type batchThrottle struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// do calls fn once any pause has passed, retrying it after rate limits.
+func (t *batchThrottle) do(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		if err := t.wait(ctx); err != nil {
+			return err
+		}
+		err := fn()
+		if err == nil || attempt == batchRetries || !isRateLimitError(err) {
+			return err
 		}
+		t.pause(retryDelay(err, attempt))
 	}
+}
 
-	return lastErr
+// wait blocks until the pause is over or ctx is done.
+func (t *batchThrottle) wait(ctx context.Context) error {
+	for {
+		t.mu.Lock()
+		d := time.Until(t.until)
+		t.mu.Unlock()
+		if d <= 0 {
+			return ctx.Err()
+		}
+
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// pause holds off calls for d, unless a longer pause is already set.
+func (t *batchThrottle) pause(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until := time.Now().Add(d); until.After(t.until) {
+		t.until = until
+	}
+}
+
+// retryDelay returns how long to wait before retrying after the rate limit
+// err: the time 1Password asked for, or batchBackoff doubled per attempt.
+func retryDelay(err error, attempt int) time.Duration {
+	var rl *RateLimitError
+	if errors.As(err, &rl) && rl.RetryAfter > 0 {
+		return rl.RetryAfter
+	}
+	return batchBackoff << attempt
 }
 
 // Ensure Provider implements vault.BatchVault.
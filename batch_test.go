@@ -0,0 +1,170 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestBatch_ConcurrentWithClose(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(testMockAPI(), Config{})
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			_, _ = p.GetBatch(ctx, []string{"Private/Database", "Work/API"})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = p.SetBatch(ctx, map[string]*vault.Secret{"Work/API/token": {Value: "x"}})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = p.DeleteBatch(ctx, []string{"Work/Missing"})
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = p.Close()
+	}()
+	wg.Wait()
+
+	if _, err := p.GetBatch(ctx, []string{"Private/Database"}); !errors.Is(err, vault.ErrClosed) {
+		t.Errorf("GetBatch() after Close error = %v, want ErrClosed", err)
+	}
+	if err := p.SetBatch(ctx, nil); !errors.Is(err, vault.ErrClosed) {
+		t.Errorf("SetBatch() after Close error = %v, want ErrClosed", err)
+	}
+	if err := p.DeleteBatch(ctx, nil); !errors.Is(err, vault.ErrClosed) {
+		t.Errorf("DeleteBatch() after Close error = %v, want ErrClosed", err)
+	}
+}
+
+func TestGetBatch_SkipsFailures(t *testing.T) {
+	p := newMockProvider(testMockAPI(), Config{})
+
+	got, err := p.GetBatch(context.Background(), []string{"Private/Database", "Private/Missing"})
+	if err != nil {
+		t.Fatalf("GetBatch() error = %v", err)
+	}
+	if len(got) != 1 || got["Private/Database"] == nil {
+		t.Errorf("GetBatch() = %v, want only Private/Database", got)
+	}
+}
+
+// countingItems tracks how many Creates are in flight at once, failing
+// the first rateLimited of them with a rate limit.
+type countingItems struct {
+	itemsAPI
+	mu          sync.Mutex
+	inFlight    int
+	peak        int
+	rateLimited int
+}
+
+func (c *countingItems) Create(ctx context.Context, params op.ItemCreateParams) (op.Item, error) {
+	c.mu.Lock()
+	c.inFlight++
+	c.peak = max(c.peak, c.inFlight)
+	limited := c.rateLimited > 0
+	if limited {
+		c.rateLimited--
+	}
+	c.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	if limited {
+		return op.Item{}, errors.New("rate limit exceeded")
+	}
+	return c.itemsAPI.Create(ctx, params)
+}
+
+func TestSetBatch_Concurrency(t *testing.T) {
+	secrets := make(map[string]*vault.Secret)
+	for i := range 12 {
+		secrets[fmt.Sprintf("Work/item-%d/password", i)] = &vault.Secret{Value: "x"}
+	}
+
+	for _, concurrency := range []int{1, 3} {
+		m := testMockAPI()
+		items := &countingItems{itemsAPI: m}
+		p := newWithAPIs(m, items, mockVaults{m}, Config{BatchConcurrency: concurrency})
+
+		if err := p.SetBatch(t.Context(), secrets); err != nil {
+			t.Fatalf("SetBatch() error = %v", err)
+		}
+		if len(m.created) != len(secrets) {
+			t.Errorf("created %d items, want %d", len(m.created), len(secrets))
+		}
+		if items.peak > concurrency || (concurrency > 1 && items.peak < 2) {
+			t.Errorf("BatchConcurrency %d: peak in-flight writes = %d", concurrency, items.peak)
+		}
+	}
+}
+
+func TestSetBatch_RetriesRateLimits(t *testing.T) {
+	defer func(d time.Duration) { batchBackoff = d }(batchBackoff)
+	batchBackoff = time.Millisecond
+
+	m := testMockAPI()
+	items := &countingItems{itemsAPI: m, rateLimited: 2}
+	p := newWithAPIs(m, items, mockVaults{m}, Config{})
+
+	err := p.SetBatch(t.Context(), map[string]*vault.Secret{
+		"Work/a/password": {Value: "x"},
+		"Work/b/password": {Value: "y"},
+	})
+	if err != nil {
+		t.Fatalf("SetBatch() error = %v", err)
+	}
+	if len(m.created) != 2 {
+		t.Errorf("created %d items, want 2", len(m.created))
+	}
+
+	// Retries give up eventually
+	items.rateLimited = batchRetries + 1
+	err = p.SetBatch(t.Context(), map[string]*vault.Secret{"Work/c/password": {Value: "z"}})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("SetBatch() error = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestBatch_AggregatesErrors(t *testing.T) {
+	m := testMockAPI()
+	p := newWithAPIs(m, storingItems{mockAPI: m, fail: "Bad"}, mockVaults{m}, Config{})
+
+	err := p.SetBatch(t.Context(), map[string]*vault.Secret{
+		"Work/Good/password": {Value: "x"},
+		"Work/Bad/password":  {Value: "y"},
+		"Missing/Item/field": {Value: "z"},
+	})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("SetBatch() error = %v, want *BatchError", err)
+	}
+	if len(batchErr.Errors) != 2 || batchErr.Errors["Work/Bad/password"] == nil || batchErr.Errors["Missing/Item/field"] == nil {
+		t.Errorf("BatchError.Errors = %v, want Work/Bad/password and Missing/Item/field", batchErr.Errors)
+	}
+	if !errors.Is(err, vault.ErrSecretNotFound) {
+		t.Errorf("SetBatch() error = %v, want to match ErrSecretNotFound", err)
+	}
+
+	err = p.DeleteBatch(t.Context(), []string{"Work/Good", ""})
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || batchErr.Errors[""] == nil {
+		t.Errorf("DeleteBatch() error = %v, want only the empty path to fail", err)
+	}
+}
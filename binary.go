@@ -0,0 +1,284 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// BytesField is the field SetBytes and GetBytes use for an item path.
+const BytesField = "data"
+
+// ErrChecksumMismatch is returned by GetBytes when the stored data doesn't
+// match its recorded length or SHA-256 checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// bytesChunkSize is the most base64 characters stored in one field.
+var bytesChunkSize = 64 << 10
+
+// SetBytes stores binary data base64-encoded in concealed fields of an
+// item, creating the item if needed. The path names the field
+// ("vault/item/field" or "vault/item/section/field", which adds the
+// section as needed); an item path uses BytesField. Data longer than one
+// field holds is split across "<field>", "<field>.2", "<field>.3", and so
+// on. The length and SHA-256 checksum are stored alongside in the
+// "<field>.size" and "<field>.sha256" text fields. Chunks left over from
+// earlier, longer data are removed.
+func (p *Provider) SetBytes(ctx context.Context, path string, data []byte) error {
+	return p.writeBytes(ctx, "SetBytes", path, bytes.NewReader(data), nil, nil, anyItem)
+}
+
+// itemPresence restricts writeBytes to new or existing items.
+type itemPresence int
+
+const (
+	anyItem itemPresence = iota
+	newItem
+	existingItem
+)
+
+// writeBytes stores the data read from r as SetBytes does, reporting its
+// progress, along with extra fields that replace the item's fields with the
+// same titles. It fails with vault.ErrAlreadyExists or
+// vault.ErrSecretNotFound if the item's presence doesn't match want.
+func (p *Provider) writeBytes(ctx context.Context, operation, path string, r io.Reader, progress func(int64), extra []op.ItemField, want itemPresence) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	if p.closed.Load() {
+		return vault.NewVaultError(operation, path, ProviderName, vault.ErrClosed)
+	}
+
+	if err := p.refuseDryRun(ctx, operation, path); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Write)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return vault.NewVaultError(operation, path, ProviderName, err)
+	}
+	name := bytesFieldName(parsed)
+	fields, err := readBytesFields(name, r, progress)
+	if err != nil {
+		return vault.NewVaultError(operation, path, ProviderName, err)
+	}
+
+	vaultID, err := p.resolveVaultID(ctx, parsed.Vault)
+	if err != nil {
+		return mapError(operation, path, err)
+	}
+
+	itemID, err := p.resolveItemID(ctx, vaultID, parsed.Item)
+	if isNotFoundError(err) {
+		if want == existingItem {
+			return mapError(operation, path, err)
+		}
+		params := op.ItemCreateParams{
+			VaultID:  vaultID,
+			Title:    parsed.Item,
+			Category: p.config.DefaultCategory,
+		}
+		if parsed.Section != "" {
+			placeInSection(fields, ensureSection(&params.Sections, parsed.Section))
+		}
+		params.Fields = append(fields, extra...)
+		if _, err := p.items.Create(ctx, params); err != nil {
+			return mapError(operation, path, err)
+		}
+		return p.mirrorBytes(ctx, operation, parsed)
+	}
+	if err != nil {
+		return mapError(operation, path, err)
+	}
+	if want == newItem {
+		return vault.NewVaultError(operation, path, ProviderName, vault.ErrAlreadyExists)
+	}
+
+	item, err := p.items.Get(ctx, vaultID, itemID)
+	if err != nil {
+		return mapError(operation, path, err)
+	}
+
+	// With a section, only the chunks in that section are replaced
+	if parsed.Section != "" {
+		placeInSection(fields, ensureSection(&item.Sections, parsed.Section))
+	}
+	kept := item.Fields[:0]
+	for _, field := range item.Fields {
+		chunk := isBytesField(name, field.Title) && (parsed.Section == "" || inSection(item, field, parsed.Section))
+		if !chunk && !slices.ContainsFunc(extra, func(f op.ItemField) bool { return f.Title == field.Title }) {
+			kept = append(kept, field)
+		}
+	}
+	item.Fields = append(append(kept, fields...), extra...)
+
+	if _, err := p.items.Put(ctx, item); err != nil {
+		return mapError(operation, path, err)
+	}
+	return p.mirrorBytes(ctx, operation, parsed)
+}
+
+// mirrorBytes mirrors the item writeBytes wrote to, as a whole.
+func (p *Provider) mirrorBytes(ctx context.Context, operation string, parsed *ParsedPath) error {
+	item := &ParsedPath{Vault: parsed.Vault, Item: parsed.Item}
+	return p.mirrorItem(ctx, operation, item.String(), item)
+}
+
+// GetBytes returns binary data stored by SetBytes. The chunks are joined
+// and decoded, and checked against the recorded length and checksum when
+// present, failing with ErrChecksumMismatch on a difference. A single
+// base64 field written by other tools can be read as well.
+func (p *Provider) GetBytes(ctx context.Context, path string) ([]byte, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("GetBytes", path, ProviderName, vault.ErrClosed)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return nil, vault.NewVaultError("GetBytes", path, ProviderName, err)
+	}
+	name := bytesFieldName(parsed)
+
+	item, err := p.fetchItem(ctx, parsed)
+	if err != nil {
+		return nil, mapError("GetBytes", path, err)
+	}
+
+	data, err := decodeBytes(item, parsed.Section, name)
+	if err != nil {
+		return nil, vault.NewVaultError("GetBytes", path, ProviderName, err)
+	}
+	return data, nil
+}
+
+// decodeBytes returns the data SetBytes stored in the named field of item,
+// checked against its recorded length and checksum.
+func decodeBytes(item op.Item, section, name string) ([]byte, error) {
+	first, ok := findField(item, section, name)
+	if !ok {
+		return nil, ErrFieldNotFound
+	}
+	var encoded bytes.Buffer
+	encoded.WriteString(first.Value)
+	for n := 2; ; n++ {
+		chunk, ok := findField(item, section, bytesChunkName(name, n))
+		if !ok {
+			break
+		}
+		encoded.WriteString(chunk.Value)
+	}
+
+	// Decode from the buffer rather than a string copy, and zero it after
+	data := make([]byte, base64.StdEncoding.DecodedLen(encoded.Len()))
+	n, err := base64.StdEncoding.Decode(data, encoded.Bytes())
+	zero(encoded.Bytes())
+	data = data[:n]
+	if err != nil {
+		zero(data)
+		return nil, fmt.Errorf("invalid base64 data: %w", err)
+	}
+
+	if size, ok := findField(item, section, name+".size"); ok && size.Value != strconv.Itoa(len(data)) {
+		return nil, fmt.Errorf("%w: read %d bytes, expected %s", ErrChecksumMismatch, len(data), size.Value)
+	}
+	if sum, ok := findField(item, section, name+".sha256"); ok && sum.Value != sha256Hex(data) {
+		return nil, fmt.Errorf("%w: SHA-256 differs from %s", ErrChecksumMismatch, sum.Value)
+	}
+	return data, nil
+}
+
+// bytesFieldName returns the field named by parsed, or BytesField.
+func bytesFieldName(parsed *ParsedPath) string {
+	if parsed.Field != "" {
+		return parsed.Field
+	}
+	return BytesField
+}
+
+// bytesChunkName returns the title of the nth chunk of the named field.
+func bytesChunkName(name string, n int) string {
+	return name + "." + strconv.Itoa(n)
+}
+
+// isBytesField reports whether title is a chunk or integrity field that
+// SetBytes writes for name.
+func isBytesField(name, title string) bool {
+	if title == name || title == name+".size" || title == name+".sha256" {
+		return true
+	}
+	if len(title) <= len(name)+1 || title[:len(name)+1] != name+"." {
+		return false
+	}
+	n, err := strconv.Atoi(title[len(name)+1:])
+	return err == nil && n >= 2
+}
+
+// bytesFields encodes data into chunk fields and integrity fields.
+func bytesFields(name string, data []byte) []op.ItemField {
+	fields, _ := readBytesFields(name, bytes.NewReader(data), nil)
+	return fields
+}
+
+// readBytesFields encodes the data read from r into chunk fields and
+// integrity fields, one chunk at a time, calling progress, if set, with
+// the number of bytes read so far.
+func readBytesFields(name string, r io.Reader, progress func(int64)) ([]op.ItemField, error) {
+	id := sanitizeID(name)
+	hash := sha256.New()
+	block := make([]byte, max(bytesChunkSize/4*3, 3))
+	defer zero(block)
+
+	var fields []op.ItemField
+	var size int64
+	for n := 1; ; n++ {
+		read, err := io.ReadFull(r, block)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+		if read == 0 && n > 1 {
+			break
+		}
+		hash.Write(block[:read])
+		size += int64(read)
+		if progress != nil && read > 0 {
+			progress(size)
+		}
+
+		field := op.ItemField{ID: id, Title: name, Value: base64.StdEncoding.EncodeToString(block[:read]), FieldType: op.ItemFieldTypeConcealed}
+		if n > 1 {
+			field.ID = id + "_" + strconv.Itoa(n)
+			field.Title = bytesChunkName(name, n)
+		}
+		fields = append(fields, field)
+		if read < len(block) {
+			break
+		}
+	}
+
+	return append(fields,
+		op.ItemField{ID: id + "_size", Title: name + ".size", Value: strconv.FormatInt(size, 10), FieldType: op.ItemFieldTypeText},
+		op.ItemField{ID: id + "_sha256", Title: name + ".sha256", Value: hex.EncodeToString(hash.Sum(nil)), FieldType: op.ItemFieldTypeText},
+	), nil
+}
+
+// sha256Hex returns the hex-encoded SHA-256 checksum of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
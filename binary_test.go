@@ -0,0 +1,135 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func TestSetBytes_GetBytes(t *testing.T) {
+	ctx := context.Background()
+	defer func(size int) { bytesChunkSize = size }(bytesChunkSize)
+	bytesChunkSize = 8
+
+	data := []byte("\x00\x01binary\xff payload")
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	if err := p.SetBytes(ctx, "Private/Keystore", data); err != nil {
+		t.Fatalf("SetBytes() error = %v", err)
+	}
+	if len(m.created) != 1 {
+		t.Fatalf("created %d items, want 1", len(m.created))
+	}
+	fields := m.created[0].Fields
+
+	var titles []string
+	for _, f := range fields {
+		titles = append(titles, f.Title)
+	}
+	want := []string{"data", "data.2", "data.3", "data.size", "data.sha256"}
+	if len(titles) != len(want) {
+		t.Fatalf("fields = %v, want %v", titles, want)
+	}
+	for i := range want {
+		if titles[i] != want[i] {
+			t.Fatalf("fields = %v, want %v", titles, want)
+		}
+	}
+
+	m.items["v1"] = append(m.items["v1"], op.Item{ID: "i3", Title: "Keystore", VaultID: "v1", Fields: fields})
+	got, err := p.GetBytes(ctx, "Private/Keystore")
+	if err != nil {
+		t.Fatalf("GetBytes() error = %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("GetBytes() = %q, want %q", got, data)
+	}
+
+	// Shorter data replaces all earlier chunks
+	if err := p.SetBytes(ctx, "Private/Keystore", []byte("x")); err != nil {
+		t.Fatalf("SetBytes(update) error = %v", err)
+	}
+	if n := len(m.put[0].Fields); n != 3 {
+		t.Errorf("updated item has %d fields, want 3: %+v", n, m.put[0].Fields)
+	}
+}
+
+func TestSetBytes_Section(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	p := newWithAPIs(m, storingItems{mockAPI: m}, mockVaults{m}, Config{})
+
+	if err := p.SetBytes(ctx, "Private/Keystore/certs/p12", []byte("first")); err != nil {
+		t.Fatalf("SetBytes(new) error = %v", err)
+	}
+	if got, err := p.GetBytes(ctx, "Private/Keystore/certs/p12"); err != nil || string(got) != "first" {
+		t.Fatalf("GetBytes(new) = %q, %v; want first", got, err)
+	}
+
+	// Replacing the data in the section leaves a top-level field of the
+	// same name alone
+	if err := p.SetBytes(ctx, "Private/Keystore/p12", []byte("top")); err != nil {
+		t.Fatalf("SetBytes(top) error = %v", err)
+	}
+	if err := p.SetBytes(ctx, "Private/Keystore/certs/p12", []byte("second")); err != nil {
+		t.Fatalf("SetBytes(update) error = %v", err)
+	}
+	if got, err := p.GetBytes(ctx, "Private/Keystore/certs/p12"); err != nil || string(got) != "second" {
+		t.Errorf("GetBytes(update) = %q, %v; want second", got, err)
+	}
+	item := m.put[len(m.put)-1]
+	if len(item.Sections) != 1 || item.Sections[0].Title != "certs" {
+		t.Errorf("sections = %+v, want certs", item.Sections)
+	}
+	if n := len(item.Fields); n != 6 {
+		t.Errorf("item has %d fields, want 6: %+v", n, item.Fields)
+	}
+}
+
+func TestGetBytes_Integrity(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	fields := bytesFields("cert", []byte("original"))
+	fields[0].Value = "dGFtcGVyZWQ=" // "tampered"
+	m.items["v1"] = append(m.items["v1"],
+		op.Item{ID: "i3", Title: "Tampered", VaultID: "v1", Fields: fields},
+		op.Item{ID: "i4", Title: "Plain", VaultID: "v1", Fields: []op.ItemField{{Title: "blob", Value: "aGk="}}},
+	)
+
+	if _, err := p.GetBytes(ctx, "Private/Tampered/cert"); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("GetBytes(tampered) error = %v, want ErrChecksumMismatch", err)
+	}
+	if got, err := p.GetBytes(ctx, "Private/Plain/blob"); err != nil || string(got) != "hi" {
+		t.Errorf("GetBytes(plain) = %q, %v; want hi", got, err)
+	}
+	if _, err := p.GetBytes(ctx, "Private/Plain"); !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("GetBytes(no data field) error = %v, want ErrFieldNotFound", err)
+	}
+}
+
+func TestIsBytesField(t *testing.T) {
+	tests := []struct {
+		title string
+		want  bool
+	}{
+		{"data", true},
+		{"data.2", true},
+		{"data.size", true},
+		{"data.sha256", true},
+		{"data.1", false},
+		{"data.x", false},
+		{"database", false},
+		{"other", false},
+	}
+	for _, tt := range tests {
+		if got := isBytesField("data", tt.title); got != tt.want {
+			t.Errorf("isBytesField(%q) = %v, want %v", tt.title, got, tt.want)
+		}
+	}
+}
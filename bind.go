@@ -0,0 +1,187 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// tagName is the struct tag read by GetInto.
+const tagName = "op"
+
+// boundField is a struct field tagged with a secret path.
+type boundField struct {
+	value    reflect.Value
+	name     string
+	path     string
+	optional bool
+}
+
+// GetInto populates the tagged fields of the struct pointed to by dst.
+// Fields are tagged with a secret path, which may rely on the default vault:
+//
+//	type Config struct {
+//	    DBPassword string        `op:"Prod/Database/password"`
+//	    APIKey     []byte        `op:"API Keys/stripe"`
+//	    Port       int           `op:"Prod/Database/port"`
+//	    Debug      bool          `op:"Prod/Flags/debug,optional"`
+//	    Timeout    time.Duration `op:"Prod/Flags/timeout,optional"`
+//	}
+//
+// Supported field types are string, []byte, bool, signed and unsigned
+// integers, floats, and time.Duration. Untagged struct fields are searched
+// recursively. Every distinct path is resolved once, and all failures are
+// reported together. A secret that does not exist leaves an ",optional"
+// field unchanged.
+func (p *Provider) GetInto(ctx context.Context, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return vault.NewVaultError("GetInto", "", ProviderName,
+			fmt.Errorf("%w: destination must be a non-nil pointer to a struct", vault.ErrInvalidPath))
+	}
+
+	fields, err := collectBoundFields(rv.Elem(), "")
+	if err != nil {
+		return vault.NewVaultError("GetInto", "", ProviderName, err)
+	}
+
+	// Resolve each distinct path once
+	secrets := make(map[string]*vault.Secret)
+	failures := make(map[string]error)
+	for _, f := range fields {
+		if _, done := secrets[f.path]; done {
+			continue
+		}
+		if _, done := failures[f.path]; done {
+			continue
+		}
+		secret, err := p.Get(ctx, f.path)
+		if err != nil {
+			failures[f.path] = err
+			continue
+		}
+		secrets[f.path] = secret
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if err, failed := failures[f.path]; failed {
+			if f.optional && errors.Is(err, vault.ErrSecretNotFound) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", f.name, err))
+			continue
+		}
+		if err := setFieldValue(f.value, secrets[f.path].Value); err != nil {
+			errs = append(errs, vault.NewVaultError("GetInto", f.path, ProviderName,
+				fmt.Errorf("%s: %w", f.name, err)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// collectBoundFields returns the tagged fields of the struct v, descending
+// into untagged struct fields.
+func collectBoundFields(v reflect.Value, prefix string) ([]boundField, error) {
+	var fields []boundField
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := prefix + sf.Name
+
+		tag, ok := sf.Tag.Lookup(tagName)
+		if !ok || tag == "-" {
+			if !ok && sf.Type.Kind() == reflect.Struct {
+				nested, err := collectBoundFields(v.Field(i), name+".")
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, nested...)
+			}
+			continue
+		}
+
+		path, opts, _ := strings.Cut(tag, ",")
+		if path == "" {
+			return nil, fmt.Errorf("%s: empty %s tag", name, tagName)
+		}
+		optional := false
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "":
+			case "optional":
+				optional = true
+			default:
+				return nil, fmt.Errorf("%s: unknown tag option %q", name, opt)
+			}
+		}
+
+		fields = append(fields, boundField{
+			value:    v.Field(i),
+			name:     name,
+			path:     path,
+			optional: optional,
+		})
+	}
+	return fields, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setFieldValue converts s to the type of v and stores it.
+func setFieldValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported field type %s", v.Type())
+		}
+		v.SetBytes([]byte(s))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package onepassword
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCollectBoundFields(t *testing.T) {
+	type nested struct {
+		Token string `op:"Work/API/token"`
+	}
+	type config struct {
+		Password string `op:"Prod/DB/password"`
+		Port     int    `op:"Prod/DB/port,optional"`
+		Skipped  string `op:"-"`
+		Plain    string
+		API      nested
+		internal string `op:"Prod/DB/user"` //nolint:unused
+	}
+
+	var cfg config
+	fields, err := collectBoundFields(reflect.ValueOf(&cfg).Elem(), "")
+	if err != nil {
+		t.Fatalf("collectBoundFields() error = %v", err)
+	}
+
+	want := []struct {
+		name     string
+		path     string
+		optional bool
+	}{
+		{"Password", "Prod/DB/password", false},
+		{"Port", "Prod/DB/port", true},
+		{"API.Token", "Work/API/token", false},
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("collectBoundFields() returned %d fields, want %d", len(fields), len(want))
+	}
+	for i, w := range want {
+		f := fields[i]
+		if f.name != w.name || f.path != w.path || f.optional != w.optional {
+			t.Errorf("field %d = {%s %s %v}, want %+v", i, f.name, f.path, f.optional, w)
+		}
+	}
+}
+
+func TestCollectBoundFields_InvalidTag(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  any
+	}{
+		{"empty path", &struct {
+			A string `op:",optional"`
+		}{}},
+		{"unknown option", &struct {
+			A string `op:"V/I/f,required"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := collectBoundFields(reflect.ValueOf(tt.dst).Elem(), ""); err == nil {
+				t.Error("collectBoundFields() should fail")
+			}
+		})
+	}
+}
+
+func TestSetFieldValue(t *testing.T) {
+	var cfg struct {
+		S   string
+		B   []byte
+		OK  bool
+		N   int
+		U   uint16
+		F   float64
+		D   time.Duration
+		Bad []string
+	}
+	v := reflect.ValueOf(&cfg).Elem()
+
+	tests := []struct {
+		field   string
+		input   string
+		wantErr bool
+	}{
+		{"S", "hunter2", false},
+		{"B", "bytes", false},
+		{"OK", "true", false},
+		{"N", " 5432\n", false},
+		{"U", "70000", true},
+		{"U", "443", false},
+		{"F", "0.5", false},
+		{"D", "30s", false},
+		{"N", "abc", true},
+		{"Bad", "x", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field+"="+tt.input, func(t *testing.T) {
+			err := setFieldValue(v.FieldByName(tt.field), tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("setFieldValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if cfg.S != "hunter2" || string(cfg.B) != "bytes" || !cfg.OK || cfg.N != 5432 ||
+		cfg.U != 443 || cfg.F != 0.5 || cfg.D != 30*time.Second {
+		t.Errorf("unexpected result %+v", cfg)
+	}
+}
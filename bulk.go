@@ -0,0 +1,142 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// BulkDeleteOptions controls DeleteByPrefix and DeleteByTag.
+type BulkDeleteOptions struct {
+	// Confirm deletes the matching items. Without it nothing is deleted,
+	// and the paths that would be deleted are returned: a dry run.
+	Confirm bool
+
+	// Glob matches the DeleteByPrefix prefix as a glob pattern, as
+	// ListGlob does, rather than literally.
+	Glob bool
+}
+
+// DeleteByPrefix deletes every item List returns for prefix, or ListGlob
+// with opts.Glob, and returns the paths of the items deleted (or, without
+// opts.Confirm, of the items that would be). The prefix must not be empty.
+// Failures don't stop the other deletions; they are returned joined.
+//
+//	// Review first
+//	paths, err := provider.DeleteByPrefix(ctx, "CI/run-*", onepassword.BulkDeleteOptions{Glob: true})
+//	// Then delete
+//	paths, err = provider.DeleteByPrefix(ctx, "CI/run-*", onepassword.BulkDeleteOptions{Glob: true, Confirm: true})
+func (p *Provider) DeleteByPrefix(ctx context.Context, prefix string, opts BulkDeleteOptions) ([]string, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("DeleteByPrefix", prefix, ProviderName, vault.ErrClosed)
+	}
+	if prefix == "" {
+		return nil, vault.NewVaultError("DeleteByPrefix", prefix, ProviderName,
+			fmt.Errorf("%w: a prefix is required", ErrInvalidPath))
+	}
+
+	wantVault, wantItem, err := listMatcher(prefix, opts.Glob)
+	if err != nil {
+		return nil, vault.NewVaultError("DeleteByPrefix", prefix, ProviderName, err)
+	}
+
+	var items []ItemInfo
+	listCtx, cancel := withTimeout(ctx, p.config.Timeouts.List)
+	err = p.walkItems(listCtx, wantVault, func(v op.VaultOverview, item op.ItemOverview) bool {
+		if wantItem(v, item) {
+			items = append(items, newItemInfo(v, item))
+		}
+		return true
+	})
+	cancel()
+	if err != nil {
+		return nil, mapError("DeleteByPrefix", prefix, err)
+	}
+
+	return p.deleteItems(ctx, "DeleteByPrefix", items, opts)
+}
+
+// DeleteByTag deletes every item in a vault having tag and returns the
+// paths of the items deleted (or, without opts.Confirm, of the items that
+// would be). A "key" tag also matches "key:value" tags, as in Search.
+// Failures don't stop the other deletions; they are returned joined.
+func (p *Provider) DeleteByTag(ctx context.Context, vaultName, tag string, opts BulkDeleteOptions) ([]string, error) {
+	if vaultName == "" {
+		vaultName = p.getDefaultVault()
+	}
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("DeleteByTag", vaultName, ProviderName, vault.ErrClosed)
+	}
+	if vaultName == "" || tag == "" {
+		return nil, vault.NewVaultError("DeleteByTag", vaultName, ProviderName,
+			fmt.Errorf("%w: a vault (or default vault) and a tag are required", ErrInvalidPath))
+	}
+
+	vaultID, err := p.resolveVaultID(ctx, vaultName)
+	if err != nil {
+		return nil, mapError("DeleteByTag", vaultName, err)
+	}
+	items, err := p.Search(ctx, "", SearchOptions{Vaults: []string{vaultID}, Tags: []string{tag}})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.deleteItems(ctx, "DeleteByTag", items, opts)
+}
+
+// deleteItems deletes items by ID, or only reports their paths without
+// opts.Confirm. Items already gone count as deleted.
+func (p *Provider) deleteItems(ctx context.Context, operation string, items []ItemInfo, opts BulkDeleteOptions) ([]string, error) {
+	if !opts.Confirm {
+		paths := make([]string, len(items))
+		for i, item := range items {
+			paths[i] = item.Path
+		}
+		return paths, nil
+	}
+
+	if plan := p.planFor(ctx); plan != nil {
+		paths := make([]string, len(items))
+		for i, item := range items {
+			paths[i] = item.Path
+			planDelete(plan, item.Path, func(ctx context.Context, p *Provider) error {
+				_, err := p.deleteItems(ctx, operation, []ItemInfo{item}, BulkDeleteOptions{Confirm: true})
+				return err
+			})
+		}
+		return paths, nil
+	}
+
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	if p.closed.Load() {
+		return nil, vault.NewVaultError(operation, "", ProviderName, vault.ErrClosed)
+	}
+
+	var deleted []string
+	var errs []error
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, vault.NewVaultError(operation, item.Path, ProviderName, err))
+			break
+		}
+
+		p.invalidateDiskCache(ctx, &ParsedPath{Vault: item.VaultTitle, Item: item.Title})
+		itemCtx, cancel := withTimeout(ctx, p.config.Timeouts.Write)
+		err := p.items.Delete(itemCtx, item.VaultID, item.ID)
+		cancel()
+		if err != nil && !isNotFoundError(err) {
+			errs = append(errs, mapError(operation, item.Path, err))
+			continue
+		}
+		deleted = append(deleted, item.Path)
+		if err := p.mirrorDeleteItem(ctx, operation, item.Path, &ParsedPath{Vault: item.VaultTitle, Item: item.Title}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return deleted, errors.Join(errs...)
+}
@@ -0,0 +1,68 @@
+package onepassword
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestProvider_DeleteByPrefix(t *testing.T) {
+	ctx := t.Context()
+	m := listMockAPI()
+	p := newMockProvider(m, Config{})
+
+	want := []string{"Work/api-eu-prod", "Work/api-us-prod"}
+	got, err := p.DeleteByPrefix(ctx, "Work/api-*-prod", BulkDeleteOptions{Glob: true})
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("DeleteByPrefix(dry run) = %v, %v; want %v", got, err, want)
+	}
+	if len(m.deleted) != 0 {
+		t.Fatalf("dry run deleted %v", m.deleted)
+	}
+
+	got, err = p.DeleteByPrefix(ctx, "Work/api-*-prod", BulkDeleteOptions{Glob: true, Confirm: true})
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("DeleteByPrefix() = %v, %v; want %v", got, err, want)
+	}
+	if !reflect.DeepEqual(m.deleted, []string{"i3", "i4"}) {
+		t.Errorf("deleted = %v, want [i3 i4]", m.deleted)
+	}
+
+	if _, err := p.DeleteByPrefix(ctx, "", BulkDeleteOptions{Confirm: true}); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("DeleteByPrefix(\"\") error = %v, want ErrInvalidPath", err)
+	}
+}
+
+func TestProvider_DeleteByTag(t *testing.T) {
+	ctx := t.Context()
+	m := searchMockAPI()
+	p := newMockProvider(m, Config{})
+
+	got, err := p.DeleteByTag(ctx, "Private", "env:prod", BulkDeleteOptions{})
+	if want := []string{"Private/Database", "Private/postgres-prod"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("DeleteByTag(dry run) = %v, %v; want %v", got, err, want)
+	}
+	if len(m.deleted) != 0 {
+		t.Fatalf("dry run deleted %v", m.deleted)
+	}
+
+	got, err = p.DeleteByTag(ctx, "Private", "team", BulkDeleteOptions{Confirm: true})
+	if want := []string{"Private/postgres-prod"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("DeleteByTag() = %v, %v; want %v", got, err, want)
+	}
+	if !reflect.DeepEqual(m.deleted, []string{"i4"}) {
+		t.Errorf("deleted = %v, want [i4]", m.deleted)
+	}
+}
+
+func TestProvider_DeleteItems_Errors(t *testing.T) {
+	m := listMockAPI()
+	p := newMockProvider(m, Config{})
+
+	items := []ItemInfo{{ID: "i3", VaultID: "v2", Path: "Work/api-eu-prod"}}
+	m.err = errors.New("connection reset")
+	got, err := p.deleteItems(t.Context(), "DeleteByPrefix", items, BulkDeleteOptions{Confirm: true})
+	if err == nil || len(got) != 0 {
+		t.Errorf("deleteItems() = %v, %v; want an error and nothing deleted", got, err)
+	}
+}
@@ -0,0 +1,400 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Bundle format identifiers.
+const (
+	BundleFormat  = "omnivault-onepassword-bundle"
+	BundleVersion = 1
+)
+
+// ErrBundlePassphrase is returned by Import when a bundle is encrypted and
+// the passphrase is missing or wrong.
+var ErrBundlePassphrase = errors.New("bundle passphrase missing or incorrect")
+
+// scrypt parameters for bundle encryption.
+const (
+	bundleScryptN = 1 << 15
+	bundleScryptR = 8
+	bundleScryptP = 1
+)
+
+// Limits on the scrypt parameters read from a bundle, which would
+// otherwise let a crafted bundle make Import use unbounded memory and
+// time. scrypt needs 128*N*r bytes of memory, at most 256 MiB here.
+const (
+	bundleMaxScryptNR = 1 << 21
+	bundleMaxScryptP  = 16
+)
+
+// bundleFile is the top-level JSON document written by Export:
+//
+//	{
+//	  "format": "omnivault-onepassword-bundle",
+//	  "version": 1,
+//	  "items": [BundleItem, ...]
+//	}
+//
+// An encrypted bundle replaces "items" with "encryption" and "ciphertext",
+// the AES-256-GCM sealed JSON array of items:
+//
+//	{
+//	  "format": "omnivault-onepassword-bundle",
+//	  "version": 1,
+//	  "encryption": {"kdf": "scrypt", "n": 32768, "r": 8, "p": 1,
+//	                 "salt": "<base64>", "cipher": "aes-256-gcm", "nonce": "<base64>"},
+//	  "ciphertext": "<base64>"
+//	}
+type bundleFile struct {
+	Format     string            `json:"format"`
+	Version    int               `json:"version"`
+	Items      []BundleItem      `json:"items,omitempty"`
+	Encryption *bundleEncryption `json:"encryption,omitempty"`
+	Ciphertext []byte            `json:"ciphertext,omitempty"`
+}
+
+// bundleEncryption describes how a bundle's items were encrypted.
+type bundleEncryption struct {
+	KDF    string `json:"kdf"`
+	N      int    `json:"n"`
+	R      int    `json:"r"`
+	P      int    `json:"p"`
+	Salt   []byte `json:"salt"`
+	Cipher string `json:"cipher"`
+	Nonce  []byte `json:"nonce"`
+}
+
+// BundleItem is one item in a bundle.
+type BundleItem struct {
+	Vault    string          `json:"vault"`
+	Title    string          `json:"title"`
+	Category string          `json:"category"`
+	Tags     []string        `json:"tags,omitempty"`
+	Sections []BundleSection `json:"sections,omitempty"`
+	Fields   []BundleField   `json:"fields"`
+	Websites []BundleWebsite `json:"websites,omitempty"`
+}
+
+// BundleSection is a section of a bundled item.
+type BundleSection struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// BundleField is a field of a bundled item. Section is the ID of the
+// section holding the field, if any.
+type BundleField struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Type    string `json:"type"`
+	Value   string `json:"value"`
+	Section string `json:"section,omitempty"`
+}
+
+// BundleWebsite is an autofill website of a bundled item.
+type BundleWebsite struct {
+	URL      string `json:"url"`
+	Label    string `json:"label,omitempty"`
+	Autofill string `json:"autofill,omitempty"`
+}
+
+// ExportOptions configures Export.
+type ExportOptions struct {
+	// Passphrase, when set, encrypts the items with AES-256-GCM using a key
+	// derived with scrypt.
+	Passphrase string
+}
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// Passphrase decrypts an encrypted bundle.
+	Passphrase string
+
+	// Vault, when set, imports every item into this vault instead of the
+	// vault it was exported from.
+	Vault string
+
+	// Overwrite replaces the content of existing items with the same title.
+	// Otherwise they are skipped.
+	Overwrite bool
+}
+
+// ImportResult reports what Import did, by "vault/item" path.
+type ImportResult struct {
+	Created []string
+	Updated []string
+	Skipped []string
+}
+
+// Export writes the items matching prefix (see List) to w as a JSON bundle,
+// preserving categories, fields and their types, sections, tags, and
+// websites. Set opts.Passphrase to encrypt the bundle.
+//
+//	f, _ := os.Create("backup.json")
+//	err := provider.Export(ctx, "Prod/", f, onepassword.ExportOptions{Passphrase: pass})
+func (p *Provider) Export(ctx context.Context, prefix string, w io.Writer, opts ExportOptions) error {
+	paths, err := p.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	if p.closed.Load() {
+		return vault.NewVaultError("Export", prefix, ProviderName, vault.ErrClosed)
+	}
+
+	items := make([]BundleItem, 0, len(paths))
+	for _, path := range paths {
+		parsed, err := p.parsePath(path)
+		if err != nil {
+			return vault.NewVaultError("Export", path, ProviderName, err)
+		}
+		item, err := p.fetchItem(ctx, parsed)
+		if err != nil {
+			return mapError("Export", path, err)
+		}
+		items = append(items, itemToBundle(item, parsed.Vault))
+	}
+
+	file, err := sealBundle(items, opts.Passphrase)
+	if err != nil {
+		return vault.NewVaultError("Export", prefix, ProviderName, err)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(file); err != nil {
+		return vault.NewVaultError("Export", prefix, ProviderName, err)
+	}
+	return nil
+}
+
+// Import creates the items of a bundle written by Export. Items whose title
+// already exists in the target vault are skipped unless opts.Overwrite is
+// set. Import stops at the first failure; the result lists what was done.
+func (p *Provider) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	var file bundleFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, vault.NewVaultError("Import", "", ProviderName, fmt.Errorf("invalid bundle: %w", err))
+	}
+	items, err := openBundle(&file, opts.Passphrase)
+	if err != nil {
+		return nil, vault.NewVaultError("Import", "", ProviderName, err)
+	}
+
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("Import", "", ProviderName, vault.ErrClosed)
+	}
+
+	if err := p.refuseDryRun(ctx, "Import", ""); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	for _, item := range items {
+		vaultName := item.Vault
+		if opts.Vault != "" {
+			vaultName = opts.Vault
+		}
+		path := BuildPath(vaultName, item.Title)
+
+		vaultID, err := p.resolveVaultID(ctx, vaultName)
+		if err != nil {
+			return result, mapError("Import", path, err)
+		}
+		params := bundleToCreateParams(item, vaultID)
+
+		itemID, err := p.resolveItemID(ctx, vaultID, item.Title)
+		switch {
+		case err == nil && !opts.Overwrite:
+			result.Skipped = append(result.Skipped, path)
+			continue
+		case err == nil:
+			existing, err := p.items.Get(ctx, vaultID, itemID)
+			if err != nil {
+				return result, mapError("Import", path, err)
+			}
+			existing.Fields = params.Fields
+			existing.Sections = params.Sections
+			existing.Tags = params.Tags
+			existing.Websites = params.Websites
+			if _, err := p.items.Put(ctx, existing); err != nil {
+				return result, mapError("Import", path, err)
+			}
+			result.Updated = append(result.Updated, path)
+		case isNotFoundError(err):
+			if _, err := p.items.Create(ctx, params); err != nil {
+				return result, mapError("Import", path, err)
+			}
+			result.Created = append(result.Created, path)
+		default:
+			return result, mapError("Import", path, err)
+		}
+		if err := p.mirrorItem(ctx, "Import", path, &ParsedPath{Vault: vaultName, Item: item.Title}); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// itemToBundle converts an item to its bundle form.
+func itemToBundle(item op.Item, vaultName string) BundleItem {
+	b := BundleItem{
+		Vault:    vaultName,
+		Title:    item.Title,
+		Category: string(item.Category),
+		Tags:     item.Tags,
+		Fields:   make([]BundleField, 0, len(item.Fields)),
+	}
+	for _, s := range item.Sections {
+		b.Sections = append(b.Sections, BundleSection{ID: s.ID, Title: s.Title})
+	}
+	for _, f := range item.Fields {
+		field := BundleField{ID: f.ID, Title: f.Title, Type: string(f.FieldType), Value: f.Value}
+		if f.SectionID != nil {
+			field.Section = *f.SectionID
+		}
+		b.Fields = append(b.Fields, field)
+	}
+	for _, w := range item.Websites {
+		b.Websites = append(b.Websites, BundleWebsite{URL: w.URL, Label: w.Label, Autofill: string(w.AutofillBehavior)})
+	}
+	return b
+}
+
+// bundleToCreateParams converts a bundled item to item creation parameters.
+func bundleToCreateParams(b BundleItem, vaultID string) op.ItemCreateParams {
+	params := op.ItemCreateParams{
+		Category: op.ItemCategory(b.Category),
+		VaultID:  vaultID,
+		Title:    b.Title,
+		Tags:     b.Tags,
+	}
+	for _, s := range b.Sections {
+		params.Sections = append(params.Sections, op.ItemSection{ID: s.ID, Title: s.Title})
+	}
+	for _, f := range b.Fields {
+		field := op.ItemField{ID: f.ID, Title: f.Title, FieldType: op.ItemFieldType(f.Type), Value: f.Value}
+		if f.Section != "" {
+			section := f.Section
+			field.SectionID = &section
+		}
+		params.Fields = append(params.Fields, field)
+	}
+	for _, w := range b.Websites {
+		params.Websites = append(params.Websites, op.Website{
+			URL:              w.URL,
+			Label:            w.Label,
+			AutofillBehavior: op.AutofillBehavior(w.Autofill),
+		})
+	}
+	return params
+}
+
+// sealBundle builds a bundle file, encrypting the items if passphrase is set.
+func sealBundle(items []BundleItem, passphrase string) (*bundleFile, error) {
+	file := &bundleFile{Format: BundleFormat, Version: BundleVersion}
+	if passphrase == "" {
+		file.Items = items
+		return file, nil
+	}
+
+	plaintext, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := &bundleEncryption{
+		KDF:    "scrypt",
+		N:      bundleScryptN,
+		R:      bundleScryptR,
+		P:      bundleScryptP,
+		Salt:   make([]byte, 16),
+		Cipher: "aes-256-gcm",
+	}
+	if _, err := rand.Read(enc.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := bundleAEAD(passphrase, enc)
+	if err != nil {
+		return nil, err
+	}
+	enc.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(enc.Nonce); err != nil {
+		return nil, err
+	}
+
+	file.Encryption = enc
+	file.Ciphertext = aead.Seal(nil, enc.Nonce, plaintext, []byte(BundleFormat))
+	return file, nil
+}
+
+// openBundle validates a bundle file and returns its items, decrypting them
+// if needed.
+func openBundle(file *bundleFile, passphrase string) ([]BundleItem, error) {
+	if file.Format != BundleFormat {
+		return nil, fmt.Errorf("not a bundle: format %q", file.Format)
+	}
+	if file.Version != BundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", file.Version)
+	}
+	if file.Encryption == nil {
+		return file.Items, nil
+	}
+
+	enc := file.Encryption
+	if enc.KDF != "scrypt" || enc.Cipher != "aes-256-gcm" {
+		return nil, fmt.Errorf("unsupported bundle encryption %s/%s", enc.KDF, enc.Cipher)
+	}
+	if enc.N <= 1 || enc.R <= 0 || enc.P <= 0 || enc.N > bundleMaxScryptNR/enc.R || enc.P > bundleMaxScryptP {
+		return nil, fmt.Errorf("unsupported bundle scrypt parameters n=%d r=%d p=%d", enc.N, enc.R, enc.P)
+	}
+	if passphrase == "" {
+		return nil, ErrBundlePassphrase
+	}
+	aead, err := bundleAEAD(passphrase, enc)
+	if err != nil {
+		return nil, err
+	}
+	if len(enc.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid bundle nonce")
+	}
+	plaintext, err := aead.Open(nil, enc.Nonce, file.Ciphertext, []byte(BundleFormat))
+	if err != nil {
+		return nil, ErrBundlePassphrase
+	}
+
+	var items []BundleItem
+	if err := json.Unmarshal(plaintext, &items); err != nil {
+		return nil, fmt.Errorf("invalid bundle contents: %w", err)
+	}
+	return items, nil
+}
+
+// bundleAEAD derives the bundle key from passphrase and returns the cipher.
+func bundleAEAD(passphrase string, enc *bundleEncryption) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), enc.Salt, enc.N, enc.R, enc.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,115 @@
+package onepassword
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func TestItemToBundle_RoundTrip(t *testing.T) {
+	section := "sec1"
+	item := op.Item{
+		ID:       "item-id",
+		Title:    "Database",
+		Category: op.ItemCategoryLogin,
+		VaultID:  "vault-id",
+		Tags:     []string{"prod"},
+		Sections: []op.ItemSection{{ID: section, Title: "Connection"}},
+		Fields: []op.ItemField{
+			{ID: "username", Title: "username", FieldType: op.ItemFieldTypeText, Value: "admin"},
+			{ID: "host", Title: "host", FieldType: op.ItemFieldTypeText, Value: "db1", SectionID: &section},
+		},
+		Websites: []op.Website{{URL: "https://db.example.com", Label: "website", AutofillBehavior: op.AutofillBehaviorAnywhereOnWebsite}},
+	}
+
+	b := itemToBundle(item, "Prod")
+	if b.Vault != "Prod" || b.Title != "Database" || b.Category != string(op.ItemCategoryLogin) {
+		t.Errorf("itemToBundle() = %+v", b)
+	}
+	if b.Fields[1].Section != section {
+		t.Errorf("field section = %q, want %q", b.Fields[1].Section, section)
+	}
+
+	params := bundleToCreateParams(b, "other-vault")
+	if params.VaultID != "other-vault" || params.Title != item.Title || params.Category != item.Category {
+		t.Errorf("bundleToCreateParams() = %+v", params)
+	}
+	if !reflect.DeepEqual(params.Fields, item.Fields) {
+		t.Errorf("Fields = %+v, want %+v", params.Fields, item.Fields)
+	}
+	if !reflect.DeepEqual(params.Sections, item.Sections) {
+		t.Errorf("Sections = %+v, want %+v", params.Sections, item.Sections)
+	}
+	if !reflect.DeepEqual(params.Websites, item.Websites) {
+		t.Errorf("Websites = %+v, want %+v", params.Websites, item.Websites)
+	}
+	if !reflect.DeepEqual(params.Tags, item.Tags) {
+		t.Errorf("Tags = %v, want %v", params.Tags, item.Tags)
+	}
+}
+
+func TestSealBundle(t *testing.T) {
+	items := []BundleItem{{
+		Vault:    "Prod",
+		Title:    "API",
+		Category: string(op.ItemCategoryAPICredentials),
+		Fields:   []BundleField{{ID: "credential", Title: "credential", Type: string(op.ItemFieldTypeConcealed), Value: "s3cret"}},
+	}}
+
+	tests := []struct {
+		name       string
+		passphrase string
+		open       string
+		wantErr    error
+	}{
+		{"plaintext", "", "", nil},
+		{"encrypted", "correct horse", "correct horse", nil},
+		{"wrong passphrase", "correct horse", "battery staple", ErrBundlePassphrase},
+		{"missing passphrase", "correct horse", "", ErrBundlePassphrase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := sealBundle(items, tt.passphrase)
+			if err != nil {
+				t.Fatalf("sealBundle() error = %v", err)
+			}
+			if tt.passphrase != "" && (file.Items != nil || strings.Contains(string(file.Ciphertext), "s3cret")) {
+				t.Fatal("encrypted bundle exposes plaintext items")
+			}
+
+			got, err := openBundle(file, tt.open)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("openBundle() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, items) {
+				t.Errorf("openBundle() = %+v, want %+v", got, items)
+			}
+		})
+	}
+}
+
+func TestOpenBundle_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		file bundleFile
+	}{
+		{"wrong format", bundleFile{Format: "other", Version: BundleVersion}},
+		{"future version", bundleFile{Format: BundleFormat, Version: BundleVersion + 1}},
+		{"unknown cipher", bundleFile{Format: BundleFormat, Version: BundleVersion, Encryption: &bundleEncryption{KDF: "scrypt", Cipher: "rot13"}}},
+		{"scrypt memory", bundleFile{Format: BundleFormat, Version: BundleVersion, Encryption: &bundleEncryption{KDF: "scrypt", N: 1 << 30, R: 8, P: 1, Cipher: "aes-256-gcm"}}},
+		{"scrypt parallelism", bundleFile{Format: BundleFormat, Version: BundleVersion, Encryption: &bundleEncryption{KDF: "scrypt", N: 2, R: 8, P: 1 << 20, Cipher: "aes-256-gcm"}}},
+		{"missing scrypt parameters", bundleFile{Format: BundleFormat, Version: BundleVersion, Encryption: &bundleEncryption{KDF: "scrypt", Cipher: "aes-256-gcm"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := openBundle(&tt.file, "pass"); err == nil {
+				t.Error("openBundle() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// DefaultNegativeCacheTTL is how long a vault name that wasn't found is
+// remembered before the vaults are listed again.
+const DefaultNegativeCacheTTL = 30 * time.Second
+
+// vaultCache maps vault names and IDs to vault IDs. Names that weren't
+// found are remembered for a short time, and concurrent misses share one
+// vault listing instead of each listing the vaults.
+type vaultCache struct {
+	ttl         time.Duration // zero caches forever, negative disables
+	negativeTTL time.Duration // negative disables
+	now         func() time.Time
+
+	mu      sync.Mutex
+	ids     map[string]string
+	expires time.Time            // when ids must be refreshed; zero for never
+	misses  map[string]time.Time // name -> when the miss expires
+	loading *vaultLoad
+}
+
+// vaultLoad is a vault listing in progress, shared by all callers that
+// miss the cache meanwhile.
+type vaultLoad struct {
+	done chan struct{}
+	ids  map[string]string
+	err  error
+}
+
+func newVaultCache(ttl, negativeTTL time.Duration) *vaultCache {
+	return &vaultCache{
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+		ids:         make(map[string]string),
+		misses:      make(map[string]time.Time),
+	}
+}
+
+// get looks up a vault name or ID. found reports a cached ID; missing
+// reports a recent miss.
+func (c *vaultCache) get(nameOrID string) (id string, found, missing bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if !c.expires.IsZero() && !now.Before(c.expires) {
+		c.ids = make(map[string]string)
+		c.expires = time.Time{}
+	}
+	if id, ok := c.ids[nameOrID]; ok {
+		return id, true, false
+	}
+	if until, ok := c.misses[nameOrID]; ok {
+		if now.Before(until) {
+			return "", false, true
+		}
+		delete(c.misses, nameOrID)
+	}
+	return "", false, false
+}
+
+// add caches a vault found outside a full listing, such as by List.
+func (c *vaultCache) add(title, id string) {
+	if c.ttl < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[title] = id
+	c.ids[id] = id
+	delete(c.misses, title)
+}
+
+// miss records that nameOrID matched no vault.
+func (c *vaultCache) miss(nameOrID string) {
+	if c.negativeTTL < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.misses[nameOrID] = c.now().Add(c.negativeTTL)
+}
+
+// load lists the vaults with list, or waits for a listing already in
+// progress, and replaces the cached IDs with the result.
+func (c *vaultCache) load(ctx context.Context, list func(context.Context) (map[string]string, error)) (map[string]string, error) {
+	for {
+		c.mu.Lock()
+		l := c.loading
+		if l == nil {
+			break
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-l.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// A listing cut short by its caller's context says nothing
+		// about ours; start another
+		if l.err == nil || !isContextError(l.err) {
+			return l.ids, l.err
+		}
+	}
+
+	l := &vaultLoad{done: make(chan struct{})}
+	c.loading = l
+	c.mu.Unlock()
+
+	l.ids, l.err = list(ctx)
+
+	c.mu.Lock()
+	c.loading = nil
+	if l.err == nil && c.ttl >= 0 {
+		c.ids = maps.Clone(l.ids)
+		c.misses = make(map[string]time.Time)
+		c.expires = time.Time{}
+		if c.ttl > 0 {
+			c.expires = c.now().Add(c.ttl)
+		}
+	}
+	c.mu.Unlock()
+	close(l.done)
+
+	return l.ids, l.err
+}
+
+// listVaultIDs lists all vaults, mapping each title and ID to the ID. A
+// title shared by several vaults maps to the first listed.
+func (p *Provider) listVaultIDs(ctx context.Context) (map[string]string, error) {
+	vaultsIter, err := p.vaults.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]string)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		v, err := vaultsIter.Next()
+		if err == op.ErrorIteratorDone {
+			return ids, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := ids[v.Title]; !ok {
+			ids[v.Title] = v.ID
+		}
+		ids[v.ID] = v.ID
+	}
+}
+
+// cachedItems caches item listings per vault for Config.CacheTTL, so
+// repeated title lookups in a vault don't each list its items. Writes
+// through it drop the listing of the vault written to; changes made
+// elsewhere show up once the listing expires.
+type cachedItems struct {
+	itemsAPI
+	cache *itemCache
+}
+
+// itemCache holds item listings by vault ID.
+type itemCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	listings map[string]itemListing
+}
+
+// itemListing is a cached item listing of one vault.
+type itemListing struct {
+	items   []op.ItemOverview
+	expires time.Time
+}
+
+func newItemCache(ttl time.Duration) *itemCache {
+	return &itemCache{ttl: ttl, now: time.Now, listings: make(map[string]itemListing)}
+}
+
+// get returns the cached listing of a vault, if it hasn't expired.
+func (c *itemCache) get(vaultID string) ([]op.ItemOverview, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	listing, ok := c.listings[vaultID]
+	if !ok || !c.now().Before(listing.expires) {
+		return nil, false
+	}
+	return listing.items, true
+}
+
+// put caches the listing of a vault.
+func (c *itemCache) put(vaultID string, items []op.ItemOverview) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listings[vaultID] = itemListing{items: items, expires: c.now().Add(c.ttl)}
+}
+
+// invalidate drops the listing of a vault.
+func (c *itemCache) invalidate(vaultID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.listings, vaultID)
+}
+
+func (c cachedItems) Create(ctx context.Context, params op.ItemCreateParams) (op.Item, error) {
+	defer c.cache.invalidate(params.VaultID)
+	return c.itemsAPI.Create(ctx, params)
+}
+
+func (c cachedItems) Get(ctx context.Context, vaultID, itemID string) (op.Item, error) {
+	item, err := c.itemsAPI.Get(ctx, vaultID, itemID)
+	if isNotFoundError(err) {
+		// The item was deleted since the vault was listed
+		c.cache.invalidate(vaultID)
+	}
+	return item, err
+}
+
+func (c cachedItems) Put(ctx context.Context, item op.Item) (op.Item, error) {
+	defer c.cache.invalidate(item.VaultID)
+	return c.itemsAPI.Put(ctx, item)
+}
+
+func (c cachedItems) Delete(ctx context.Context, vaultID, itemID string) error {
+	defer c.cache.invalidate(vaultID)
+	return c.itemsAPI.Delete(ctx, vaultID, itemID)
+}
+
+func (c cachedItems) ListAll(ctx context.Context, vaultID string) (*op.Iterator[op.ItemOverview], error) {
+	if !cacheBypassed(ctx) {
+		if items, ok := c.cache.get(vaultID); ok {
+			return op.NewIterator(items), nil
+		}
+	}
+
+	iter, err := c.itemsAPI.ListAll(ctx, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	var items []op.ItemOverview
+	for {
+		item, err := iter.Next()
+		if err == op.ErrorIteratorDone {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+
+	c.cache.put(vaultID, items)
+	return op.NewIterator(items), nil
+}
+
+// WarmCache lists the vaults, and the items of each vault matching one of
+// the path prefixes, so the listing cost is paid up front (e.g. at startup)
+// rather than by the first requests. With no prefixes, every vault is
+// warmed. Vault IDs are always cached; item listings only when
+// Config.CacheTTL is positive, and only for that long.
+//
+//	err := provider.WarmCache(ctx, "Production/", "Shared/API")
+//
+// All vaults are attempted; failures are reported together.
+func (p *Provider) WarmCache(ctx context.Context, prefixes ...string) error {
+	if p.closed.Load() {
+		return vault.NewVaultError("WarmCache", "", ProviderName, vault.ErrClosed)
+	}
+
+	vaultsIter, err := p.vaults.ListAll(ctx)
+	if err != nil {
+		return mapError("WarmCache", "", err)
+	}
+
+	var errs []error
+	for {
+		if err := ctx.Err(); err != nil {
+			return vault.NewVaultError("WarmCache", "", ProviderName, err)
+		}
+
+		v, err := vaultsIter.Next()
+		if err == op.ErrorIteratorDone {
+			break
+		}
+		if err != nil {
+			return mapError("WarmCache", "", err)
+		}
+
+		p.cacheVaultID(v.Title, v.ID)
+		if !warmsVault(BuildPath(v.Title), prefixes) || p.config.CacheTTL <= 0 {
+			continue
+		}
+		if _, err := p.items.ListAll(ctx, v.ID); err != nil {
+			errs = append(errs, mapError("WarmCache", BuildPath(v.Title), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// warmsVault reports whether the vault path could hold paths starting with
+// one of the prefixes.
+func warmsVault(vaultPath string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(vaultPath+"/", prefix) || strings.HasPrefix(prefix, vaultPath+"/") {
+			return true
+		}
+	}
+	return false
+}
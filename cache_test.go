@@ -0,0 +1,227 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestResolveVaultID_NegativeCache(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+	now := time.Now()
+	p.vaultCache.now = func() time.Time { return now }
+
+	for range 3 {
+		if _, err := p.resolveVaultID(ctx, "Missing"); !errors.Is(err, ErrVaultNotFound) {
+			t.Fatalf("resolveVaultID() error = %v, want ErrVaultNotFound", err)
+		}
+	}
+	if m.vaultLists != 1 {
+		t.Errorf("listed vaults %d times, want 1", m.vaultLists)
+	}
+
+	// The miss expires and the vault has since been created
+	m.vaults = append(m.vaults, op.VaultOverview{ID: "v3", Title: "Missing"})
+	now = now.Add(DefaultNegativeCacheTTL)
+	if id, err := p.resolveVaultID(ctx, "Missing"); err != nil || id != "v3" {
+		t.Errorf("resolveVaultID() = %q, %v; want v3", id, err)
+	}
+
+	// Bypassing the cache ignores recorded misses
+	p.vaultCache.miss("Work")
+	if _, err := p.resolveVaultID(withoutCache(ctx), "Work"); err != nil {
+		t.Errorf("resolveVaultID(no cache) error = %v", err)
+	}
+}
+
+func TestResolveVaultID_Refresh(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{CacheTTL: time.Minute})
+	now := time.Now()
+	p.vaultCache.now = func() time.Time { return now }
+
+	if id, _ := p.resolveVaultID(ctx, "Private"); id != "v1" {
+		t.Fatalf("resolveVaultID() = %q, want v1", id)
+	}
+	m.vaults = []op.VaultOverview{{ID: "v9", Title: "Private"}}
+
+	if id, _ := p.resolveVaultID(ctx, "Private"); id != "v1" {
+		t.Errorf("resolveVaultID() before TTL = %q, want cached v1", id)
+	}
+	now = now.Add(time.Minute)
+	if id, _ := p.resolveVaultID(ctx, "Private"); id != "v9" {
+		t.Errorf("resolveVaultID() after TTL = %q, want refreshed v9", id)
+	}
+	if m.vaultLists != 2 {
+		t.Errorf("listed vaults %d times, want 2", m.vaultLists)
+	}
+}
+
+func TestResolveVaultID_Disabled(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{CacheTTL: -1, NegativeCacheTTL: -1})
+
+	for range 2 {
+		if _, err := p.resolveVaultID(ctx, "Private"); err != nil {
+			t.Fatalf("resolveVaultID() error = %v", err)
+		}
+		_, _ = p.resolveVaultID(ctx, "Missing")
+	}
+	if m.vaultLists != 4 {
+		t.Errorf("listed vaults %d times, want 4", m.vaultLists)
+	}
+}
+
+func TestVaultCache_SingleFlight(t *testing.T) {
+	c := newVaultCache(0, DefaultNegativeCacheTTL)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var lists atomic.Int32
+	list := func(context.Context) (map[string]string, error) {
+		if lists.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return map[string]string{"Private": "v1", "v1": "v1"}, nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	load := func() {
+		defer wg.Done()
+		ids, err := c.load(context.Background(), list)
+		if err == nil && ids["Private"] != "v1" {
+			err = errors.New("missing vault")
+		}
+		errs <- err
+	}
+
+	wg.Add(1)
+	go load()
+	<-started
+	for range 9 {
+		wg.Add(1)
+		go load()
+	}
+
+	// Give the other callers time to join the listing in progress
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("load() error = %v", err)
+		}
+	}
+	if n := lists.Load(); n != 1 {
+		t.Errorf("listed vaults %d times, want 1", n)
+	}
+}
+
+func TestVaultCache_LeaderCancelled(t *testing.T) {
+	c := newVaultCache(0, DefaultNegativeCacheTTL)
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+
+	go func() {
+		_, _ = c.load(ctx, func(ctx context.Context) (map[string]string, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+	}()
+	<-started
+
+	done := make(chan error)
+	go func() {
+		_, err := c.load(context.Background(), func(context.Context) (map[string]string, error) {
+			return map[string]string{"v1": "v1"}, nil
+		})
+		done <- err
+	}()
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("load() after cancelled listing error = %v, want a fresh listing", err)
+	}
+}
+
+func TestWarmCache(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{CacheTTL: time.Minute})
+
+	if err := p.WarmCache(ctx, "Private/Data"); err != nil {
+		t.Fatalf("WarmCache() error = %v", err)
+	}
+	if m.vaultLists != 1 || m.itemLists != 1 {
+		t.Errorf("listed vaults %d and items %d times, want 1 and 1", m.vaultLists, m.itemLists)
+	}
+
+	// Reads are served from the warmed listings
+	if _, err := p.Get(ctx, "Private/Database"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if m.vaultLists != 1 || m.itemLists != 1 {
+		t.Errorf("Get() listed again: vaults %d, items %d", m.vaultLists, m.itemLists)
+	}
+
+	// A write drops the vault's listing
+	if err := p.Set(ctx, "Private/New", &vault.Secret{Value: "x"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := p.Exists(ctx, "Private/Database"); err != nil {
+		t.Fatalf("Exists() error = %v", err)
+	}
+	if m.itemLists != 2 {
+		t.Errorf("listed items %d times, want 2 (warm, relist after Set)", m.itemLists)
+	}
+}
+
+func TestWarmCache_WithoutItemCache(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	if err := p.WarmCache(context.Background()); err != nil {
+		t.Fatalf("WarmCache() error = %v", err)
+	}
+	if m.itemLists != 0 {
+		t.Errorf("listed items %d times, want 0 without CacheTTL", m.itemLists)
+	}
+	if id, _, _ := p.vaultCache.get("Work"); id != "v2" {
+		t.Errorf("vault cache has Work = %q, want v2", id)
+	}
+}
+
+func TestWarmsVault(t *testing.T) {
+	tests := []struct {
+		vault    string
+		prefixes []string
+		want     bool
+	}{
+		{"Private", nil, true},
+		{"Private", []string{"Private/"}, true},
+		{"Private", []string{"Private/Data"}, true},
+		{"Private", []string{"Pri"}, true},
+		{"Private", []string{"Work/", "Private"}, true},
+		{"Private", []string{"Work/"}, false},
+		{"Private", []string{"PrivateX/"}, false},
+	}
+	for _, tt := range tests {
+		if got := warmsVault(tt.vault, tt.prefixes); got != tt.want {
+			t.Errorf("warmsVault(%q, %q) = %v, want %v", tt.vault, tt.prefixes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package onepassword
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// CategoryKey is the Secret.Metadata.Extra key holding the category of a new
+// item, e.g. "Login" or "API Credentials". Get fills it in, so a secret read
+// from one item keeps its category when written to another.
+const CategoryKey = "category"
+
+// itemCategory picks the category for a new item: opts.Category, then
+// secret.Metadata.Extra[CategoryKey], then a category inferred from the
+// field names, then Config.DefaultCategory.
+func (p *Provider) itemCategory(secret *vault.Secret, opts SetOptions) (op.ItemCategory, error) {
+	category, err := explicitCategory(secret, opts.Category)
+	if category != "" || err != nil {
+		return category, err
+	}
+
+	if !p.config.DisableCategoryInference {
+		if category, ok := inferCategory(secret.Fields); ok {
+			return category, nil
+		}
+	}
+	return p.config.DefaultCategory, nil
+}
+
+// explicitCategory returns the category set by override or by
+// Secret.Metadata.Extra[CategoryKey], or "" if neither sets one.
+func explicitCategory(secret *vault.Secret, override op.ItemCategory) (op.ItemCategory, error) {
+	if override != "" {
+		return override, nil
+	}
+
+	var name string
+	switch extra := secret.Metadata.Extra[CategoryKey].(type) {
+	case nil:
+	case op.ItemCategory:
+		name = string(extra)
+	case string:
+		name = extra
+	default:
+		return "", fmt.Errorf("%s must be a string, got %T", CategoryKey, extra)
+	}
+	// Unsupported is what Get reports for categories the SDK doesn't know;
+	// it can't be used to create an item
+	if name != "" && !strings.EqualFold(name, string(op.ItemCategoryUnsupported)) {
+		return parseItemCategory(name)
+	}
+	return "", nil
+}
+
+// fieldNameNormalizer strips the separators that field name matching
+// ignores, along with case.
+var fieldNameNormalizer = strings.NewReplacer(" ", "", "_", "", "-", "")
+
+// normalizeFieldName returns name in the form field name matching uses.
+func normalizeFieldName(name string) string {
+	return strings.ToLower(fieldNameNormalizer.Replace(name))
+}
+
+// inferCategory guesses an item category from field names: a username and
+// password make a Login, and a private key an SSH Key.
+func inferCategory(fields map[string]string) (op.ItemCategory, bool) {
+	has := make(map[string]bool, len(fields))
+	for name := range fields {
+		has[normalizeFieldName(name)] = true
+	}
+
+	switch {
+	case has["privatekey"]:
+		return op.ItemCategorySSHKey, true
+	case has["username"] && has["password"]:
+		return op.ItemCategoryLogin, true
+	default:
+		return "", false
+	}
+}
+
+// builtinField is a field that items of a category have built in, which the
+// 1Password apps show in place rather than as a custom field.
+type builtinField struct {
+	id, title string
+	// key names the field in Secret.Fields
+	key       string
+	fieldType op.ItemFieldType
+}
+
+// builtinFields lists the built-in fields of each category that writes map
+// to. The username and password of Login items are what browsers and the
+// 1Password CLI fill in. The SDK has no date or menu field types, so the
+// dates and type of API Credentials are written as text.
+var builtinFields = map[op.ItemCategory][]builtinField{
+	op.ItemCategoryLogin: {
+		{id: "username", title: "username", key: "username", fieldType: op.ItemFieldTypeText},
+		{id: "password", title: "password", key: "password", fieldType: op.ItemFieldTypeConcealed},
+	},
+	op.ItemCategoryAPICredentials: {
+		{id: "username", title: "username", key: "username", fieldType: op.ItemFieldTypeText},
+		{id: "credential", title: "credential", key: "credential", fieldType: op.ItemFieldTypeConcealed},
+		{id: "type", title: "type", key: "type", fieldType: op.ItemFieldTypeText},
+		{id: "filename", title: "filename", key: "filename", fieldType: op.ItemFieldTypeText},
+		{id: "validFrom", title: "valid from", key: "valid_from", fieldType: op.ItemFieldTypeText},
+		{id: "expires", title: "expires", key: "expires", fieldType: op.ItemFieldTypeText},
+		{id: "hostname", title: "hostname", key: "hostname", fieldType: op.ItemFieldTypeText},
+	},
+}
+
+// findBuiltinField returns the built-in field of category named name,
+// matched as inferCategory matches names.
+func findBuiltinField(category op.ItemCategory, name string) (builtinField, bool) {
+	name = normalizeFieldName(name)
+	for _, b := range builtinFields[category] {
+		if name == normalizeFieldName(b.key) {
+			return b, true
+		}
+	}
+	return builtinField{}, false
+}
+
+// useBuiltinFields turns the top-level fields named as a built-in field of
+// category into that field, with its ID, title, and type.
+func useBuiltinFields(category op.ItemCategory, fields []op.ItemField) {
+	for i := range fields {
+		if fields[i].SectionID != nil {
+			continue
+		}
+		if b, ok := findBuiltinField(category, fields[i].Title); ok {
+			fields[i].ID, fields[i].Title, fields[i].FieldType = b.id, b.title, b.fieldType
+		}
+	}
+}
+
+// isBuiltinField reports whether field is a built-in field of category.
+func isBuiltinField(category op.ItemCategory, field op.ItemField) bool {
+	if field.SectionID != nil {
+		return false
+	}
+	for _, b := range builtinFields[category] {
+		if field.ID == b.id {
+			return true
+		}
+	}
+	return false
+}
+
+// withBuiltinKeys returns item with its built-in fields titled by their
+// Secret.Fields key, so they read back under the same names whatever the
+// 1Password apps title them.
+func withBuiltinKeys(item op.Item) op.Item {
+	builtins := builtinFields[item.Category]
+	if len(builtins) == 0 {
+		return item
+	}
+	item.Fields = slices.Clone(item.Fields)
+	for i, field := range item.Fields {
+		if field.SectionID != nil {
+			continue
+		}
+		for _, b := range builtins {
+			if field.ID == b.id {
+				item.Fields[i].Title = b.key
+			}
+		}
+	}
+	return item
+}
@@ -0,0 +1,165 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestItemCategory(t *testing.T) {
+	login := map[string]string{"Username": "admin", "password": "x"}
+
+	tests := []struct {
+		name    string
+		fields  map[string]string
+		extra   any
+		opts    SetOptions
+		disable bool
+		want    op.ItemCategory
+		wantErr bool
+	}{
+		{"default", map[string]string{"password": "x"}, nil, SetOptions{}, false, CategoryDatabase, false},
+		{"login inferred", login, nil, SetOptions{}, false, CategoryLogin, false},
+		{"ssh key inferred", map[string]string{"private key": "-----BEGIN"}, nil, SetOptions{}, false, CategorySSHKey, false},
+		{"inference disabled", login, nil, SetOptions{}, true, CategoryDatabase, false},
+		{"metadata", login, "API Credentials", SetOptions{}, false, CategoryAPICredentials, false},
+		{"metadata typed", login, CategoryServer, SetOptions{}, false, CategoryServer, false},
+		{"unsupported metadata ignored", login, "Unsupported", SetOptions{}, false, CategoryLogin, false},
+		{"options win", login, "Server", SetOptions{Category: CategoryPassword}, false, CategoryPassword, false},
+		{"unknown metadata", nil, "Spaceship", SetOptions{}, false, "", true},
+		{"non-string metadata", nil, 42, SetOptions{}, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Provider{config: Config{DefaultCategory: CategoryDatabase, DisableCategoryInference: tt.disable}}
+			secret := &vault.Secret{Fields: tt.fields}
+			if tt.extra != nil {
+				secret.Metadata.Extra = map[string]any{CategoryKey: tt.extra}
+			}
+
+			got, err := p.itemCategory(secret, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("itemCategory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("itemCategory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetWithOptions_Category(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	err := p.SetWithOptions(context.Background(), "Private/Stripe", &vault.Secret{
+		Fields: map[string]string{"credential": "sk_live"},
+	}, SetOptions{Category: CategoryAPICredentials})
+	if err != nil {
+		t.Fatalf("SetWithOptions() error = %v", err)
+	}
+
+	if len(m.created) != 1 || m.created[0].Category != CategoryAPICredentials {
+		t.Fatalf("Created %+v, want one API Credentials item", m.created)
+	}
+
+	// Category only applies to new items
+	err = p.SetWithOptions(context.Background(), "Private/Database", &vault.Secret{
+		Fields: map[string]string{"host": "db1"},
+	}, SetOptions{Category: CategoryAPICredentials})
+	if err != nil {
+		t.Fatalf("SetWithOptions() error = %v", err)
+	}
+	if len(m.created) != 1 {
+		t.Errorf("Expected existing item to be updated, created %+v", m.created)
+	}
+}
+
+func TestSetWithOptions_LoginFields(t *testing.T) {
+	ctx := context.Background()
+
+	m := testMockAPI()
+	p := newMockProvider(m, Config{DefaultSection: "omnivault"})
+	err := p.Set(ctx, "Private/GitHub", &vault.Secret{
+		Fields: map[string]string{"Username": "octocat", "Password": "x", "token": "t"},
+	})
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	created := m.created[0]
+	if created.Category != op.ItemCategoryLogin {
+		t.Fatalf("created category = %s, want Login", created.Category)
+	}
+	for _, field := range created.Fields {
+		switch field.Title {
+		case "username", "password":
+			if field.ID != field.Title || field.SectionID != nil {
+				t.Errorf("field %q has ID %q in section %v, want the built-in field", field.Title, field.ID, field.SectionID)
+			}
+		case "token":
+			if field.SectionID == nil {
+				t.Errorf("custom field %q not in the default section", field.Title)
+			}
+		default:
+			t.Errorf("unexpected field %+v", field)
+		}
+		if field.ID == "password" && field.FieldType != op.ItemFieldTypeConcealed {
+			t.Errorf("password field type = %s, want Concealed", field.FieldType)
+		}
+	}
+
+	// Updates to an existing Login item set its built-in fields
+	m = testMockAPI()
+	m.items["v1"][0].Category = op.ItemCategoryLogin
+	p = newMockProvider(m, Config{})
+	if err := p.Set(ctx, "Private/Database", &vault.Secret{Fields: map[string]string{"User Name": "root"}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	fields := m.put[0].Fields
+	if len(fields) != 2 || fields[0].ID != "username" || fields[0].Value != "root" {
+		t.Errorf("updated fields = %+v, want the username field set", fields)
+	}
+}
+
+func TestAPICredentialsFields(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+	err := p.SetWithOptions(t.Context(), "Private/Stripe", &vault.Secret{
+		Fields: map[string]string{"Credential": "sk_live", "valid_from": "2026-01-01", "Hostname": "api.stripe.com", "region": "eu"},
+	}, SetOptions{Category: CategoryAPICredentials})
+	if err != nil {
+		t.Fatalf("SetWithOptions() error = %v", err)
+	}
+
+	want := map[string]op.ItemField{
+		"credential": {ID: "credential", Title: "credential", FieldType: op.ItemFieldTypeConcealed},
+		"validFrom":  {ID: "validFrom", Title: "valid from", FieldType: op.ItemFieldTypeText},
+		"hostname":   {ID: "hostname", Title: "hostname", FieldType: op.ItemFieldTypeText},
+		"region":     {ID: "region", Title: "region", FieldType: op.ItemFieldTypeText},
+	}
+	for _, field := range m.created[0].Fields {
+		w, ok := want[field.ID]
+		if !ok || field.Title != w.Title || field.FieldType != w.FieldType {
+			t.Errorf("created field %+v, want %+v", field, w)
+		}
+	}
+
+	// Built-in fields read back under their keys, whatever their titles
+	m.items["v1"] = append(m.items["v1"], op.Item{
+		ID: "i3", Title: "Native", VaultID: "v1", Category: op.ItemCategoryAPICredentials,
+		Fields: []op.ItemField{
+			{ID: "credential", Title: "Credential", Value: "k", FieldType: op.ItemFieldTypeConcealed},
+			{ID: "validFrom", Title: "valid from", Value: "2026-01-01"},
+		},
+	})
+	secret, err := p.Get(t.Context(), "Private/Native")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if secret.Fields["credential"] != "k" || secret.Fields["valid_from"] != "2026-01-01" {
+		t.Errorf("Get() fields = %v, want credential and valid_from", secret.Fields)
+	}
+}
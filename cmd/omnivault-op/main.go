@@ -0,0 +1,460 @@
+// Command omnivault-op reads and writes 1Password secrets through the
+// omnivault-onepassword provider.
+//
+// Usage:
+//
+//	omnivault-op [-vault NAME] [-strict] <command> [arguments]
+//
+// Commands:
+//
+//	get [-json] PATH                    print a secret (all fields with -json)
+//	set [-replace] [-field K=V]... PATH [VALUE]
+//	                                    write a secret; VALUE defaults to stdin
+//	list [PREFIX]                       list item paths
+//	delete PATH                         delete an item (PATH must not name a field)
+//	inject [-i FILE] [-o FILE]          render op:// references in a template
+//	export [-format dotenv|k8s] [-name NAME] [-namespace NS] PATH
+//	                                    write an item as a .env file or a
+//	                                    Kubernetes Secret (-name required)
+//	agent [-socket PATH] [-cache-ttl DURATION] [-allow-uid UID]...
+//	                                    serve secrets to local processes
+//	serve [-addr ADDR] [-grpc-addr ADDR] [-no-rest] [-tls-cert FILE -tls-key FILE]
+//	      [-client-ca FILE] [-read-only]
+//	                                    serve the REST and gRPC APIs as a
+//	                                    sidecar; the bearer token is read
+//	                                    from OMNIVAULT_OP_SERVER_TOKEN
+//
+// Authentication uses OP_SERVICE_ACCOUNT_TOKEN. The default vault is read
+// from -vault or OP_VAULT_NAME. Paths use the same syntax as the package.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	op "github.com/agentplexus/omnivault-onepassword"
+	"github.com/agentplexus/omnivault-onepassword/agent"
+	"github.com/agentplexus/omnivault-onepassword/server"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// EnvVaultName is the environment variable holding the default vault.
+const EnvVaultName = "OP_VAULT_NAME"
+
+// errUsage reports a command line error; usage has already been printed.
+var errUsage = errors.New("usage error")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "omnivault-op:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run executes the command line args.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("omnivault-op", flag.ContinueOnError)
+	vaultName := fs.String("vault", os.Getenv(EnvVaultName), "default vault `name`")
+	strict := fs.Bool("strict", false, "require fully qualified paths")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: omnivault-op [-vault NAME] [-strict] get|set|list|delete|inject|export|agent|serve [arguments]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	commands := map[string]func(context.Context, *cli, []string) error{
+		"get":    cmdGet,
+		"set":    cmdSet,
+		"list":   cmdList,
+		"delete": cmdDelete,
+		"inject": cmdInject,
+		"export": cmdExport,
+		"agent":  cmdAgent,
+		"serve":  cmdServe,
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(fs.Output(), "unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return errUsage
+	}
+
+	c := &cli{
+		stdin:  stdin,
+		stdout: stdout,
+		config: op.Config{
+			DefaultVaultName: *vaultName,
+			StrictPaths:      *strict,
+			IntegrationName:  "omnivault-op",
+		},
+	}
+	defer c.close()
+
+	return cmd(ctx, c, fs.Args()[1:])
+}
+
+// cli holds the state shared by subcommands.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	config op.Config
+
+	p *op.Provider
+}
+
+// provider returns the provider, creating it on first use so that usage
+// errors are reported without credentials.
+func (c *cli) provider() (*op.Provider, error) {
+	if c.p == nil {
+		p, err := op.New(c.config)
+		if err != nil {
+			return nil, err
+		}
+		c.p = p
+	}
+	return c.p, nil
+}
+
+// parsePath parses a path as the provider will.
+func (c *cli) parsePath(path string) (*op.ParsedPath, error) {
+	if c.config.StrictPaths {
+		return op.ParsePathStrict(path)
+	}
+	return op.ParsePath(path, c.config.DefaultVaultName)
+}
+
+// close closes the provider if it was created.
+func (c *cli) close() {
+	if c.p != nil {
+		c.p.Close()
+	}
+}
+
+// parseArgs parses a subcommand's flags and checks its positional argument count.
+func parseArgs(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() < minArgs || fs.NArg() > maxArgs {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// newFlagSet returns a flag set for a subcommand with the given usage line.
+func newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: omnivault-op %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func cmdGet(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("get", "[-json] PATH")
+	asJSON := fs.Bool("json", false, "print all fields as a JSON object")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	p, err := c.provider()
+	if err != nil {
+		return err
+	}
+
+	secret, err := p.Get(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(c.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(secret.Fields)
+	}
+	_, err = fmt.Fprintln(c.stdout, secret.Value)
+	return err
+}
+
+func cmdSet(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("set", "[-replace] [-field K=V]... PATH [VALUE]")
+	replace := fs.Bool("replace", false, "replace all fields instead of merging")
+	fields := fieldFlag{}
+	fs.Var(fields, "field", "set field `K=V` (repeatable)")
+	if err := parseArgs(fs, args, 1, 2); err != nil {
+		return err
+	}
+	p, err := c.provider()
+	if err != nil {
+		return err
+	}
+
+	secret := &vault.Secret{Fields: fields}
+	switch {
+	case fs.NArg() == 2:
+		secret.Value = fs.Arg(1)
+	case len(fields) == 0:
+		data, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return err
+		}
+		secret.Value = strings.TrimSuffix(string(data), "\n")
+	}
+
+	opts := op.SetOptions{}
+	if *replace {
+		opts.Mode = op.WriteModeReplace
+	}
+	return p.SetWithOptions(ctx, fs.Arg(0), secret, opts)
+}
+
+func cmdList(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("list", "[PREFIX]")
+	if err := parseArgs(fs, args, 0, 1); err != nil {
+		return err
+	}
+	p, err := c.provider()
+	if err != nil {
+		return err
+	}
+
+	paths, err := p.List(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if _, err := fmt.Fprintln(c.stdout, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cmdDelete(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("delete", "PATH")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	parsed, err := c.parsePath(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	// Delete removes whole items, so a field path would lose the other
+	// fields too
+	if parsed.Field != "" {
+		fmt.Fprintf(fs.Output(), "%s is a field; delete removes whole items, given as VAULT/ITEM\n", fs.Arg(0))
+		fs.Usage()
+		return errUsage
+	}
+	p, err := c.provider()
+	if err != nil {
+		return err
+	}
+	return p.Delete(ctx, fs.Arg(0))
+}
+
+func cmdInject(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("inject", "[-i FILE] [-o FILE]")
+	in := fs.String("i", "", "template `file` (default stdin)")
+	out := fs.String("o", "", "output `file` (default stdout)")
+	if err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	p, err := c.provider()
+	if err != nil {
+		return err
+	}
+
+	r := c.stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	if *out == "" {
+		return p.Inject(ctx, r, c.stdout)
+	}
+
+	// Render fully before creating the file so failures don't truncate it
+	var buf strings.Builder
+	if err := p.Inject(ctx, r, &buf); err != nil {
+		return err
+	}
+	return os.WriteFile(*out, []byte(buf.String()), 0o600)
+}
+
+func cmdExport(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("export", "[-format dotenv|k8s] [-name NAME] [-namespace NS] PATH")
+	format := fs.String("format", "dotenv", "output `format`: dotenv or k8s")
+	name := fs.String("name", "", "Kubernetes Secret `name` (required for k8s)")
+	namespace := fs.String("namespace", "", "Kubernetes `namespace`")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	switch {
+	case *format != "dotenv" && *format != "k8s":
+		fmt.Fprintf(fs.Output(), "unknown format %q\n", *format)
+		fs.Usage()
+		return errUsage
+	case *format == "k8s" && *name == "":
+		fmt.Fprintln(fs.Output(), "-name is required with -format k8s")
+		fs.Usage()
+		return errUsage
+	}
+	p, err := c.provider()
+	if err != nil {
+		return err
+	}
+
+	if *format == "dotenv" {
+		return p.ExportDotenv(ctx, fs.Arg(0), c.stdout)
+	}
+	manifest, err := p.ExportK8sSecret(ctx, fs.Arg(0), *name, *namespace)
+	if err != nil {
+		return err
+	}
+	_, err = c.stdout.Write(manifest)
+	return err
+}
+
+func cmdAgent(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("agent", "[-socket PATH] [-cache-ttl DURATION] [-allow-uid UID]...")
+	socket := fs.String("socket", agent.DefaultSocketPath(), "socket `path`")
+	cacheTTL := fs.Duration("cache-ttl", agent.DefaultCacheTTL, "how long to cache secrets (negative disables)")
+	var uids uidFlag
+	fs.Var(&uids, "allow-uid", "allow connections from user `ID` (repeatable; default: current user)")
+	if err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	p, err := c.provider()
+	if err != nil {
+		return err
+	}
+
+	server := agent.NewServer(p, agent.Config{
+		SocketPath:  *socket,
+		AllowedUIDs: uids,
+		CacheTTL:    *cacheTTL,
+		Logger:      slog.New(slog.NewTextHandler(os.Stderr, nil)),
+	})
+	return server.ListenAndServe(ctx)
+}
+
+func cmdServe(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("serve", "[-addr ADDR] [-grpc-addr ADDR] [-no-rest] [-tls-cert FILE -tls-key FILE] [-client-ca FILE] [-read-only]")
+	addr := fs.String("addr", "127.0.0.1:8200", "REST API listen `address`")
+	grpcAddr := fs.String("grpc-addr", "", "gRPC API listen `address` (default: gRPC disabled)")
+	noREST := fs.Bool("no-rest", false, "serve only the gRPC API")
+	certFile := fs.String("tls-cert", "", "TLS certificate `file`")
+	keyFile := fs.String("tls-key", "", "TLS private key `file`")
+	caFile := fs.String("client-ca", "", "require client certificates signed by the CA in `file`")
+	readOnly := fs.Bool("read-only", false, "reject writes and deletes")
+	if err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if (*certFile == "") != (*keyFile == "") || (*caFile != "" && *certFile == "") {
+		fmt.Fprintln(fs.Output(), "-tls-cert and -tls-key must be set together, and -client-ca requires them")
+		fs.Usage()
+		return errUsage
+	}
+	if *noREST && *grpcAddr == "" {
+		fmt.Fprintln(fs.Output(), "-no-rest requires -grpc-addr")
+		fs.Usage()
+		return errUsage
+	}
+
+	var tlsConfig *tls.Config
+	if *certFile != "" {
+		cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+		if err != nil {
+			return err
+		}
+		tlsConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+		if *caFile != "" {
+			caPEM, err := os.ReadFile(*caFile)
+			if err != nil {
+				return err
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(caPEM) {
+				return fmt.Errorf("no certificates found in %s", *caFile)
+			}
+			tlsConfig.ClientCAs = pool
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	p, err := c.provider()
+	if err != nil {
+		return err
+	}
+
+	srv := server.New(p, server.Config{
+		Addr:        *addr,
+		GRPCAddr:    *grpcAddr,
+		DisableREST: *noREST,
+		Token:       os.Getenv(server.EnvToken),
+		TLSConfig:   tlsConfig,
+		ReadOnly:    *readOnly,
+		Logger:      slog.New(slog.NewTextHandler(os.Stderr, nil)),
+	})
+	return srv.ListenAndServe(ctx)
+}
+
+// uidFlag collects repeated -allow-uid flags.
+type uidFlag []int
+
+func (f *uidFlag) String() string { return fmt.Sprint([]int(*f)) }
+
+func (f *uidFlag) Set(s string) error {
+	uid, err := strconv.Atoi(s)
+	if err != nil || uid < 0 {
+		return fmt.Errorf("invalid user ID %q", s)
+	}
+	*f = append(*f, uid)
+	return nil
+}
+
+// fieldFlag collects repeated -field K=V flags.
+type fieldFlag map[string]string
+
+func (f fieldFlag) String() string { return "" }
+
+func (f fieldFlag) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected K=V, got %q", s)
+	}
+	f[key] = value
+	return nil
+}
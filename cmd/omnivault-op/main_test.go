@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRun_UsageErrors(t *testing.T) {
+	// Usage errors are reported before a provider is created, so no token
+	// is needed.
+	t.Setenv("OP_SERVICE_ACCOUNT_TOKEN", "")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no command", nil},
+		{"unknown command", []string{"bogus"}},
+		{"get without path", []string{"get"}},
+		{"get extra args", []string{"get", "a", "b"}},
+		{"set without path", []string{"set"}},
+		{"set bad field", []string{"set", "-field", "novalue", "V/I"}},
+		{"delete without path", []string{"delete"}},
+		{"delete field", []string{"delete", "V/I/password"}},
+		{"delete field in default vault", []string{"-vault", "V", "delete", "I/password"}},
+		{"inject with args", []string{"inject", "extra"}},
+		{"export unknown format", []string{"export", "-format", "xml", "V/I"}},
+		{"export k8s without name", []string{"export", "-format", "k8s", "V/I"}},
+		{"agent bad uid", []string{"agent", "-allow-uid", "root"}},
+		{"agent with args", []string{"agent", "extra"}},
+		{"serve cert without key", []string{"serve", "-tls-cert", "cert.pem"}},
+		{"serve ca without cert", []string{"serve", "-client-ca", "ca.pem"}},
+		{"serve no-rest without grpc", []string{"serve", "-no-rest"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(context.Background(), tt.args, nil, &bytes.Buffer{})
+			if !errors.Is(err, errUsage) {
+				t.Errorf("run(%q) error = %v, want errUsage", tt.args, err)
+			}
+		})
+	}
+}
+
+func TestFieldFlag(t *testing.T) {
+	f := fieldFlag{}
+	if err := f.Set("username=admin"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := f.Set("url=https://example.com/?a=b"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if f["username"] != "admin" || f["url"] != "https://example.com/?a=b" {
+		t.Errorf("fieldFlag = %v", f)
+	}
+	if err := f.Set("=x"); err == nil {
+		t.Error("Set() should reject an empty key")
+	}
+}
@@ -0,0 +1,63 @@
+package onepassword
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ErrNotModified is returned by GetIfChanged when the item is still at the
+// version the caller has.
+var ErrNotModified error = &sentinelError{"item not modified", nil}
+
+// GetIfChanged reads the secret at path like Get, unless its item is still
+// at lastVersion, the Metadata.Version of the caller's copy. It then
+// returns ErrNotModified, leaving the secret unconverted and the disk cache
+// untouched. An empty lastVersion always reads the secret.
+//
+//	secret, err := provider.GetIfChanged(ctx, "Prod/app", current.Metadata.Version)
+//	if errors.Is(err, onepassword.ErrNotModified) {
+//	    secret = current
+//	}
+//
+// The item is fetched either way, as the SDK can't report a version
+// alone. Field reads always fetch the item instead of resolving a secret
+// reference, and a TOTP code, which changes without the item changing, is
+// always returned. The secret's Metadata.Version is set for field reads
+// too.
+func (p *Provider) GetIfChanged(ctx context.Context, path, lastVersion string) (*vault.Secret, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("GetIfChanged", path, ProviderName, vault.ErrClosed)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return nil, vault.NewVaultError("GetIfChanged", path, ProviderName, err)
+	}
+
+	item, err := p.fetchItem(ctx, parsed)
+	if err != nil {
+		return nil, mapError("GetIfChanged", path, err)
+	}
+	version := strconv.FormatUint(uint64(item.Version), 10)
+	if lastVersion != "" && parsed.Attribute != AttributeTOTP && lastVersion == version {
+		return nil, vault.NewVaultError("GetIfChanged", path, ProviderName, ErrNotModified)
+	}
+
+	var secret *vault.Secret
+	if parsed.Field != "" {
+		secret, err = p.fieldSecret(item, parsed)
+	} else {
+		secret, err = p.itemSecret(item, parsed, parsed.Section)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Field reads report the version to pass next time too
+	secret.Metadata.Version = version
+	return p.cachedGet(ctx, *parsed, secret, nil)
+}
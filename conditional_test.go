@@ -0,0 +1,44 @@
+package onepassword
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetIfChanged(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	tests := []struct {
+		path        string
+		lastVersion string
+		want        string
+		err         error
+	}{
+		{"Private/Database", "", "hunter2", nil},
+		{"Private/Database", "2", "hunter2", nil},
+		{"Private/Database", "3", "", ErrNotModified},
+		{"Private/Database/password", "2", "hunter2", nil},
+		{"Private/Database/password", "3", "", ErrNotModified},
+		{"Private/Missing", "3", "", ErrItemNotFound},
+	}
+	for _, tt := range tests {
+		secret, err := p.GetIfChanged(t.Context(), tt.path, tt.lastVersion)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) || secret != nil {
+				t.Errorf("GetIfChanged(%q, %q) = %v, %v, want %v", tt.path, tt.lastVersion, secret, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("GetIfChanged(%q, %q) error = %v", tt.path, tt.lastVersion, err)
+		}
+		value := secret.Value
+		if value == "" {
+			value = secret.Fields["password"]
+		}
+		if value != tt.want || secret.Metadata.Version != "3" {
+			t.Errorf("GetIfChanged(%q, %q) = %q at version %q, want %q at 3", tt.path, tt.lastVersion, value, secret.Metadata.Version, tt.want)
+		}
+	}
+}
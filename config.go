@@ -1,10 +1,12 @@
 package onepassword
 
 import (
+	"context"
 	"log/slog"
 	"time"
 
 	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
 )
 
 const (
@@ -19,6 +21,9 @@ const (
 
 	// DefaultIntegrationVersion is the default version string.
 	DefaultIntegrationVersion = "0.1.0"
+
+	// DefaultTagSeparator separates the key and value of a tag.
+	DefaultTagSeparator = ":"
 )
 
 // Common item categories re-exported for convenience.
@@ -32,6 +37,62 @@ const (
 	CategorySSHKey         = op.ItemCategorySSHKey
 )
 
+// FieldType is the type of an item field.
+type FieldType = op.ItemFieldType
+
+// Field types re-exported for convenience.
+const (
+	FieldTypeText      = op.ItemFieldTypeText
+	FieldTypeConcealed = op.ItemFieldTypeConcealed
+	FieldTypeURL       = op.ItemFieldTypeURL
+	FieldTypePhone     = op.ItemFieldTypePhone
+	FieldTypeTOTP      = op.ItemFieldTypeTOTP
+)
+
+// FieldTypeRule assigns a field type to fields whose name matches a pattern.
+type FieldTypeRule struct {
+	// NamePattern is a glob matched against the field name, ignoring case:
+	// "*" matches any run of characters and "?" one character, as in
+	// path.Match. "*_dsn" matches "postgres_dsn". Malformed patterns match
+	// nothing.
+	NamePattern string
+
+	// Type is the field type to use.
+	Type FieldType
+}
+
+// AmbiguityPolicy selects how an item title that matches several items is resolved.
+type AmbiguityPolicy int
+
+const (
+	// AmbiguityError fails with an *AmbiguousItemError listing the candidates.
+	AmbiguityError AmbiguityPolicy = iota
+
+	// AmbiguityFirst picks the first match in listing order.
+	// The SDK does not expose item timestamps, so newest/oldest selection
+	// is not available.
+	AmbiguityFirst
+)
+
+// FieldKeyStyle selects the Secret.Fields keys Get uses for an item's fields.
+type FieldKeyStyle int
+
+const (
+	// FieldKeysFlat keys fields by title alone. A title used by several
+	// fields, e.g. in different sections, gets numbered keys such as
+	// "password#2" (see DuplicateFieldsKey).
+	FieldKeysFlat FieldKeyStyle = iota
+
+	// FieldKeysQualified keys fields in a section by the section title and
+	// field title, as in "Database.host". Fields outside sections keep
+	// their title.
+	FieldKeysQualified
+
+	// FieldKeysBoth keys every field by title as FieldKeysFlat does, and
+	// fields in a section also by their qualified key.
+	FieldKeysBoth
+)
+
 // Config holds configuration for the 1Password provider.
 type Config struct {
 	// ServiceAccountToken is the 1Password service account token.
@@ -54,14 +115,208 @@ type Config struct {
 	// Resolved to ID on first use.
 	DefaultVaultName string
 
-	// DefaultCategory is the item category for newly created items.
+	// StrictPaths rejects path forms whose meaning depends on the default
+	// vault ("item", "vault/item" vs "item/field"). Paths must then be op://
+	// references or fully qualified vault/item/field paths, and List
+	// returns op:// references rather than "vault/item" paths.
+	// The default vault is still used by the Path helpers.
+	StrictPaths bool
+
+	// DefaultCategory is the item category for newly created items when
+	// neither the write nor the field names select one.
 	// Default: CategorySecureNote
 	DefaultCategory op.ItemCategory
 
-	// CacheTTL enables caching of vault/item ID lookups.
-	// Zero disables caching. Default: 0 (disabled)
+	// DefaultSection is the section, created as needed, that fields the
+	// provider adds to an item are placed in when the write names no
+	// section, keeping them apart from fields managed by hand. Existing
+	// fields are updated where they are, and the notes field stays at the
+	// top level. Optional.
+	DefaultSection string
+
+	// DisableCategoryInference stops Set choosing the category of a new
+	// item from its field names (username and password for Login, private
+	// key for SSH Key).
+	DisableCategoryInference bool
+
+	// FieldTypeRules set the type of new fields whose names match a rule.
+	// Rules are tried in order before the built-in name heuristics (and
+	// before DisableTypeInference); explicit overrides still win.
+	FieldTypeRules []FieldTypeRule
+
+	// DisableTypeInference stores every new field as Concealed instead of
+	// guessing its type from its name. Explicit field type overrides and
+	// the notes field are unaffected.
+	DisableTypeInference bool
+
+	// FieldKeyStyle selects how Get keys an item's fields in
+	// Secret.Fields, so that items reusing field names across sections
+	// can be read unambiguously. Writes take field titles; use a section
+	// path or SetOptions.Section to write into a section.
+	// Default: FieldKeysFlat
+	FieldKeyStyle FieldKeyStyle
+
+	// TagSeparator separates the key from the value of a tag, so that the
+	// tag "env:prod" reads as Metadata.Tags["env"] = "prod" and is written
+	// back the same way. Tags are split at the first separator. The
+	// expiry tag (see ExpiresKey) always uses a colon.
+	// Default: DefaultTagSeparator
+	TagSeparator string
+
+	// RawTags reads tags verbatim, each as a key of Metadata.Tags with an
+	// empty value, so tags such as "owner:team:payments" aren't split.
+	// Writes still join keys and values given in Metadata.Tags with a colon.
+	// Metadata.Extra[TagListKey] lists the tags verbatim either way.
+	RawTags bool
+
+	// ManagedTag is a tag, such as "managed-by:omnivault", added to every
+	// item the provider creates, marking it as owned by automation.
+	// Optional.
+	ManagedTag string
+
+	// ProtectUnmanaged refuses, with ErrUnmanaged, to update or delete
+	// items without ManagedTag, so that items curated by hand aren't
+	// overwritten. Each update or delete then reads the item first. It is
+	// ignored without ManagedTag.
+	ProtectUnmanaged bool
+
+	// ReadOnly refuses every write to 1Password with vault.ErrReadOnly.
+	ReadOnly bool
+
+	// MirrorTo is a vault, such as another provider, that every write also
+	// applies to once it succeeds in 1Password, keeping a warm secondary
+	// for disaster recovery: Set, Delete, Ensure, CreateSSHKey, SetBytes
+	// and the other binary writes, Copy, Move, Rename, Import, ImportCSV,
+	// and the bulk deletes. The same path is written: a field with the
+	// value set, and an item or section as 1Password holds it after the
+	// write, merged fields included, without the version and IDs of the
+	// 1Password item. Deleting a field path deletes the whole item, from
+	// the mirror as well. A failed mirror write fails the call with
+	// ErrMirror, though 1Password was written. Optional.
+	MirrorTo vault.Vault
+
+	// MirrorAsync applies writes to MirrorTo in the background, in order,
+	// logging failures instead of returning them. Close waits for pending
+	// writes.
+	MirrorAsync bool
+
+	// BeforeCreate is called with the parameters of every item the
+	// provider is about to create, by Set or any other write, and may
+	// change them: set the category, add sections, fields, or websites.
+	// Returning an error fails the write with it. ManagedTag is added
+	// after the hook runs. Optional.
+	BeforeCreate func(ctx context.Context, params *op.ItemCreateParams) error
+
+	// BeforeUpdate is called with every item the provider is about to
+	// save over an existing one, after the write's changes are applied,
+	// and may change it further. Returning an error fails the write with
+	// it. Writes left out by SkipUnchanged or planned in a dry run don't
+	// reach it. Optional.
+	BeforeUpdate func(ctx context.Context, item *op.Item) error
+
+	// OnAmbiguous selects how an item title matching several items is resolved.
+	// Items addressed by ID are never ambiguous.
+	// Default: AmbiguityError
+	OnAmbiguous AmbiguityPolicy
+
+	// EnforceExpiry makes Get fail with ErrSecretExpired for items whose
+	// expiry (see ExpiresKey) has passed. Field reads then fetch the whole
+	// item instead of resolving a secret reference.
+	EnforceExpiry bool
+
+	// ShardTokens are further service account tokens to spread reads
+	// across, for workloads that hit per-account rate limits. Writes use
+	// ServiceAccountToken. All tokens must belong to the same 1Password
+	// account and have access to the same vaults. Optional.
+	ShardTokens []string
+
+	// ShardStrategy selects how reads are spread across tokens.
+	// Default: ShardRoundRobin
+	ShardStrategy ShardStrategy
+
+	// ShardCooldown is how long a token is skipped after a call through it
+	// fails with a rate limit, authentication, or connection error. The
+	// call is retried with another token.
+	// Default: DefaultShardCooldown
+	ShardCooldown time.Duration
+
+	// SkipUnchanged makes Set leave an existing item alone, at its
+	// current version, when the write wouldn't change its fields,
+	// sections, or tags. This suits reconcile loops that write the desired
+	// state over and over. See SetOptions.SkipUnchanged.
+	SkipUnchanged bool
+
+	// DryRun makes Set, SetWithOptions, Delete, Ensure, and the batch and
+	// bulk writes built on them plan their changes instead of making
+	// them. PlannedChanges returns the plan, and ChangeSet.Apply makes it.
+	// Other writes fail with ErrDryRun. Use WithDryRun to plan a single
+	// call instead.
+	DryRun bool
+
+	// BatchConcurrency is how many writes SetBatch and DeleteBatch run at
+	// once. 1 writes one secret at a time.
+	// Default: DefaultBatchConcurrency
+	BatchConcurrency int
+
+	// EagerInit creates the SDK client in New, so an invalid token fails
+	// construction. By default the client is created on the first operation
+	// and a failure is returned by every operation.
+	EagerInit bool
+
+	// RateLimit limits how fast calls are made to 1Password, smoothing
+	// bursts so they stay under the service account's quotas. The limits
+	// apply to the provider as a whole, across ShardTokens. Optional.
+	RateLimit RateLimit
+
+	// OperationTimeout bounds each call to the 1Password SDK. Zero leaves
+	// calls bounded only by the caller's context and Timeouts. Default: 0
+	OperationTimeout time.Duration
+
+	// Timeouts bound whole operations, which may each make several SDK
+	// calls, so a hung call can't hang a caller that passed an unbounded
+	// context. Zero values leave operations unbounded. Optional.
+	//
+	// Both limits apply when set along with OperationTimeout: each SDK
+	// call ends at whichever comes first of its own OperationTimeout and
+	// the deadline of the operation making it.
+	Timeouts Timeouts
+
+	// CacheTTL is how long vault name -> ID lookups are reused before the
+	// vaults are listed again. Zero keeps them for the provider's lifetime;
+	// a negative value disables the cache. A positive value also caches
+	// each vault's item listing for that long; writes through the provider
+	// refresh it. Default: 0
 	CacheTTL time.Duration
 
+	// NegativeCacheTTL is how long a vault name that wasn't found is
+	// remembered, so repeated lookups of a missing vault don't each list
+	// all vaults. A negative value disables negative caching.
+	// Default: DefaultNegativeCacheTTL
+	NegativeCacheTTL time.Duration
+
+	// CacheDir enables a persistent cache of secrets read by Get in this
+	// directory. When 1Password can't be reached or doesn't answer, Get
+	// serves the cached copy, marked with Metadata.Extra[CachedAtKey].
+	// Definite answers such as not found or access denied are never masked.
+	// Entries are encrypted with AES-256-GCM. Optional.
+	CacheDir string
+
+	// CacheKey is the secret the disk cache key is derived from.
+	// Default: the service account token, so rotating the token discards
+	// the cache
+	CacheKey []byte
+
+	// CacheMaxAge is how old a disk cache entry may be and still be served.
+	// Default: DefaultCacheMaxAge
+	CacheMaxAge time.Duration
+
+	// ZeroizeSecrets keeps secret values out of long-lived storage: the
+	// disk cache is not used, and Close calls Wipe, except on views made
+	// with With, which leave the caches to the provider they share them
+	// with. Use GetSecretBuffer or WithSecret to hold values in buffers
+	// that can be zeroed.
+	ZeroizeSecrets bool
+
 	// Logger for debug output. Optional.
 	Logger *slog.Logger
 }
@@ -77,5 +332,35 @@ func (c Config) withDefaults() Config {
 	if c.DefaultCategory == "" {
 		c.DefaultCategory = CategorySecureNote
 	}
+	if c.NegativeCacheTTL == 0 {
+		c.NegativeCacheTTL = DefaultNegativeCacheTTL
+	}
+	if c.ShardCooldown == 0 {
+		c.ShardCooldown = DefaultShardCooldown
+	}
+	if c.CacheMaxAge == 0 {
+		c.CacheMaxAge = DefaultCacheMaxAge
+	}
+	if c.TagSeparator == "" {
+		c.TagSeparator = DefaultTagSeparator
+	}
+	if c.BatchConcurrency <= 0 {
+		c.BatchConcurrency = DefaultBatchConcurrency
+	}
 	return c
 }
+
+// Timeouts bounds provider operations by kind. Batch operations apply them
+// to each secret.
+type Timeouts struct {
+	// Get bounds Get, Exists, GetMetadata, GetBytes, and the reads built
+	// on them.
+	Get time.Duration
+
+	// Write bounds Set, Delete, SetBytes, and the writes built on them,
+	// excluding time spent waiting for another write to finish.
+	Write time.Duration
+
+	// List bounds List.
+	List time.Duration
+}
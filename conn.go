@@ -0,0 +1,98 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+// clientConn creates the SDK client on first use. The client and any error
+// creating it are kept, so every operation shares one client and a bad
+// token is reported consistently instead of retried on each call.
+type clientConn struct {
+	connect func(ctx context.Context) (*op.Client, error)
+
+	once   sync.Once
+	client *op.Client
+	err    error
+}
+
+// get returns the client, creating it if needed. Creation is detached from
+// ctx's cancellation so that one caller giving up doesn't fail the client
+// for everyone.
+func (c *clientConn) get(ctx context.Context) (*op.Client, error) {
+	c.once.Do(func() {
+		c.client, c.err = c.connect(context.WithoutCancel(ctx))
+		if c.err != nil {
+			c.err = fmt.Errorf("failed to create 1Password client: %w", c.err)
+		}
+	})
+	return c.client, c.err
+}
+
+// lazySecrets implements secretsAPI on a lazily created client.
+type lazySecrets struct{ conn *clientConn }
+
+func (l lazySecrets) Resolve(ctx context.Context, secretReference string) (string, error) {
+	client, err := l.conn.get(ctx)
+	if err != nil {
+		return "", err
+	}
+	return client.Secrets.Resolve(ctx, secretReference)
+}
+
+// lazyItems implements itemsAPI on a lazily created client.
+type lazyItems struct{ conn *clientConn }
+
+func (l lazyItems) Create(ctx context.Context, params op.ItemCreateParams) (op.Item, error) {
+	client, err := l.conn.get(ctx)
+	if err != nil {
+		return op.Item{}, err
+	}
+	return client.Items.Create(ctx, params)
+}
+
+func (l lazyItems) Get(ctx context.Context, vaultID, itemID string) (op.Item, error) {
+	client, err := l.conn.get(ctx)
+	if err != nil {
+		return op.Item{}, err
+	}
+	return client.Items.Get(ctx, vaultID, itemID)
+}
+
+func (l lazyItems) Put(ctx context.Context, item op.Item) (op.Item, error) {
+	client, err := l.conn.get(ctx)
+	if err != nil {
+		return op.Item{}, err
+	}
+	return client.Items.Put(ctx, item)
+}
+
+func (l lazyItems) Delete(ctx context.Context, vaultID, itemID string) error {
+	client, err := l.conn.get(ctx)
+	if err != nil {
+		return err
+	}
+	return client.Items.Delete(ctx, vaultID, itemID)
+}
+
+func (l lazyItems) ListAll(ctx context.Context, vaultID string) (*op.Iterator[op.ItemOverview], error) {
+	client, err := l.conn.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Items.ListAll(ctx, vaultID)
+}
+
+// lazyVaults implements vaultsAPI on a lazily created client.
+type lazyVaults struct{ conn *clientConn }
+
+func (l lazyVaults) ListAll(ctx context.Context) (*op.Iterator[op.VaultOverview], error) {
+	client, err := l.conn.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Vaults.ListAll(ctx)
+}
@@ -0,0 +1,75 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func TestClientConn_MemoizesError(t *testing.T) {
+	calls := 0
+	conn := &clientConn{
+		connect: func(context.Context) (*op.Client, error) {
+			calls++
+			return nil, errors.New("invalid service account token")
+		},
+	}
+	p := newWithAPIs(lazySecrets{conn}, lazyItems{conn}, lazyVaults{conn}, Config{})
+
+	ctx := context.Background()
+	for range 3 {
+		if _, err := p.Get(ctx, "Private/Item"); err == nil {
+			t.Fatal("Get() error = nil, want client creation error")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("connect called %d times, want 1", calls)
+	}
+}
+
+func TestClientConn_SharedClient(t *testing.T) {
+	m := testMockAPI()
+	var mu sync.Mutex
+	calls := 0
+	conn := &clientConn{
+		connect: func(context.Context) (*op.Client, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			return &op.Client{Secrets: m, Items: m, Vaults: mockVaults{m}}, nil
+		},
+	}
+	p := newWithAPIs(lazySecrets{conn}, lazyItems{conn}, lazyVaults{conn}, Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // creation must not inherit the caller's cancellation
+	if _, err := conn.get(ctx); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = p.List(context.Background(), "")
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("connect called %d times, want 1", calls)
+	}
+}
+
+func TestNew_Lazy(t *testing.T) {
+	// Without EagerInit no client is created, so a bogus token is accepted
+	p, err := New(Config{ServiceAccountToken: "not-a-token"})
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	_ = p.Close()
+}
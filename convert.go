@@ -2,14 +2,62 @@ package onepassword
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	op "github.com/1password/onepassword-sdk-go"
 	"github.com/agentplexus/omnivault/vault"
 )
 
+// NotesField is the reserved field name for an item's notes. On read, the
+// notes field is returned under this name whatever its title's case; on
+// write, a field with this name is stored in the item's native notes, the
+// plain-text field with ID "notesPlain".
+const NotesField = "notes"
+
+// notesPlainID is the field ID 1Password uses for item notes.
+const notesPlainID = "notesPlain"
+
+// DuplicateFieldsKey is the Secret.Metadata.Extra key listing field names
+// shared by several fields of an item, as a map from the name to the
+// Secret.Fields keys holding them. The first field keeps the plain name and
+// later ones get a "#2", "#3", ... suffix, e.g. "password#2". Writing a
+// suffixed key back updates the same field.
+const DuplicateFieldsKey = "duplicateFields"
+
+// TagListKey is the Secret.Metadata.Extra key listing an item's tags
+// verbatim, in item order, as a []string. Unlike Metadata.Tags it keeps
+// tags that share a key, and is never split. It is ignored by writes.
+const TagListKey = "tagList"
+
+// isNotesField reports whether field holds the item notes.
+func isNotesField(field op.ItemField) bool {
+	return field.ID == notesPlainID || strings.EqualFold(field.Title, NotesField)
+}
+
+// notesField returns the native notes field holding value.
+func notesField(value string) op.ItemField {
+	return op.ItemField{
+		ID:        notesPlainID,
+		Title:     NotesField,
+		Value:     value,
+		FieldType: op.ItemFieldTypeText,
+	}
+}
+
 // itemToSecret converts a 1Password Item to an OmniVault Secret.
+// CreatedAt and ModifiedAt stay nil, as the SDK's Item has no timestamps,
+// and no favorite or other flags are reported, as it has none. Nor are
+// passkeys, which the SDK leaves out of the items it returns.
 func itemToSecret(item op.Item, path string) *vault.Secret {
+	return secretFromItem(item, path, fieldNames(item.Fields))
+}
+
+// secretFromItem converts item to a Secret, keying each field by its entry
+// in names; later fields with a name already taken get numbered keys.
+func secretFromItem(item op.Item, path string, names []string) *vault.Secret {
 	secret := &vault.Secret{
 		Fields: make(map[string]string),
 		Metadata: vault.Metadata{
@@ -17,70 +65,68 @@ func itemToSecret(item op.Item, path string) *vault.Secret {
 			Path:     path,
 			Version:  fmt.Sprintf("%d", item.Version),
 			Extra: map[string]any{
-				"vaultId":  item.VaultID,
-				"itemId":   item.ID,
-				"category": string(item.Category),
+				"vaultId":   item.VaultID,
+				"itemId":    item.ID,
+				CategoryKey: string(item.Category),
 			},
 		},
 	}
 
-	// Convert tags
+	if expires, ok := itemExpiry(item); ok {
+		secret.Metadata.ExpiresAt = vault.NewTimestamp(expires)
+	}
+
+	// Convert tags, keeping them verbatim as well
 	if len(item.Tags) > 0 {
-		secret.Metadata.Tags = make(map[string]string)
-		for _, tag := range item.Tags {
-			// Try to parse "key:value" format
-			parts := strings.SplitN(tag, ":", 2)
-			if len(parts) == 2 {
-				secret.Metadata.Tags[parts[0]] = parts[1]
-			} else {
-				secret.Metadata.Tags[tag] = ""
-			}
-		}
+		secret.Metadata.Tags = parseTags(item.Tags, DefaultTagSeparator)
+		secret.Metadata.Extra[TagListKey] = slices.Clone(item.Tags)
 	}
 
 	// Convert fields
 	var firstConcealedValue string
-	for _, field := range item.Fields {
-		name := field.Title
-		if name == "" {
-			name = field.ID
-		}
-
-		value := field.Value
-
-		// Handle TOTP fields specially - extract computed code
-		if field.FieldType == op.ItemFieldTypeTOTP {
-			if field.Details != nil {
-				if otp := field.Details.OTP(); otp != nil {
-					if otp.Code != nil {
-						value = *otp.Code
-					}
-				}
+	duplicates := make(map[string][]string)
+	for i, key := range numberKeys(names) {
+		field := item.Fields[i]
+		if name := names[i]; key != name {
+			if duplicates[name] == nil {
+				duplicates[name] = []string{name}
 			}
+			duplicates[name] = append(duplicates[name], key)
 		}
 
-		secret.Fields[name] = value
+		value := fieldValue(field)
+
+		secret.Fields[key] = value
 
 		// Track first concealed field for primary value
 		if firstConcealedValue == "" && field.FieldType == op.ItemFieldTypeConcealed {
 			firstConcealedValue = value
 		}
 
-		// Set primary value from "password" field
-		if strings.ToLower(name) == "password" {
+		// Set primary value from the first "password" field
+		if secret.Value == "" && strings.ToLower(key) == "password" {
 			secret.Value = value
 		}
 	}
 
+	if len(duplicates) > 0 {
+		secret.Metadata.Extra[DuplicateFieldsKey] = duplicates
+	}
+
 	// Use first concealed field if no "password" field
 	if secret.Value == "" && firstConcealedValue != "" {
 		secret.Value = firstConcealedValue
 	}
 
+	// A Secure Note's content is its notes
+	if secret.Value == "" && item.Category == op.ItemCategorySecureNote {
+		secret.Value = secret.Fields[NotesField]
+	}
+
 	// Fallback to first field value
-	if secret.Value == "" && len(secret.Fields) > 0 {
-		for _, v := range secret.Fields {
-			if v != "" {
+	if secret.Value == "" {
+		for _, field := range item.Fields {
+			if v := fieldValue(field); v != "" {
 				secret.Value = v
 				break
 			}
@@ -90,12 +136,238 @@ func itemToSecret(item op.Item, path string) *vault.Secret {
 	return secret
 }
 
+// fieldKey returns the Secret.Fields name of a field: its title, or its ID
+// if untitled, or NotesField for the notes.
+func fieldKey(field op.ItemField) string {
+	if isNotesField(field) {
+		return NotesField
+	}
+	if field.Title == "" {
+		return field.ID
+	}
+	return field.Title
+}
+
+// fieldKeys returns the Secret.Fields key of each field. Later fields with
+// a name already taken get a numbered key from duplicateKey.
+func fieldKeys(fields []op.ItemField) []string {
+	return numberKeys(fieldNames(fields))
+}
+
+// fieldNames returns the fieldKey of each field.
+func fieldNames(fields []op.ItemField) []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = fieldKey(field)
+	}
+	return names
+}
+
+// qualifiedFieldNames returns the name of each field of item, qualified by
+// its section title (or ID, if untitled) for fields in a section, as in
+// "Database.host".
+func qualifiedFieldNames(item op.Item) []string {
+	names := fieldNames(item.Fields)
+	for i, field := range item.Fields {
+		if section := sectionName(item, field); section != "" {
+			names[i] = section + "." + names[i]
+		}
+	}
+	return names
+}
+
+// sectionName returns the title, or the ID if untitled, of the section
+// holding field, or "" if it is in none.
+func sectionName(item op.Item, field op.ItemField) string {
+	if field.SectionID == nil {
+		return ""
+	}
+	for _, s := range item.Sections {
+		if s.ID == *field.SectionID {
+			if s.Title != "" {
+				return s.Title
+			}
+			return s.ID
+		}
+	}
+	return ""
+}
+
+// numberKeys returns names as keys, giving later occurrences of a name
+// taken already a numbered key from duplicateKey.
+func numberKeys(names []string) []string {
+	keys := make([]string, len(names))
+	taken := make(map[string]bool, len(names))
+	suffix := make(map[string]int)
+	for i, name := range names {
+		key := name
+		if taken[key] {
+			n := max(suffix[name], 1)
+			for taken[key] {
+				n++
+				key = duplicateKey(name, n)
+			}
+			suffix[name] = n
+		}
+		taken[key] = true
+		keys[i] = key
+	}
+	return keys
+}
+
+// toSecret converts item to a Secret, keying its fields according to
+// Config.FieldKeyStyle and parsing its tags according to
+// Config.TagSeparator and Config.RawTags.
+func (p *Provider) toSecret(item op.Item, path string) *vault.Secret {
+	item = withBuiltinKeys(item)
+	var secret *vault.Secret
+	switch p.config.FieldKeyStyle {
+	case FieldKeysQualified:
+		secret = secretFromItem(item, path, qualifiedFieldNames(item))
+	case FieldKeysBoth:
+		secret = itemToSecret(item, path)
+		for i, key := range numberKeys(qualifiedFieldNames(item)) {
+			if sectionName(item, item.Fields[i]) != "" {
+				secret.Fields[key] = fieldValue(item.Fields[i])
+			}
+		}
+	default:
+		secret = itemToSecret(item, path)
+	}
+
+	if sep := p.tagSeparator(); sep != DefaultTagSeparator {
+		secret.Metadata.Tags = parseTags(item.Tags, sep)
+	}
+	return secret
+}
+
+// tagSeparator returns the separator of tag keys and values, or "" with
+// Config.RawTags.
+func (p *Provider) tagSeparator() string {
+	if p.config.RawTags {
+		return ""
+	}
+	return p.config.TagSeparator
+}
+
+// secretFieldKeys returns the Secret.Fields keys toSecret gives the fields
+// of item, in field order.
+func (p *Provider) secretFieldKeys(item op.Item) []string {
+	switch p.config.FieldKeyStyle {
+	case FieldKeysQualified:
+		return numberKeys(qualifiedFieldNames(item))
+	case FieldKeysBoth:
+		keys := fieldKeys(item.Fields)
+		for i, key := range numberKeys(qualifiedFieldNames(item)) {
+			if sectionName(item, item.Fields[i]) != "" {
+				keys = append(keys, key)
+			}
+		}
+		return keys
+	}
+	return fieldKeys(item.Fields)
+}
+
+// duplicateKey returns the Secret.Fields key of the nth field named name.
+func duplicateKey(name string, n int) string {
+	return name + "#" + strconv.Itoa(n)
+}
+
+// duplicateIndex returns the index of the field addressed by a duplicate
+// key such as "password#2", or -1.
+func duplicateIndex(fields []op.ItemField, key string) int {
+	i := strings.LastIndex(key, "#")
+	if i < 0 {
+		return -1
+	}
+	n, err := strconv.Atoi(key[i+1:])
+	if err != nil || n < 2 {
+		return -1
+	}
+	name := key[:i]
+	for j := range fields {
+		if fieldKey(fields[j]) == name {
+			n--
+			if n == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// fieldValue returns the readable value of a field. TOTP fields yield the
+// computed code rather than the stored otpauth URI.
+func fieldValue(field op.ItemField) string {
+	if field.FieldType == op.ItemFieldTypeTOTP && field.Details != nil {
+		if otp := field.Details.OTP(); otp != nil && otp.Code != nil {
+			return *otp.Code
+		}
+	}
+	return field.Value
+}
+
+// findField returns the field of item matching name by title or ID. If
+// section is set, the field must belong to a section with that title or ID.
+func findField(item op.Item, section, name string) (op.ItemField, bool) {
+	for _, field := range item.Fields {
+		if field.Title != name && field.ID != name {
+			continue
+		}
+		if section != "" && !inSection(item, field, section) {
+			continue
+		}
+		return field, true
+	}
+	return op.ItemField{}, false
+}
+
+// inSection reports whether field belongs to the section with the given title or ID.
+func inSection(item op.Item, field op.ItemField, section string) bool {
+	if field.SectionID == nil {
+		return false
+	}
+	for _, s := range item.Sections {
+		if s.ID == *field.SectionID && (s.Title == section || s.ID == section) {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureSection returns the ID of the section titled or identified by name,
+// adding it to sections if there is none.
+func ensureSection(sections *[]op.ItemSection, name string) string {
+	for _, s := range *sections {
+		if s.Title == name || s.ID == name {
+			return s.ID
+		}
+	}
+	id := sanitizeID(name)
+	*sections = append(*sections, op.ItemSection{ID: id, Title: name})
+	return id
+}
+
+// placeInSection moves fields into the section with the given ID. The notes
+// field belongs to the item and is left alone.
+func placeInSection(fields []op.ItemField, sectionID string) {
+	for i := range fields {
+		if !isNotesField(fields[i]) {
+			id := sectionID
+			fields[i].SectionID = &id
+		}
+	}
+}
+
 // secretToFields converts an OmniVault Secret to 1Password ItemFields.
 func secretToFields(secret *vault.Secret, fieldName string) []op.ItemField {
 	var fields []op.ItemField
 
 	// If a specific field name is provided, create a single field
 	if fieldName != "" {
+		if strings.EqualFold(fieldName, NotesField) {
+			return []op.ItemField{notesField(secret.Value)}
+		}
 		fields = append(fields, op.ItemField{
 			ID:        sanitizeID(fieldName),
 			Title:     fieldName,
@@ -105,8 +377,19 @@ func secretToFields(secret *vault.Secret, fieldName string) []op.ItemField {
 		return fields
 	}
 
-	// Create fields from secret.Fields
-	for name, value := range secret.Fields {
+	// Create fields from secret.Fields, in name order so that writes are
+	// deterministic
+	names := make([]string, 0, len(secret.Fields))
+	for name := range secret.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := secret.Fields[name]
+		if strings.EqualFold(name, NotesField) {
+			fields = append(fields, notesField(value))
+			continue
+		}
 		fieldType := inferFieldType(name, value)
 		fields = append(fields, op.ItemField{
 			ID:        sanitizeID(name),
@@ -129,6 +412,45 @@ func secretToFields(secret *vault.Secret, fieldName string) []op.ItemField {
 	return fields
 }
 
+// mergeFields upserts updates into existing, matching fields by title or ID.
+// An update placed in a section only matches fields in that section.
+// Matched fields keep their ID, type, and section; unmatched updates are appended.
+func mergeFields(existing, updates []op.ItemField) []op.ItemField {
+	merged := append([]op.ItemField(nil), existing...)
+	for _, update := range updates {
+		if i := mergeIndex(merged, update); i >= 0 {
+			merged[i].Value = update.Value
+			continue
+		}
+		merged = append(merged, update)
+	}
+	return merged
+}
+
+// mergeIndex returns the index of the field in fields that update writes
+// to, or -1 if it adds a field.
+func mergeIndex(fields []op.ItemField, update op.ItemField) int {
+	for i := range fields {
+		if update.SectionID != nil && !sameSection(fields[i], update) {
+			continue
+		}
+		if fields[i].Title == update.Title || fields[i].ID == update.Title ||
+			(isNotesField(fields[i]) && isNotesField(update)) {
+			return i
+		}
+	}
+	// A duplicate key from itemToSecret, such as "password#2"
+	return duplicateIndex(fields, update.Title)
+}
+
+// sameSection reports whether a and b belong to the same section.
+func sameSection(a, b op.ItemField) bool {
+	if a.SectionID == nil || b.SectionID == nil {
+		return a.SectionID == b.SectionID
+	}
+	return *a.SectionID == *b.SectionID
+}
+
 // inferFieldType infers the 1Password field type from the field name and value.
 func inferFieldType(name, value string) op.ItemFieldType {
 	nameLower := strings.ToLower(name)
@@ -183,19 +505,94 @@ func sanitizeID(name string) string {
 	return sanitized
 }
 
-// tagsToStrings converts vault.Secret tags to 1Password tag format.
-func tagsToStrings(tags map[string]string) []string {
+// tagsToStrings converts vault.Secret tags to 1Password tag format, sorted,
+// joining keys and values with sep (see splitTag).
+func tagsToStrings(tags map[string]string, sep string) []string {
 	if len(tags) == 0 {
 		return nil
 	}
 
 	var result []string
 	for k, v := range tags {
-		if v != "" {
-			result = append(result, fmt.Sprintf("%s:%s", k, v))
-		} else {
-			result = append(result, k)
-		}
+		result = append(result, joinTag(k, v, sep))
 	}
+	sort.Strings(result)
 	return result
 }
+
+// mergeTags adds tags to existing, replacing an existing "key:value" tag
+// with the same key.
+func mergeTags(existing []string, tags map[string]string, sep string) []string {
+	written := tagsToStrings(tags, sep)
+	merged := make([]string, 0, len(existing)+len(written))
+	for _, tag := range existing {
+		key, _ := splitTag(tag, sep)
+		if _, ok := tags[key]; !ok && !slices.Contains(written, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return append(merged, written...)
+}
+
+// addTags adds to existing the tags whose keys it doesn't have yet.
+func addTags(existing []string, tags map[string]string, sep string) []string {
+	have := make(map[string]bool, len(existing))
+	for _, tag := range existing {
+		key, _ := splitTag(tag, sep)
+		have[key] = true
+		have[tag] = true
+	}
+	added := slices.Clone(existing)
+	for _, tag := range tagsToStrings(tags, sep) {
+		if key, _ := splitTag(tag, sep); !have[key] && !have[tag] {
+			added = append(added, tag)
+		}
+	}
+	return added
+}
+
+// parseTags converts 1Password tags to vault.Secret tags, splitting each
+// into a key and value with sep (see splitTag). An empty sep keeps every
+// tag verbatim as a key with an empty value.
+func parseTags(tags []string, sep string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	parsed := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		if sep == "" {
+			parsed[tag] = ""
+			continue
+		}
+		key, value := splitTag(tag, sep)
+		parsed[key] = value
+	}
+	return parsed
+}
+
+// splitTag splits a tag at the first sep into a key and value. A tag
+// without sep, or any tag when sep is empty, is a key without a value. The
+// expiry tag is always split at a colon (see ExpiresKey).
+func splitTag(tag, sep string) (key, value string) {
+	if key, value, ok := strings.Cut(tag, ":"); ok && key == ExpiresKey {
+		return key, value
+	}
+	if sep != "" {
+		if key, value, ok := strings.Cut(tag, sep); ok {
+			return key, value
+		}
+	}
+	return tag, ""
+}
+
+// joinTag joins a tag key and value with sep, or a colon for the expiry tag
+// or an empty sep.
+func joinTag(key, value, sep string) string {
+	switch {
+	case value == "":
+		return key
+	case sep == "" || key == ExpiresKey:
+		sep = DefaultTagSeparator
+	}
+	return key + sep + value
+}
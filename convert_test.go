@@ -1,6 +1,8 @@
 package onepassword
 
 import (
+	"reflect"
+	"slices"
 	"testing"
 
 	op "github.com/1password/onepassword-sdk-go"
@@ -160,6 +162,76 @@ func TestItemToSecret_NoNotesOrConcealed(t *testing.T) {
 	}
 }
 
+func TestItemToSecret_Notes(t *testing.T) {
+	tests := []struct {
+		name      string
+		category  op.ItemCategory
+		field     op.ItemField
+		wantValue string
+	}{
+		{"secure note body", op.ItemCategorySecureNote,
+			op.ItemField{ID: "notesPlain", Title: "Notes", Value: "line one\nline two", FieldType: op.ItemFieldTypeText}, "line one\nline two"},
+		{"titled notes", op.ItemCategorySecureNote,
+			op.ItemField{ID: "abc", Title: "NOTES", Value: "body", FieldType: op.ItemFieldTypeText}, "body"},
+		{"login notes are not the value", op.ItemCategoryLogin,
+			op.ItemField{ID: "notesPlain", Title: "", Value: "body", FieldType: op.ItemFieldTypeText}, "hunter2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := op.Item{Category: tt.category, Fields: []op.ItemField{tt.field}}
+			if tt.category == op.ItemCategoryLogin {
+				item.Fields = append(item.Fields, op.ItemField{ID: "password", Title: "password", Value: "hunter2", FieldType: op.ItemFieldTypeConcealed})
+			}
+
+			secret := itemToSecret(item, "Private/Item")
+			if secret.Fields[NotesField] != tt.field.Value {
+				t.Errorf("Fields[notes] = %q, want %q", secret.Fields[NotesField], tt.field.Value)
+			}
+			if secret.Value != tt.wantValue {
+				t.Errorf("Value = %q, want %q", secret.Value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestSecretToFields_Notes(t *testing.T) {
+	for _, fields := range [][]op.ItemField{
+		secretToFields(&vault.Secret{Fields: map[string]string{"Notes": "api key rotation steps"}}, ""),
+		secretToFields(&vault.Secret{Value: "api key rotation steps"}, "notes"),
+	} {
+		if len(fields) != 1 {
+			t.Fatalf("got %d fields, want 1", len(fields))
+		}
+		if fields[0].ID != notesPlainID || fields[0].FieldType != op.ItemFieldTypeText {
+			t.Errorf("notes field = %+v, want the plain-text %s field", fields[0], notesPlainID)
+		}
+	}
+
+	existing := []op.ItemField{{ID: "notesPlain", Title: "Notes", Value: "old", FieldType: op.ItemFieldTypeText}}
+	merged := mergeFields(existing, []op.ItemField{notesField("new")})
+	if len(merged) != 1 || merged[0].Value != "new" || merged[0].ID != "notesPlain" {
+		t.Errorf("mergeFields() = %+v, want the existing notes field updated", merged)
+	}
+}
+
+func TestSetNotes_RoundTrip(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+	if err := p.Set(t.Context(), "Private/runbook/notes", &vault.Secret{Value: "1. Rotate the key"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	created := m.created[0]
+	if len(created.Fields) != 1 || created.Fields[0].ID != notesPlainID {
+		t.Fatalf("created fields = %+v, want the native notes field", created.Fields)
+	}
+	secret := p.toSecret(op.Item{Category: created.Category, Fields: created.Fields}, "Private/runbook")
+	if secret.Fields[NotesField] != "1. Rotate the key" || secret.Value != "1. Rotate the key" {
+		t.Errorf("read back %+v, want the notes", secret)
+	}
+}
+
 func TestSecretToFields(t *testing.T) {
 	t.Run("with specific field name", func(t *testing.T) {
 		secret := &vault.Secret{Value: "mytoken123"}
@@ -225,6 +297,143 @@ func TestSecretToFields(t *testing.T) {
 	})
 }
 
+func TestMergeFields(t *testing.T) {
+	existing := []op.ItemField{
+		{ID: "username", Title: "username", Value: "olduser", FieldType: op.ItemFieldTypeText},
+		{ID: "password", Title: "password", Value: "oldpass", FieldType: op.ItemFieldTypeConcealed},
+	}
+	updates := []op.ItemField{
+		{ID: "username", Title: "username", Value: "newuser", FieldType: op.ItemFieldTypeConcealed},
+		{ID: "url", Title: "url", Value: "https://example.com", FieldType: op.ItemFieldTypeURL},
+	}
+
+	merged := mergeFields(existing, updates)
+
+	if len(merged) != 3 {
+		t.Fatalf("Expected 3 fields, got %d", len(merged))
+	}
+	if merged[0].Value != "newuser" {
+		t.Errorf("Expected username = 'newuser', got %q", merged[0].Value)
+	}
+	if merged[0].FieldType != op.ItemFieldTypeText {
+		t.Errorf("Expected username to keep FieldType = Text, got %v", merged[0].FieldType)
+	}
+	if merged[1].Value != "oldpass" {
+		t.Errorf("Expected password to be preserved, got %q", merged[1].Value)
+	}
+	if merged[2].Title != "url" {
+		t.Errorf("Expected url to be appended, got %q", merged[2].Title)
+	}
+	if existing[0].Value != "olduser" {
+		t.Error("mergeFields should not modify the existing slice")
+	}
+}
+
+func TestFindField(t *testing.T) {
+	dev, prod := "dev", "prod"
+	item := op.Item{
+		Fields: []op.ItemField{
+			{ID: "f1", Title: "host", Value: "dev.example.com", SectionID: &dev},
+			{ID: "f2", Title: "host", Value: "prod.example.com", SectionID: &prod},
+		},
+		Sections: []op.ItemSection{
+			{ID: dev, Title: "Development"},
+			{ID: prod, Title: "Production"},
+		},
+	}
+
+	tests := []struct {
+		section, name string
+		want          string
+		found         bool
+	}{
+		{"", "host", "dev.example.com", true},
+		{"Production", "host", "prod.example.com", true},
+		{"prod", "f2", "prod.example.com", true},
+		{"Staging", "host", "", false},
+		{"", "missing", "", false},
+	}
+
+	for _, tt := range tests {
+		field, found := findField(item, tt.section, tt.name)
+		if found != tt.found || field.Value != tt.want {
+			t.Errorf("findField(%q, %q) = %q, %v; want %q, %v", tt.section, tt.name, field.Value, found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestItemToSecret_DuplicateFields(t *testing.T) {
+	item := op.Item{
+		Fields: []op.ItemField{
+			{ID: "password", Title: "password", Value: "first", FieldType: op.ItemFieldTypeConcealed},
+			{ID: "host", Title: "host", Value: "a"},
+			{ID: "password#2", Title: "password#2", Value: "literal"},
+			{ID: "p2", Title: "password", Value: "second", FieldType: op.ItemFieldTypeConcealed},
+			{ID: "h2", Title: "host", Value: "b"},
+		},
+	}
+
+	secret := itemToSecret(item, "vault/item")
+
+	want := map[string]string{
+		"password": "first", "password#2": "literal", "password#3": "second",
+		"host": "a", "host#2": "b",
+	}
+	if !reflect.DeepEqual(secret.Fields, want) {
+		t.Errorf("Fields = %v, want %v", secret.Fields, want)
+	}
+	if secret.Value != "first" {
+		t.Errorf("Value = %q, want the first password", secret.Value)
+	}
+
+	wantDuplicates := map[string][]string{
+		"password": {"password", "password#3"},
+		"host":     {"host", "host#2"},
+	}
+	if got := secret.Metadata.Extra[DuplicateFieldsKey]; !reflect.DeepEqual(got, wantDuplicates) {
+		t.Errorf("Extra[%s] = %v, want %v", DuplicateFieldsKey, got, wantDuplicates)
+	}
+
+	if _, ok := itemToSecret(op.Item{Fields: item.Fields[:2]}, "").Metadata.Extra[DuplicateFieldsKey]; ok {
+		t.Error("Extra[DuplicateFieldsKey] set for an item without duplicates")
+	}
+}
+
+func TestMergeFields_DuplicateKey(t *testing.T) {
+	existing := []op.ItemField{
+		{ID: "host", Title: "host", Value: "a"},
+		{ID: "h2", Title: "host", Value: "b"},
+	}
+	updates := []op.ItemField{
+		{ID: "host_2", Title: "host#2", Value: "c"},
+		{ID: "host_3", Title: "host#3", Value: "d"},
+	}
+
+	merged := mergeFields(existing, updates)
+
+	var got []string
+	for _, f := range merged {
+		got = append(got, f.Title+"="+f.Value)
+	}
+	if want := []string{"host=a", "host=c", "host#3=d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeFields() = %v, want %v", got, want)
+	}
+}
+
+func TestSecretToFields_Order(t *testing.T) {
+	secret := &vault.Secret{Fields: map[string]string{"c": "3", "a": "1", "b": "2", "d": "4"}}
+
+	for range 10 {
+		var titles []string
+		for _, f := range secretToFields(secret, "") {
+			titles = append(titles, f.Title)
+		}
+		if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(titles, want) {
+			t.Fatalf("secretToFields() order = %v, want %v", titles, want)
+		}
+	}
+}
+
 func TestTagsToStrings(t *testing.T) {
 	tests := []struct {
 		name string
@@ -240,10 +449,133 @@ func TestTagsToStrings(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tagsToStrings(tt.tags)
+			got := tagsToStrings(tt.tags, DefaultTagSeparator)
 			if len(got) != tt.want {
 				t.Errorf("tagsToStrings() returned %d tags, want %d", len(got), tt.want)
 			}
 		})
 	}
 }
+
+func TestTagsToStrings_Sorted(t *testing.T) {
+	got := tagsToStrings(map[string]string{"team": "infra", "env": "prod", "urgent": ""}, DefaultTagSeparator)
+	if want := []string{"env:prod", "team:infra", "urgent"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tagsToStrings() = %v, want %v", got, want)
+	}
+}
+
+func TestFieldKeyStyle(t *testing.T) {
+	tests := []struct {
+		style FieldKeyStyle
+		want  map[string]string
+		names []string
+	}{
+		{
+			FieldKeysFlat,
+			map[string]string{"username": "admin", "password": "hunter2", "host": "db.prod", "password#2": "prodpass"},
+			[]string{"username", "password", "host", "password#2"},
+		},
+		{
+			FieldKeysQualified,
+			map[string]string{"username": "admin", "password": "hunter2", "Production.host": "db.prod", "Production.password": "prodpass"},
+			[]string{"username", "password", "Production.host", "Production.password"},
+		},
+		{
+			FieldKeysBoth,
+			map[string]string{
+				"username": "admin", "password": "hunter2", "host": "db.prod", "password#2": "prodpass",
+				"Production.host": "db.prod", "Production.password": "prodpass",
+			},
+			[]string{"username", "password", "host", "password#2", "Production.host", "Production.password"},
+		},
+	}
+	for _, tt := range tests {
+		p := newMockProvider(sectionedMockAPI(), Config{FieldKeyStyle: tt.style})
+
+		secret, err := p.Get(t.Context(), "Private/Database")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if !reflect.DeepEqual(secret.Fields, tt.want) {
+			t.Errorf("style %d: Fields = %v, want %v", tt.style, secret.Fields, tt.want)
+		}
+		if secret.Value != "hunter2" {
+			t.Errorf("style %d: Value = %q, want hunter2", tt.style, secret.Value)
+		}
+
+		meta, err := p.GetMetadata(t.Context(), "Private/Database")
+		if err != nil {
+			t.Fatalf("GetMetadata() error = %v", err)
+		}
+		if names := meta.Extra[FieldNamesKey]; !reflect.DeepEqual(names, tt.names) {
+			t.Errorf("style %d: field names = %v, want %v", tt.style, names, tt.names)
+		}
+	}
+}
+
+func TestTagParsing(t *testing.T) {
+	tags := []string{"owner:team:payments", "env=prod", "env=dev", "expires:2030-01-01", "urgent"}
+
+	tests := []struct {
+		name   string
+		config Config
+		want   map[string]string
+	}{
+		{"default", Config{}, map[string]string{
+			"owner": "team:payments", "env=prod": "", "env=dev": "", "expires": "2030-01-01", "urgent": "",
+		}},
+		{"separator", Config{TagSeparator: "="}, map[string]string{
+			"owner:team:payments": "", "env": "dev", "expires": "2030-01-01", "urgent": "",
+		}},
+		{"raw", Config{RawTags: true}, map[string]string{
+			"owner:team:payments": "", "env=prod": "", "env=dev": "", "expires:2030-01-01": "", "urgent": "",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testMockAPI()
+			m.items["v1"][0].Tags = tags
+			p := newMockProvider(m, tt.config)
+
+			secret, err := p.Get(t.Context(), "Private/Database")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if !reflect.DeepEqual(secret.Metadata.Tags, tt.want) {
+				t.Errorf("Tags = %v, want %v", secret.Metadata.Tags, tt.want)
+			}
+			if got := secret.Metadata.Extra[TagListKey]; !reflect.DeepEqual(got, tags) {
+				t.Errorf("Extra[TagListKey] = %v, want %v", got, tags)
+			}
+
+			// Writing the tags back leaves them as they were, apart from
+			// keys shared by several tags
+			secret.Fields = nil
+			if err := p.Set(t.Context(), "Private/Database/password", secret); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			want := slices.Clone(tags)
+			if tt.name == "separator" {
+				want = slices.DeleteFunc(want, func(tag string) bool { return tag == "env=prod" })
+			}
+			slices.Sort(want)
+			if got := m.put[0].Tags; !reflect.DeepEqual(got, want) {
+				t.Errorf("written tags = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTagWriting(t *testing.T) {
+	tags := map[string]string{"env": "prod", "expires": "2030-01-01", "urgent": ""}
+
+	if got, want := tagsToStrings(tags, "="), []string{"env=prod", "expires:2030-01-01", "urgent"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tagsToStrings(=) = %v, want %v", got, want)
+	}
+	if got, want := tagsToStrings(tags, ""), []string{"env:prod", "expires:2030-01-01", "urgent"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tagsToStrings(raw) = %v, want %v", got, want)
+	}
+	if got, want := mergeTags([]string{"env=dev", "expires:2029-01-01", "keep"}, tags, "="), []string{"keep", "env=prod", "expires:2030-01-01", "urgent"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeTags(=) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,268 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// DefaultCSVTagSeparator separates tags within the tags column.
+const DefaultCSVTagSeparator = ","
+
+// ColumnMap describes how ImportCSV turns CSV columns into items. Columns
+// are named by the CSV header row.
+type ColumnMap struct {
+	// Title is the column holding the item title. Required.
+	Title string
+
+	// Fields maps columns to field titles. When nil, every column other than
+	// Title, Category, and Tags becomes a field named after its header.
+	// Empty cells are skipped. Field types are chosen as in Set.
+	Fields map[string]string
+
+	// Category is the column holding the item category, e.g. "Login" or
+	// "API Credentials" (case, spaces, and underscores are ignored). Empty
+	// cells use DefaultCategory.
+	Category string
+
+	// DefaultCategory is the category for rows without one.
+	// Default: Config.DefaultCategory
+	DefaultCategory op.ItemCategory
+
+	// Tags is the column holding the item tags.
+	Tags string
+
+	// TagSeparator separates tags within the Tags column.
+	// Default: ","
+	TagSeparator string
+
+	// DryRun reports what would be created without writing to 1Password.
+	DryRun bool
+}
+
+// itemCategories are the categories accepted in the CSV category column.
+var itemCategories = []op.ItemCategory{
+	op.ItemCategoryLogin,
+	op.ItemCategorySecureNote,
+	op.ItemCategoryCreditCard,
+	op.ItemCategoryCryptoWallet,
+	op.ItemCategoryIdentity,
+	op.ItemCategoryPassword,
+	op.ItemCategoryDocument,
+	op.ItemCategoryAPICredentials,
+	op.ItemCategoryBankAccount,
+	op.ItemCategoryDatabase,
+	op.ItemCategoryDriverLicense,
+	op.ItemCategoryEmail,
+	op.ItemCategoryMedicalRecord,
+	op.ItemCategoryMembership,
+	op.ItemCategoryOutdoorLicense,
+	op.ItemCategoryPassport,
+	op.ItemCategoryRewards,
+	op.ItemCategoryRouter,
+	op.ItemCategoryServer,
+	op.ItemCategorySSHKey,
+	op.ItemCategorySocialSecurityNumber,
+	op.ItemCategorySoftwareLicense,
+	op.ItemCategoryPerson,
+}
+
+// ImportCSV creates one item per CSV row in the named vault. The first row
+// must be a header. Every row is validated before anything is written, so a
+// malformed file creates no items. Rows whose title already exists in the
+// vault are skipped. With mapping.DryRun set, the result lists what would be
+// created and skipped.
+//
+//	result, err := provider.ImportCSV(ctx, "Imported", f, onepassword.ColumnMap{
+//	    Title:  "name",
+//	    Fields: map[string]string{"login": "username", "pass": "password", "site": "website"},
+//	    Tags:   "labels",
+//	})
+func (p *Provider) ImportCSV(ctx context.Context, vaultName string, r io.Reader, mapping ColumnMap) (*ImportResult, error) {
+	if mapping.DefaultCategory == "" {
+		mapping.DefaultCategory = p.config.DefaultCategory
+	}
+	rows, err := parseCSVItems(r, mapping)
+	if err != nil {
+		return nil, vault.NewVaultError("ImportCSV", vaultName, ProviderName, err)
+	}
+	for _, row := range rows {
+		p.applyTypeRules(row.Fields)
+	}
+
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("ImportCSV", vaultName, ProviderName, vault.ErrClosed)
+	}
+
+	if err := p.refuseDryRun(ctx, "ImportCSV", vaultName); err != nil {
+		return nil, err
+	}
+
+	vaultID, err := p.resolveVaultID(ctx, vaultName)
+	if err != nil {
+		return nil, mapError("ImportCSV", vaultName, err)
+	}
+
+	result := &ImportResult{}
+	for _, params := range rows {
+		path := BuildPath(vaultName, params.Title)
+
+		_, err := p.resolveItemID(ctx, vaultID, params.Title)
+		if err == nil {
+			result.Skipped = append(result.Skipped, path)
+			continue
+		}
+		if !isNotFoundError(err) {
+			return result, mapError("ImportCSV", path, err)
+		}
+
+		if !mapping.DryRun {
+			params.VaultID = vaultID
+			if _, err := p.items.Create(ctx, params); err != nil {
+				return result, mapError("ImportCSV", path, err)
+			}
+			if err := p.mirrorItem(ctx, "ImportCSV", path, &ParsedPath{Vault: vaultName, Item: params.Title}); err != nil {
+				result.Created = append(result.Created, path)
+				return result, err
+			}
+		}
+		result.Created = append(result.Created, path)
+	}
+	return result, nil
+}
+
+// parseCSVItems reads a CSV file and converts each row to item creation
+// parameters without a vault ID. All row errors are reported together.
+func parseCSVItems(r io.Reader, mapping ColumnMap) ([]op.ItemCreateParams, error) {
+	if mapping.Title == "" {
+		return nil, errors.New("column map needs a title column")
+	}
+	if mapping.TagSeparator == "" {
+		mapping.TagSeparator = DefaultCSVTagSeparator
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("CSV has no header row")
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	fields := mapping.Fields
+	if fields == nil {
+		fields = make(map[string]string)
+		for name := range columns {
+			if name != "" && name != mapping.Title && name != mapping.Category && name != mapping.Tags {
+				fields[name] = name
+			}
+		}
+	}
+
+	for _, name := range append([]string{mapping.Title, mapping.Category, mapping.Tags}, mapKeys(fields)...) {
+		if _, ok := columns[name]; name != "" && !ok {
+			return nil, fmt.Errorf("CSV has no %q column", name)
+		}
+	}
+
+	cell := func(record []string, column string) string {
+		i, ok := columns[column]
+		if column == "" || !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var (
+		items  []op.ItemCreateParams
+		errs   []error
+		titles = make(map[string]int)
+	)
+	for n, record := range records[1:] {
+		line := n + 2
+
+		title := cell(record, mapping.Title)
+		if title == "" {
+			errs = append(errs, fmt.Errorf("line %d: empty title", line))
+			continue
+		}
+		if first, ok := titles[title]; ok {
+			errs = append(errs, fmt.Errorf("line %d: duplicate title %q (first on line %d)", line, title, first))
+			continue
+		}
+		titles[title] = line
+
+		category := mapping.DefaultCategory
+		if name := cell(record, mapping.Category); name != "" {
+			category, err = parseItemCategory(name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+				continue
+			}
+		}
+
+		values := make(map[string]string)
+		for column, field := range fields {
+			if value := cell(record, column); value != "" {
+				values[field] = value
+			}
+		}
+
+		var tags []string
+		for _, tag := range strings.Split(cell(record, mapping.Tags), mapping.TagSeparator) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+
+		items = append(items, op.ItemCreateParams{
+			Title:    title,
+			Category: category,
+			Fields:   secretToFields(&vault.Secret{Fields: values}, ""),
+			Tags:     tags,
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return items, nil
+}
+
+// parseItemCategory matches a category name, ignoring case, spaces,
+// hyphens, and underscores.
+func parseItemCategory(name string) (op.ItemCategory, error) {
+	normalize := strings.NewReplacer(" ", "", "_", "", "-", "")
+	want := strings.ToLower(normalize.Replace(name))
+	for _, category := range itemCategories {
+		if strings.ToLower(string(category)) == want {
+			return category, nil
+		}
+	}
+	return "", fmt.Errorf("unknown category %q", name)
+}
+
+// mapKeys returns the keys of m.
+func mapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
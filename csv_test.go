@@ -0,0 +1,118 @@
+package onepassword
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func TestParseCSVItems(t *testing.T) {
+	input := `name,login,pass,kind,labels,notes
+GitHub,octocat,hunter2,Login,"dev, env:prod",
+Stripe,,sk_live_x,API Credentials,,billing
+`
+	items, err := parseCSVItems(strings.NewReader(input), ColumnMap{
+		Title:           "name",
+		Fields:          map[string]string{"login": "username", "pass": "password"},
+		Category:        "kind",
+		DefaultCategory: op.ItemCategoryPassword,
+		Tags:            "labels",
+	})
+	if err != nil {
+		t.Fatalf("parseCSVItems() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+
+	tests := []struct {
+		title    string
+		category op.ItemCategory
+		fields   []string
+		tags     []string
+	}{
+		{"GitHub", op.ItemCategoryLogin, []string{"password", "username"}, []string{"dev", "env:prod"}},
+		{"Stripe", op.ItemCategoryAPICredentials, []string{"password"}, nil},
+	}
+	for i, tt := range tests {
+		item := items[i]
+		if item.Title != tt.title || item.Category != tt.category {
+			t.Errorf("item %d = %s (%s), want %s (%s)", i, item.Title, item.Category, tt.title, tt.category)
+		}
+		var fields []string
+		for _, f := range item.Fields {
+			fields = append(fields, f.Title)
+		}
+		sort.Strings(fields)
+		if !reflect.DeepEqual(fields, tt.fields) {
+			t.Errorf("item %d fields = %v, want %v", i, fields, tt.fields)
+		}
+		if !reflect.DeepEqual(item.Tags, tt.tags) {
+			t.Errorf("item %d tags = %v, want %v", i, item.Tags, tt.tags)
+		}
+	}
+}
+
+func TestParseCSVItems_AllColumns(t *testing.T) {
+	input := "title,username,api key\nsvc,bot,abc\n"
+	items, err := parseCSVItems(strings.NewReader(input), ColumnMap{Title: "title", DefaultCategory: op.ItemCategoryLogin})
+	if err != nil {
+		t.Fatalf("parseCSVItems() error = %v", err)
+	}
+
+	types := make(map[string]op.ItemFieldType)
+	for _, f := range items[0].Fields {
+		types[f.Title] = f.FieldType
+	}
+	want := map[string]op.ItemFieldType{"username": op.ItemFieldTypeText, "api key": op.ItemFieldTypeConcealed}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("fields = %v, want %v", types, want)
+	}
+}
+
+func TestParseCSVItems_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		mapping ColumnMap
+		wantErr string
+	}{
+		{"no title column", "a,b\n", ColumnMap{}, "title column"},
+		{"missing column", "name\nx\n", ColumnMap{Title: "name", Tags: "labels"}, `no "labels" column`},
+		{"empty title", "name,v\n,1\n", ColumnMap{Title: "name"}, "line 2: empty title"},
+		{"duplicate title", "name\nx\nx\n", ColumnMap{Title: "name"}, "line 3: duplicate title"},
+		{"unknown category", "name,kind\nx,Spaceship\n", ColumnMap{Title: "name", Category: "kind"}, `unknown category "Spaceship"`},
+		{"empty file", "", ColumnMap{Title: "name"}, "no header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCSVItems(strings.NewReader(tt.input), tt.mapping)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseCSVItems() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseItemCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		want op.ItemCategory
+	}{
+		{"Login", op.ItemCategoryLogin},
+		{"secure note", op.ItemCategorySecureNote},
+		{"ssh_key", op.ItemCategorySSHKey},
+		{"API-Credentials", op.ItemCategoryAPICredentials},
+	}
+
+	for _, tt := range tests {
+		got, err := parseItemCategory(tt.name)
+		if err != nil || got != tt.want {
+			t.Errorf("parseItemCategory(%q) = %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// DiffValueField is the field name used in a FieldDiff for Secret.Value.
+const DiffValueField = "(value)"
+
+// DiffOptions configures DiffWithOptions.
+type DiffOptions struct {
+	// MapPath converts a provider path to the path in the other vault.
+	// Default: the same path
+	MapPath func(path string) string
+
+	// IncludeValues adds the differing values to the report. By default
+	// only hashes are reported.
+	IncludeValues bool
+}
+
+// DiffReport lists the differences between the provider and another vault.
+// Paths are the provider's paths.
+type DiffReport struct {
+	// Added lists secrets present here but missing from the other vault.
+	Added []string
+
+	// Removed lists secrets present only in the other vault (its paths).
+	Removed []string
+
+	// Changed lists secrets present in both with different content.
+	Changed []SecretDiff
+}
+
+// HasDrift reports whether any differences were found.
+func (r *DiffReport) HasDrift() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0 || len(r.Changed) > 0
+}
+
+// SecretDiff lists the field differences of one secret.
+type SecretDiff struct {
+	// Path is the provider path; OtherPath the path in the other vault.
+	Path      string
+	OtherPath string
+
+	// Fields are the differing fields, sorted by name.
+	Fields []FieldDiff
+}
+
+// FieldDiff describes one differing field. An empty hash means the field is
+// missing on that side.
+type FieldDiff struct {
+	// Name is the field name, or DiffValueField for the primary value.
+	Name string
+
+	// Hash and OtherHash are keyed hashes of the values. The key is random
+	// per diff, so hashes can be compared within a report but not across
+	// reports, and can't be used to guess values.
+	Hash      string
+	OtherHash string
+
+	// Value and OtherValue are set only with DiffOptions.IncludeValues.
+	Value      string
+	OtherValue string
+}
+
+// Diff compares the secrets matching prefix with the same paths in other,
+// reporting which are missing on either side and which fields differ. Values
+// are reported as hashes only. Use it to detect drift before Sync:
+//
+//	report, err := provider.Diff(ctx, awsProvider, "Prod/")
+//	if report.HasDrift() { ... }
+func (p *Provider) Diff(ctx context.Context, other vault.Vault, prefix string) (*DiffReport, error) {
+	return p.DiffWithOptions(ctx, other, prefix, DiffOptions{})
+}
+
+// DiffWithOptions is Diff with options.
+func (p *Provider) DiffWithOptions(ctx context.Context, other vault.Vault, prefix string, opts DiffOptions) (*DiffReport, error) {
+	return diffVaults(ctx, p, other, prefix, opts)
+}
+
+// diffVaults implements DiffWithOptions for any pair of vaults.
+func diffVaults(ctx context.Context, v, other vault.Vault, prefix string, opts DiffOptions) (*DiffReport, error) {
+	mapPath := opts.MapPath
+	if mapPath == nil {
+		mapPath = func(p string) string { return p }
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	hash := func(value string) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(value))
+		return hex.EncodeToString(mac.Sum(nil))[:16]
+	}
+
+	paths, err := v.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	otherPaths, err := other.List(ctx, mapPath(prefix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	sort.Strings(otherPaths)
+
+	remaining := make(map[string]bool, len(otherPaths))
+	for _, path := range otherPaths {
+		remaining[path] = true
+	}
+
+	report := &DiffReport{}
+	for _, path := range paths {
+		otherPath := mapPath(path)
+		if !remaining[otherPath] {
+			report.Added = append(report.Added, path)
+			continue
+		}
+		delete(remaining, otherPath)
+
+		secret, err := v.Get(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		otherSecret, err := other.Get(ctx, otherPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if fields := diffFields(secret, otherSecret, hash, opts.IncludeValues); len(fields) > 0 {
+			report.Changed = append(report.Changed, SecretDiff{Path: path, OtherPath: otherPath, Fields: fields})
+		}
+	}
+
+	for _, path := range otherPaths {
+		if remaining[path] {
+			report.Removed = append(report.Removed, path)
+		}
+	}
+	return report, nil
+}
+
+// diffFields returns the differing fields of a and b, including the primary
+// value as DiffValueField.
+func diffFields(a, b *vault.Secret, hash func(string) string, includeValues bool) []FieldDiff {
+	fieldsOf := func(s *vault.Secret) map[string]string {
+		fields := make(map[string]string, len(s.Fields)+1)
+		for name, value := range s.Fields {
+			fields[name] = value
+		}
+		if s.Value != "" {
+			fields[DiffValueField] = s.Value
+		}
+		return fields
+	}
+	af, bf := fieldsOf(a), fieldsOf(b)
+
+	names := make(map[string]bool)
+	for name := range af {
+		names[name] = true
+	}
+	for name := range bf {
+		names[name] = true
+	}
+
+	var diffs []FieldDiff
+	for name := range names {
+		av, aok := af[name]
+		bv, bok := bf[name]
+		if aok == bok && av == bv {
+			continue
+		}
+
+		d := FieldDiff{Name: name}
+		if aok {
+			d.Hash = hash(av)
+		}
+		if bok {
+			d.OtherHash = hash(bv)
+		}
+		if includeValues {
+			d.Value, d.OtherValue = av, bv
+		}
+		diffs = append(diffs, d)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs
+}
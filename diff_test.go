@@ -0,0 +1,75 @@
+package onepassword
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agentplexus/omnivault/providers/memory"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestDiffVaults(t *testing.T) {
+	ctx := context.Background()
+	a := memory.New()
+	b := memory.New()
+
+	_ = a.Set(ctx, "Prod/only-here", &vault.Secret{Value: "x"})
+	_ = a.Set(ctx, "Prod/same", &vault.Secret{Value: "v", Fields: map[string]string{"user": "admin"}})
+	_ = a.Set(ctx, "Prod/drifted", &vault.Secret{Value: "new", Fields: map[string]string{"user": "admin", "host": "db1"}})
+
+	_ = b.Set(ctx, "Prod/same", &vault.Secret{Value: "v", Fields: map[string]string{"user": "admin"}})
+	_ = b.Set(ctx, "Prod/drifted", &vault.Secret{Value: "old", Fields: map[string]string{"user": "admin", "port": "5432"}})
+	_ = b.Set(ctx, "Prod/only-there", &vault.Secret{Value: "y"})
+
+	report, err := diffVaults(ctx, a, b, "Prod/", DiffOptions{})
+	if err != nil {
+		t.Fatalf("diffVaults() error = %v", err)
+	}
+
+	if !report.HasDrift() {
+		t.Error("HasDrift() = false, want true")
+	}
+	if !reflect.DeepEqual(report.Added, []string{"Prod/only-here"}) {
+		t.Errorf("Added = %v", report.Added)
+	}
+	if !reflect.DeepEqual(report.Removed, []string{"Prod/only-there"}) {
+		t.Errorf("Removed = %v", report.Removed)
+	}
+	if len(report.Changed) != 1 || report.Changed[0].Path != "Prod/drifted" {
+		t.Fatalf("Changed = %+v", report.Changed)
+	}
+
+	fields := report.Changed[0].Fields
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Name)
+		if f.Value != "" || f.OtherValue != "" {
+			t.Errorf("field %s includes values without IncludeValues", f.Name)
+		}
+	}
+	if !reflect.DeepEqual(names, []string{DiffValueField, "host", "port"}) {
+		t.Errorf("changed fields = %v", names)
+	}
+	if fields[1].Hash == "" || fields[1].OtherHash != "" {
+		t.Errorf("host diff = %+v, want hash only on this side", fields[1])
+	}
+}
+
+func TestDiffVaults_IncludeValues(t *testing.T) {
+	ctx := context.Background()
+	a := memory.NewWithSecrets(map[string]string{"p": "new"})
+	b := memory.NewWithSecrets(map[string]string{"p": "old"})
+
+	report, err := diffVaults(ctx, a, b, "", DiffOptions{IncludeValues: true})
+	if err != nil {
+		t.Fatalf("diffVaults() error = %v", err)
+	}
+	if len(report.Changed) != 1 {
+		t.Fatalf("Changed = %+v", report.Changed)
+	}
+	f := report.Changed[0].Fields[0]
+	if f.Value != "new" || f.OtherValue != "old" || f.Hash == f.OtherHash {
+		t.Errorf("field diff = %+v", f)
+	}
+}
@@ -0,0 +1,294 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// DefaultCacheMaxAge is how long secrets in the disk cache may be served.
+const DefaultCacheMaxAge = 24 * time.Hour
+
+// CachedAtKey is the Secret.Metadata.Extra key set, to the time the secret
+// was cached, on secrets Get served from the disk cache.
+const CachedAtKey = "cachedAt"
+
+// diskCacheExt is the file extension of disk cache files.
+const diskCacheExt = ".cache"
+
+// diskCache keeps secrets read by Get encrypted on disk, one file per item,
+// so they can be served while 1Password is unavailable. File names are
+// keyed hashes of the vault and item names, and contents are sealed with
+// AES-256-GCM, so neither names nor values are readable without the key.
+type diskCache struct {
+	dir    string
+	maxAge time.Duration
+	aead   cipher.AEAD
+	macKey []byte
+	now    func() time.Time
+
+	mu sync.Mutex
+}
+
+// diskEntry is a cached secret.
+type diskEntry struct {
+	Secret  *vault.Secret `json:"secret"`
+	SavedAt time.Time     `json:"savedAt"`
+}
+
+// newDiskCache returns a disk cache in config.CacheDir keyed by
+// config.CacheKey, or by the service account token if no key is set.
+func newDiskCache(config Config) (*diskCache, error) {
+	secret := config.CacheKey
+	if len(secret) == 0 {
+		token := config.ServiceAccountToken
+		if token == "" {
+			token = os.Getenv(EnvServiceAccountToken)
+		}
+		secret = []byte(token)
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("disk cache requires Config.CacheKey or a service account token")
+	}
+
+	keys, err := hkdf.Key(sha256.New, secret, nil, "omnivault-onepassword disk cache", 64)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(keys[:32])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &diskCache{dir: config.CacheDir, maxAge: config.CacheMaxAge, aead: aead, macKey: keys[32:], now: time.Now}, nil
+}
+
+// file returns the name of the cache file of the item addressed by parsed.
+func (c *diskCache) file(parsed *ParsedPath) string {
+	mac := hmac.New(sha256.New, c.macKey)
+	mac.Write([]byte(parsed.Vault + "\x00" + parsed.Item))
+	return hex.EncodeToString(mac.Sum(nil)) + diskCacheExt
+}
+
+// get returns the secret cached under key for the item of parsed, if it
+// isn't older than the maximum age.
+func (c *diskCache) get(parsed *ParsedPath, key string) (*vault.Secret, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.read(c.file(parsed))
+	if err != nil {
+		return nil, false
+	}
+	entry, ok := entries[key]
+	if !ok || c.now().Sub(entry.SavedAt) > c.maxAge {
+		return nil, false
+	}
+
+	secret := entry.Secret
+	if secret.Metadata.Extra == nil {
+		secret.Metadata.Extra = make(map[string]any)
+	}
+	secret.Metadata.Extra[CachedAtKey] = entry.SavedAt
+	return secret, true
+}
+
+// put caches secret under key for the item of parsed, dropping entries
+// past the maximum age.
+func (c *diskCache) put(parsed *ParsedPath, key string, secret *vault.Secret) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := c.file(parsed)
+	entries, err := c.read(name)
+	if err != nil {
+		entries = make(map[string]diskEntry)
+	}
+	now := c.now()
+	for k, entry := range entries {
+		if now.Sub(entry.SavedAt) > c.maxAge {
+			delete(entries, k)
+		}
+	}
+	entries[key] = diskEntry{Secret: secret, SavedAt: now}
+	return c.write(name, entries)
+}
+
+// remove drops the cached secrets of the item of parsed.
+func (c *diskCache) remove(parsed *ParsedPath) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := os.Remove(filepath.Join(c.dir, c.file(parsed)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// clear removes every cache file.
+func (c *diskCache) clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files, err := os.ReadDir(c.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), diskCacheExt) {
+			if err := os.Remove(filepath.Join(c.dir, f.Name())); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// read decrypts the cache file with the given name.
+func (c *diskCache) read(name string) (map[string]diskEntry, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, name)) //nolint:gosec // G304: name is a hex digest within the cache directory
+	if err != nil {
+		return nil, err
+	}
+	size := c.aead.NonceSize()
+	if len(data) < size {
+		return nil, errors.New("cache file truncated")
+	}
+	plaintext, err := c.aead.Open(nil, data[:size], data[size:], []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("cache file cannot be decrypted: %w", err)
+	}
+
+	var entries map[string]diskEntry
+	if err := json.Unmarshal(plaintext, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// write encrypts entries into the cache file with the given name. The file
+// is replaced atomically so readers never see a partial write.
+func (c *diskCache) write(name string, entries map[string]diskEntry) error {
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data := c.aead.Seal(nonce, nonce, plaintext, []byte(name))
+
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, name+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // already renamed on success
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() //nolint:errcheck,gosec // the write error is reported
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, name))
+}
+
+// isUnavailable reports whether err means 1Password couldn't be reached or
+// didn't answer, rather than answering with a definite failure such as a
+// missing secret or a rejected token.
+func isUnavailable(err error) bool {
+	for _, definite := range []error{
+		context.Canceled, vault.ErrSecretNotFound, vault.ErrInvalidPath, vault.ErrClosed,
+		ErrAuth, ErrAmbiguous, ErrSecretExpired,
+	} {
+		if errors.Is(err, definite) {
+			return false
+		}
+	}
+	return true
+}
+
+// cachedGet serves a Get from the disk cache when 1Password is unavailable,
+// and caches successful reads. Computed TOTP codes aren't cached.
+func (p *Provider) cachedGet(ctx context.Context, parsed ParsedPath, secret *vault.Secret, err error) (*vault.Secret, error) {
+	if p.diskCache == nil || parsed.Attribute == AttributeTOTP {
+		return secret, err
+	}
+
+	// Item and field reads of the same path are distinct entries
+	key := "field:" + parsed.String()
+	if parsed.Field == "" {
+		key = "item:" + parsed.String()
+	}
+
+	if err == nil {
+		if err := p.diskCache.put(&parsed, key, secret); err != nil {
+			p.logWarn(ctx, "disk cache write failed", "path", parsed.String(), "error", err)
+		}
+		return secret, nil
+	}
+
+	if !isUnavailable(err) {
+		return nil, err
+	}
+	cached, ok := p.diskCache.get(&parsed, key)
+	if !ok {
+		return nil, err
+	}
+	if p.config.EnforceExpiry && cached.Metadata.ExpiresAt != nil && !p.diskCache.now().Before(cached.Metadata.ExpiresAt.Time) {
+		return nil, err
+	}
+	p.logDebug(ctx, "serving secret from disk cache", "path", parsed.String(), "error", err)
+	return cached, nil
+}
+
+// invalidateDiskCache drops the disk cache entries of the item of parsed
+// before a write.
+func (p *Provider) invalidateDiskCache(ctx context.Context, parsed *ParsedPath) {
+	if p.diskCache == nil {
+		return
+	}
+	if err := p.diskCache.remove(parsed); err != nil {
+		p.logWarn(ctx, "disk cache invalidation failed", "path", parsed.String(), "error", err)
+	}
+}
+
+// ClearDiskCache deletes every secret in the disk cache. It does nothing
+// if Config.CacheDir isn't set.
+func (p *Provider) ClearDiskCache() error {
+	if p.diskCache == nil {
+		return nil
+	}
+	if err := p.diskCache.clear(); err != nil {
+		return vault.NewVaultError("ClearDiskCache", "", ProviderName, err)
+	}
+	return nil
+}
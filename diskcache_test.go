@@ -0,0 +1,172 @@
+package onepassword
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func newDiskCacheProvider(t *testing.T, m *mockAPI, key string) *Provider {
+	t.Helper()
+	return newMockProvider(m, Config{CacheDir: t.TempDir(), CacheKey: []byte(key)})
+}
+
+func TestDiskCache_ServesWhenUnavailable(t *testing.T) {
+	ctx := t.Context()
+	m := testMockAPI()
+	p := newDiskCacheProvider(t, m, "key")
+
+	for _, path := range []string{"Private/Database/username", "Private/Database"} {
+		if _, err := p.Get(ctx, path); err != nil {
+			t.Fatalf("Get(%q) error = %v", path, err)
+		}
+	}
+
+	m.err = errors.New("connection refused")
+	secret, err := p.Get(ctx, "Private/Database/username")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if secret.Value != "admin" {
+		t.Errorf("Value = %q, want admin", secret.Value)
+	}
+	if _, ok := secret.Metadata.Extra[CachedAtKey].(time.Time); !ok {
+		t.Errorf("Extra[%q] = %v, want cache time", CachedAtKey, secret.Metadata.Extra[CachedAtKey])
+	}
+
+	item, err := p.Get(ctx, "Private/Database")
+	if err != nil {
+		t.Fatalf("Get() item error = %v", err)
+	}
+	if item.Fields["password"] != "hunter2" {
+		t.Errorf("Fields = %v", item.Fields)
+	}
+
+	// Never read, so not cached
+	if _, err := p.Get(ctx, "Private/Database/password"); err == nil {
+		t.Error("Get() of uncached field succeeded")
+	}
+}
+
+func TestDiskCache_DefiniteErrors(t *testing.T) {
+	ctx := t.Context()
+	m := testMockAPI()
+	p := newDiskCacheProvider(t, m, "key")
+
+	if _, err := p.Get(ctx, "Private/Database/username"); err != nil {
+		t.Fatal(err)
+	}
+
+	m.err = errors.New("fieldNotFound")
+	if _, err := p.Get(ctx, "Private/Database/username"); err == nil {
+		t.Error("Get() served a cached secret for a not found error")
+	}
+}
+
+func TestDiskCache_MaxAge(t *testing.T) {
+	ctx := t.Context()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{CacheDir: t.TempDir(), CacheKey: []byte("key"), CacheMaxAge: time.Hour})
+
+	now := time.Now()
+	p.diskCache.now = func() time.Time { return now }
+	if _, err := p.Get(ctx, "Private/Database/username"); err != nil {
+		t.Fatal(err)
+	}
+
+	m.err = errors.New("connection refused")
+	now = now.Add(2 * time.Hour)
+	if _, err := p.Get(ctx, "Private/Database/username"); err == nil {
+		t.Error("Get() served an entry past CacheMaxAge")
+	}
+}
+
+func TestDiskCache_Encrypted(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{CacheDir: dir, CacheKey: []byte("key")})
+
+	if _, err := p.Get(ctx, "Private/Database"); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+diskCacheExt))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("cache files = %v, %v; want one", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, plain := range []string{"hunter2", "Database"} {
+		if bytes.Contains(data, []byte(plain)) || bytes.Contains([]byte(files[0]), []byte(plain)) {
+			t.Errorf("cache file exposes %q", plain)
+		}
+	}
+
+	// Another key can't read the cache
+	other := newMockProvider(m, Config{CacheDir: dir, CacheKey: []byte("other")})
+	m.err = errors.New("connection refused")
+	if _, err := other.Get(ctx, "Private/Database"); err == nil {
+		t.Error("Get() with another key served a cached secret")
+	}
+}
+
+func TestDiskCache_Invalidation(t *testing.T) {
+	ctx := t.Context()
+	m := testMockAPI()
+	p := newDiskCacheProvider(t, m, "key")
+
+	read := func() {
+		t.Helper()
+		m.err = nil
+		if _, err := p.Get(ctx, "Private/Database/username"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cached := func() bool {
+		_, ok := p.diskCache.get(&ParsedPath{Vault: "Private", Item: "Database"}, "field:Private/Database/username")
+		return ok
+	}
+
+	read()
+	if err := p.Set(ctx, "Private/Database/username", &vault.Secret{Value: "root"}); err != nil {
+		t.Fatal(err)
+	}
+	if cached() {
+		t.Error("Set left the item cached")
+	}
+
+	read()
+	if err := p.Delete(ctx, "Private/Database"); err != nil {
+		t.Fatal(err)
+	}
+	if cached() {
+		t.Error("Delete left the item cached")
+	}
+
+	read()
+	if err := p.ClearDiskCache(); err != nil {
+		t.Fatal(err)
+	}
+	if cached() {
+		t.Error("ClearDiskCache left the item cached")
+	}
+}
+
+func TestDiskCache_RequiresKey(t *testing.T) {
+	t.Setenv(EnvServiceAccountToken, "")
+	p := newMockProvider(testMockAPI(), Config{CacheDir: t.TempDir()})
+	if p.diskCache != nil {
+		t.Error("disk cache enabled without a key")
+	}
+	if err := p.ClearDiskCache(); err != nil {
+		t.Errorf("ClearDiskCache() error = %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// Field titles searched, in order, by DockerConfig.
+var (
+	dockerUsernameFields = []string{"username", "user", "login"}
+	dockerPasswordFields = []string{"password", "token", "credential", "secret"}
+)
+
+// dockerConfig is the subset of ~/.docker/config.json written by DockerConfig.
+type dockerConfig struct {
+	Auths map[string]dockerAuth `json:"auths"`
+}
+
+// dockerAuth holds the credentials for one registry.
+type dockerAuth struct {
+	Auth string `json:"auth"`
+}
+
+// DockerConfig renders a Docker config.json granting access to registries.
+// registries maps each registry host to the item holding its credentials.
+// The username is read from the first field titled "username", "user", or
+// "login" and the password from "password", "token", "credential", or
+// "secret" (case-insensitive).
+//
+//	config, err := provider.DockerConfig(ctx, map[string]string{
+//	    "ghcr.io":                   "CI/GitHub Packages",
+//	    "registry.example.com:5000": "CI/Internal Registry",
+//	})
+//	// write config to $DOCKER_CONFIG/config.json
+func (p *Provider) DockerConfig(ctx context.Context, registries map[string]string) ([]byte, error) {
+	config := dockerConfig{Auths: make(map[string]dockerAuth, len(registries))}
+
+	for registry, itemPath := range registries {
+		secret, err := p.Get(ctx, itemPath)
+		if err != nil {
+			return nil, err
+		}
+
+		auth, err := dockerAuthFromFields(secret.Fields)
+		if err != nil {
+			return nil, vault.NewVaultError("DockerConfig", itemPath, ProviderName, err)
+		}
+		config.Auths[registry] = auth
+	}
+
+	return json.MarshalIndent(config, "", "\t")
+}
+
+// dockerAuthFromFields encodes the username and password found in fields.
+func dockerAuthFromFields(fields map[string]string) (dockerAuth, error) {
+	username, ok := lookupField(fields, dockerUsernameFields)
+	if !ok {
+		return dockerAuth{}, fmt.Errorf("%w: no username field (tried %s)",
+			vault.ErrSecretNotFound, strings.Join(dockerUsernameFields, ", "))
+	}
+	password, ok := lookupField(fields, dockerPasswordFields)
+	if !ok {
+		return dockerAuth{}, fmt.Errorf("%w: no password field (tried %s)",
+			vault.ErrSecretNotFound, strings.Join(dockerPasswordFields, ", "))
+	}
+
+	return dockerAuth{
+		Auth: base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+	}, nil
+}
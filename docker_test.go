@@ -0,0 +1,56 @@
+package onepassword
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestDockerAuthFromFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "login item",
+			fields: map[string]string{"username": "bot", "password": "pw"},
+			want:   "Ym90OnB3",
+		},
+		{
+			name:   "token field, case-insensitive",
+			fields: map[string]string{"User": "bot", "Token": "pw"},
+			want:   "Ym90OnB3",
+		},
+		{
+			name:    "missing password",
+			fields:  map[string]string{"username": "bot"},
+			wantErr: true,
+		},
+		{
+			name:    "missing username",
+			fields:  map[string]string{"password": "pw"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dockerAuthFromFields(tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("dockerAuthFromFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, vault.ErrSecretNotFound) {
+					t.Errorf("error = %v, want ErrSecretNotFound", err)
+				}
+				return
+			}
+			if got.Auth != tt.want {
+				t.Errorf("Auth = %q, want %q", got.Auth, tt.want)
+			}
+		})
+	}
+}
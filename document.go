@@ -0,0 +1,99 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// DocumentFilenameField is the field holding a document's file name.
+const DocumentFilenameField = "filename"
+
+// errNativeDocument explains why GetDocument can't read 1Password Document
+// items.
+var errNativeDocument = fmt.Errorf("%w: the 1Password SDK cannot read the file of a Document item", vault.ErrNotSupported)
+
+// Document is a file stored by CreateDocument.
+type Document struct {
+	// Filename is the name of the file.
+	Filename string
+
+	// Content is the content of the file.
+	Content []byte
+
+	// Metadata is the metadata of the item, as Get reports it.
+	Metadata vault.Metadata
+}
+
+// CreateDocument stores a file in a new item titled title in vaultName. It
+// fails with vault.ErrAlreadyExists if the item exists.
+//
+// The 1Password SDK can't upload files yet, so the content is stored the
+// way SetBytes stores data, in the BytesField fields of an item of
+// Config.DefaultCategory, with the file name in DocumentFilenameField. The
+// item is not a 1Password Document item.
+//
+//	err := provider.CreateDocument(ctx, "Infra", "prod kubeconfig", "kubeconfig.yaml", data)
+func (p *Provider) CreateDocument(ctx context.Context, vaultName, title, filename string, content []byte) error {
+	return p.writeBytes(ctx, "CreateDocument", BuildPath(vaultName, title), bytes.NewReader(content), nil, documentFields(filename), newItem)
+}
+
+// ReplaceDocument replaces the content of the document in the item at
+// path ("vault/item"), and its file name unless filename is empty. It
+// fails with vault.ErrSecretNotFound if the item doesn't exist.
+func (p *Provider) ReplaceDocument(ctx context.Context, path, filename string, content []byte) error {
+	return p.writeBytes(ctx, "ReplaceDocument", path, bytes.NewReader(content), nil, documentFields(filename), existingItem)
+}
+
+// GetDocument reads the document stored in the item at path ("vault/item")
+// by CreateDocument. It fails with vault.ErrNotSupported for 1Password
+// Document items, whose files the SDK can't read yet.
+func (p *Provider) GetDocument(ctx context.Context, path string) (*Document, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("GetDocument", path, ProviderName, vault.ErrClosed)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return nil, vault.NewVaultError("GetDocument", path, ProviderName, err)
+	}
+	item, err := p.fetchItem(ctx, parsed)
+	if err != nil {
+		return nil, mapError("GetDocument", path, err)
+	}
+
+	content, err := decodeBytes(item, parsed.Section, bytesFieldName(parsed))
+	if errors.Is(err, ErrFieldNotFound) && item.Category == op.ItemCategoryDocument {
+		err = errNativeDocument
+	}
+	if err != nil {
+		return nil, vault.NewVaultError("GetDocument", path, ProviderName, err)
+	}
+
+	doc := &Document{Content: content, Metadata: p.toSecret(item, parsed.String()).Metadata}
+	if field, ok := findField(item, "", DocumentFilenameField); ok {
+		doc.Filename = field.Value
+	}
+	return doc, nil
+}
+
+// documentFields returns the fields recording a document's file name, if
+// given.
+func documentFields(filename string) []op.ItemField {
+	if filename == "" {
+		return nil
+	}
+	return []op.ItemField{{
+		ID:        DocumentFilenameField,
+		Title:     DocumentFilenameField,
+		Value:     filename,
+		FieldType: op.ItemFieldTypeText,
+	}}
+}
@@ -0,0 +1,54 @@
+package onepassword
+
+import (
+	"errors"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestDocument(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+	content := []byte("apiVersion: v1\nkind: Config\n")
+
+	if err := p.CreateDocument(t.Context(), "Private", "kubeconfig", "config.yaml", content); err != nil {
+		t.Fatalf("CreateDocument() error = %v", err)
+	}
+	if err := p.CreateDocument(t.Context(), "Private", "Database", "db.txt", content); !errors.Is(err, vault.ErrAlreadyExists) {
+		t.Errorf("CreateDocument(existing) error = %v, want vault.ErrAlreadyExists", err)
+	}
+	if err := p.ReplaceDocument(t.Context(), "Private/missing", "", content); !errors.Is(err, vault.ErrSecretNotFound) {
+		t.Errorf("ReplaceDocument(missing) error = %v, want vault.ErrSecretNotFound", err)
+	}
+
+	m.items["v1"] = append(m.items["v1"], op.Item{ID: "i3", Title: "kubeconfig", VaultID: "v1", Fields: m.created[0].Fields})
+	doc, err := p.GetDocument(t.Context(), "Private/kubeconfig")
+	if err != nil {
+		t.Fatalf("GetDocument() error = %v", err)
+	}
+	if doc.Filename != "config.yaml" || string(doc.Content) != string(content) {
+		t.Errorf("GetDocument() = %q, %q", doc.Filename, doc.Content)
+	}
+
+	// Replacing keeps the file name unless a new one is given
+	if err := p.ReplaceDocument(t.Context(), "Private/kubeconfig", "", []byte("new")); err != nil {
+		t.Fatalf("ReplaceDocument() error = %v", err)
+	}
+	m.items["v1"][1] = m.put[0]
+	if doc, err := p.GetDocument(t.Context(), "Private/kubeconfig"); err != nil || doc.Filename != "config.yaml" || string(doc.Content) != "new" {
+		t.Errorf("GetDocument() after replace = %+v, %v", doc, err)
+	}
+	if err := p.ReplaceDocument(t.Context(), "Private/kubeconfig", "prod.yaml", []byte("new")); err != nil {
+		t.Fatalf("ReplaceDocument() error = %v", err)
+	}
+	if names := fieldKeys(m.put[1].Fields); len(names) != 4 {
+		t.Errorf("fields after renaming = %v, want data, size, checksum, and one filename", names)
+	}
+
+	m.items["v1"] = append(m.items["v1"], op.Item{ID: "i4", Title: "scan", VaultID: "v1", Category: op.ItemCategoryDocument})
+	if _, err := p.GetDocument(t.Context(), "Private/scan"); !errors.Is(err, vault.ErrNotSupported) {
+		t.Errorf("GetDocument(Document item) error = %v, want vault.ErrNotSupported", err)
+	}
+}
@@ -0,0 +1,157 @@
+package onepassword
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ImportDotenv reads KEY=VALUE lines from r and stores them as fields of the
+// item at itemPath, creating the item if needed. Fields already on the item
+// that are not in the file are kept.
+//
+// Blank lines, comments, an optional "export " prefix, single-quoted
+// (literal) values, and double-quoted values with \n, \", and \\ escapes are
+// supported.
+//
+//	f, _ := os.Open(".env")
+//	err := provider.ImportDotenv(ctx, "Dev/my-service", f)
+func (p *Provider) ImportDotenv(ctx context.Context, itemPath string, r io.Reader) error {
+	fields, err := parseDotenv(r)
+	if err != nil {
+		return vault.NewVaultError("ImportDotenv", itemPath, ProviderName, err)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return p.Set(ctx, itemPath, &vault.Secret{Fields: fields})
+}
+
+// ExportDotenv writes the fields of the item at path to w as KEY=VALUE lines,
+// sorted by key. Values that need it are double-quoted.
+//
+//	err := provider.ExportDotenv(ctx, "Dev/my-service", os.Stdout)
+func (p *Provider) ExportDotenv(ctx context.Context, path string, w io.Writer) error {
+	secret, err := p.Get(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := writeDotenv(w, secret.Fields); err != nil {
+		return vault.NewVaultError("ExportDotenv", path, ProviderName, err)
+	}
+	return nil
+}
+
+// parseDotenv parses dotenv-formatted input into a map.
+func parseDotenv(r io.Reader) (map[string]string, error) {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, raw, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNum)
+		}
+
+		value, err := parseDotenvValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		fields[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// parseDotenvValue unquotes a dotenv value and strips trailing comments from
+// unquoted values.
+func parseDotenvValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	switch raw[0] {
+	case '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single-quoted value")
+		}
+		return raw[1 : end+1], nil
+
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(raw); i++ {
+			c := raw[i]
+			switch {
+			case c == '"':
+				return b.String(), nil
+			case c == '\\' && i+1 < len(raw):
+				i++
+				switch raw[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(raw[i])
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated double-quoted value")
+	}
+
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = strings.TrimSpace(raw[:i])
+	}
+	return raw, nil
+}
+
+// writeDotenv writes fields as sorted KEY=VALUE lines.
+func writeDotenv(w io.Writer, fields map[string]string) error {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if key == "" || strings.ContainsAny(key, "= \t\r\n#") {
+			return fmt.Errorf("field %q is not a valid dotenv key", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", key, quoteDotenvValue(fields[key])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quoteDotenvValue double-quotes value if it contains characters that would
+// not survive parseDotenv unquoted.
+func quoteDotenvValue(value string) string {
+	if value == "" || !strings.ContainsAny(value, " \t\r\n#\"'\\$`") {
+		return value
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(value) + `"`
+}
@@ -0,0 +1,90 @@
+package onepassword
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	input := `# database
+DB_HOST=localhost
+export DB_PORT=5432
+DB_PASSWORD="p@ss \"word\"\nline2"
+RAW='literal \n $value'
+COMMENTED=value # trailing comment
+EMPTY=
+  SPACED = padded  
+`
+	want := map[string]string{
+		"DB_HOST":     "localhost",
+		"DB_PORT":     "5432",
+		"DB_PASSWORD": "p@ss \"word\"\nline2",
+		"RAW":         `literal \n $value`,
+		"COMMENTED":   "value",
+		"EMPTY":       "",
+		"SPACED":      "padded",
+	}
+
+	got, err := parseDotenv(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseDotenv() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDotenv() = %q, want %q", got, want)
+	}
+}
+
+func TestParseDotenv_Invalid(t *testing.T) {
+	tests := []string{
+		"NO_EQUALS",
+		"=value",
+		"BAD KEY=value",
+		`UNTERMINATED="value`,
+		"UNTERMINATED='value",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if _, err := parseDotenv(strings.NewReader(input)); err == nil {
+				t.Errorf("parseDotenv(%q) should fail", input)
+			}
+		})
+	}
+}
+
+func TestDotenv_RoundTrip(t *testing.T) {
+	fields := map[string]string{
+		"PLAIN":   "value",
+		"SPACES":  "a b c",
+		"QUOTES":  `say "hi" it's`,
+		"NEWLINE": "line1\nline2",
+		"HASH":    "abc #def",
+		"BACKSL":  `C:\path`,
+		"EMPTY":   "",
+	}
+
+	var buf bytes.Buffer
+	if err := writeDotenv(&buf, fields); err != nil {
+		t.Fatalf("writeDotenv() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "BACKSL=") {
+		t.Errorf("writeDotenv() output not sorted:\n%s", buf.String())
+	}
+
+	got, err := parseDotenv(&buf)
+	if err != nil {
+		t.Fatalf("parseDotenv() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, fields) {
+		t.Errorf("round trip = %q, want %q", got, fields)
+	}
+}
+
+func TestWriteDotenv_InvalidKey(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeDotenv(&buf, map[string]string{"api key": "x"}); err == nil {
+		t.Error("writeDotenv() should reject keys with spaces")
+	}
+}
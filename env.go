@@ -0,0 +1,91 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// resolveFunc resolves a secret reference to its value.
+type resolveFunc func(ctx context.Context, ref string) (string, error)
+
+// resolveValue resolves a reference with Get and returns the secret value.
+func (p *Provider) resolveValue(ctx context.Context, ref string) (string, error) {
+	secret, err := p.Get(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return secret.Value, nil
+}
+
+// resolveAll resolves each distinct reference once. All failures are
+// reported together.
+func resolveAll(ctx context.Context, refs []string, resolve resolveFunc) (map[string]string, error) {
+	values := make(map[string]string, len(refs))
+	var errs []error
+	for _, ref := range refs {
+		if _, done := values[ref]; done {
+			continue
+		}
+		value, err := resolve(ctx, ref)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		values[ref] = value
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return values, nil
+}
+
+// LoadEnv resolves each secret path in mapping and sets the environment
+// variable it is keyed by. No variables are set unless every path resolves.
+//
+//	err := provider.LoadEnv(ctx, map[string]string{
+//	    "DATABASE_PASSWORD": "Prod/Database/password",
+//	    "STRIPE_KEY":        "op://Payments/Stripe/secret key",
+//	})
+func (p *Provider) LoadEnv(ctx context.Context, mapping map[string]string) error {
+	return loadEnv(ctx, mapping, p.resolveValue)
+}
+
+// ResolveEnviron replaces every environment variable whose value is an op://
+// reference with the referenced secret, the equivalent of `op run` without
+// the 1Password CLI:
+//
+//	DATABASE_PASSWORD=op://Prod/Database/password ./server
+//
+// No variables are changed unless every reference resolves.
+func (p *Provider) ResolveEnviron(ctx context.Context) error {
+	mapping := make(map[string]string)
+	for _, kv := range os.Environ() {
+		name, value, ok := strings.Cut(kv, "=")
+		if ok && name != "" && strings.HasPrefix(value, "op://") {
+			mapping[name] = value
+		}
+	}
+	return loadEnv(ctx, mapping, p.resolveValue)
+}
+
+// loadEnv implements LoadEnv with the given resolver.
+func loadEnv(ctx context.Context, mapping map[string]string, resolve resolveFunc) error {
+	refs := make([]string, 0, len(mapping))
+	for _, ref := range mapping {
+		refs = append(refs, ref)
+	}
+
+	values, err := resolveAll(ctx, refs, resolve)
+	if err != nil {
+		return err
+	}
+
+	for name, ref := range mapping {
+		if err := os.Setenv(name, values[ref]); err != nil {
+			return fmt.Errorf("failed to set %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestLoadEnv(t *testing.T) {
+	calls := 0
+	resolve := func(_ context.Context, ref string) (string, error) {
+		calls++
+		switch ref {
+		case "Prod/Database/password":
+			return "hunter2", nil
+		case "op://Work/API/token":
+			return "tok", nil
+		}
+		return "", errors.New("not found: " + ref)
+	}
+
+	t.Setenv("TEST_DB_PASSWORD", "")
+	t.Setenv("TEST_DB_PASSWORD_COPY", "")
+	t.Setenv("TEST_API_TOKEN", "")
+
+	err := loadEnv(context.Background(), map[string]string{
+		"TEST_DB_PASSWORD":      "Prod/Database/password",
+		"TEST_DB_PASSWORD_COPY": "Prod/Database/password",
+		"TEST_API_TOKEN":        "op://Work/API/token",
+	}, resolve)
+	if err != nil {
+		t.Fatalf("loadEnv() error = %v", err)
+	}
+
+	if got := os.Getenv("TEST_DB_PASSWORD"); got != "hunter2" {
+		t.Errorf("TEST_DB_PASSWORD = %q, want 'hunter2'", got)
+	}
+	if got := os.Getenv("TEST_DB_PASSWORD_COPY"); got != "hunter2" {
+		t.Errorf("TEST_DB_PASSWORD_COPY = %q, want 'hunter2'", got)
+	}
+	if got := os.Getenv("TEST_API_TOKEN"); got != "tok" {
+		t.Errorf("TEST_API_TOKEN = %q, want 'tok'", got)
+	}
+	if calls != 2 {
+		t.Errorf("resolve called %d times, want 2", calls)
+	}
+}
+
+func TestLoadEnv_Failure(t *testing.T) {
+	resolve := func(_ context.Context, ref string) (string, error) {
+		if ref == "Prod/Missing/x" {
+			return "", errors.New("not found")
+		}
+		return "value", nil
+	}
+
+	t.Setenv("TEST_OK", "op://Prod/OK/x")
+	t.Setenv("TEST_MISSING", "op://Prod/Missing/x")
+
+	err := loadEnv(context.Background(), map[string]string{
+		"TEST_OK":      "Prod/OK/x",
+		"TEST_MISSING": "Prod/Missing/x",
+	}, resolve)
+	if err == nil {
+		t.Fatal("loadEnv() should fail when a reference doesn't resolve")
+	}
+	if got := os.Getenv("TEST_OK"); got != "op://Prod/OK/x" {
+		t.Errorf("TEST_OK = %q; no variables should change on failure", got)
+	}
+}
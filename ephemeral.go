@@ -0,0 +1,93 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// EphemeralTag marks items written by SetEphemeral, so CleanupExpired only
+// deletes items that were meant to be short-lived.
+const EphemeralTag = "ephemeral"
+
+// SetEphemeral stores a secret like Set and tags its item as ephemeral and
+// expiring after ttl ("expires:<RFC 3339 time>"). Other tags on an existing
+// item are kept. CleanupExpired deletes the item once it has expired, and
+// Get refuses it meanwhile with Config.EnforceExpiry:
+//
+//	err := provider.SetEphemeral(ctx, "CI/run-1234", secret, time.Hour)
+func (p *Provider) SetEphemeral(ctx context.Context, path string, secret *vault.Secret, ttl time.Duration) error {
+	if ttl <= 0 {
+		return vault.NewVaultError("SetEphemeral", path, ProviderName, fmt.Errorf("ttl must be positive, got %s", ttl))
+	}
+
+	tagged := *secret
+	tagged.Metadata.Tags = maps.Clone(secret.Metadata.Tags)
+	if tagged.Metadata.Tags == nil {
+		tagged.Metadata.Tags = make(map[string]string)
+	}
+	tagged.Metadata.Tags[EphemeralTag] = ""
+	tagged.Metadata.Tags[ExpiresKey] = time.Now().Add(ttl).UTC().Format(time.RFC3339)
+
+	return p.SetWithOptions(ctx, path, &tagged, SetOptions{Tags: TagsMerge})
+}
+
+// CleanupExpired deletes the ephemeral items (see SetEphemeral) under
+// prefix, which may be empty for all vaults, whose expiry
+// has passed. It returns the paths of the items deleted. Each item under
+// prefix is fetched to read its tags. Failures don't stop the other
+// deletions; they are returned joined.
+//
+// Run it periodically, e.g. at the start of a CI job:
+//
+//	deleted, err := provider.CleanupExpired(ctx, "CI/")
+func (p *Provider) CleanupExpired(ctx context.Context, prefix string) ([]string, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("CleanupExpired", prefix, ProviderName, vault.ErrClosed)
+	}
+
+	wantVault, wantItem, err := listMatcher(prefix, false)
+	if err != nil {
+		return nil, vault.NewVaultError("CleanupExpired", prefix, ProviderName, err)
+	}
+
+	listCtx, cancel := withTimeout(ctx, p.config.Timeouts.List)
+	defer cancel()
+
+	now := time.Now()
+	var expired []ItemInfo
+	var fetchErr error
+	err = p.walkItems(listCtx, wantVault, func(v op.VaultOverview, overview op.ItemOverview) bool {
+		if !wantItem(v, overview) {
+			return true
+		}
+		item, err := p.items.Get(listCtx, v.ID, overview.ID)
+		if err != nil {
+			if isNotFoundError(err) {
+				return true
+			}
+			fetchErr = err
+			return false
+		}
+		if !slices.Contains(item.Tags, EphemeralTag) {
+			return true
+		}
+		if expires, ok := itemExpiry(item); ok && !now.Before(expires) {
+			expired = append(expired, newItemInfo(v, overview))
+		}
+		return true
+	})
+	if err == nil {
+		err = fetchErr
+	}
+	if err != nil {
+		return nil, mapError("CleanupExpired", prefix, err)
+	}
+
+	return p.deleteItems(ctx, "CleanupExpired", expired, BulkDeleteOptions{Confirm: true})
+}
@@ -0,0 +1,80 @@
+package onepassword
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestProvider_SetEphemeral(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	secret := &vault.Secret{Value: "token", Metadata: vault.Metadata{Tags: map[string]string{"team": "ci"}}}
+	if err := p.SetEphemeral(t.Context(), "Private/run-1", secret, time.Hour); err != nil {
+		t.Fatalf("SetEphemeral() error = %v", err)
+	}
+	if len(secret.Metadata.Tags) != 1 {
+		t.Errorf("SetEphemeral() modified the secret's tags: %v", secret.Metadata.Tags)
+	}
+
+	tags := m.created[0].Tags
+	if !slices.Contains(tags, EphemeralTag) || !slices.Contains(tags, "team:ci") {
+		t.Errorf("tags = %v", tags)
+	}
+	expires, ok := itemExpiry(op.Item{Tags: tags})
+	if !ok || expires.Before(time.Now().Add(59*time.Minute)) || expires.After(time.Now().Add(time.Hour)) {
+		t.Errorf("expiry = %v, %v; want in an hour", expires, ok)
+	}
+
+	// Existing tags are kept and the expiry replaced
+	if err := p.SetEphemeral(t.Context(), "Private/Database", &vault.Secret{Value: "x"}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	put := m.put[0].Tags
+	if !slices.Contains(put, "env:prod") || strings.Count(strings.Join(put, " "), ExpiresKey+":") != 1 {
+		t.Errorf("tags = %v", put)
+	}
+
+	if err := p.SetEphemeral(t.Context(), "Private/run-2", secret, 0); err == nil {
+		t.Error("SetEphemeral() with zero ttl succeeded")
+	}
+}
+
+func TestProvider_CleanupExpired(t *testing.T) {
+	past := ExpiresKey + ":" + time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	future := ExpiresKey + ":" + time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	m := testMockAPI()
+	m.items["v2"] = append(m.items["v2"],
+		op.Item{ID: "e1", Title: "run-1", VaultID: "v2", Tags: []string{EphemeralTag, past}},
+		op.Item{ID: "e2", Title: "run-2", VaultID: "v2", Tags: []string{EphemeralTag, future}},
+		op.Item{ID: "e3", Title: "run-3", VaultID: "v2", Tags: []string{past}},
+		op.Item{ID: "e4", Title: "run-4", VaultID: "v2", Tags: []string{EphemeralTag}},
+	)
+	m.items["v1"] = append(m.items["v1"],
+		op.Item{ID: "e5", Title: "run-5", VaultID: "v1", Tags: []string{EphemeralTag, past}},
+	)
+	p := newMockProvider(m, Config{})
+
+	deleted, err := p.CleanupExpired(t.Context(), "Work/")
+	if err != nil {
+		t.Fatalf("CleanupExpired() error = %v", err)
+	}
+	if want := []string{"Work/run-1"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("CleanupExpired() = %v, want %v", deleted, want)
+	}
+
+	deleted, err = p.CleanupExpired(t.Context(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Private/run-5", "Work/run-1"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("CleanupExpired(\"\") = %v, want %v", deleted, want)
+	}
+}
@@ -1,50 +1,219 @@
 package onepassword
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/agentplexus/omnivault/vault"
 )
 
+// ErrConflict is returned when a conditional write finds that the item has
+// been modified since the expected version was read.
+var ErrConflict = errors.New("version conflict")
+
+// Sentinel errors classifying 1Password failures. Each wraps the matching
+// vault error, so callers can test for either:
+//
+//	errors.Is(err, onepassword.ErrItemNotFound) // this provider only
+//	errors.Is(err, vault.ErrSecretNotFound)     // any provider
+var (
+	// ErrVaultNotFound is returned when a vault does not exist or is not
+	// accessible. It wraps vault.ErrSecretNotFound.
+	ErrVaultNotFound error = &sentinelError{"vault not found", []error{vault.ErrSecretNotFound}}
+
+	// ErrItemNotFound is returned when an item does not exist.
+	// It wraps vault.ErrSecretNotFound.
+	ErrItemNotFound error = &sentinelError{"item not found", []error{vault.ErrSecretNotFound}}
+
+	// ErrFieldNotFound is returned when an item has no such field.
+	// It wraps vault.ErrSecretNotFound.
+	ErrFieldNotFound error = &sentinelError{"field not found", []error{vault.ErrSecretNotFound}}
+
+	// ErrAmbiguous is returned when a path matches more than one vault,
+	// item, or field.
+	ErrAmbiguous error = &sentinelError{"ambiguous path: multiple matches found", nil}
+
+	// ErrAmbiguousItem is returned when an item title refers to more than
+	// one item in a vault. It wraps ErrAmbiguous.
+	ErrAmbiguousItem error = &sentinelError{"ambiguous item title", []error{ErrAmbiguous}}
+
+	// ErrRateLimited is returned when 1Password throttles requests. The
+	// error is a *RateLimitError carrying retry hints.
+	ErrRateLimited error = &sentinelError{"rate limited", nil}
+
+	// ErrAuth is returned when the token is invalid or lacks access. It
+	// wraps vault.ErrAccessDenied and vault.ErrAuthenticationFailed.
+	ErrAuth error = &sentinelError{"authentication failed", []error{vault.ErrAccessDenied, vault.ErrAuthenticationFailed}}
+)
+
+// sentinelError is an error value that also matches the errors it wraps.
+type sentinelError struct {
+	msg     string
+	wrapped []error
+}
+
+func (e *sentinelError) Error() string   { return e.msg }
+func (e *sentinelError) Unwrap() []error { return e.wrapped }
+
+// classifiedError attaches a sentinel to an SDK error while keeping the
+// SDK's message and error chain.
+type classifiedError struct {
+	kind error
+	err  error
+}
+
+func (e *classifiedError) Error() string   { return e.err.Error() }
+func (e *classifiedError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// AmbiguousItemError is returned when an item title matches more than one item.
+type AmbiguousItemError struct {
+	// Title is the item title that was looked up.
+	Title string
+
+	// VaultID is the vault that was searched.
+	VaultID string
+
+	// Candidates are the IDs of all matching items.
+	Candidates []string
+}
+
+// Error implements the error interface.
+func (e *AmbiguousItemError) Error() string {
+	return fmt.Sprintf("%v: %q matches %d items in vault %s (candidate IDs: %s)",
+		ErrAmbiguousItem, e.Title, len(e.Candidates), e.VaultID, strings.Join(e.Candidates, ", "))
+}
+
+// Unwrap returns ErrAmbiguousItem.
+func (e *AmbiguousItemError) Unwrap() error {
+	return ErrAmbiguousItem
+}
+
+// RateLimitError is returned when 1Password throttles a request. It matches
+// ErrRateLimited and carries the retry hints found in the response:
+//
+//	var rl *onepassword.RateLimitError
+//	if errors.As(err, &rl) && rl.RetryAfter > 0 {
+//	    time.Sleep(rl.RetryAfter)
+//	}
+type RateLimitError struct {
+	// RetryAfter is how long 1Password asked the caller to wait, or zero if
+	// it did not say.
+	RetryAfter time.Duration
+
+	// Remaining is the remaining request quota, or -1 if unknown.
+	Remaining int
+
+	// Err is the error returned by the SDK.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%v (retry after %s): %v", ErrRateLimited, e.RetryAfter, e.Err)
+	}
+	return fmt.Sprintf("%v: %v", ErrRateLimited, e.Err)
+}
+
+// Unwrap returns ErrRateLimited and the SDK error.
+func (e *RateLimitError) Unwrap() []error {
+	return []error{ErrRateLimited, e.Err}
+}
+
+// Patterns extracting retry hints from rate limit messages.
+var (
+	retryAfterPattern = regexp.MustCompile(`(?i)retry[-_ ]?after["':= ]+(\d+)`)
+	remainingPattern  = regexp.MustCompile(`(?i)remaining["':= ]+(\d+)`)
+)
+
+// newRateLimitError wraps a rate limit error from the SDK, parsing any
+// retry-after (in seconds) and remaining quota hints from its message.
+func newRateLimitError(err error) *RateLimitError {
+	e := &RateLimitError{Remaining: -1, Err: err}
+	msg := err.Error()
+	if m := retryAfterPattern.FindStringSubmatch(msg); m != nil {
+		if secs, convErr := strconv.Atoi(m[1]); convErr == nil {
+			e.RetryAfter = time.Duration(secs) * time.Second
+		}
+	}
+	if m := remainingPattern.FindStringSubmatch(msg); m != nil {
+		if n, convErr := strconv.Atoi(m[1]); convErr == nil {
+			e.Remaining = n
+		}
+	}
+	return e
+}
+
+// isRateLimitError reports whether err indicates throttling.
+func isRateLimitError(err error) bool {
+	return errors.Is(classifyError(err), ErrRateLimited)
+}
+
+// sentinels are the errors classifyError recognizes as already classified.
+var sentinels = []error{
+	ErrVaultNotFound, ErrItemNotFound, ErrFieldNotFound,
+	ErrAmbiguous, ErrRateLimited, ErrAuth, ErrUnmanaged,
+}
+
 // mapError converts 1Password SDK errors to OmniVault errors.
 func mapError(operation string, path string, err error) error {
 	if err == nil {
 		return nil
 	}
+	return vault.NewVaultError(operation, path, ProviderName, classifyError(err))
+}
 
-	errStr := err.Error()
+// classifyError attaches the matching sentinel error to err. Errors raised
+// by the provider already carry one and context errors are left alone. The
+// SDK returns untyped errors, so its messages are matched as a last resort.
+func classifyError(err error) error {
+	for _, sentinel := range sentinels {
+		if errors.Is(err, sentinel) {
+			return err
+		}
+	}
+	if isContextError(err) {
+		return err
+	}
+
+	kind := classifyMessage(err.Error())
+	if kind == ErrRateLimited {
+		return newRateLimitError(err)
+	}
+	if kind != nil {
+		return &classifiedError{kind: kind, err: err}
+	}
+	return err
+}
 
-	// Map common error patterns to vault errors
+// classifyMessage returns the sentinel for a known SDK error message.
+func classifyMessage(msg string) error {
 	switch {
-	case containsAny(errStr,
-		"itemNotFound",
-		"vaultNotFound",
-		"fieldNotFound",
-		"noMatchingSections",
-		"item not found",
-		"vault not found"):
-		return vault.NewVaultError(operation, path, ProviderName, vault.ErrSecretNotFound)
-
-	case containsAny(errStr,
+	case containsAny(msg, "vaultNotFound", "vault not found"):
+		return ErrVaultNotFound
+	case containsAny(msg, "itemNotFound", "item not found"):
+		return ErrItemNotFound
+	case containsAny(msg, "fieldNotFound", "noMatchingSections"):
+		return ErrFieldNotFound
+	case containsAny(msg,
 		"unauthorized",
 		"forbidden",
 		"access denied",
 		"AccessDenied",
 		"invalid service account token",
 		"authentication failed"):
-		return vault.NewVaultError(operation, path, ProviderName, vault.ErrAccessDenied)
-
-	case containsAny(errStr,
-		"tooManyVaults",
-		"tooManyItems",
-		"tooManyMatchingFields"):
-		return vault.NewVaultError(operation, path, ProviderName,
-			errors.New("ambiguous path: multiple matches found"))
+		return ErrAuth
+	case containsAny(msg, "tooManyVaults", "tooManyItems", "tooManyMatchingFields"):
+		return ErrAmbiguous
+	case containsAny(msg, "rate limit", "ratelimit", "too many requests", "throttl"):
+		return ErrRateLimited
 	}
-
-	// Return original error wrapped in VaultError
-	return vault.NewVaultError(operation, path, ProviderName, err)
+	return nil
 }
 
 // containsAny returns true if s contains any of the substrings.
@@ -63,11 +232,17 @@ func isNotFoundError(err error) bool {
 	if err == nil {
 		return false
 	}
-	errStr := strings.ToLower(err.Error())
-	return containsAny(errStr,
-		"itemnotfound",
-		"vaultnotfound",
-		"fieldnotfound",
-		"not found",
-	)
+	err = classifyError(err)
+	for _, sentinel := range sentinels {
+		if errors.Is(err, sentinel) {
+			return errors.Is(err, vault.ErrSecretNotFound)
+		}
+	}
+	// Not found errors of unknown origin
+	return containsAny(err.Error(), "not found")
+}
+
+// isContextError reports whether err is a context cancellation or deadline.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
 }
@@ -0,0 +1,111 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestMapError(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		want  error
+		vault error
+	}{
+		{"sdk item", errors.New("error resolving: itemNotFound"), ErrItemNotFound, vault.ErrSecretNotFound},
+		{"sdk vault", errors.New("vaultNotFound"), ErrVaultNotFound, vault.ErrSecretNotFound},
+		{"sdk field", errors.New("fieldNotFound in item"), ErrFieldNotFound, vault.ErrSecretNotFound},
+		{"sdk auth", errors.New("invalid service account token"), ErrAuth, vault.ErrAccessDenied},
+		{"sdk ambiguous", errors.New("tooManyItems"), ErrAmbiguous, nil},
+		{"sdk throttled", errors.New("Too Many Requests"), ErrRateLimited, nil},
+		{"provider item", fmt.Errorf("%w: db", ErrItemNotFound), ErrItemNotFound, vault.ErrSecretNotFound},
+		{"provider path", ErrInvalidPath, ErrInvalidPath, vault.ErrInvalidPath},
+		{"ambiguous title", &AmbiguousItemError{Title: "not found", Candidates: []string{"a", "b"}}, ErrAmbiguous, nil},
+		{"cancelled", context.Canceled, context.Canceled, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mapError("Get", "Private/db", tt.err)
+
+			var vaultErr *vault.VaultError
+			if !errors.As(err, &vaultErr) || vaultErr.Op != "Get" || vaultErr.Path != "Private/db" {
+				t.Fatalf("mapError() = %v, want a *vault.VaultError", err)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.want)
+			}
+			if tt.vault != nil && !errors.Is(err, tt.vault) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.vault)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("mapError() lost the original error %v", tt.err)
+			}
+		})
+	}
+}
+
+func TestMapError_Unknown(t *testing.T) {
+	err := mapError("Get", "p", errors.New("boom"))
+	for _, sentinel := range sentinels {
+		if errors.Is(err, sentinel) {
+			t.Errorf("unknown error classified as %v", sentinel)
+		}
+	}
+	if mapError("Get", "p", nil) != nil {
+		t.Error("mapError(nil) != nil")
+	}
+}
+
+func TestIsNotFoundError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("itemNotFound"), true},
+		{fmt.Errorf("%w: x", ErrVaultNotFound), true},
+		{errors.New("something not found"), true},
+		{errors.New("unauthorized"), false},
+		{&AmbiguousItemError{Title: "not found"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := isNotFoundError(tt.err); got != tt.want {
+			t.Errorf("isNotFoundError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestNewRateLimitError(t *testing.T) {
+	tests := []struct {
+		msg        string
+		retryAfter time.Duration
+		remaining  int
+	}{
+		{"too many requests", 0, -1},
+		{`rateLimitExceeded: {"retry_after": 30, "remaining": 0}`, 30 * time.Second, 0},
+		{"Rate limit hit, Retry-After: 5", 5 * time.Second, -1},
+	}
+
+	for _, tt := range tests {
+		err := mapError("Get", "p", errors.New(tt.msg))
+
+		var rl *RateLimitError
+		if !errors.As(err, &rl) {
+			t.Fatalf("mapError(%q) = %v, want *RateLimitError", tt.msg, err)
+		}
+		if rl.RetryAfter != tt.retryAfter || rl.Remaining != tt.remaining {
+			t.Errorf("%q: RetryAfter = %v, Remaining = %d; want %v, %d",
+				tt.msg, rl.RetryAfter, rl.Remaining, tt.retryAfter, tt.remaining)
+		}
+		if !errors.Is(err, ErrRateLimited) {
+			t.Errorf("%q: errors.Is(ErrRateLimited) = false", tt.msg)
+		}
+	}
+}
@@ -63,10 +63,10 @@ func main() {
 			log.Printf("Get failed: %v", err)
 		} else {
 			fmt.Printf("Path: %s\n", secret.Metadata.Path)
-			fmt.Printf("Value: %s\n", maskSecret(secret.Value))
+			fmt.Printf("Value: %s\n", op.Mask(secret.Value))
 			fmt.Printf("Fields:\n")
 			for name, value := range secret.Fields {
-				fmt.Printf("  %s: %s\n", name, maskSecret(value))
+				fmt.Printf("  %s: %s\n", name, op.Mask(value))
 			}
 		}
 	} else {
@@ -125,11 +125,3 @@ func main() {
 	fmt.Printf("MultiField: %v\n", caps.MultiField)
 	fmt.Printf("Batch: %v\n", caps.Batch)
 }
-
-// maskSecret masks a secret value for display
-func maskSecret(s string) string {
-	if len(s) <= 4 {
-		return "****"
-	}
-	return s[:2] + "****" + s[len(s)-2:]
-}
@@ -66,7 +66,7 @@ func main() {
 		fmt.Println("Try setting OP_SECRET_URI to a valid secret reference:")
 		fmt.Println("  export OP_SECRET_URI=\"op://YourVault/YourItem/field\"")
 	} else {
-		fmt.Printf("  Value: %s\n", maskSecret(value))
+		fmt.Printf("  Value: %s\n", op.Mask(value))
 	}
 
 	// Example: Using native op:// references
@@ -80,11 +80,3 @@ func main() {
 	fmt.Println("  op://Work/Database/prod/password")
 	fmt.Println("  op://Shared/AWS Credentials/access_key")
 }
-
-// maskSecret masks a secret value for display
-func maskSecret(s string) string {
-	if len(s) <= 4 {
-		return "****"
-	}
-	return s[:2] + "****" + s[len(s)-2:]
-}
@@ -0,0 +1,178 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// PathLevel is how much of a path exists, as reported by Probe.
+type PathLevel int
+
+const (
+	// LevelNone means the vault doesn't exist or isn't accessible.
+	LevelNone PathLevel = iota
+
+	// LevelVault means the vault exists but the item doesn't.
+	LevelVault
+
+	// LevelItem means the item exists but the field or section doesn't.
+	LevelItem
+
+	// LevelField means the field exists.
+	LevelField
+)
+
+// String returns the level's name.
+func (l PathLevel) String() string {
+	switch l {
+	case LevelNone:
+		return "none"
+	case LevelVault:
+		return "vault"
+	case LevelItem:
+		return "item"
+	case LevelField:
+		return "field"
+	default:
+		return fmt.Sprintf("PathLevel(%d)", int(l))
+	}
+}
+
+// Probe reports the deepest level of path that exists, so automation can
+// tell whether to request a vault, create an item, or add a field:
+//
+//	switch level, err := provider.Probe(ctx, "Production/Database/password"); {
+//	case err != nil:
+//	    return err
+//	case level == onepassword.LevelNone:
+//	    // ask for the vault to be created
+//	case level == onepassword.LevelVault:
+//	    // create the item
+//	case level == onepassword.LevelItem:
+//	    // add the field
+//	}
+func (p *Provider) Probe(ctx context.Context, path string) (PathLevel, error) {
+	if p.closed.Load() {
+		return LevelNone, vault.NewVaultError("Probe", path, ProviderName, vault.ErrClosed)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return LevelNone, vault.NewVaultError("Probe", path, ProviderName, err)
+	}
+	return p.probe(ctx, "Probe", parsed)
+}
+
+// VaultExists reports whether a vault with the given name or ID exists and
+// is accessible. An empty name uses the default vault.
+func (p *Provider) VaultExists(ctx context.Context, vaultName string) (bool, error) {
+	if vaultName == "" {
+		vaultName = p.getDefaultVault()
+	}
+	if p.closed.Load() {
+		return false, vault.NewVaultError("VaultExists", vaultName, ProviderName, vault.ErrClosed)
+	}
+	if vaultName == "" {
+		return false, vault.NewVaultError("VaultExists", vaultName, ProviderName,
+			fmt.Errorf("%w: vault name or default vault is required", ErrInvalidPath))
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	level, err := p.probe(ctx, "VaultExists", &ParsedPath{Vault: vaultName})
+	return level >= LevelVault, err
+}
+
+// ItemExists reports whether an item exists in a vault. The names are used
+// as is, without path parsing or escaping. An empty vault name uses the
+// default vault. A missing vault is reported as false; use VaultExists or
+// Probe to tell the two apart.
+func (p *Provider) ItemExists(ctx context.Context, vaultName, item string) (bool, error) {
+	path := NewPath(vaultName, item)
+	if path.Vault == "" {
+		path.Vault = p.getDefaultVault()
+	}
+	if p.closed.Load() {
+		return false, vault.NewVaultError("ItemExists", path.String(), ProviderName, vault.ErrClosed)
+	}
+	if path.Vault == "" || path.Item == "" {
+		return false, vault.NewVaultError("ItemExists", path.String(), ProviderName,
+			fmt.Errorf("%w: vault (or default vault) and item are required", ErrInvalidPath))
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	level, err := p.probe(ctx, "ItemExists", &path)
+	return level >= LevelItem, err
+}
+
+// FieldExists reports whether the field a path names exists. The path
+// must name a field. A missing vault or item is reported as false; use
+// Probe to tell which level is missing.
+func (p *Provider) FieldExists(ctx context.Context, path string) (bool, error) {
+	if p.closed.Load() {
+		return false, vault.NewVaultError("FieldExists", path, ProviderName, vault.ErrClosed)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return false, vault.NewVaultError("FieldExists", path, ProviderName, err)
+	}
+	if parsed.Field == "" {
+		return false, vault.NewVaultError("FieldExists", path, ProviderName,
+			fmt.Errorf("%w: path must name a field", ErrInvalidPath))
+	}
+
+	level, err := p.probe(ctx, "FieldExists", parsed)
+	return level == LevelField, err
+}
+
+// probe returns the deepest level of parsed that exists. A path without an
+// item stops at the vault, and one without a field at the item.
+func (p *Provider) probe(ctx context.Context, operation string, parsed *ParsedPath) (PathLevel, error) {
+	vaultID, err := p.resolveVaultID(ctx, parsed.Vault)
+	if err != nil {
+		if isNotFoundError(err) {
+			return LevelNone, nil
+		}
+		return LevelNone, mapError(operation, parsed.String(), err)
+	}
+	if parsed.Item == "" {
+		return LevelVault, nil
+	}
+
+	itemID, err := p.resolveItemID(ctx, vaultID, parsed.Item)
+	if err != nil {
+		if isNotFoundError(err) {
+			return LevelVault, nil
+		}
+		return LevelVault, mapError(operation, parsed.String(), err)
+	}
+	if parsed.Field == "" {
+		return LevelItem, nil
+	}
+
+	// Look the field up on the item rather than resolving a reference,
+	// which can't express every path
+	item, err := p.items.Get(ctx, vaultID, itemID)
+	if err != nil {
+		if isNotFoundError(err) {
+			return LevelVault, nil
+		}
+		return LevelVault, mapError(operation, parsed.String(), err)
+	}
+	if _, ok := findField(item, parsed.Section, parsed.Field); !ok {
+		return LevelItem, nil
+	}
+	return LevelField, nil
+}
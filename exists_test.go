@@ -0,0 +1,71 @@
+package onepassword
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProvider_Probe(t *testing.T) {
+	p := newMockProvider(sectionedMockAPI(), Config{})
+
+	tests := []struct {
+		path string
+		want PathLevel
+	}{
+		{"Missing/Database/password", LevelNone},
+		{"Private/Missing/password", LevelVault},
+		{"Private/Database/missing", LevelItem},
+		{"Private/Database", LevelItem},
+		{"Private/Database/password", LevelField},
+		{"Private/Database/Production/host", LevelField},
+		{"Private/Database/Production/username", LevelItem},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := p.Probe(t.Context(), tt.path)
+			if err != nil {
+				t.Fatalf("Probe() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Probe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvider_LevelExists(t *testing.T) {
+	ctx := t.Context()
+	p := newMockProvider(testMockAPI(), Config{DefaultVaultName: "Private"})
+
+	check := func(name string, got bool, err error, want bool) {
+		t.Helper()
+		if err != nil {
+			t.Errorf("%s error = %v", name, err)
+		} else if got != want {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+
+	ok, err := p.VaultExists(ctx, "Work")
+	check(`VaultExists("Work")`, ok, err, true)
+	ok, err = p.VaultExists(ctx, "Missing")
+	check(`VaultExists("Missing")`, ok, err, false)
+	ok, err = p.VaultExists(ctx, "")
+	check(`VaultExists("")`, ok, err, true)
+
+	ok, err = p.ItemExists(ctx, "Private", "Database")
+	check(`ItemExists("Private", "Database")`, ok, err, true)
+	ok, err = p.ItemExists(ctx, "", "Database")
+	check(`ItemExists("", "Database")`, ok, err, true)
+	ok, err = p.ItemExists(ctx, "Missing", "Database")
+	check(`ItemExists("Missing", "Database")`, ok, err, false)
+
+	ok, err = p.FieldExists(ctx, "Private/Database/password")
+	check("FieldExists(password)", ok, err, true)
+	ok, err = p.FieldExists(ctx, "Private/Database/missing")
+	check("FieldExists(missing)", ok, err, false)
+
+	if _, err := p.FieldExists(ctx, "op://Private/Database"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("FieldExists(item path) error = %v, want ErrInvalidPath", err)
+	}
+}
@@ -0,0 +1,62 @@
+package onepassword
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ExpiresKey names an item's expiry, either as a tag ("expires:2025-01-01")
+// or as a field titled "expires". The tag wins if both are set. Values are
+// dates (2006-01-02, expiring at the start of that day in UTC) or RFC 3339
+// times; values in any other format are ignored.
+const ExpiresKey = "expires"
+
+// ErrSecretExpired is returned by Get when Config.EnforceExpiry is set and
+// the item's expiry has passed.
+var ErrSecretExpired = errors.New("secret expired")
+
+// itemExpiry returns the expiry of item, if it has a valid one.
+func itemExpiry(item op.Item) (time.Time, bool) {
+	for _, tag := range item.Tags {
+		if key, value, ok := strings.Cut(tag, ":"); ok && key == ExpiresKey {
+			return parseExpiry(value)
+		}
+	}
+	for _, field := range item.Fields {
+		if strings.EqualFold(field.Title, ExpiresKey) {
+			return parseExpiry(field.Value)
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseExpiry parses a date or RFC 3339 time.
+func parseExpiry(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// checkExpiry returns ErrSecretExpired if Config.EnforceExpiry is set and
+// item has expired.
+func (p *Provider) checkExpiry(item op.Item, path string) error {
+	if !p.config.EnforceExpiry {
+		return nil
+	}
+	expires, ok := itemExpiry(item)
+	if !ok || time.Now().Before(expires) {
+		return nil
+	}
+	return vault.NewVaultError("Get", path, ProviderName,
+		fmt.Errorf("%w on %s", ErrSecretExpired, expires.Format(time.RFC3339)))
+}
@@ -0,0 +1,64 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func TestItemExpiry(t *testing.T) {
+	tests := []struct {
+		name   string
+		item   op.Item
+		want   time.Time
+		wantOK bool
+	}{
+		{"none", op.Item{Tags: []string{"env:prod"}}, time.Time{}, false},
+		{"tag date", op.Item{Tags: []string{"expires:2025-01-01"}}, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"tag time", op.Item{Tags: []string{"expires:2025-01-01T12:00:00Z"}}, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), true},
+		{"field", op.Item{Fields: []op.ItemField{{Title: "Expires", Value: "2030-06-01"}}}, time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC), true},
+		{"tag wins", op.Item{
+			Tags:   []string{"expires:2025-01-01"},
+			Fields: []op.ItemField{{Title: "expires", Value: "2030-06-01"}},
+		}, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"malformed", op.Item{Tags: []string{"expires:soon"}}, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := itemExpiry(tt.item)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("itemExpiry() = %v, %v; want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGet_EnforceExpiry(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	m.items["v1"][0].Tags = []string{"expires:2020-01-01"}
+	m.items["v2"][0].Tags = []string{"expires:2999-01-01"}
+
+	p := newMockProvider(m, Config{})
+	secret, err := p.Get(ctx, "Private/Database")
+	if err != nil {
+		t.Fatalf("Get() without enforcement error = %v", err)
+	}
+	if secret.Metadata.ExpiresAt == nil || secret.Metadata.ExpiresAt.Year() != 2020 {
+		t.Errorf("ExpiresAt = %v, want 2020-01-01", secret.Metadata.ExpiresAt)
+	}
+
+	p = newMockProvider(m, Config{EnforceExpiry: true})
+	for _, path := range []string{"Private/Database", "Private/Database/password"} {
+		if _, err := p.Get(ctx, path); !errors.Is(err, ErrSecretExpired) {
+			t.Errorf("Get(%q) error = %v, want ErrSecretExpired", path, err)
+		}
+	}
+	if _, err := p.Get(ctx, "Work/API"); err != nil {
+		t.Errorf("Get(unexpired) error = %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// Fallback is a vault.Vault reading from a provider and, for secrets it
+// doesn't have, from other vaults in turn, such as environment variables
+// or files. Only a missing secret (vault.ErrSecretNotFound, which covers
+// ErrVaultNotFound, ErrItemNotFound, and ErrFieldNotFound) falls through;
+// any other error, such as a rejected token or a rate limit, is returned,
+// so a 1Password outage doesn't silently serve stale or default values.
+// Writes go to the provider only. Every vault is given the same path.
+type Fallback struct {
+	provider  *Provider
+	fallbacks []vault.Vault
+}
+
+// WithFallback returns a vault.Vault reading from p, then from each of
+// fallbacks in order:
+//
+//	v := onepassword.WithFallback(provider, envVault)
+//	secret, err := v.Get(ctx, "DATABASE_PASSWORD")
+func WithFallback(p *Provider, fallbacks ...vault.Vault) *Fallback {
+	return &Fallback{provider: p, fallbacks: fallbacks}
+}
+
+// Provider returns the provider read first.
+func (f *Fallback) Provider() *Provider {
+	return f.provider
+}
+
+// vaults returns the provider and the fallbacks, in order.
+func (f *Fallback) vaults() []vault.Vault {
+	return append([]vault.Vault{f.provider}, f.fallbacks...)
+}
+
+// Get retrieves the secret at path from the first vault that has it.
+func (f *Fallback) Get(ctx context.Context, path string) (*vault.Secret, error) {
+	var err error
+	for _, v := range f.vaults() {
+		var secret *vault.Secret
+		secret, err = v.Get(ctx, path)
+		if err == nil || !errors.Is(err, vault.ErrSecretNotFound) {
+			return secret, err
+		}
+	}
+	return nil, err
+}
+
+// Exists reports whether any vault has a secret at path, stopping at the
+// first error.
+func (f *Fallback) Exists(ctx context.Context, path string) (bool, error) {
+	for _, v := range f.vaults() {
+		if ok, err := v.Exists(ctx, path); ok || err != nil {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// Set stores the secret in the provider.
+func (f *Fallback) Set(ctx context.Context, path string, secret *vault.Secret) error {
+	return f.provider.Set(ctx, path, secret)
+}
+
+// Delete removes the secret from the provider.
+func (f *Fallback) Delete(ctx context.Context, path string) error {
+	return f.provider.Delete(ctx, path)
+}
+
+// List returns the paths matching prefix in any vault: the provider's
+// first, then those of each fallback not listed already.
+func (f *Fallback) List(ctx context.Context, prefix string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, v := range f.vaults() {
+		listed, err := v.List(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range listed {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// Name returns the provider name.
+func (f *Fallback) Name() string {
+	return f.provider.Name()
+}
+
+// Capabilities returns the capabilities of the provider.
+func (f *Fallback) Capabilities() vault.Capabilities {
+	return f.provider.Capabilities()
+}
+
+// Close closes the provider and every fallback.
+func (f *Fallback) Close() error {
+	var errs []error
+	for _, v := range f.vaults() {
+		if err := v.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Ensure Fallback implements vault.Vault.
+var _ vault.Vault = (*Fallback)(nil)
@@ -0,0 +1,60 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/agentplexus/omnivault/providers/memory"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestWithFallback(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	backup := memory.NewWithSecrets(map[string]string{
+		"Private/Database": "from-memory",
+		"Private/Cache":    "cache-secret",
+	})
+	v := WithFallback(newMockProvider(m, Config{}), backup)
+
+	secret, err := v.Get(ctx, "Private/Database")
+	if err != nil || secret.Fields["username"] != "admin" {
+		t.Errorf("Get() = %+v, %v; want the 1Password item", secret, err)
+	}
+	secret, err = v.Get(ctx, "Private/Cache")
+	if err != nil || secret.Value != "cache-secret" {
+		t.Errorf("Get(missing in 1Password) = %+v, %v; want the fallback's", secret, err)
+	}
+	if _, err := v.Get(ctx, "Private/Missing"); !errors.Is(err, vault.ErrSecretNotFound) {
+		t.Errorf("Get(missing everywhere) error = %v, want ErrSecretNotFound", err)
+	}
+	if ok, err := v.Exists(ctx, "Private/Cache"); err != nil || !ok {
+		t.Errorf("Exists(fallback) = %v, %v", ok, err)
+	}
+
+	paths, err := v.List(ctx, "Private")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if want := []string{"Private/Database", "Private/Cache"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("List() = %v, want %v", paths, want)
+	}
+
+	if err := v.Set(ctx, "Private/New/password", &vault.Secret{Value: "x"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if len(m.created) != 1 {
+		t.Errorf("Set() created %d 1Password items, want 1", len(m.created))
+	}
+	if ok, _ := backup.Exists(ctx, "Private/New/password"); ok {
+		t.Error("Set() wrote to the fallback")
+	}
+
+	// Other errors don't fall through
+	m.err = errors.New("unauthorized")
+	if _, err := v.Get(ctx, "Private/Cache"); !errors.Is(err, ErrAuth) {
+		t.Errorf("Get() with a rejected token error = %v, want ErrAuth", err)
+	}
+}
@@ -0,0 +1,151 @@
+package onepassword
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// FieldTypesKey is the Secret.Metadata.Extra key holding per-field type
+// overrides for Set, as a map from field name to type name ("Text",
+// "Concealed", "Url", "Phone", "Totp"; case-insensitive):
+//
+//	secret.Metadata.Extra = map[string]any{
+//	    onepassword.FieldTypesKey: map[string]string{"monkey": "Text"},
+//	}
+const FieldTypesKey = "fieldTypes"
+
+// fieldTypes are the field types accepted as overrides.
+var fieldTypes = []op.ItemFieldType{
+	op.ItemFieldTypeText,
+	op.ItemFieldTypeConcealed,
+	op.ItemFieldTypeURL,
+	op.ItemFieldTypePhone,
+	op.ItemFieldTypeTOTP,
+	op.ItemFieldTypeCreditCardType,
+}
+
+// parseFieldType matches a field type name, ignoring case.
+func parseFieldType(name string) (op.ItemFieldType, error) {
+	for _, t := range fieldTypes {
+		if strings.EqualFold(string(t), name) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown field type %q", name)
+}
+
+// fieldTypeOverrides collects the field type overrides for a write from
+// secret.Metadata.Extra[FieldTypesKey] and opts.FieldTypes, which wins.
+func fieldTypeOverrides(secret *vault.Secret, opts SetOptions) (map[string]op.ItemFieldType, error) {
+	types := make(map[string]op.ItemFieldType)
+
+	add := func(field, name string) error {
+		t, err := parseFieldType(name)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", field, err)
+		}
+		types[field] = t
+		return nil
+	}
+
+	switch extra := secret.Metadata.Extra[FieldTypesKey].(type) {
+	case nil:
+	case map[string]op.ItemFieldType:
+		for field, t := range extra {
+			if err := add(field, string(t)); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]string:
+		for field, name := range extra {
+			if err := add(field, name); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]any:
+		// As decoded from JSON
+		for field, v := range extra {
+			name, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("field %q: field type must be a string, got %T", field, v)
+			}
+			if err := add(field, name); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("metadata %q must map field names to types, got %T", FieldTypesKey, extra)
+	}
+
+	for field, t := range opts.FieldTypes {
+		if err := add(field, string(t)); err != nil {
+			return nil, err
+		}
+	}
+	return types, nil
+}
+
+// buildFields converts a secret to fields, applying the provider's type
+// rules and inference setting and then the explicit overrides in types.
+func (p *Provider) buildFields(secret *vault.Secret, fieldName string, types map[string]op.ItemFieldType) []op.ItemField {
+	fields := secretToFields(secret, fieldName)
+	p.applyTypeRules(fields)
+	applyFieldTypes(fields, types)
+	return fields
+}
+
+// applyTypeRules retypes new fields according to Config.FieldTypeRules and
+// Config.DisableTypeInference. The notes field is left as plain text.
+func (p *Provider) applyTypeRules(fields []op.ItemField) {
+	for i := range fields {
+		if isNotesField(fields[i]) {
+			continue
+		}
+		if t, ok := matchTypeRule(p.config.FieldTypeRules, fields[i].Title); ok {
+			fields[i].FieldType = t
+		} else if p.config.DisableTypeInference {
+			fields[i].FieldType = op.ItemFieldTypeConcealed
+		}
+	}
+}
+
+// matchTypeRule returns the type of the first rule matching name.
+func matchTypeRule(rules []FieldTypeRule, name string) (op.ItemFieldType, bool) {
+	name = strings.ToLower(name)
+	for _, rule := range rules {
+		if ok, err := path.Match(strings.ToLower(rule.NamePattern), name); ok && err == nil {
+			return rule.Type, true
+		}
+	}
+	return "", false
+}
+
+// applyFieldTypes sets the type of each field named in types, matching
+// titles exactly or, failing that, ignoring case.
+func applyFieldTypes(fields []op.ItemField, types map[string]op.ItemFieldType) {
+	for name, t := range types {
+		if i := fieldIndex(fields, name); i >= 0 {
+			fields[i].FieldType = t
+		}
+	}
+}
+
+// fieldIndex returns the index of the field titled name, preferring an
+// exact match over a case-insensitive one, or -1.
+func fieldIndex(fields []op.ItemField, name string) int {
+	for i := range fields {
+		if fields[i].Title == name {
+			return i
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].Title, name) {
+			return i
+		}
+	}
+	return -1
+}
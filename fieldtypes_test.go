@@ -0,0 +1,159 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestFieldTypeOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		extra   any
+		opts    map[string]op.ItemFieldType
+		want    map[string]op.ItemFieldType
+		wantErr bool
+	}{
+		{"none", nil, nil, map[string]op.ItemFieldType{}, false},
+		{"string map", map[string]string{"monkey": "text"}, nil,
+			map[string]op.ItemFieldType{"monkey": op.ItemFieldTypeText}, false},
+		{"json map", map[string]any{"site": "URL"}, nil,
+			map[string]op.ItemFieldType{"site": op.ItemFieldTypeURL}, false},
+		{"options win", map[string]string{"pin": "Text"}, map[string]op.ItemFieldType{"pin": op.ItemFieldTypeConcealed},
+			map[string]op.ItemFieldType{"pin": op.ItemFieldTypeConcealed}, false},
+		{"unknown type", map[string]string{"x": "Email"}, nil, nil, true},
+		{"non-string value", map[string]any{"x": 1}, nil, nil, true},
+		{"wrong shape", []string{"x"}, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &vault.Secret{Metadata: vault.Metadata{Extra: map[string]any{}}}
+			if tt.extra != nil {
+				secret.Metadata.Extra[FieldTypesKey] = tt.extra
+			}
+
+			got, err := fieldTypeOverrides(secret, SetOptions{FieldTypes: tt.opts})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fieldTypeOverrides() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("fieldTypeOverrides() = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("type of %q = %v, want %v", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildFields(t *testing.T) {
+	secret := &vault.Secret{Fields: map[string]string{
+		"monkey": "banana", "host": "db1", "postgres_dsn": "postgres://", "notes": "text",
+	}}
+	types := map[string]op.ItemFieldType{"Monkey": op.ItemFieldTypeText}
+	rules := []FieldTypeRule{
+		{NamePattern: "*_DSN", Type: op.ItemFieldTypeConcealed},
+		{NamePattern: "host", Type: op.ItemFieldTypeURL},
+		{NamePattern: "mon*", Type: op.ItemFieldTypePhone},
+		{NamePattern: "*", Type: op.ItemFieldTypeConcealed},
+	}
+
+	tests := []struct {
+		name    string
+		disable bool
+		rules   []FieldTypeRule
+		want    map[string]op.ItemFieldType
+	}{
+		{"inferred", false, nil, map[string]op.ItemFieldType{
+			"monkey": op.ItemFieldTypeText, "host": op.ItemFieldTypeText,
+			"postgres_dsn": op.ItemFieldTypeText, "notes": op.ItemFieldTypeText,
+		}},
+		{"inference disabled", true, nil, map[string]op.ItemFieldType{
+			"monkey": op.ItemFieldTypeText, "host": op.ItemFieldTypeConcealed,
+			"postgres_dsn": op.ItemFieldTypeConcealed, "notes": op.ItemFieldTypeText,
+		}},
+		{"rules", false, rules, map[string]op.ItemFieldType{
+			"monkey": op.ItemFieldTypeText, "host": op.ItemFieldTypeURL,
+			"postgres_dsn": op.ItemFieldTypeConcealed, "notes": op.ItemFieldTypeText,
+		}},
+		{"rules before disabled inference", true, rules[1:2], map[string]op.ItemFieldType{
+			"monkey": op.ItemFieldTypeText, "host": op.ItemFieldTypeURL,
+			"postgres_dsn": op.ItemFieldTypeConcealed, "notes": op.ItemFieldTypeText,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Provider{config: Config{DisableTypeInference: tt.disable, FieldTypeRules: tt.rules}}
+			for _, f := range p.buildFields(secret, "", types) {
+				if f.FieldType != tt.want[f.Title] {
+					t.Errorf("type of %q = %v, want %v", f.Title, f.FieldType, tt.want[f.Title])
+				}
+			}
+		})
+	}
+}
+
+func TestMatchTypeRule(t *testing.T) {
+	rules := []FieldTypeRule{
+		{NamePattern: "[", Type: op.ItemFieldTypeURL},
+		{NamePattern: "contact_*", Type: op.ItemFieldTypeText},
+		{NamePattern: "*_dsn", Type: op.ItemFieldTypeConcealed},
+		{NamePattern: "*", Type: op.ItemFieldTypePhone},
+	}
+
+	tests := []struct {
+		name string
+		want op.ItemFieldType
+	}{
+		{"contact_dsn", op.ItemFieldTypeText},
+		{"Primary_DSN", op.ItemFieldTypeConcealed},
+		{"other", op.ItemFieldTypePhone},
+		{"[", op.ItemFieldTypePhone},
+	}
+
+	for _, tt := range tests {
+		got, ok := matchTypeRule(rules, tt.name)
+		if !ok || got != tt.want {
+			t.Errorf("matchTypeRule(%q) = %v, %v; want %v", tt.name, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := matchTypeRule(rules[:3], "other"); ok {
+		t.Error("matchTypeRule() matched without a matching rule")
+	}
+}
+
+func TestSetWithOptions_FieldTypes(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	err := p.SetWithOptions(context.Background(), "Private/Database", &vault.Secret{
+		Fields: map[string]string{"username": "root"},
+	}, SetOptions{FieldTypes: map[string]op.ItemFieldType{"username": op.ItemFieldTypeConcealed}})
+	if err != nil {
+		t.Fatalf("SetWithOptions() error = %v", err)
+	}
+
+	for _, f := range m.put[0].Fields {
+		if f.Title == "username" && f.FieldType != op.ItemFieldTypeConcealed {
+			t.Errorf("existing username type = %v, want Concealed", f.FieldType)
+		}
+		if f.Title == "password" && f.FieldType != op.ItemFieldTypeConcealed {
+			t.Errorf("untouched password type = %v", f.FieldType)
+		}
+	}
+
+	bad := &vault.Secret{Value: "x", Metadata: vault.Metadata{Extra: map[string]any{FieldTypesKey: map[string]string{"x": "nope"}}}}
+	if err := p.Set(context.Background(), "Private/Database/x", bad); err == nil {
+		t.Error("Set() with unknown field type succeeded")
+	}
+}
@@ -5,10 +5,18 @@ go 1.24.0
 require (
 	github.com/1password/onepassword-sdk-go v0.1.3
 	github.com/agentplexus/omnivault v0.2.0
+	golang.org/x/crypto v0.46.0
+	golang.org/x/oauth2 v0.34.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20251202230838-ff82c1b0f217
+	google.golang.org/grpc v1.79.3
+	google.golang.org/protobuf v1.36.10
 )
 
 require (
 	github.com/extism/go-sdk v1.3.1 // indirect
 	github.com/gobwas/glob v0.2.3 // indirect
 	github.com/tetratelabs/wazero v1.8.1 // indirect
+	golang.org/x/net v0.48.0 // indirect
+	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
 )
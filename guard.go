@@ -0,0 +1,147 @@
+package onepassword
+
+import (
+	"context"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+// callGuard wraps every SDK call made by a provider: it applies
+// Config.RateLimit and Config.OperationTimeout and records rate-limit
+// errors in the provider's statistics.
+type callGuard struct {
+	timeout time.Duration
+	stats   *providerStats
+
+	// reads and writes limit the rate of calls; nil allows every call
+	reads, writes *tokenBucket
+}
+
+// context waits for the rate limit of a read or write, then returns ctx
+// bounded by the operation timeout, if any.
+func (g *callGuard) context(ctx context.Context, write bool) (context.Context, context.CancelFunc, error) {
+	bucket := g.reads
+	if write {
+		bucket = g.writes
+	}
+	delayed, err := bucket.wait(ctx)
+	if delayed {
+		g.stats.throttled.Add(1)
+	}
+	if err != nil {
+		return ctx, func() {}, err
+	}
+
+	if g.timeout <= 0 {
+		return ctx, func() {}, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	return ctx, cancel, nil
+}
+
+// withTimeout returns ctx bounded by d, if positive. It applies
+// Config.Timeouts to whole operations, which may make several SDK calls.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// observe records err and returns it unchanged.
+func (g *callGuard) observe(err error) error {
+	if err != nil && isRateLimitError(err) {
+		g.stats.rateLimited.Add(1)
+	}
+	return err
+}
+
+// guardedSecrets applies a callGuard to a secretsAPI.
+type guardedSecrets struct {
+	next  secretsAPI
+	guard *callGuard
+}
+
+func (g guardedSecrets) Resolve(ctx context.Context, secretReference string) (string, error) {
+	ctx, cancel, err := g.guard.context(ctx, false)
+	if err != nil {
+		return "", err
+	}
+	defer cancel()
+	value, err := g.next.Resolve(ctx, secretReference)
+	return value, g.guard.observe(err)
+}
+
+// guardedItems applies a callGuard to an itemsAPI.
+type guardedItems struct {
+	next  itemsAPI
+	guard *callGuard
+}
+
+func (g guardedItems) Create(ctx context.Context, params op.ItemCreateParams) (op.Item, error) {
+	ctx, cancel, err := g.guard.context(ctx, true)
+	if err != nil {
+		return op.Item{}, err
+	}
+	defer cancel()
+	item, err := g.next.Create(ctx, params)
+	return item, g.guard.observe(err)
+}
+
+func (g guardedItems) Get(ctx context.Context, vaultID, itemID string) (op.Item, error) {
+	ctx, cancel, err := g.guard.context(ctx, false)
+	if err != nil {
+		return op.Item{}, err
+	}
+	defer cancel()
+	item, err := g.next.Get(ctx, vaultID, itemID)
+	return item, g.guard.observe(err)
+}
+
+func (g guardedItems) Put(ctx context.Context, item op.Item) (op.Item, error) {
+	ctx, cancel, err := g.guard.context(ctx, true)
+	if err != nil {
+		return op.Item{}, err
+	}
+	defer cancel()
+	updated, err := g.next.Put(ctx, item)
+	return updated, g.guard.observe(err)
+}
+
+func (g guardedItems) Delete(ctx context.Context, vaultID, itemID string) error {
+	ctx, cancel, err := g.guard.context(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return g.guard.observe(g.next.Delete(ctx, vaultID, itemID))
+}
+
+// ListAll guards fetching the listing; the SDK returns it fully loaded, so
+// iterating it afterwards makes no further calls.
+func (g guardedItems) ListAll(ctx context.Context, vaultID string) (*op.Iterator[op.ItemOverview], error) {
+	ctx, cancel, err := g.guard.context(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	iter, err := g.next.ListAll(ctx, vaultID)
+	return iter, g.guard.observe(err)
+}
+
+// guardedVaults applies a callGuard to a vaultsAPI.
+type guardedVaults struct {
+	next  vaultsAPI
+	guard *callGuard
+}
+
+func (g guardedVaults) ListAll(ctx context.Context) (*op.Iterator[op.VaultOverview], error) {
+	ctx, cancel, err := g.guard.context(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	iter, err := g.next.ListAll(ctx)
+	return iter, g.guard.observe(err)
+}
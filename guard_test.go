@@ -0,0 +1,126 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// blockingItems is an itemsAPI whose Get waits for its context to end.
+type blockingItems struct{ *mockAPI }
+
+func (b blockingItems) Get(ctx context.Context, _, _ string) (op.Item, error) {
+	<-ctx.Done()
+	return op.Item{}, ctx.Err()
+}
+
+func TestOperationTimeout(t *testing.T) {
+	m := testMockAPI()
+	p := newWithAPIs(m, blockingItems{m}, mockVaults{m}, Config{OperationTimeout: 20 * time.Millisecond})
+
+	start := time.Now()
+	_, err := p.Get(context.Background(), "Private/Database")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Get() error = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Get() took %v, want about the operation timeout", elapsed)
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	m := testMockAPI()
+	p := newWithAPIs(m, blockingItems{m}, mockVaults{m}, Config{
+		Timeouts: Timeouts{Get: 20 * time.Millisecond, Write: 20 * time.Millisecond},
+	})
+
+	tests := []struct {
+		name string
+		op   func(ctx context.Context) error
+	}{
+		{"Get", func(ctx context.Context) error {
+			_, err := p.Get(ctx, "Private/Database")
+			return err
+		}},
+		{"Set", func(ctx context.Context) error {
+			return p.Set(ctx, "Private/Database/password", &vault.Secret{Value: "x"})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			if err := tt.op(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("error = %v, want DeadlineExceeded", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("took %v, want about the timeout", elapsed)
+			}
+		})
+	}
+
+	// List has no timeout configured and doesn't call Get
+	if _, err := p.List(context.Background(), ""); err != nil {
+		t.Errorf("List() error = %v", err)
+	}
+}
+
+func TestTimeouts_OperationTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"shorter call timeout", Config{OperationTimeout: 20 * time.Millisecond, Timeouts: Timeouts{Get: time.Hour}}},
+		{"shorter operation timeout", Config{OperationTimeout: time.Hour, Timeouts: Timeouts{Get: 20 * time.Millisecond}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testMockAPI()
+			p := newWithAPIs(m, blockingItems{m}, mockVaults{m}, tt.config)
+
+			start := time.Now()
+			if _, err := p.Get(context.Background(), "Private/Database"); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("Get() error = %v, want DeadlineExceeded", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Get() took %v, want about the shorter timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestList_Cancelled(t *testing.T) {
+	p := newMockProvider(testMockAPI(), Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := p.List(ctx, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("List() error = %v, want Canceled", err)
+	}
+	if _, err := p.Exists(ctx, "Private/Database"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Exists() error = %v, want Canceled", err)
+	}
+}
+
+func TestStats_RateLimited(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	m.err = errors.New("too many requests")
+	_, _ = p.Get(context.Background(), "Private/Database/username")
+	_, _ = p.List(context.Background(), "")
+
+	if got := p.Stats().RateLimited; got != 2 {
+		t.Errorf("Stats().RateLimited = %d, want 2", got)
+	}
+
+	m.err = errors.New("unauthorized")
+	_, _ = p.Get(context.Background(), "Private/Database/username")
+	if got := p.Stats().RateLimited; got != 2 {
+		t.Errorf("Stats().RateLimited = %d after other error, want 2", got)
+	}
+}
@@ -0,0 +1,34 @@
+package onepassword
+
+import (
+	"context"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+// writeHooks runs Config.BeforeCreate and Config.BeforeUpdate on the items
+// written through it, so they see every create and update the provider
+// makes, whichever operation makes it.
+type writeHooks struct {
+	itemsAPI
+	beforeCreate func(ctx context.Context, params *op.ItemCreateParams) error
+	beforeUpdate func(ctx context.Context, item *op.Item) error
+}
+
+func (h writeHooks) Create(ctx context.Context, params op.ItemCreateParams) (op.Item, error) {
+	if h.beforeCreate != nil {
+		if err := h.beforeCreate(ctx, &params); err != nil {
+			return op.Item{}, err
+		}
+	}
+	return h.itemsAPI.Create(ctx, params)
+}
+
+func (h writeHooks) Put(ctx context.Context, item op.Item) (op.Item, error) {
+	if h.beforeUpdate != nil {
+		if err := h.beforeUpdate(ctx, &item); err != nil {
+			return op.Item{}, err
+		}
+	}
+	return h.itemsAPI.Put(ctx, item)
+}
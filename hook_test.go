@@ -0,0 +1,64 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestWriteHooks(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{
+		ManagedTag: testManagedTag,
+		BeforeCreate: func(_ context.Context, params *op.ItemCreateParams) error {
+			params.Category = CategoryServer
+			params.Websites = append(params.Websites, op.Website{URL: "https://example.com"})
+			params.Tags = nil
+			return nil
+		},
+		BeforeUpdate: func(_ context.Context, item *op.Item) error {
+			item.Fields = append(item.Fields, op.ItemField{ID: "cc", Title: "cost-center", Value: "42", FieldType: FieldTypeText})
+			return nil
+		},
+	})
+
+	if err := p.Set(t.Context(), "Private/New/password", &vault.Secret{Value: "x"}); err != nil {
+		t.Fatalf("Set() create error = %v", err)
+	}
+	created := m.created[0]
+	if created.Category != CategoryServer || len(created.Websites) != 1 {
+		t.Errorf("created category %q websites %v", created.Category, created.Websites)
+	}
+	if len(created.Tags) != 1 || created.Tags[0] != testManagedTag {
+		t.Errorf("created tags = %v, want only the managed tag", created.Tags)
+	}
+
+	if err := p.Set(t.Context(), "Private/Database/password", &vault.Secret{Value: "y"}); err != nil {
+		t.Fatalf("Set() update error = %v", err)
+	}
+	if findValue(m.put[0], "cost-center") != "42" {
+		t.Errorf("updated fields = %+v, want cost-center added", m.put[0].Fields)
+	}
+}
+
+func TestWriteHooks_Error(t *testing.T) {
+	refused := errors.New("refused by policy")
+	m := testMockAPI()
+	p := newMockProvider(m, Config{
+		BeforeCreate: func(context.Context, *op.ItemCreateParams) error { return refused },
+		BeforeUpdate: func(context.Context, *op.Item) error { return refused },
+	})
+
+	if err := p.Set(t.Context(), "Private/New/password", &vault.Secret{Value: "x"}); !errors.Is(err, refused) {
+		t.Errorf("Set() create error = %v, want hook error", err)
+	}
+	if err := p.Set(t.Context(), "Private/Database/password", &vault.Secret{Value: "y"}); !errors.Is(err, refused) {
+		t.Errorf("Set() update error = %v, want hook error", err)
+	}
+	if len(m.created) != 0 || len(m.put) != 0 {
+		t.Errorf("wrote despite hook error: created %d, put %d", len(m.created), len(m.put))
+	}
+}
@@ -0,0 +1,74 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// referencePattern matches "{{ op://... }}" placeholders (group 1) and bare
+// op:// references (group 2). A bare reference ends at whitespace, a quote,
+// or a brace, and leaves out trailing punctuation such as "," ";" ")" or
+// ".", so references containing spaces or ending in punctuation must use
+// the placeholder form.
+var referencePattern = regexp.MustCompile(`\{\{\s*(op://[^}\n]+?)\s*\}\}|(op://[^\s"'` + "`" + `<>{}]*[^\s"'` + "`" + `<>{}.,;:!?)\]])`)
+
+// Inject copies the template read from r to w, replacing each op://
+// reference and "{{ op://... }}" placeholder with the referenced secret.
+// This is the equivalent of `op inject` without the 1Password CLI:
+//
+//	# config.yaml
+//	database:
+//	  password: {{ op://Prod/Database/password }}
+//	  url: op://Prod/Database/url
+//
+// Each distinct reference is resolved once. Nothing is written unless every
+// reference resolves; all failures are reported together. Other "{{ }}"
+// placeholders are left untouched.
+func (p *Provider) Inject(ctx context.Context, r io.Reader, w io.Writer) error {
+	return inject(ctx, r, w, p.resolveValue)
+}
+
+// inject implements Inject with the given resolver.
+func inject(ctx context.Context, r io.Reader, w io.Writer, resolve resolveFunc) error {
+	tmpl, err := io.ReadAll(r)
+	if err != nil {
+		return vault.NewVaultError("Inject", "", ProviderName, fmt.Errorf("failed to read template: %w", err))
+	}
+
+	matches := referencePattern.FindAllSubmatchIndex(tmpl, -1)
+
+	refs := make([]string, len(matches))
+	for i, m := range matches {
+		refs[i] = matchedReference(tmpl, m)
+	}
+	values, err := resolveAll(ctx, refs, resolve)
+	if err != nil {
+		return err
+	}
+
+	out := make([]byte, 0, len(tmpl))
+	last := 0
+	for _, m := range matches {
+		out = append(out, tmpl[last:m[0]]...)
+		out = append(out, values[matchedReference(tmpl, m)]...)
+		last = m[1]
+	}
+	out = append(out, tmpl[last:]...)
+
+	if _, err := w.Write(out); err != nil {
+		return vault.NewVaultError("Inject", "", ProviderName, fmt.Errorf("failed to write output: %w", err))
+	}
+	return nil
+}
+
+// matchedReference returns the reference captured by a referencePattern match.
+func matchedReference(src []byte, m []int) string {
+	if m[2] >= 0 {
+		return string(src[m[2]:m[3]])
+	}
+	return string(src[m[4]:m[5]])
+}
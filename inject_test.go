@@ -0,0 +1,103 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestInject(t *testing.T) {
+	secrets := map[string]string{
+		"op://Prod/Database/password": "hunter2",
+		"op://Prod/Database/url":      "postgres://db:5432",
+		"op://Work/API Keys/token":    "tok",
+	}
+	resolve := func(_ context.Context, ref string) (string, error) {
+		value, ok := secrets[ref]
+		if !ok {
+			return "", errors.New("not found: " + ref)
+		}
+		return value, nil
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "placeholder",
+			input: "password: {{ op://Prod/Database/password }}\n",
+			want:  "password: hunter2\n",
+		},
+		{
+			name:  "placeholder with spaces in path",
+			input: `token="{{op://Work/API Keys/token}}"`,
+			want:  `token="tok"`,
+		},
+		{
+			name:  "bare reference",
+			input: "url: op://Prod/Database/url\nother: x",
+			want:  "url: postgres://db:5432\nother: x",
+		},
+		{
+			name:  "quoted bare reference",
+			input: `{"password": "op://Prod/Database/password"}`,
+			want:  `{"password": "hunter2"}`,
+		},
+		{
+			name:  "bare reference before punctuation",
+			input: "see op://Prod/Database/url, (op://Prod/Database/password); op://Prod/Database/url.",
+			want:  "see postgres://db:5432, (hunter2); postgres://db:5432.",
+		},
+		{
+			name:  "other placeholders untouched",
+			input: "name: {{ .Values.name }}",
+			want:  "name: {{ .Values.name }}",
+		},
+		{
+			name:    "unresolved reference",
+			input:   "a: op://Prod/Missing/x",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := inject(context.Background(), strings.NewReader(tt.input), &out, resolve)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("inject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if out.Len() != 0 {
+					t.Errorf("inject() wrote %q on failure", out.String())
+				}
+				return
+			}
+			if out.String() != tt.want {
+				t.Errorf("inject() = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestInject_ResolvesOnce(t *testing.T) {
+	calls := 0
+	resolve := func(context.Context, string) (string, error) {
+		calls++
+		return "v", nil
+	}
+
+	input := "a: op://V/I/f\nb: {{ op://V/I/f }}\n"
+	var out bytes.Buffer
+	if err := inject(context.Background(), strings.NewReader(input), &out, resolve); err != nil {
+		t.Fatalf("inject() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("resolve called %d times, want 1", calls)
+	}
+}
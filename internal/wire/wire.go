@@ -0,0 +1,99 @@
+// Package wire holds the error codes shared by the agent and server
+// packages, their JSON error format, and their mapping to and from the
+// vault sentinel errors.
+package wire
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// Error codes carried in error responses.
+const (
+	CodeNotFound      = "not_found"
+	CodeAccessDenied  = "access_denied"
+	CodeInvalidPath   = "invalid_path"
+	CodeAlreadyExists = "already_exists"
+	CodeReadOnly      = "read_only"
+	CodeNotSupported  = "not_supported"
+	CodeClosed        = "closed"
+	CodeBadRequest    = "bad_request"
+	CodeInternal      = "internal"
+)
+
+// codes pairs each error code with its sentinel error and HTTP status.
+var codes = []struct {
+	code   string
+	err    error
+	status int
+}{
+	{CodeNotFound, vault.ErrSecretNotFound, http.StatusNotFound},
+	{CodeAccessDenied, vault.ErrAccessDenied, http.StatusForbidden},
+	{CodeInvalidPath, vault.ErrInvalidPath, http.StatusBadRequest},
+	{CodeAlreadyExists, vault.ErrAlreadyExists, http.StatusConflict},
+	{CodeReadOnly, vault.ErrReadOnly, http.StatusMethodNotAllowed},
+	{CodeNotSupported, vault.ErrNotSupported, http.StatusNotImplemented},
+	{CodeClosed, vault.ErrClosed, http.StatusServiceUnavailable},
+}
+
+// ErrorResponse is the body of a failed request.
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// WriteJSON writes v as a JSON response with the given status.
+func WriteJSON(w http.ResponseWriter, status int, v any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(v)
+}
+
+// Code returns the error code matching err, or CodeInternal.
+func Code(err error) string {
+	for _, c := range codes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return CodeInternal
+}
+
+// FromCode returns an error with message wrapping the sentinel error
+// matching code, or a plain error for an unknown code.
+func FromCode(code, message string) error {
+	for _, c := range codes {
+		if c.code == code {
+			return fmt.Errorf("%w: %s", c.err, message)
+		}
+	}
+	return errors.New(message)
+}
+
+// WriteError writes err as an error response with a matching status code.
+func WriteError(w http.ResponseWriter, err error) error {
+	code, status := CodeInternal, http.StatusInternalServerError
+	for _, c := range codes {
+		if errors.Is(err, c.err) {
+			code, status = c.code, c.status
+			break
+		}
+	}
+	return WriteJSON(w, status, ErrorResponse{Error: err.Error(), Code: code})
+}
+
+// ReadError converts a failed response into an error wrapping the matching
+// sentinel error.
+func ReadError(resp *http.Response) error {
+	var errResp ErrorResponse
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if json.Unmarshal(body, &errResp) != nil || errResp.Error == "" {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	return FromCode(errResp.Code, errResp.Error)
+}
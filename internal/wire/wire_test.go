@@ -0,0 +1,56 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestError_RoundTrip(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+	}{
+		{vault.ErrSecretNotFound, http.StatusNotFound},
+		{vault.ErrAccessDenied, http.StatusForbidden},
+		{vault.ErrInvalidPath, http.StatusBadRequest},
+		{vault.ErrAlreadyExists, http.StatusConflict},
+		{vault.ErrReadOnly, http.StatusMethodNotAllowed},
+		{vault.ErrNotSupported, http.StatusNotImplemented},
+		{vault.ErrClosed, http.StatusServiceUnavailable},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			wrapped := vault.NewVaultError("Get", "V/I", "onepassword", fmt.Errorf("wrapped: %w", tt.err))
+			if err := WriteError(rec, wrapped); err != nil {
+				t.Fatalf("WriteError() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			got := ReadError(rec.Result())
+			if tt.wantStatus != http.StatusInternalServerError && !errors.Is(got, tt.err) {
+				t.Errorf("ReadError() = %v, want wrapping %v", got, tt.err)
+			}
+			if got.Error() == "" {
+				t.Error("ReadError() returned an empty message")
+			}
+		})
+	}
+}
+
+func TestReadError_NonJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	http.Error(rec, "bad gateway", http.StatusBadGateway)
+	if err := ReadError(rec.Result()); err == nil {
+		t.Error("ReadError() should return an error")
+	}
+}
@@ -0,0 +1,151 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+var (
+	// k8sNamePattern matches a DNS-1123 subdomain, the format of Secret names.
+	k8sNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+	// k8sNamespacePattern matches a DNS-1123 label, the format of namespaces.
+	k8sNamespacePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+	// k8sKeyInvalid matches characters not allowed in Secret data keys.
+	k8sKeyInvalid = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+)
+
+// ExportK8sSecret renders the fields of the item at path as a Kubernetes
+// Secret manifest of type Opaque. Field names are used as data keys, with
+// characters Kubernetes doesn't allow replaced by "_" ("api key" becomes
+// "api_key"). An empty namespace is omitted from the manifest.
+//
+//	manifest, err := provider.ExportK8sSecret(ctx, "Prod/Database", "db-credentials", "backend")
+func (p *Provider) ExportK8sSecret(ctx context.Context, path, name, namespace string) ([]byte, error) {
+	secret, err := p.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := renderK8sSecret(name, namespace, secret.Fields)
+	if err != nil {
+		return nil, vault.NewVaultError("ExportK8sSecret", path, ProviderName, err)
+	}
+	return manifest, nil
+}
+
+// ExportK8sSecrets renders every item matching prefix (see List) as a
+// Kubernetes Secret, joined into a multi-document YAML stream. Each Secret is
+// named after its item title, lowercased with other characters Kubernetes
+// doesn't allow replaced by "-". It fails if a title leaves no name, or if
+// two items get the same name.
+//
+//	manifests, err := provider.ExportK8sSecrets(ctx, "Prod/", "backend")
+func (p *Provider) ExportK8sSecrets(ctx context.Context, prefix, namespace string) ([]byte, error) {
+	paths, err := p.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	names := make(map[string]string, len(paths))
+	for i, path := range paths {
+		parsed, err := ParsePath(path, "")
+		if err != nil {
+			return nil, vault.NewVaultError("ExportK8sSecrets", path, ProviderName, err)
+		}
+		name, err := k8sSecretName(parsed.Item)
+		if err != nil {
+			return nil, vault.NewVaultError("ExportK8sSecrets", path, ProviderName, err)
+		}
+		if other, dup := names[name]; dup {
+			return nil, vault.NewVaultError("ExportK8sSecrets", path, ProviderName, fmt.Errorf("items %s and %s map to the same Secret name %q", other, path, name))
+		}
+		names[name] = path
+
+		manifest, err := p.ExportK8sSecret(ctx, path, name, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(manifest)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderK8sSecret renders fields as an Opaque Secret manifest.
+func renderK8sSecret(name, namespace string, fields map[string]string) ([]byte, error) {
+	if len(name) > 253 || !k8sNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid Kubernetes Secret name %q", name)
+	}
+	if namespace != "" && (len(namespace) > 63 || !k8sNamespacePattern.MatchString(namespace)) {
+		return nil, fmt.Errorf("invalid Kubernetes namespace %q", namespace)
+	}
+
+	data := make(map[string]string, len(fields))
+	for field, value := range fields {
+		key := k8sKeyInvalid.ReplaceAllString(field, "_")
+		if _, dup := data[key]; dup {
+			return nil, fmt.Errorf("fields map to the same Secret key %q", key)
+		}
+		data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("apiVersion: v1\nkind: Secret\nmetadata:\n")
+	fmt.Fprintf(&b, "  name: %s\n", name)
+	if namespace != "" {
+		fmt.Fprintf(&b, "  namespace: %s\n", namespace)
+	}
+	b.WriteString("type: Opaque\n")
+	if len(keys) == 0 {
+		b.WriteString("data: {}\n")
+	} else {
+		b.WriteString("data:\n")
+		for _, key := range keys {
+			fmt.Fprintf(&b, "  %q: %s\n", key, data[key])
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+// k8sSecretName derives a valid Secret name from an item title. It fails
+// if the title has no characters a name can use.
+func k8sSecretName(title string) (string, error) {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+
+	// Trim before truncating to keep as much of the title as fits, and
+	// after, so the name doesn't end with a cut-off separator
+	name := strings.Trim(b.String(), "-.")
+	if len(name) > 253 {
+		name = strings.TrimRight(name[:253], "-.")
+	}
+	if name == "" {
+		return "", fmt.Errorf("item title %q has no characters valid in a Kubernetes Secret name", title)
+	}
+	return name, nil
+}
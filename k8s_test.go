@@ -0,0 +1,95 @@
+package onepassword
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func TestRenderK8sSecret(t *testing.T) {
+	got, err := renderK8sSecret("db-credentials", "backend", map[string]string{
+		"username": "admin",
+		"api key":  "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("renderK8sSecret() error = %v", err)
+	}
+
+	want := `apiVersion: v1
+kind: Secret
+metadata:
+  name: db-credentials
+  namespace: backend
+type: Opaque
+data:
+  "api_key": czNjcmV0
+  "username": YWRtaW4=
+`
+	if string(got) != want {
+		t.Errorf("renderK8sSecret() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderK8sSecret_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		namespace string
+		fields    map[string]string
+	}{
+		{"uppercase name", "DB", "", nil},
+		{"empty name", "", "", nil},
+		{"dotted namespace", "db", "a.b", nil},
+		{"colliding keys", "db", "", map[string]string{"api key": "a", "api_key": "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := renderK8sSecret(tt.secret, tt.namespace, tt.fields); err == nil {
+				t.Error("renderK8sSecret() should fail")
+			}
+		})
+	}
+}
+
+func TestK8sSecretName(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Database", "database"},
+		{"Prod DB Credentials", "prod-db-credentials"},
+		{"  API/Key!", "api-key"},
+		{"tls.example.com", "tls.example.com"},
+		{strings.Repeat("-", 10) + strings.Repeat("a", 300), strings.Repeat("a", 253)},
+		{strings.Repeat("a", 252) + " b", strings.Repeat("a", 252)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := k8sSecretName(tt.title)
+			if err != nil || got != tt.want {
+				t.Errorf("k8sSecretName(%q) = %q, %v; want %q", tt.title, got, err, tt.want)
+			}
+		})
+	}
+
+	for _, title := range []string{"", "!!!", "日本"} {
+		if got, err := k8sSecretName(title); err == nil {
+			t.Errorf("k8sSecretName(%q) = %q, want error", title, got)
+		}
+	}
+}
+
+func TestExportK8sSecrets_Collision(t *testing.T) {
+	m := testMockAPI()
+	m.items["v1"] = append(m.items["v1"], op.Item{ID: "i3", Title: "database", VaultID: "v1", Fields: []op.ItemField{{Title: "password", Value: "x"}}})
+	p := newMockProvider(m, Config{})
+
+	_, err := p.ExportK8sSecrets(context.Background(), "Private/", "")
+	if err == nil || !strings.Contains(err.Error(), `same Secret name "database"`) {
+		t.Errorf("ExportK8sSecrets() error = %v, want a name collision", err)
+	}
+}
@@ -0,0 +1,308 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// globMatcher matches "vault/item" paths against a glob pattern, one
+// component at a time, so * never crosses from the vault into the item.
+type globMatcher struct {
+	vault *regexp.Regexp
+	item  *regexp.Regexp // nil matches every item
+}
+
+// compileGlob compiles a "vault/item" glob. * matches any run of
+// characters, ? any one character, and [...] a character class ([!...]
+// negated); a backslash makes the next character literal, including a
+// slash in a name. A pattern without an item part matches every item of
+// the matching vaults.
+func compileGlob(pattern string) (*globMatcher, error) {
+	vaultPattern, itemPattern, hasItem := cutUnescaped(pattern, '/')
+
+	m := &globMatcher{}
+	var err error
+	if m.vault, err = globRegexp(vaultPattern); err != nil {
+		return nil, err
+	}
+	if hasItem {
+		if m.item, err = globRegexp(itemPattern); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// matchVault reports whether the vault may hold matching items.
+func (m *globMatcher) matchVault(title string) bool {
+	return m.vault.MatchString(title)
+}
+
+// matchItem reports whether the item matches.
+func (m *globMatcher) matchItem(title string) bool {
+	return m.item == nil || m.item.MatchString(title)
+}
+
+// cutUnescaped splits s around the first sep not preceded by a backslash.
+func cutUnescaped(s string, sep rune) (before, after string, found bool) {
+	escaped := false
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// globRegexp converts a glob over one path component to an anchored
+// regular expression.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(runes[i])))
+			} else {
+				b.WriteString(`\\`)
+			}
+		case '[':
+			end := i + 1
+			if end < len(runes) && runes[end] == '!' {
+				end++
+			}
+			if end < len(runes) && runes[end] == ']' {
+				end++
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("%w: unterminated character class in %q", ErrInvalidPath, glob)
+			}
+			class := runes[i+1 : end]
+			b.WriteString("[")
+			if len(class) > 0 && class[0] == '!' {
+				b.WriteString("^")
+				class = class[1:]
+			}
+			for _, c := range class {
+				if c == '\\' || c == '[' || c == ']' || c == '^' {
+					b.WriteString(`\`)
+				}
+				b.WriteRune(c)
+			}
+			b.WriteString("]")
+			i = end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// listMatcher returns the vault and item filters for a List prefix or, if
+// glob, a ListGlob pattern. A leading "op://" is ignored.
+func listMatcher(prefix string, glob bool) (func(op.VaultOverview) bool, func(op.VaultOverview, op.ItemOverview) bool, error) {
+	prefix = strings.TrimPrefix(prefix, "op://")
+	if glob {
+		m, err := compileGlob(prefix)
+		if err != nil {
+			return nil, nil, err
+		}
+		return func(v op.VaultOverview) bool { return m.matchVault(v.Title) },
+			func(_ op.VaultOverview, item op.ItemOverview) bool { return m.matchItem(item.Title) },
+			nil
+	}
+
+	return func(v op.VaultOverview) bool {
+			// Filter by prefix if it specifies a vault
+			vaultPath := EscapePathComponent(v.Title)
+			return prefix == "" || strings.HasPrefix(vaultPath, prefix) || strings.HasPrefix(prefix, vaultPath+"/")
+		},
+		func(v op.VaultOverview, item op.ItemOverview) bool {
+			return prefix == "" || strings.HasPrefix(BuildPath(v.Title, item.Title), prefix)
+		},
+		nil
+}
+
+// ListGlob lists the "vault/item" paths, as returned by List, that match a
+// glob pattern, matched against the whole path. * matches any run of
+// characters but never crosses the slash between vault and item, ? matches
+// one character, and [...] a character class ([!...] negated); a
+// backslash makes the next character literal. A pattern without an item
+// part matches every item of the matching vaults.
+//
+//	paths, err := provider.ListGlob(ctx, "Work/api-*-prod")
+func (p *Provider) ListGlob(ctx context.Context, pattern string) ([]string, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("ListGlob", pattern, ProviderName, vault.ErrClosed)
+	}
+
+	wantVault, wantItem, err := listMatcher(pattern, true)
+	if err != nil {
+		return nil, vault.NewVaultError("ListGlob", pattern, ProviderName, err)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.List)
+	defer cancel()
+
+	var results []string
+	err = p.walkItems(ctx, wantVault, func(v op.VaultOverview, item op.ItemOverview) bool {
+		if wantItem(v, item) {
+			results = append(results, p.listedPath(BuildPath(v.Title, item.Title)))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, mapError("ListGlob", pattern, err)
+	}
+	return results, nil
+}
+
+// ListRegex lists the "vault/item" paths, as returned by List, that match
+// a regular expression. The expression isn't anchored; use ^ and $ to
+// match whole paths.
+//
+//	paths, err := provider.ListRegex(ctx, `^Work/api-(eu|us)-prod$`)
+func (p *Provider) ListRegex(ctx context.Context, pattern string) ([]string, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("ListRegex", pattern, ProviderName, vault.ErrClosed)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, vault.NewVaultError("ListRegex", pattern, ProviderName, fmt.Errorf("%w: %w", ErrInvalidPath, err))
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.List)
+	defer cancel()
+
+	var results []string
+	err = p.walkItems(ctx,
+		func(op.VaultOverview) bool { return true },
+		func(v op.VaultOverview, item op.ItemOverview) bool {
+			if path := BuildPath(v.Title, item.Title); re.MatchString(path) {
+				results = append(results, p.listedPath(path))
+			}
+			return true
+		})
+	if err != nil {
+		return nil, mapError("ListRegex", pattern, err)
+	}
+	return results, nil
+}
+
+// DefaultListPageSize is the page size ListPage uses for a limit of zero.
+const DefaultListPageSize = 100
+
+// ListPage returns one page of the paths List returns for prefix, in
+// lexical order, and a cursor for the next page, which is empty after the
+// last page. Pass an empty cursor for the first page. A limit of zero or
+// less uses DefaultListPageSize.
+//
+// Each page lists the vaults again but holds at most limit paths in
+// memory. Changes between pages don't disturb paging: no path is
+// repeated, and items added or removed after the cursor appear or
+// disappear as expected. Items sharing a path are listed once.
+//
+//	cursor := ""
+//	for {
+//	    paths, next, err := provider.ListPage(ctx, "Production/", cursor, 50)
+//	    ...
+//	    if next == "" {
+//	        break
+//	    }
+//	    cursor = next
+//	}
+func (p *Provider) ListPage(ctx context.Context, prefix, cursor string, limit int) ([]string, string, error) {
+	if p.closed.Load() {
+		return nil, "", vault.NewVaultError("ListPage", prefix, ProviderName, vault.ErrClosed)
+	}
+	if limit <= 0 {
+		limit = DefaultListPageSize
+	}
+
+	after, err := decodeListCursor(cursor)
+	if err != nil {
+		return nil, "", vault.NewVaultError("ListPage", prefix, ProviderName, err)
+	}
+	wantVault, wantItem, err := listMatcher(prefix, false)
+	if err != nil {
+		return nil, "", vault.NewVaultError("ListPage", prefix, ProviderName, err)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.List)
+	defer cancel()
+
+	// Keep the limit+1 smallest paths after the cursor; the extra one tells
+	// whether there is another page
+	page := make([]string, 0, limit+1)
+	err = p.walkItems(ctx, wantVault, func(v op.VaultOverview, item op.ItemOverview) bool {
+		if !wantItem(v, item) {
+			return true
+		}
+		path := BuildPath(v.Title, item.Title)
+		if path <= after {
+			return true
+		}
+		i, found := slices.BinarySearch(page, path)
+		if found || (len(page) == limit+1 && i == len(page)) {
+			return true
+		}
+		if len(page) == limit+1 {
+			page = page[:limit]
+		}
+		page = slices.Insert(page, i, path)
+		return true
+	})
+	if err != nil {
+		return nil, "", mapError("ListPage", prefix, err)
+	}
+
+	next := ""
+	if len(page) > limit {
+		page = page[:limit]
+		next = encodeListCursor(page[limit-1])
+	}
+	for i := range page {
+		page[i] = p.listedPath(page[i])
+	}
+	return page, next, nil
+}
+
+// encodeListCursor returns the cursor for the page after path.
+func encodeListCursor(path string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(path))
+}
+
+// decodeListCursor returns the path a cursor continues after.
+func decodeListCursor(cursor string) (string, error) {
+	path, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid cursor", ErrInvalidPath)
+	}
+	return string(path), nil
+}
@@ -0,0 +1,140 @@
+package onepassword
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func listMockAPI() *mockAPI {
+	m := testMockAPI()
+	m.vaults = append(m.vaults, op.VaultOverview{ID: "v3", Title: "Work/Legacy"})
+	m.items["v2"] = append(m.items["v2"],
+		op.Item{ID: "i3", Title: "api-eu-prod", VaultID: "v2"},
+		op.Item{ID: "i4", Title: "api-us-prod", VaultID: "v2"},
+		op.Item{ID: "i5", Title: "api-eu-dev", VaultID: "v2"},
+		op.Item{ID: "i6", Title: "a*b", VaultID: "v2"},
+	)
+	m.items["v3"] = []op.Item{{ID: "i7", Title: "api-old-prod", VaultID: "v3"}}
+	return m
+}
+
+func TestProvider_ListGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"Work/api-*-prod", []string{"Work/api-eu-prod", "Work/api-us-prod"}},
+		{"Work/api-??-*", []string{"Work/api-eu-prod", "Work/api-us-prod", "Work/api-eu-dev"}},
+		{"Work/api-[!u]*", []string{"Work/api-eu-prod", "Work/api-eu-dev"}},
+		{"W*/api-eu-prod", []string{"Work/api-eu-prod"}},
+		{`Work\/L*/*`, []string{`Work\/Legacy/api-old-prod`}},
+		{`Work/a\**`, []string{"Work/a*b"}},
+		{"Pri*", []string{"Private/Database"}},
+		{"*/Data*", []string{"Private/Database"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p := newMockProvider(listMockAPI(), Config{})
+			got, err := p.ListGlob(t.Context(), tt.pattern)
+			if err != nil {
+				t.Fatalf("ListGlob() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListGlob(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+
+	p := newMockProvider(listMockAPI(), Config{})
+	if _, err := p.ListGlob(t.Context(), "Work/[abc"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("ListGlob(malformed) error = %v, want ErrInvalidPath", err)
+	}
+
+	// List matches glob metacharacters literally
+	if got, err := p.List(t.Context(), BuildPath("Work", "a*")); err != nil || !reflect.DeepEqual(got, []string{"Work/a*b"}) {
+		t.Errorf("List(Work/a*) = %v, %v; want [Work/a*b]", got, err)
+	}
+	if got, err := p.List(t.Context(), "Work/[abc"); err != nil || len(got) != 0 {
+		t.Errorf("List(Work/[abc) = %v, %v; want nothing", got, err)
+	}
+}
+
+func TestProvider_ListRegex(t *testing.T) {
+	p := newMockProvider(listMockAPI(), Config{})
+
+	got, err := p.ListRegex(t.Context(), `^Work/api-(eu|us)-prod$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Work/api-eu-prod", "Work/api-us-prod"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListRegex() = %v, want %v", got, want)
+	}
+
+	got, err = p.ListRegex(t.Context(), `old`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{`Work\/Legacy/api-old-prod`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListRegex() = %v, want %v", got, want)
+	}
+
+	if _, err := p.ListRegex(t.Context(), `(`); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("ListRegex(malformed) error = %v, want ErrInvalidPath", err)
+	}
+}
+
+func TestProvider_ListPage(t *testing.T) {
+	ctx := t.Context()
+	m := listMockAPI()
+	p := newMockProvider(m, Config{})
+
+	all, err := p.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(all)
+
+	var got []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > len(all) {
+			t.Fatal("ListPage() didn't finish")
+		}
+		page, next, err := p.ListPage(ctx, "", cursor, 3)
+		if err != nil {
+			t.Fatalf("ListPage() error = %v", err)
+		}
+		if len(page) > 3 {
+			t.Fatalf("ListPage() returned %d paths, want at most 3", len(page))
+		}
+		got = append(got, page...)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if !reflect.DeepEqual(got, all) {
+		t.Errorf("pages = %v, want %v", got, all)
+	}
+
+	page, next, err := p.ListPage(ctx, "Work/api-eu", "", 1)
+	if err != nil || !reflect.DeepEqual(page, []string{"Work/api-eu-dev"}) || next == "" {
+		t.Fatalf("ListPage(prefix) = %v, %q, %v", page, next, err)
+	}
+
+	// A removed item doesn't disturb the next page
+	m.items["v2"] = slices.DeleteFunc(m.items["v2"], func(item op.Item) bool { return item.Title == "api-eu-dev" })
+	page, next, err = p.ListPage(ctx, "Work/api-eu", next, 1)
+	if err != nil || !reflect.DeepEqual(page, []string{"Work/api-eu-prod"}) || next != "" {
+		t.Errorf("ListPage(prefix, next) = %v, %q, %v", page, next, err)
+	}
+
+	if _, _, err := p.ListPage(ctx, "", "not a cursor!", 1); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("ListPage(bad cursor) error = %v, want ErrInvalidPath", err)
+	}
+}
@@ -0,0 +1,139 @@
+package onepassword
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// DefaultLiveInterval is how often GetLive refreshes a secret.
+const DefaultLiveInterval = time.Minute
+
+// LiveOptions configures GetLiveWithOptions.
+type LiveOptions struct {
+	// Interval is how often the secret is refreshed (see Watch).
+	// Default: 1 minute
+	Interval time.Duration
+}
+
+// LiveSecret is a handle to a secret that is refreshed in the background,
+// so rotated credentials are picked up without restarting. It is safe for
+// concurrent use.
+type LiveSecret struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu        sync.RWMutex
+	secret    *vault.Secret
+	err       error
+	callbacks []func(SecretChange)
+}
+
+// GetLive reads the secret at path and keeps it current by polling every
+// DefaultLiveInterval. Call Close, or cancel ctx, to stop refreshing.
+//
+//	password, err := provider.GetLive(ctx, "Prod/Database/password")
+//	defer password.Close()
+//	password.OnChange(func(c onepassword.SecretChange) { pool.Reset() })
+//	db.Connect(password.Value())
+func (p *Provider) GetLive(ctx context.Context, path string) (*LiveSecret, error) {
+	return p.GetLiveWithOptions(ctx, path, LiveOptions{})
+}
+
+// GetLiveWithOptions is GetLive with options.
+func (p *Provider) GetLiveWithOptions(ctx context.Context, path string, opts LiveOptions) (*LiveSecret, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultLiveInterval
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	current, changes, err := p.watch(ctx, path, opts.Interval)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return newLiveSecret(current, changes, cancel), nil
+}
+
+// newLiveSecret returns a LiveSecret starting at current and updated from
+// changes until the channel is closed.
+func newLiveSecret(current *vault.Secret, changes <-chan SecretChange, cancel context.CancelFunc) *LiveSecret {
+	l := &LiveSecret{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		secret: current,
+	}
+	go l.consume(changes)
+	return l
+}
+
+// consume applies changes and runs callbacks.
+func (l *LiveSecret) consume(changes <-chan SecretChange) {
+	defer close(l.done)
+
+	for change := range changes {
+		l.mu.Lock()
+		switch change.Type {
+		case ChangeUpdated, ChangeCreated:
+			l.secret, l.err = change.Secret, nil
+		case ChangeDeleted:
+			l.err = vault.NewVaultError("GetLive", change.Path, ProviderName, vault.ErrSecretNotFound)
+		case ChangeError:
+			l.err = change.Err
+		}
+		callbacks := l.callbacks
+		l.mu.Unlock()
+
+		for _, fn := range callbacks {
+			fn(change)
+		}
+	}
+}
+
+// Value returns the current value. If the secret has been deleted or the
+// last refresh failed, the last value read is returned; see Err.
+func (l *LiveSecret) Value() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.secret.Value
+}
+
+// Field returns the current value of the named field.
+func (l *LiveSecret) Field(name string) string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.secret.GetField(name)
+}
+
+// Secret returns the current secret. It must not be modified.
+func (l *LiveSecret) Secret() *vault.Secret {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.secret
+}
+
+// Err returns the error from the last refresh, or nil if it succeeded.
+func (l *LiveSecret) Err() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.err
+}
+
+// OnChange registers fn to be called with every change, including refresh
+// errors. Callbacks run one at a time on the refresh goroutine, so a slow
+// callback delays the next refresh.
+func (l *LiveSecret) OnChange(fn func(SecretChange)) {
+	l.mu.Lock()
+	l.callbacks = append(l.callbacks, fn)
+	l.mu.Unlock()
+}
+
+// Close stops refreshing and waits for running callbacks to return. The last
+// value remains readable.
+func (l *LiveSecret) Close() error {
+	l.cancel()
+	<-l.done
+	return nil
+}
@@ -0,0 +1,41 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestLiveSecret(t *testing.T) {
+	changes := make(chan SecretChange)
+	_, cancel := context.WithCancel(context.Background())
+	live := newLiveSecret(secretVersion("v1", "1"), changes, cancel)
+
+	var seen []ChangeType
+	live.OnChange(func(c SecretChange) { seen = append(seen, c.Type) })
+
+	if live.Value() != "v1" || live.Err() != nil {
+		t.Fatalf("Value() = %q, Err() = %v; want v1, nil", live.Value(), live.Err())
+	}
+
+	changes <- SecretChange{Type: ChangeUpdated, Secret: &vault.Secret{Value: "v2", Fields: map[string]string{"user": "admin"}}}
+	changes <- SecretChange{Type: ChangeError, Err: errors.New("rate limited")}
+	changes <- SecretChange{Type: ChangeDeleted, Path: "V/I/f"}
+	close(changes)
+	live.Close()
+
+	if live.Value() != "v2" {
+		t.Errorf("Value() = %q, want last value 'v2'", live.Value())
+	}
+	if live.Field("user") != "admin" {
+		t.Errorf("Field(user) = %q, want 'admin'", live.Field("user"))
+	}
+	if !errors.Is(live.Err(), vault.ErrSecretNotFound) {
+		t.Errorf("Err() = %v, want ErrSecretNotFound", live.Err())
+	}
+	if len(seen) != 3 {
+		t.Errorf("OnChange saw %v, want 3 changes", seen)
+	}
+}
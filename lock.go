@@ -0,0 +1,56 @@
+package onepassword
+
+import "sync"
+
+// itemLocks hands out one mutex per item, so writes to different items can
+// run at once while writes to the same item stay serialized. Locks are
+// dropped once no writer holds or waits for them.
+type itemLocks struct {
+	mu    sync.Mutex
+	locks map[string]*itemLock
+}
+
+// itemLock is the mutex of one item and the number of writers using it.
+type itemLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lock locks the item named by key and returns the function unlocking it.
+func (l *itemLocks) lock(key string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*itemLock)
+	}
+	lk := l.locks[key]
+	if lk == nil {
+		lk = &itemLock{}
+		l.locks[key] = lk
+	}
+	lk.refs++
+	l.mu.Unlock()
+
+	lk.mu.Lock()
+	return func() {
+		lk.mu.Unlock()
+		l.mu.Lock()
+		if lk.refs--; lk.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// lockItem serializes a write to the item named by parsed with other writes
+// to it. Other writes, such as SetBytes or Move, take writeMu exclusively,
+// so they wait for item writes in progress and hold off new ones.
+// Items are keyed by the vault and item as written in the path, so a write
+// by title and one by ID to the same item aren't serialized.
+func (p *Provider) lockItem(parsed *ParsedPath) func() {
+	p.writeMu.RLock()
+	unlock := p.itemLocks.lock(parsed.Vault + "\x00" + parsed.Item)
+	return func() {
+		unlock()
+		p.writeMu.RUnlock()
+	}
+}
@@ -0,0 +1,41 @@
+package onepassword
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestItemLocks(t *testing.T) {
+	var l itemLocks
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		inFlight = make(map[string]int)
+	)
+	for i := range 40 {
+		key := []string{"a", "b"}[i%2]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := l.lock(key)
+			defer unlock()
+
+			mu.Lock()
+			inFlight[key]++
+			n := inFlight[key]
+			mu.Unlock()
+			if n > 1 {
+				t.Errorf("%d writers hold the lock of %q", n, key)
+			}
+			mu.Lock()
+			inFlight[key]--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if len(l.locks) != 0 {
+		t.Errorf("%d locks left after all were released", len(l.locks))
+	}
+}
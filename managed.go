@@ -0,0 +1,82 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ErrUnmanaged is returned when Config.ProtectUnmanaged refuses to update or
+// delete an item without the Config.ManagedTag marker. It wraps
+// vault.ErrAccessDenied.
+var ErrUnmanaged error = &sentinelError{"item not managed by this provider", []error{vault.ErrAccessDenied}}
+
+// managedItems tags the items created through it with Config.ManagedTag
+// and, with Config.ProtectUnmanaged, refuses to update or delete items
+// that don't carry the tag. Protection costs a read of the item before
+// each update or delete.
+type managedItems struct {
+	itemsAPI
+	tag     string
+	protect bool
+}
+
+func (m managedItems) Create(ctx context.Context, params op.ItemCreateParams) (op.Item, error) {
+	if !slices.Contains(params.Tags, m.tag) {
+		params.Tags = append(slices.Clone(params.Tags), m.tag)
+	}
+	return m.itemsAPI.Create(ctx, params)
+}
+
+func (m managedItems) Put(ctx context.Context, item op.Item) (op.Item, error) {
+	if m.protect {
+		if err := m.check(ctx, item.VaultID, item.ID); err != nil {
+			return op.Item{}, err
+		}
+	}
+	return m.itemsAPI.Put(ctx, item)
+}
+
+func (m managedItems) Delete(ctx context.Context, vaultID, itemID string) error {
+	if m.protect {
+		// A missing item is left for Delete to report
+		if err := m.check(ctx, vaultID, itemID); err != nil && !isNotFoundError(err) {
+			return err
+		}
+	}
+	return m.itemsAPI.Delete(ctx, vaultID, itemID)
+}
+
+// check returns ErrUnmanaged if the stored item lacks the marker tag.
+func (m managedItems) check(ctx context.Context, vaultID, itemID string) error {
+	current, err := m.itemsAPI.Get(ctx, vaultID, itemID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(current.Tags, m.tag) {
+		return ErrUnmanaged
+	}
+	return nil
+}
+
+// checkManaged returns ErrUnmanaged if writes to item are refused by
+// Config.ProtectUnmanaged. Writes that create a replacement before
+// deleting the original check first, so a refusal doesn't leave both.
+func (p *Provider) checkManaged(item op.Item) error {
+	if p.config.ManagedTag != "" && p.config.ProtectUnmanaged && !slices.Contains(item.Tags, p.config.ManagedTag) {
+		return ErrUnmanaged
+	}
+	return nil
+}
+
+// keepManagedTag adds the marker tag to tags if the item's current tags
+// have it, so replacing an item's tags doesn't unmark it.
+func (p *Provider) keepManagedTag(current, tags []string) []string {
+	tag := p.config.ManagedTag
+	if tag != "" && slices.Contains(current, tag) && !slices.Contains(tags, tag) {
+		return append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,69 @@
+package onepassword
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+const testManagedTag = "managed-by:omnivault"
+
+func TestManagedTag_Create(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{ManagedTag: testManagedTag})
+
+	secret := &vault.Secret{Value: "x", Metadata: vault.Metadata{Tags: map[string]string{"env": "dev"}}}
+	if err := p.Set(t.Context(), "Private/New/password", secret); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if tags := m.created[0].Tags; !slices.Contains(tags, testManagedTag) || !slices.Contains(tags, "env:dev") {
+		t.Errorf("created tags = %v, want env:dev and %s", tags, testManagedTag)
+	}
+
+	// Without protection, unmarked items can still be written
+	if err := p.Set(t.Context(), "Private/Database/password", &vault.Secret{Value: "y"}); err != nil {
+		t.Errorf("Set() unmarked error = %v", err)
+	}
+}
+
+func TestProtectUnmanaged(t *testing.T) {
+	config := Config{ManagedTag: testManagedTag, ProtectUnmanaged: true}
+
+	t.Run("unmarked", func(t *testing.T) {
+		m := testMockAPI()
+		p := newMockProvider(m, config)
+
+		err := p.Set(t.Context(), "Private/Database/password", &vault.Secret{Value: "x"})
+		if !errors.Is(err, ErrUnmanaged) || !errors.Is(err, vault.ErrAccessDenied) {
+			t.Errorf("Set() error = %v, want ErrUnmanaged", err)
+		}
+		if err := p.Delete(t.Context(), "Private/Database"); !errors.Is(err, ErrUnmanaged) {
+			t.Errorf("Delete() error = %v, want ErrUnmanaged", err)
+		}
+		if err := p.Move(t.Context(), "Private/Database", "Work/Database"); !errors.Is(err, ErrUnmanaged) {
+			t.Errorf("Move() error = %v, want ErrUnmanaged", err)
+		}
+		if len(m.put) != 0 || len(m.deleted) != 0 || len(m.created) != 0 {
+			t.Errorf("wrote unmanaged item: put %d, deleted %v, created %d", len(m.put), m.deleted, len(m.created))
+		}
+	})
+
+	t.Run("marked", func(t *testing.T) {
+		m := testMockAPI()
+		m.items["v1"][0].Tags = []string{"env:prod", testManagedTag}
+		p := newMockProvider(m, config)
+
+		secret := &vault.Secret{Value: "x", Metadata: vault.Metadata{Tags: map[string]string{"team": "infra"}}}
+		if err := p.SetWithOptions(t.Context(), "Private/Database/password", secret, SetOptions{Tags: TagsReplace}); err != nil {
+			t.Fatalf("SetWithOptions() error = %v", err)
+		}
+		if tags := m.put[0].Tags; !slices.Equal(tags, []string{"team:infra", testManagedTag}) {
+			t.Errorf("replaced tags = %v, want the marker kept", tags)
+		}
+		if err := p.Delete(t.Context(), "Private/Database"); err != nil {
+			t.Errorf("Delete() error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// SecretBuffer holds a secret value in a byte slice that can be zeroed
+// once it's no longer needed, unlike a string. A buffer that is garbage
+// collected without being destroyed is zeroed then.
+//
+// The value still passes through strings inside the 1Password SDK, which
+// Go can't zero; a SecretBuffer only bounds how long this package's copy
+// lives.
+type SecretBuffer struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+// NewSecretBuffer returns a buffer holding a copy of value.
+func NewSecretBuffer(value []byte) *SecretBuffer {
+	b := &SecretBuffer{data: append([]byte(nil), value...)}
+	runtime.AddCleanup(b, zero, b.data)
+	return b
+}
+
+// Bytes returns the value. The slice is zeroed by Destroy, so it must not
+// be used afterwards; it is nil once the buffer is destroyed.
+func (b *SecretBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.data
+}
+
+// Len returns the length of the value.
+func (b *SecretBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.data)
+}
+
+// Destroy zeroes the value. It is safe to call more than once.
+func (b *SecretBuffer) Destroy() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	zero(b.data)
+	b.data = nil
+}
+
+// Destroyed reports whether Destroy was called.
+func (b *SecretBuffer) Destroyed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.data == nil
+}
+
+// String returns RedactedText, so printing a buffer never shows its value.
+func (b *SecretBuffer) String() string {
+	return RedactedText
+}
+
+// Format writes RedactedText for every verb.
+func (b *SecretBuffer) Format(f fmt.State, _ rune) {
+	fmt.Fprint(f, RedactedText)
+}
+
+// zero overwrites data with zeros.
+func zero(data []byte) {
+	clear(data)
+	runtime.KeepAlive(data)
+}
+
+// GetSecretBuffer retrieves the value of a secret into a SecretBuffer. For
+// an item path this is the item's primary value, as in Secret.Value. The
+// caller must Destroy the buffer when done with it.
+func (p *Provider) GetSecretBuffer(ctx context.Context, path string) (*SecretBuffer, error) {
+	secret, err := p.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return NewSecretBuffer([]byte(secret.Value)), nil
+}
+
+// WithSecret calls fn with the value of a secret and zeroes it when fn
+// returns. fn must not retain the slice.
+//
+//	err := provider.WithSecret(ctx, "Private/Signing/key", func(key []byte) error {
+//	    return sign(key, payload)
+//	})
+func (p *Provider) WithSecret(ctx context.Context, path string, fn func(value []byte) error) error {
+	buf, err := p.GetSecretBuffer(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer buf.Destroy()
+	return fn(buf.Bytes())
+}
+
+// Wipe clears every cache the provider keeps: vault name lookups, item
+// listings, and the disk cache. With Config.ZeroizeSecrets set, Close
+// calls it.
+func (p *Provider) Wipe() error {
+	p.vaultCache.clear()
+	if p.itemCache != nil {
+		p.itemCache.clear()
+	}
+	if p.diskCache != nil {
+		if err := p.diskCache.clear(); err != nil {
+			return vault.NewVaultError("Wipe", "", ProviderName, err)
+		}
+	}
+	return nil
+}
+
+// clear drops all cached vault IDs and misses.
+func (c *vaultCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids = make(map[string]string)
+	c.misses = make(map[string]time.Time)
+	c.expires = time.Time{}
+}
+
+// clear drops all cached item listings.
+func (c *itemCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listings = make(map[string]itemListing)
+}
@@ -0,0 +1,96 @@
+package onepassword
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSecretBuffer(t *testing.T) {
+	value := []byte("hunter2")
+	b := NewSecretBuffer(value)
+
+	value[0] = 'x'
+	if got := string(b.Bytes()); got != "hunter2" {
+		t.Errorf("Bytes() = %q, want a copy of the value", got)
+	}
+
+	data := b.Bytes()
+	b.Destroy()
+	if !bytes.Equal(data, make([]byte, len(data))) {
+		t.Errorf("Destroy() left %q", data)
+	}
+	if !b.Destroyed() || b.Bytes() != nil || b.Len() != 0 {
+		t.Error("buffer still holds a value after Destroy()")
+	}
+	b.Destroy()
+}
+
+func TestProvider_WithSecret(t *testing.T) {
+	p := newMockProvider(testMockAPI(), Config{})
+
+	var seen []byte
+	err := p.WithSecret(t.Context(), "Private/Database/username", func(value []byte) error {
+		if string(value) != "admin" {
+			t.Errorf("value = %q, want admin", value)
+		}
+		seen = value
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithSecret() error = %v", err)
+	}
+	if !bytes.Equal(seen, make([]byte, len(seen))) {
+		t.Errorf("value not zeroed after WithSecret: %q", seen)
+	}
+
+	want := errors.New("boom")
+	if err := p.WithSecret(t.Context(), "Private/Database/username", func([]byte) error { return want }); !errors.Is(err, want) {
+		t.Errorf("WithSecret() error = %v, want %v", err, want)
+	}
+}
+
+func TestProvider_Wipe(t *testing.T) {
+	ctx := t.Context()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{CacheTTL: time.Hour, CacheDir: t.TempDir(), CacheKey: []byte("key")})
+
+	if _, err := p.Get(ctx, "Private/Database"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := p.vaultCache.get("Private"); !found {
+		t.Fatal("vault cache empty before Wipe()")
+	}
+	if err := p.Wipe(); err != nil {
+		t.Fatalf("Wipe() error = %v", err)
+	}
+
+	if _, found, _ := p.vaultCache.get("Private"); found {
+		t.Error("vault cache not cleared")
+	}
+	if _, ok := p.itemCache.get("v1"); ok {
+		t.Error("item cache not cleared")
+	}
+	if _, ok := p.diskCache.get(&ParsedPath{Vault: "Private", Item: "Database"}, "item:Private/Database"); ok {
+		t.Error("disk cache not cleared")
+	}
+}
+
+func TestZeroizeSecrets(t *testing.T) {
+	ctx := t.Context()
+	p := newMockProvider(testMockAPI(), Config{ZeroizeSecrets: true, CacheDir: t.TempDir(), CacheKey: []byte("key")})
+	if p.diskCache != nil {
+		t.Error("disk cache enabled with ZeroizeSecrets")
+	}
+
+	if _, err := p.Get(ctx, "Private/Database"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := p.vaultCache.get("Private"); found {
+		t.Error("Close() didn't wipe the vault cache")
+	}
+}
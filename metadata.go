@@ -0,0 +1,149 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// FieldNamesKey is the Metadata.Extra key listing an item's field names, in
+// item order, as GetMetadata reports them. The names are the Secret.Fields
+// keys that Get would use.
+const FieldNamesKey = "fieldNames"
+
+// GetMetadata returns the metadata of an item without any secret material:
+// its version, tags, category, IDs, and field names in
+// Extra[FieldNamesKey]. It is meant for inventory and policy tooling that
+// shouldn't handle secret values.
+//
+// The SDK has no metadata-only read, so the item is fetched and its values
+// are dropped before anything is returned. Timestamps aren't reported by
+// the SDK and are left nil. A field path reports the field's item and
+// fails with ErrFieldNotFound if the field doesn't exist.
+func (p *Provider) GetMetadata(ctx context.Context, path string) (*vault.Metadata, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("GetMetadata", path, ProviderName, vault.ErrClosed)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return nil, vault.NewVaultError("GetMetadata", path, ProviderName, err)
+	}
+
+	item, err := p.fetchItem(ctx, parsed)
+	if err != nil {
+		return nil, mapError("GetMetadata", path, err)
+	}
+
+	if parsed.Field != "" {
+		if _, ok := findField(item, parsed.Section, parsed.Field); !ok {
+			return nil, vault.NewVaultError("GetMetadata", path, ProviderName, ErrFieldNotFound)
+		}
+	}
+
+	return p.itemMetadata(item, parsed.String()), nil
+}
+
+// itemMetadata returns the metadata of item with its field values removed.
+func (p *Provider) itemMetadata(item op.Item, path string) *vault.Metadata {
+	expires, hasExpiry := itemExpiry(item)
+
+	fields := make([]op.ItemField, len(item.Fields))
+	for i, field := range item.Fields {
+		fields[i] = op.ItemField{ID: field.ID, Title: field.Title, SectionID: field.SectionID, FieldType: field.FieldType}
+	}
+	item.Fields = fields
+
+	secret := p.toSecret(item, path)
+	secret.Metadata.Extra[FieldNamesKey] = p.secretFieldKeys(item)
+	if hasExpiry {
+		// An expiry field's value isn't secret
+		secret.Metadata.ExpiresAt = vault.NewTimestamp(expires)
+	}
+	return &secret.Metadata
+}
+
+// GetItemVersion returns the version of the item at path, as reported in
+// Metadata.Version by Get, without converting any field values. It suits
+// change-detection loops that read secrets only once the version moves:
+//
+//	if v, err := provider.GetItemVersion(ctx, "Prod/app"); err == nil && v != last {
+//	    secret, err = provider.Get(ctx, "Prod/app")
+//	}
+//
+// Item overviews in the SDK carry no version, so the item is fetched and
+// everything but its version dropped. A field path reports its item's
+// version.
+func (p *Provider) GetItemVersion(ctx context.Context, path string) (int, error) {
+	if p.closed.Load() {
+		return 0, vault.NewVaultError("GetItemVersion", path, ProviderName, vault.ErrClosed)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return 0, vault.NewVaultError("GetItemVersion", path, ProviderName, err)
+	}
+
+	item, err := p.fetchItem(ctx, parsed)
+	if err != nil {
+		return 0, mapError("GetItemVersion", path, err)
+	}
+	return int(item.Version), nil
+}
+
+// Fingerprint returns a hex SHA-256 hash of the field names and values of
+// the item at path, computed locally, so deployment tooling can record
+// which credentials a build used and later check that none changed without
+// keeping the values. Fields are named as with Config.FieldKeyStyle set to
+// FieldKeysQualified, and their order, types, and the item's tags and
+// version don't count. TOTP fields count by their secret, not the current
+// code. A field path fingerprints its item.
+//
+// The hash isn't salted, so values that can be guessed, such as short
+// passwords, can be recovered from it by trying candidates.
+func (p *Provider) Fingerprint(ctx context.Context, path string) (string, error) {
+	if p.closed.Load() {
+		return "", vault.NewVaultError("Fingerprint", path, ProviderName, vault.ErrClosed)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return "", vault.NewVaultError("Fingerprint", path, ProviderName, err)
+	}
+
+	item, err := p.fetchItem(ctx, parsed)
+	if err != nil {
+		return "", mapError("Fingerprint", path, err)
+	}
+	return fingerprint(item), nil
+}
+
+// fingerprint hashes the qualified names and values of the fields of item,
+// in any order.
+func fingerprint(item op.Item) string {
+	keys := numberKeys(qualifiedFieldNames(item))
+	entries := make([]string, len(keys))
+	for i, key := range keys {
+		entries[i] = hashEntry(key, item.Fields[i].Value)
+	}
+	slices.Sort(entries)
+
+	h := sha256.New()
+	for _, entry := range entries {
+		h.Write([]byte(entry))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,107 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetMetadata(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(sectionedMockAPI(), Config{})
+
+	meta, err := p.GetMetadata(ctx, "Private/Database")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+
+	if meta.Version != "3" || meta.Tags["env"] != "prod" || meta.Path != "Private/Database" {
+		t.Errorf("Metadata = %+v", meta)
+	}
+	if meta.Extra["itemId"] != "i1" {
+		t.Errorf("Extra[itemId] = %v, want i1", meta.Extra["itemId"])
+	}
+	want := []string{"username", "password", "host", "password#2"}
+	if got := meta.Extra[FieldNamesKey]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Extra[%s] = %v, want %v", FieldNamesKey, got, want)
+	}
+
+	// No secret value may leak through any metadata
+	dump := fmt.Sprintf("%+v", meta)
+	for _, value := range []string{"hunter2", "prodpass", "admin"} {
+		if strings.Contains(dump, value) {
+			t.Errorf("Metadata contains field value %q: %s", value, dump)
+		}
+	}
+}
+
+func TestGetMetadata_Field(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(testMockAPI(), Config{})
+
+	meta, err := p.GetMetadata(ctx, "Private/Database/password")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if meta.Path != "Private/Database/password" {
+		t.Errorf("Path = %q", meta.Path)
+	}
+
+	if _, err := p.GetMetadata(ctx, "Private/Database/missing"); !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("GetMetadata(missing field) error = %v, want ErrFieldNotFound", err)
+	}
+	if _, err := p.GetMetadata(ctx, "Private/Missing"); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("GetMetadata(missing item) error = %v, want ErrItemNotFound", err)
+	}
+}
+
+func TestGetItemVersion(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(testMockAPI(), Config{})
+
+	for _, path := range []string{"Private/Database", "Private/Database/password"} {
+		version, err := p.GetItemVersion(ctx, path)
+		if err != nil {
+			t.Fatalf("GetItemVersion(%q) error = %v", path, err)
+		}
+		if version != 3 {
+			t.Errorf("GetItemVersion(%q) = %d, want 3", path, version)
+		}
+	}
+	if _, err := p.GetItemVersion(ctx, "Private/Missing"); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("GetItemVersion(missing item) error = %v, want ErrItemNotFound", err)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	first, err := p.Fingerprint(ctx, "Private/Database")
+	if err != nil {
+		t.Fatalf("Fingerprint() error = %v", err)
+	}
+	if strings.Contains(first, "hunter2") || len(first) != 64 {
+		t.Errorf("Fingerprint() = %q, want a hex SHA-256", first)
+	}
+
+	// Reordering fields and bumping the version don't count
+	item := &m.items["v1"][0]
+	item.Fields[0], item.Fields[1] = item.Fields[1], item.Fields[0]
+	item.Version++
+	if got, _ := p.Fingerprint(ctx, "Private/Database/username"); got != first {
+		t.Errorf("Fingerprint() after reordering = %q, want %q", got, first)
+	}
+
+	item.Fields[0].Value = "changed"
+	if got, _ := p.Fingerprint(ctx, "Private/Database"); got == first {
+		t.Error("Fingerprint() unchanged after a value changed")
+	}
+	if _, err := p.Fingerprint(ctx, "Private/Missing"); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("Fingerprint(missing item) error = %v, want ErrItemNotFound", err)
+	}
+}
@@ -0,0 +1,101 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// Operations passed to middleware in OpRequest.Op.
+const (
+	OpGet    = "Get"
+	OpSet    = "Set"
+	OpDelete = "Delete"
+	OpList   = "List"
+)
+
+// OpRequest is an operation passed through middleware.
+type OpRequest struct {
+	// Op is OpGet, OpSet, OpDelete, or OpList.
+	Op string
+
+	// Path is the path operated on, or the prefix for OpList. Middleware
+	// may change it before calling the next function.
+	Path string
+
+	// Secret is the secret written by OpSet, and nil otherwise.
+	// Middleware may replace it.
+	Secret *vault.Secret
+
+	// Requestor and Tenant identify the caller the operation is made
+	// for, as set on its context with WithRequestor and WithTenant.
+	Requestor string
+	Tenant    string
+}
+
+// OpResponse is the result of an operation passed through middleware.
+type OpResponse struct {
+	// Secret is the secret read by OpGet.
+	Secret *vault.Secret
+
+	// Paths are the paths listed by OpList.
+	Paths []string
+}
+
+// OpFunc performs an operation. Middleware receives the next OpFunc in
+// the chain and returns one wrapping it.
+type OpFunc func(ctx context.Context, req *OpRequest) (*OpResponse, error)
+
+// Middleware wraps the operations of a provider with cross-cutting
+// behavior such as logging, metrics, policy checks, or caching.
+//
+//	provider.Use(func(next onepassword.OpFunc) onepassword.OpFunc {
+//	    return func(ctx context.Context, req *onepassword.OpRequest) (*onepassword.OpResponse, error) {
+//	        start := time.Now()
+//	        resp, err := next(ctx, req)
+//	        log.Printf("%s %s took %s: %v", req.Op, req.Path, time.Since(start), err)
+//	        return resp, err
+//	    }
+//	})
+type Middleware func(next OpFunc) OpFunc
+
+// Use adds middleware around Get, Set, Delete, and List, and their
+// variants with options. The first middleware added is outermost.
+// SetBatch and DeleteBatch pass through the middleware for each secret. Use is safe to call
+// concurrently with operations, which see the middleware added before
+// they started.
+func (p *Provider) Use(mw ...Middleware) {
+	p.middlewareMu.Lock()
+	defer p.middlewareMu.Unlock()
+	chain := slices.Concat(p.loadMiddleware(), mw)
+	p.middleware.Store(&chain)
+}
+
+// loadMiddleware returns the middleware added with Use.
+func (p *Provider) loadMiddleware() []Middleware {
+	if chain := p.middleware.Load(); chain != nil {
+		return *chain
+	}
+	return nil
+}
+
+// intercept runs fn through the middleware. A middleware returning no
+// response and no error yields a zero response.
+func (p *Provider) intercept(ctx context.Context, req *OpRequest, fn OpFunc) (*OpResponse, error) {
+	info := RequestInfoFrom(ctx)
+	req.Requestor, req.Tenant = info.Requestor, info.Tenant
+	if info.Requestor != "" {
+		p.stats.countRequest(info.Requestor)
+	}
+
+	chain := p.loadMiddleware()
+	for i := len(chain) - 1; i >= 0; i-- {
+		fn = chain[i](fn)
+	}
+	resp, err := fn(ctx, req)
+	if resp == nil {
+		resp = &OpResponse{}
+	}
+	return resp, err
+}
@@ -0,0 +1,80 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestUse(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	var trace []string
+	record := func(name string) Middleware {
+		return func(next OpFunc) OpFunc {
+			return func(ctx context.Context, req *OpRequest) (*OpResponse, error) {
+				trace = append(trace, name+" "+req.Op+" "+req.Path)
+				return next(ctx, req)
+			}
+		}
+	}
+	p.Use(record("outer"), record("inner"))
+
+	if _, err := p.Get(t.Context(), "Private/Database"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if err := p.Set(t.Context(), "Private/Database/password", &vault.Secret{Value: "x"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := p.List(t.Context(), "Private"); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if err := p.Delete(t.Context(), "Private/Database"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	want := []string{
+		"outer Get Private/Database", "inner Get Private/Database",
+		"outer Set Private/Database/password", "inner Set Private/Database/password",
+		"outer List Private", "inner List Private",
+		"outer Delete Private/Database", "inner Delete Private/Database",
+	}
+	if !reflect.DeepEqual(trace, want) {
+		t.Errorf("trace = %q, want %q", trace, want)
+	}
+}
+
+func TestUse_Rewrite(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+	denied := errors.New("writes to Work are not allowed")
+	p.Use(func(next OpFunc) OpFunc {
+		return func(ctx context.Context, req *OpRequest) (*OpResponse, error) {
+			if req.Op == OpSet && strings.HasPrefix(req.Path, "Work/") {
+				return nil, denied
+			}
+			req.Path = strings.Replace(req.Path, "/db", "/Database", 1)
+			return next(ctx, req)
+		}
+	})
+
+	secret, err := p.Get(t.Context(), "Private/db")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if secret.Fields["password"] != "hunter2" {
+		t.Errorf("Get() fields = %v, want the rewritten path's", secret.Fields)
+	}
+
+	if err := p.Set(t.Context(), "Work/API/key", &vault.Secret{Value: "x"}); !errors.Is(err, denied) {
+		t.Errorf("Set() error = %v, want the middleware's", err)
+	}
+	if len(m.put) != 0 || len(m.created) != 0 {
+		t.Errorf("denied Set wrote: put %d, created %d", len(m.put), len(m.created))
+	}
+}
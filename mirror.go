@@ -0,0 +1,199 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ErrMirror is returned by Set and Delete when the write to 1Password
+// succeeded but the write to Config.MirrorTo failed.
+var ErrMirror = errors.New("mirror write failed")
+
+// mirror applies writes to Config.MirrorTo, in order.
+type mirror struct {
+	to    vault.Vault
+	async bool
+
+	// queue holds the writes not yet applied when async; a goroutine runs
+	// them while it is non-empty
+	mu      sync.Mutex
+	queue   []func()
+	running bool
+	pending sync.WaitGroup
+}
+
+// newMirror returns a mirror to to, or nil if to is nil.
+func newMirror(to vault.Vault, async bool) *mirror {
+	if to == nil {
+		return nil
+	}
+	return &mirror{to: to, async: async}
+}
+
+// enqueue runs write after the writes queued before it.
+func (m *mirror) enqueue(write func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queue = append(m.queue, write)
+	m.pending.Add(1)
+	if !m.running {
+		m.running = true
+		go m.run()
+	}
+}
+
+// run applies queued writes until there are none.
+func (m *mirror) run() {
+	for {
+		m.mu.Lock()
+		if len(m.queue) == 0 {
+			m.running = false
+			m.mu.Unlock()
+			return
+		}
+		write := m.queue[0]
+		m.queue = m.queue[1:]
+		m.mu.Unlock()
+
+		write()
+		m.pending.Done()
+	}
+}
+
+// flush waits for the queued writes to be applied.
+func (m *mirror) flush() {
+	if m != nil {
+		m.pending.Wait()
+	}
+}
+
+// mirrorSet applies a Set of secret at path to Config.MirrorTo, if set. A
+// field is mirrored as given. An item or section is mirrored as 1Password
+// holds it after the write (see mirrorItem).
+func (p *Provider) mirrorSet(ctx context.Context, operation, path string, secret *vault.Secret) error {
+	if p.mirror == nil {
+		return nil
+	}
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return vault.NewVaultError(operation, path, ProviderName, err)
+	}
+	sectionWrite(parsed, secret)
+	if parsed.Field == "" {
+		return p.mirrorItem(ctx, operation, path, parsed)
+	}
+
+	// The caller may reuse or wipe the secret once the write returns
+	secret = syncCopy(copySecret(secret))
+	return p.mirrorWrite(ctx, operation, path, func(ctx context.Context) error {
+		return p.mirror.to.Set(ctx, path, secret)
+	})
+}
+
+// mirrorItem writes the item or section at parsed to Config.MirrorTo, if
+// set, under path, as 1Password holds it after a write: read back from it,
+// so fields a merge kept are mirrored too and the mirror doesn't drift from
+// 1Password. Like Sync, it leaves out the metadata describing the source,
+// such as its version and IDs.
+func (p *Provider) mirrorItem(ctx context.Context, operation, path string, parsed *ParsedPath) error {
+	if p.mirror == nil {
+		return nil
+	}
+	return p.mirrorWrite(ctx, operation, path, func(ctx context.Context) error {
+		written, err := p.writtenSecret(ctx, parsed)
+		if err != nil {
+			return fmt.Errorf("failed to read back the written item: %w", err)
+		}
+		return p.mirror.to.Set(ctx, path, syncCopy(written))
+	})
+}
+
+// writtenSecret reads the item or section at parsed from 1Password, past
+// the caches, as Get would return it.
+func (p *Provider) writtenSecret(ctx context.Context, parsed *ParsedPath) (*vault.Secret, error) {
+	ctx, cancel := withTimeout(withoutCache(ctx), p.config.Timeouts.Get)
+	defer cancel()
+
+	item, err := p.fetchItem(ctx, parsed)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Section != "" {
+		var fields []op.ItemField
+		for _, field := range item.Fields {
+			if inSection(item, field, parsed.Section) {
+				fields = append(fields, field)
+			}
+		}
+		item.Fields = fields
+	}
+	return p.toSecret(item, parsed.String()), nil
+}
+
+// mirrorDelete applies a Delete of path to Config.MirrorTo, if set. A
+// secret missing from the mirror is not an error.
+func (p *Provider) mirrorDelete(ctx context.Context, operation, path string) error {
+	if p.mirror == nil {
+		return nil
+	}
+	return p.mirrorWrite(ctx, operation, path, func(ctx context.Context) error {
+		return p.deleteMirrored(ctx, path)
+	})
+}
+
+// mirrorDeleteItem applies the deletion of the item at parsed to
+// Config.MirrorTo, if set. Deleting a field or section path deletes the
+// whole item from 1Password, so the item path is deleted from the mirror,
+// and path as well if it differs.
+func (p *Provider) mirrorDeleteItem(ctx context.Context, operation, path string, parsed *ParsedPath) error {
+	if p.mirror == nil {
+		return nil
+	}
+	itemPath := ParsedPath{Vault: parsed.Vault, Item: parsed.Item}.String()
+	return p.mirrorWrite(ctx, operation, path, func(ctx context.Context) error {
+		if err := p.deleteMirrored(ctx, itemPath); err != nil || path == itemPath {
+			return err
+		}
+		return p.deleteMirrored(ctx, path)
+	})
+}
+
+// deleteMirrored deletes path from the mirror, ignoring a missing secret.
+func (p *Provider) deleteMirrored(ctx context.Context, path string) error {
+	if err := p.mirror.to.Delete(ctx, path); err != nil && !errors.Is(err, vault.ErrSecretNotFound) {
+		return err
+	}
+	return nil
+}
+
+// mirrorWrite applies write to the mirror: right away, failing with
+// ErrMirror, or queued with Config.MirrorAsync, logging failures. Failures
+// are counted in Stats.MirrorFailures either way. Planned writes aren't
+// mirrored.
+func (p *Provider) mirrorWrite(ctx context.Context, operation, path string, write func(context.Context) error) error {
+	if p.planFor(ctx) != nil {
+		return nil
+	}
+
+	if !p.mirror.async {
+		if err := write(ctx); err != nil {
+			p.stats.mirrorFailures.Add(1)
+			return vault.NewVaultError(operation, path, ProviderName, fmt.Errorf("%w: %w", ErrMirror, err))
+		}
+		return nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	p.mirror.enqueue(func() {
+		if err := write(ctx); err != nil {
+			p.stats.mirrorFailures.Add(1)
+			p.logWarn(ctx, "mirror write failed", "op", operation, "path", path, "error", err)
+		}
+	})
+	return nil
+}
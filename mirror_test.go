@@ -0,0 +1,154 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agentplexus/omnivault/providers/memory"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// failingVault is a memory vault whose writes fail.
+type failingVault struct {
+	*memory.Provider
+}
+
+func (failingVault) Set(context.Context, string, *vault.Secret) error {
+	return errors.New("secondary unavailable")
+}
+
+func TestMirrorTo(t *testing.T) {
+	ctx := context.Background()
+	secondary := memory.New()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{MirrorTo: secondary})
+
+	if err := p.Set(ctx, "Private/Database/password", &vault.Secret{Value: "new"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if secret, err := secondary.Get(ctx, "Private/Database/password"); err != nil || secret.Value != "new" {
+		t.Errorf("mirror Get() = %+v, %v; want the secret written", secret, err)
+	}
+
+	// An item written whole is mirrored with the fields the merge kept
+	if err := p.Set(ctx, "Private/Database", &vault.Secret{Fields: map[string]string{"password": "newer"}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if secret, err := secondary.Get(ctx, "Private/Database"); err != nil || secret.Fields["password"] != "newer" || secret.Fields["username"] != "admin" {
+		t.Errorf("mirror Get() = %+v, %v; want the merged item", secret, err)
+	}
+
+	// Deleting a field path deletes the whole item, in the mirror too
+	if err := p.Delete(ctx, "Private/Database/password"); err != nil {
+		t.Fatalf("Delete() of a field error = %v", err)
+	}
+	for _, path := range []string{"Private/Database", "Private/Database/password"} {
+		if ok, _ := secondary.Exists(ctx, path); ok {
+			t.Errorf("mirror still has the deleted %s", path)
+		}
+	}
+
+	// A failed 1Password write isn't mirrored
+	m.err = errors.New("unauthorized")
+	if err := p.Set(ctx, "Private/Other/password", &vault.Secret{Value: "x"}); err == nil {
+		t.Fatal("Set() should fail")
+	}
+	if ok, _ := secondary.Exists(ctx, "Private/Other/password"); ok {
+		t.Error("failed write was mirrored")
+	}
+
+	// A failed mirror write is reported
+	p = newMockProvider(testMockAPI(), Config{MirrorTo: failingVault{memory.New()}})
+	if err := p.Set(ctx, "Private/Database/password", &vault.Secret{Value: "new"}); !errors.Is(err, ErrMirror) {
+		t.Errorf("Set() error = %v, want ErrMirror", err)
+	}
+	if got := p.Stats().MirrorFailures; got != 1 {
+		t.Errorf("Stats().MirrorFailures = %d, want 1", got)
+	}
+}
+
+func TestMirrorTo_Provider(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	m.items["v1"][0].Version = 9
+	secondary := testMockAPI()
+	p := newMockProvider(m, Config{MirrorTo: newMockProvider(secondary, Config{})})
+
+	// The primary's version and IDs aren't carried over to the mirror
+	if err := p.Set(ctx, "Private/Database", &vault.Secret{Fields: map[string]string{"password": "newer"}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if len(secondary.put) != 1 || findValue(secondary.put[0], "password") != "newer" {
+		t.Errorf("mirror put = %+v, want the item updated", secondary.put)
+	}
+}
+
+func TestMirrorTo_OtherWrites(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	secondary := memory.New()
+	p := newWithAPIs(m, storingItems{mockAPI: m}, mockVaults{m}, Config{MirrorTo: secondary})
+
+	if err := p.SetBytes(ctx, "Private/Blob", []byte("binary")); err != nil {
+		t.Fatalf("SetBytes() error = %v", err)
+	}
+	if _, err := p.Ensure(ctx, "Private/Ensured", &vault.Secret{Fields: map[string]string{"key": "value"}}, EnsureOptions{}); err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	if err := p.Copy(ctx, "Private/Database", "Work/Database"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if err := p.Rename(ctx, "Private/Database", "Renamed"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	for path, field := range map[string]string{
+		"Private/Blob":    BytesField,
+		"Private/Ensured": "key",
+		"Work/Database":   "username",
+		"Private/Renamed": "username",
+	} {
+		if secret, err := secondary.Get(ctx, path); err != nil || secret.Fields[field] == "" {
+			t.Errorf("mirror Get(%s) = %+v, %v; want field %s", path, secret, err, field)
+		}
+	}
+
+	if _, err := p.DeleteByPrefix(ctx, "Work/", BulkDeleteOptions{Confirm: true}); err != nil {
+		t.Fatalf("DeleteByPrefix() error = %v", err)
+	}
+	if ok, _ := secondary.Exists(ctx, "Work/Database"); ok {
+		t.Error("mirror still has the bulk-deleted item")
+	}
+}
+
+func TestMirrorTo_Async(t *testing.T) {
+	ctx := context.Background()
+	secondary := memory.New()
+	p := newMockProvider(testMockAPI(), Config{MirrorTo: secondary, MirrorAsync: true})
+
+	secret := &vault.Secret{Fields: map[string]string{"password": "first"}}
+	if err := p.Set(ctx, "Private/Database", secret); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	secret.Fields["password"] = "changed after Set"
+	if err := p.Set(ctx, "Private/Database", &vault.Secret{Fields: map[string]string{"password": "second"}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// Close waits for the writes, which are applied in order
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got, err := secondary.Get(ctx, "Private/Database"); err != nil || got.Fields["password"] != "second" {
+		t.Errorf("mirror Get() = %+v, %v; want the last write", got, err)
+	}
+
+	failing := newMockProvider(testMockAPI(), Config{MirrorTo: failingVault{memory.New()}, MirrorAsync: true})
+	if err := failing.Set(ctx, "Private/Database/password", &vault.Secret{Value: "x"}); err != nil {
+		t.Errorf("async Set() error = %v, want mirror failures only logged", err)
+	}
+	failing.Close()
+	if got := failing.Stats().MirrorFailures; got != 1 {
+		t.Errorf("Stats().MirrorFailures = %d, want 1", got)
+	}
+}
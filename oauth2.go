@@ -0,0 +1,144 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/agentplexus/omnivault/vault"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Default field titles read by OAuth2TokenSource.
+const (
+	DefaultOAuth2ClientIDField     = "client_id"
+	DefaultOAuth2ClientSecretField = "client_secret"
+	DefaultOAuth2RefreshTokenField = "refresh_token"
+)
+
+// OAuth2Config describes an OAuth2 client whose credentials live in a
+// 1Password item.
+type OAuth2Config struct {
+	// ItemPath is the item holding the client credentials.
+	ItemPath string
+
+	// Endpoint is the authorization server's token endpoint.
+	Endpoint oauth2.Endpoint
+
+	// Scopes are the requested scopes.
+	Scopes []string
+
+	// ClientIDField is the field holding the client ID.
+	// Default: "client_id"
+	ClientIDField string
+
+	// ClientSecretField is the field holding the client secret.
+	// Default: "client_secret"
+	ClientSecretField string
+
+	// RefreshTokenField is the field holding a refresh token. When the field
+	// is present and non-empty, tokens are minted with the refresh token
+	// grant and rotated refresh tokens are written back to it. Otherwise the
+	// client credentials grant is used.
+	// Default: "refresh_token"
+	RefreshTokenField string
+}
+
+// withDefaults returns a copy of the config with default field names applied.
+func (c OAuth2Config) withDefaults() OAuth2Config {
+	if c.ClientIDField == "" {
+		c.ClientIDField = DefaultOAuth2ClientIDField
+	}
+	if c.ClientSecretField == "" {
+		c.ClientSecretField = DefaultOAuth2ClientSecretField
+	}
+	if c.RefreshTokenField == "" {
+		c.RefreshTokenField = DefaultOAuth2RefreshTokenField
+	}
+	return c
+}
+
+// OAuth2TokenSource returns an oauth2.TokenSource that mints tokens from the
+// credentials stored in the configured item. Tokens are cached until they
+// expire. The context is used for token requests and refresh token
+// write-backs.
+//
+//	ts, err := provider.OAuth2TokenSource(ctx, onepassword.OAuth2Config{
+//	    ItemPath: "Work/Partner API",
+//	    Endpoint: oauth2.Endpoint{TokenURL: "https://auth.example.com/token"},
+//	})
+//	client := oauth2.NewClient(ctx, ts)
+func (p *Provider) OAuth2TokenSource(ctx context.Context, config OAuth2Config) (oauth2.TokenSource, error) {
+	config = config.withDefaults()
+
+	secret, err := p.Get(ctx, config.ItemPath)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID := secret.Fields[config.ClientIDField]
+	clientSecret := secret.Fields[config.ClientSecretField]
+	refreshToken := secret.Fields[config.RefreshTokenField]
+
+	if refreshToken != "" {
+		oauthConfig := &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Endpoint:     config.Endpoint,
+			Scopes:       config.Scopes,
+		}
+		return &persistingTokenSource{
+			base:         oauthConfig.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}),
+			refreshToken: refreshToken,
+			save: func(token string) error {
+				return p.Set(ctx, joinField(config.ItemPath, config.RefreshTokenField), &vault.Secret{Value: token})
+			},
+		}, nil
+	}
+
+	if clientID == "" || clientSecret == "" {
+		return nil, vault.NewVaultError("OAuth2TokenSource", config.ItemPath, ProviderName,
+			fmt.Errorf("%w: item needs %q and %q fields or a %q field",
+				vault.ErrSecretNotFound, config.ClientIDField, config.ClientSecretField, config.RefreshTokenField))
+	}
+
+	ccConfig := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     config.Endpoint.TokenURL,
+		Scopes:       config.Scopes,
+		AuthStyle:    config.Endpoint.AuthStyle,
+	}
+	return ccConfig.TokenSource(ctx), nil
+}
+
+// persistingTokenSource writes rotated refresh tokens back to 1Password.
+type persistingTokenSource struct {
+	base oauth2.TokenSource
+	save func(refreshToken string) error
+
+	mu           sync.Mutex
+	refreshToken string
+}
+
+// Token returns a token from the underlying source, saving its refresh token
+// first if the authorization server rotated it. A failed save is returned as
+// an error and retried on the next call, so a rotated token is never dropped.
+func (s *persistingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token.RefreshToken != "" && token.RefreshToken != s.refreshToken {
+		if err := s.save(token.RefreshToken); err != nil {
+			return nil, fmt.Errorf("failed to save rotated refresh token: %w", err)
+		}
+		s.refreshToken = token.RefreshToken
+	}
+	return token, nil
+}
@@ -0,0 +1,69 @@
+package onepassword
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// tokenSourceFunc adapts a function to oauth2.TokenSource.
+type tokenSourceFunc func() (*oauth2.Token, error)
+
+func (f tokenSourceFunc) Token() (*oauth2.Token, error) { return f() }
+
+func TestPersistingTokenSource(t *testing.T) {
+	current := &oauth2.Token{AccessToken: "a1", RefreshToken: "r1"}
+	var saved []string
+	var saveErr error
+
+	ts := &persistingTokenSource{
+		base:         tokenSourceFunc(func() (*oauth2.Token, error) { return current, nil }),
+		refreshToken: "r1",
+		save: func(token string) error {
+			if saveErr != nil {
+				return saveErr
+			}
+			saved = append(saved, token)
+			return nil
+		},
+	}
+
+	if _, err := ts.Token(); err != nil || len(saved) != 0 {
+		t.Fatalf("Token() = %v, saved %v; want no save for unchanged refresh token", err, saved)
+	}
+
+	// Rotated refresh token that fails to save is retried on the next call
+	current = &oauth2.Token{AccessToken: "a2", RefreshToken: "r2"}
+	saveErr = errors.New("unavailable")
+	if _, err := ts.Token(); err == nil {
+		t.Fatal("Token() should fail when the rotated refresh token can't be saved")
+	}
+
+	saveErr = nil
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if token.AccessToken != "a2" || len(saved) != 1 || saved[0] != "r2" {
+		t.Errorf("Token() = %q, saved %v; want a2 and [r2]", token.AccessToken, saved)
+	}
+
+	if _, err := ts.Token(); err != nil || len(saved) != 1 {
+		t.Errorf("Token() saved %v again; want a single save", saved)
+	}
+}
+
+func TestOAuth2Config_withDefaults(t *testing.T) {
+	cfg := OAuth2Config{ClientIDField: "id"}.withDefaults()
+
+	if cfg.ClientIDField != "id" {
+		t.Errorf("ClientIDField = %q, want 'id'", cfg.ClientIDField)
+	}
+	if cfg.ClientSecretField != DefaultOAuth2ClientSecretField {
+		t.Errorf("ClientSecretField = %q, want %q", cfg.ClientSecretField, DefaultOAuth2ClientSecretField)
+	}
+	if cfg.RefreshTokenField != DefaultOAuth2RefreshTokenField {
+		t.Errorf("RefreshTokenField = %q, want %q", cfg.RefreshTokenField, DefaultOAuth2RefreshTokenField)
+	}
+}
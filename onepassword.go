@@ -30,8 +30,10 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 
 	op "github.com/1password/onepassword-sdk-go"
 	"github.com/agentplexus/omnivault/vault"
@@ -39,15 +41,48 @@ import (
 
 // Provider implements vault.Vault for 1Password.
 type Provider struct {
-	client *op.Client
-	config Config
+	secrets secretsAPI
+	items   itemsAPI
+	vaults  vaultsAPI
+	config  Config
 
 	// vaultCache caches vault name -> ID mappings
-	vaultCache map[string]string
-	vaultMu    sync.RWMutex
+	vaultCache *vaultCache
 
-	mu     sync.RWMutex
-	closed bool
+	// itemCache caches item listings, if Config.CacheTTL is positive
+	itemCache *itemCache
+
+	// diskCache keeps resolved secrets on disk, if Config.CacheDir is set
+	diskCache *diskCache
+
+	// writeMu serializes writes so that a read-modify-write of an item
+	// isn't interleaved with another write from this provider. Set and
+	// Delete hold it shared along with a lock on their item (see lockItem),
+	// so writes to different items can overlap; other writes hold it
+	// exclusively. Reads take no lock. Views made with With share them.
+	writeMu   *sync.RWMutex
+	itemLocks *itemLocks
+
+	// shards spreads reads across service accounts, if Config.ShardTokens
+	// is set
+	shards *shardSet
+
+	// dryRun collects the changes planned with Config.DryRun
+	dryRun atomic.Pointer[ChangeSet]
+
+	// middleware wraps operations, see Use
+	middleware   atomic.Pointer[[]Middleware]
+	middlewareMu sync.Mutex
+
+	// mirror copies writes to Config.MirrorTo, if set
+	mirror *mirror
+
+	stats  *providerStats
+	closed atomic.Bool
+
+	// view is set on providers made with With, whose caches belong to the
+	// provider they were made from
+	view bool
 }
 
 // New creates a new 1Password provider with the given configuration.
@@ -56,6 +91,10 @@ func New(config Config) (*Provider, error) {
 }
 
 // NewWithContext creates a new 1Password provider with context.
+//
+// The SDK client is created on first use, so a provider can be built before
+// 1Password is reachable. Set Config.EagerInit to create it here instead and
+// fail fast on a bad token. The context is only used for eager creation.
 func NewWithContext(ctx context.Context, config Config) (*Provider, error) {
 	config = config.withDefaults()
 
@@ -68,20 +107,84 @@ func NewWithContext(ctx context.Context, config Config) (*Provider, error) {
 		return nil, fmt.Errorf("service account token is required: set Config.ServiceAccountToken or %s environment variable", EnvServiceAccountToken)
 	}
 
-	// Create 1Password client
-	client, err := op.NewClient(ctx,
-		op.WithServiceAccountToken(token),
-		op.WithIntegrationInfo(config.IntegrationName, config.IntegrationVersion),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create 1Password client: %w", err)
+	var shards []*shard
+	for _, token := range append([]string{token}, config.ShardTokens...) {
+		conn := &clientConn{
+			connect: func(ctx context.Context) (*op.Client, error) {
+				return op.NewClient(ctx,
+					op.WithServiceAccountToken(token),
+					op.WithIntegrationInfo(config.IntegrationName, config.IntegrationVersion),
+				)
+			},
+		}
+		if config.EagerInit {
+			if _, err := conn.get(ctx); err != nil {
+				return nil, err
+			}
+		}
+		shards = append(shards, &shard{secrets: lazySecrets{conn}, items: lazyItems{conn}, vaults: lazyVaults{conn}})
 	}
 
-	return &Provider{
-		client:     client,
+	if len(shards) == 1 {
+		return newWithAPIs(shards[0].secrets, shards[0].items, shards[0].vaults, config), nil
+	}
+	set := newShardSet(shards, config.ShardStrategy, config.ShardCooldown)
+	p := newWithAPIs(shardedSecrets{set}, shardedItems{set}, shardedVaults{set}, config)
+	p.shards = set
+	return p, nil
+}
+
+// NewWithClient creates a provider that uses an existing 1Password SDK client.
+// The client's Secrets, Items, and Vaults APIs may be any implementation,
+// which lets the onepasswordtest package substitute an in-memory backend.
+func NewWithClient(client *op.Client, config Config) *Provider {
+	return newWithAPIs(client.Secrets, client.Items, client.Vaults, config)
+}
+
+// newWithAPIs creates a provider on top of the given API implementations.
+func newWithAPIs(secrets secretsAPI, items itemsAPI, vaults vaultsAPI, config Config) *Provider {
+	config = config.withDefaults()
+	p := &Provider{
 		config:     config,
-		vaultCache: make(map[string]string),
-	}, nil
+		vaultCache: newVaultCache(config.CacheTTL, config.NegativeCacheTTL),
+		writeMu:    &sync.RWMutex{},
+		itemLocks:  &itemLocks{},
+		mirror:     newMirror(config.MirrorTo, config.MirrorAsync),
+		stats:      &providerStats{},
+	}
+	g := &callGuard{
+		timeout: config.OperationTimeout,
+		stats:   p.stats,
+		reads:   newTokenBucket(config.RateLimit.ReadsPerSecond, config.RateLimit.Burst),
+		writes:  newTokenBucket(config.RateLimit.WritesPerSecond, config.RateLimit.Burst),
+	}
+	p.secrets = guardedSecrets{secrets, g}
+	p.items = guardedItems{items, g}
+	if config.CacheTTL > 0 {
+		p.itemCache = newItemCache(config.CacheTTL)
+		p.items = cachedItems{p.items, p.itemCache}
+	}
+	if config.ManagedTag != "" {
+		p.items = managedItems{p.items, config.ManagedTag, config.ProtectUnmanaged}
+	}
+	if config.BeforeCreate != nil || config.BeforeUpdate != nil {
+		p.items = writeHooks{p.items, config.BeforeCreate, config.BeforeUpdate}
+	}
+	if config.ReadOnly {
+		p.items = readOnlyItems{p.items}
+	}
+	p.vaults = guardedVaults{vaults, g}
+	switch {
+	case config.CacheDir != "" && config.ZeroizeSecrets:
+		p.logWarn(context.Background(), "disk cache disabled by ZeroizeSecrets")
+	case config.CacheDir != "":
+		c, err := newDiskCache(config)
+		if err != nil {
+			p.logWarn(context.Background(), "disk cache disabled", "error", err)
+		}
+		p.diskCache = c
+	}
+	return p
 }
 
 // NewFromEnv creates a new provider using the OP_SERVICE_ACCOUNT_TOKEN environment variable.
@@ -97,32 +200,65 @@ func NewFromEnv() (*Provider, error) {
 //   - "item/field" - uses default vault (if configured)
 //   - "op://vault/item/field" - native 1Password secret reference
 func (p *Provider) Get(ctx context.Context, path string) (*vault.Secret, error) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	return p.GetWithOptions(ctx, path, GetOptions{})
+}
+
+// GetWithOptions retrieves a secret from 1Password using the given options.
+func (p *Provider) GetWithOptions(ctx context.Context, path string, opts GetOptions) (*vault.Secret, error) {
+	resp, err := p.intercept(ctx, &OpRequest{Op: OpGet, Path: path}, func(ctx context.Context, req *OpRequest) (*OpResponse, error) {
+		secret, err := p.getWithOptions(ctx, req.Path, opts)
+		return &OpResponse{Secret: secret}, err
+	})
+	return resp.Secret, err
+}
 
-	if p.closed {
+// getWithOptions retrieves a secret, once past the middleware.
+func (p *Provider) getWithOptions(ctx context.Context, path string, opts GetOptions) (*vault.Secret, error) {
+	if p.closed.Load() {
 		return nil, vault.NewVaultError("Get", path, ProviderName, vault.ErrClosed)
 	}
 
-	parsed, err := ParsePath(path, p.getDefaultVault())
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
 	if err != nil {
 		return nil, vault.NewVaultError("Get", path, ProviderName, err)
 	}
 
+	if opts.NoCache {
+		ctx = withoutCache(ctx)
+	}
+
 	// If field is specified, use Secrets().Resolve() for direct field access
 	if parsed.Field != "" {
-		return p.resolveField(ctx, parsed)
+		if opts.Section != "" {
+			parsed.Section = opts.Section
+		}
+		if !parsed.referenceSafe() || parsed.Attribute == AttributeOTPAuthURI || p.config.EnforceExpiry {
+			// Secret references can't address names containing slashes,
+			// return the stored OTP URI, or report the item's expiry
+			secret, err := p.getItemField(ctx, parsed)
+			return p.cachedGet(ctx, *parsed, secret, err)
+		}
+		secret, err := p.resolveField(ctx, parsed)
+		return p.cachedGet(ctx, *parsed, secret, err)
 	}
 
-	// Otherwise get the full item
-	return p.getItem(ctx, parsed)
+	// Otherwise get the full item, or one of its sections
+	section := parsed.Section
+	if opts.Section != "" {
+		section = opts.Section
+	}
+	secret, err := p.getItem(ctx, parsed, section)
+	return p.cachedGet(ctx, parsed.WithSection(section), secret, err)
 }
 
 // resolveField retrieves a single field using the Secrets API.
 func (p *Provider) resolveField(ctx context.Context, parsed *ParsedPath) (*vault.Secret, error) {
 	ref := parsed.SecretReference()
 
-	value, err := p.client.Secrets.Resolve(ctx, ref)
+	value, err := p.secrets.Resolve(ctx, ref)
 	if err != nil {
 		return nil, mapError("Get", parsed.String(), err)
 	}
@@ -136,41 +272,145 @@ func (p *Provider) resolveField(ctx context.Context, parsed *ParsedPath) (*vault
 	}, nil
 }
 
-// getItem retrieves a full item using the Items API.
-func (p *Provider) getItem(ctx context.Context, parsed *ParsedPath) (*vault.Secret, error) {
-	// Resolve vault name to ID
-	vaultID, err := p.resolveVaultID(ctx, parsed.Vault)
+// getItemField retrieves a single field by fetching the whole item. It serves
+// paths that a secret reference can't express and the otpauth-uri attribute.
+func (p *Provider) getItemField(ctx context.Context, parsed *ParsedPath) (*vault.Secret, error) {
+	item, err := p.fetchItem(ctx, parsed)
 	if err != nil {
 		return nil, mapError("Get", parsed.String(), err)
 	}
+	return p.fieldSecret(item, parsed)
+}
+
+// fieldSecret returns the field of item named by parsed as a secret.
+func (p *Provider) fieldSecret(item op.Item, parsed *ParsedPath) (*vault.Secret, error) {
+	if err := p.checkExpiry(item, parsed.String()); err != nil {
+		return nil, err
+	}
+
+	field, ok := findField(item, parsed.Section, parsed.Field)
+	if !ok {
+		return nil, vault.NewVaultError("Get", parsed.String(), ProviderName, vault.ErrSecretNotFound)
+	}
+
+	value := fieldValue(field)
+	if parsed.Attribute == AttributeOTPAuthURI {
+		value = field.Value
+	}
+
+	secret := &vault.Secret{
+		Value: value,
+		Metadata: vault.Metadata{
+			Provider: ProviderName,
+			Path:     parsed.String(),
+		},
+	}
+	if expires, ok := itemExpiry(item); ok {
+		secret.Metadata.ExpiresAt = vault.NewTimestamp(expires)
+	}
+	return secret, nil
+}
+
+// fetchItem resolves the vault and item of parsed and fetches the item.
+func (p *Provider) fetchItem(ctx context.Context, parsed *ParsedPath) (op.Item, error) {
+	vaultID, err := p.resolveVaultID(ctx, parsed.Vault)
+	if err != nil {
+		return op.Item{}, err
+	}
 
-	// Resolve item name to ID
 	itemID, err := p.resolveItemID(ctx, vaultID, parsed.Item)
 	if err != nil {
-		return nil, mapError("Get", parsed.String(), err)
+		return op.Item{}, err
 	}
 
-	item, err := p.client.Items.Get(ctx, vaultID, itemID)
+	return p.items.Get(ctx, vaultID, itemID)
+}
+
+// getItem retrieves a full item using the Items API. If section is set, only
+// the fields in that section are returned.
+func (p *Provider) getItem(ctx context.Context, parsed *ParsedPath, section string) (*vault.Secret, error) {
+	item, err := p.fetchItem(ctx, parsed)
 	if err != nil {
 		return nil, mapError("Get", parsed.String(), err)
 	}
+	return p.itemSecret(item, parsed, section)
+}
+
+// itemSecret returns item as a secret, with only the fields in section if
+// it is set.
+func (p *Provider) itemSecret(item op.Item, parsed *ParsedPath, section string) (*vault.Secret, error) {
+	if err := p.checkExpiry(item, parsed.String()); err != nil {
+		return nil, err
+	}
 
-	return itemToSecret(item, parsed.String()), nil
+	if section != "" {
+		var fields []op.ItemField
+		for _, field := range item.Fields {
+			if inSection(item, field, section) {
+				fields = append(fields, field)
+			}
+		}
+		item.Fields = fields
+	}
+
+	return p.toSecret(item, parsed.String()), nil
 }
 
 // Set stores a secret in 1Password.
+//
+// When the item already exists, the provided fields are merged into it and
+// fields not present in the secret are preserved. Use SetWithOptions with
+// WriteModeReplace to drop them instead.
+//
+// A secret with Fields written to "vault/item/section" stores its fields in
+// that section of the item, creating the section if needed, rather than in
+// a field named by the last component.
+//
+// If secret.Metadata.Version is set and the item already exists, the update
+// is refused with ErrConflict when the item's current version differs.
 func (p *Provider) Set(ctx context.Context, path string, secret *vault.Secret) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	return p.SetWithOptions(ctx, path, secret, SetOptions{})
+}
 
-	if p.closed {
+// SetWithOptions stores a secret in 1Password using the given options.
+func (p *Provider) SetWithOptions(ctx context.Context, path string, secret *vault.Secret, opts SetOptions) error {
+	_, err := p.intercept(ctx, &OpRequest{Op: OpSet, Path: path, Secret: secret}, func(ctx context.Context, req *OpRequest) (*OpResponse, error) {
+		if err := p.setWithOptions(ctx, req.Path, req.Secret, opts); err != nil {
+			return nil, err
+		}
+		return nil, p.mirrorSet(ctx, "Set", req.Path, req.Secret)
+	})
+	return err
+}
+
+// setWithOptions stores a secret, once past the middleware.
+func (p *Provider) setWithOptions(ctx context.Context, path string, secret *vault.Secret, opts SetOptions) error {
+	if p.closed.Load() {
 		return vault.NewVaultError("Set", path, ProviderName, vault.ErrClosed)
 	}
 
-	parsed, err := ParsePath(path, p.getDefaultVault())
+	parsed, err := p.parsePath(path)
 	if err != nil {
 		return vault.NewVaultError("Set", path, ProviderName, err)
 	}
+	if opts.Section == "" {
+		sectionWrite(parsed, secret)
+	}
+
+	unlock := p.lockItem(parsed)
+	defer unlock()
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Write)
+	defer cancel()
+
+	types, err := fieldTypeOverrides(secret, opts)
+	if err != nil {
+		return vault.NewVaultError("Set", path, ProviderName, err)
+	}
+	p.invalidateDiskCache(ctx, parsed)
+	if opts.Section == "" {
+		opts.Section = parsed.Section
+	}
 
 	// Resolve vault
 	vaultID, err := p.resolveVaultID(ctx, parsed.Vault)
@@ -182,28 +422,76 @@ func (p *Provider) Set(ctx context.Context, path string, secret *vault.Secret) e
 	itemID, err := p.resolveItemID(ctx, vaultID, parsed.Item)
 	if err == nil {
 		// Update existing item
-		return p.updateItem(ctx, vaultID, itemID, parsed, secret)
+		return p.updateItem(ctx, vaultID, itemID, parsed, secret, opts, types)
+	}
+	if !isNotFoundError(err) {
+		return mapError("Set", path, err)
 	}
 
 	// Create new item
-	return p.createItem(ctx, vaultID, parsed, secret)
+	return p.createItem(ctx, vaultID, parsed, secret, opts, types)
+}
+
+// sectionWrite reads a "vault/item/section" path written with a secret
+// that has Fields as naming a section rather than a field, so the fields
+// are written into that section.
+func sectionWrite(parsed *ParsedPath, secret *vault.Secret) {
+	if parsed.Field != "" && parsed.Section == "" && len(secret.Fields) > 0 {
+		parsed.Section, parsed.Field = parsed.Field, ""
+	}
+}
+
+// placeNewFields moves the fields that don't update one of existing into
+// Config.DefaultSection, if set, adding the section to sections as needed.
+// The built-in fields of category stay at the top level.
+func (p *Provider) placeNewFields(existing []op.ItemField, sections *[]op.ItemSection, fields []op.ItemField, category op.ItemCategory) {
+	if p.config.DefaultSection == "" {
+		return
+	}
+	var sectionID string
+	for i := range fields {
+		if isNotesField(fields[i]) || isBuiltinField(category, fields[i]) || mergeIndex(existing, fields[i]) >= 0 {
+			continue
+		}
+		if sectionID == "" {
+			sectionID = ensureSection(sections, p.config.DefaultSection)
+		}
+		id := sectionID
+		fields[i].SectionID = &id
+	}
 }
 
 // createItem creates a new item in 1Password.
-func (p *Provider) createItem(ctx context.Context, vaultID string, parsed *ParsedPath, secret *vault.Secret) error {
+func (p *Provider) createItem(ctx context.Context, vaultID string, parsed *ParsedPath, secret *vault.Secret, opts SetOptions, types map[string]op.ItemFieldType) error {
+	category, err := p.itemCategory(secret, opts)
+	if err != nil {
+		return vault.NewVaultError("Set", parsed.String(), ProviderName, err)
+	}
+
 	params := op.ItemCreateParams{
 		VaultID:  vaultID,
 		Title:    parsed.Item,
-		Category: p.config.DefaultCategory,
-		Fields:   secretToFields(secret, parsed.Field),
+		Category: category,
+		Fields:   p.buildFields(secret, parsed.Field, types),
+	}
+	useBuiltinFields(category, params.Fields)
+	if opts.Section != "" {
+		placeInSection(params.Fields, ensureSection(&params.Sections, opts.Section))
+	} else {
+		p.placeNewFields(nil, &params.Sections, params.Fields, category)
 	}
 
 	// Add tags from metadata
 	if secret.Metadata.Tags != nil {
-		params.Tags = tagsToStrings(secret.Metadata.Tags)
+		params.Tags = tagsToStrings(secret.Metadata.Tags, p.tagSeparator())
 	}
 
-	_, err := p.client.Items.Create(ctx, params)
+	if plan := p.planFor(ctx); plan != nil {
+		planSet(plan, parsed.String(), nil, op.Item{Fields: params.Fields, Tags: params.Tags}, secret, opts)
+		return nil
+	}
+
+	_, err = p.items.Create(ctx, params)
 	if err != nil {
 		return mapError("Set", parsed.String(), err)
 	}
@@ -212,43 +500,91 @@ func (p *Provider) createItem(ctx context.Context, vaultID string, parsed *Parse
 }
 
 // updateItem updates an existing item in 1Password.
-func (p *Provider) updateItem(ctx context.Context, vaultID, itemID string, parsed *ParsedPath, secret *vault.Secret) error {
+func (p *Provider) updateItem(ctx context.Context, vaultID, itemID string, parsed *ParsedPath, secret *vault.Secret, opts SetOptions, types map[string]op.ItemFieldType) error {
 	// Get existing item
-	item, err := p.client.Items.Get(ctx, vaultID, itemID)
+	item, err := p.items.Get(ctx, vaultID, itemID)
 	if err != nil {
 		return mapError("Set", parsed.String(), err)
 	}
 
+	// Refuse the update if the item changed since the caller read it
+	expected := secret.Metadata.Version
+	if opts.ExpectedVersion != "" {
+		expected = opts.ExpectedVersion
+	}
+	if err := checkVersion(expected, item.Version); err != nil {
+		return vault.NewVaultError("Set", parsed.String(), ProviderName, err)
+	}
+
+	skipUnchanged := opts.SkipUnchanged || p.config.SkipUnchanged
+	var before string
+	if skipUnchanged {
+		before = ContentHash(item)
+	}
+	plan := p.planFor(ctx)
+	var original op.Item
+	if plan != nil {
+		original = item
+		original.Fields = slices.Clone(item.Fields)
+		original.Tags = slices.Clone(item.Tags)
+	}
+
+	fields := p.buildFields(secret, parsed.Field, types)
+	useBuiltinFields(item.Category, fields)
+	if opts.Section != "" {
+		placeInSection(fields, ensureSection(&item.Sections, opts.Section))
+	} else if parsed.Field == "" && opts.Mode == WriteModeReplace {
+		p.placeNewFields(nil, &item.Sections, fields, item.Category)
+	} else {
+		p.placeNewFields(item.Fields, &item.Sections, fields, item.Category)
+	}
+
 	// Update fields
-	if parsed.Field != "" {
-		// Update or add specific field
-		fieldFound := false
-		for i := range item.Fields {
-			if item.Fields[i].Title == parsed.Field || item.Fields[i].ID == parsed.Field {
-				item.Fields[i].Value = secret.Value
-				fieldFound = true
-				break
+	switch {
+	case parsed.Field == "" && opts.Mode == WriteModeReplace && opts.Section != "":
+		// Replace the section's fields
+		kept := item.Fields[:0]
+		for _, field := range item.Fields {
+			if !inSection(item, field, opts.Section) {
+				kept = append(kept, field)
 			}
 		}
-		if !fieldFound {
-			item.Fields = append(item.Fields, op.ItemField{
-				ID:        sanitizeID(parsed.Field),
-				Title:     parsed.Field,
-				Value:     secret.Value,
-				FieldType: op.ItemFieldTypeConcealed,
-			})
-		}
-	} else {
+		item.Fields = append(kept, fields...)
+	case parsed.Field == "" && opts.Mode == WriteModeReplace:
 		// Replace all fields
-		item.Fields = secretToFields(secret, "")
+		item.Fields = fields
+	default:
+		// Upsert the provided fields, preserving the rest; explicit type
+		// overrides also apply to fields that already exist
+		item.Fields = mergeFields(item.Fields, fields)
+		applyFieldTypes(item.Fields, types)
 	}
 
 	// Update tags if provided
 	if secret.Metadata.Tags != nil {
-		item.Tags = tagsToStrings(secret.Metadata.Tags)
+		switch opts.Tags {
+		case TagsAddOnly:
+			item.Tags = addTags(item.Tags, secret.Metadata.Tags, p.tagSeparator())
+		case TagsReplace:
+			item.Tags = p.keepManagedTag(item.Tags, tagsToStrings(secret.Metadata.Tags, p.tagSeparator()))
+		default:
+			item.Tags = mergeTags(item.Tags, secret.Metadata.Tags, p.tagSeparator())
+		}
 	}
 
-	_, err = p.client.Items.Put(ctx, item)
+	// Leave the item at its version if the write changes nothing
+	if skipUnchanged && ContentHash(item) == before {
+		p.stats.skippedWrites.Add(1)
+		p.logDebug(ctx, "skipping unchanged write", "path", parsed.String())
+		return nil
+	}
+
+	if plan != nil {
+		planSet(plan, parsed.String(), &original, item, secret, opts)
+		return nil
+	}
+
+	_, err = p.items.Put(ctx, item)
 	if err != nil {
 		return mapError("Set", parsed.String(), err)
 	}
@@ -256,20 +592,59 @@ func (p *Provider) updateItem(ctx context.Context, vaultID, itemID string, parse
 	return nil
 }
 
+// SetIfVersion stores a secret only if the existing item is still at the
+// given version, returning ErrConflict otherwise. The version is the value
+// reported in Metadata.Version by Get. Creating a new item is unconditional.
+func (p *Provider) SetIfVersion(ctx context.Context, path string, secret *vault.Secret, version string) error {
+	return p.SetWithOptions(ctx, path, secret, SetOptions{ExpectedVersion: version})
+}
+
+// checkVersion returns ErrConflict if an expected version is set and
+// differs from the actual item version.
+func checkVersion(expected string, actual uint32) error {
+	if expected == "" {
+		return nil
+	}
+	if expected != strconv.FormatUint(uint64(actual), 10) {
+		return fmt.Errorf("%w: expected version %s, found %d", ErrConflict, expected, actual)
+	}
+	return nil
+}
+
 // Delete removes a secret from 1Password.
 func (p *Provider) Delete(ctx context.Context, path string) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	_, err := p.intercept(ctx, &OpRequest{Op: OpDelete, Path: path}, func(ctx context.Context, req *OpRequest) (*OpResponse, error) {
+		if err := p.deleteSecret(ctx, req.Path); err != nil {
+			return nil, err
+		}
+		parsed, err := p.parsePath(req.Path)
+		if err != nil {
+			return nil, vault.NewVaultError("Delete", req.Path, ProviderName, err)
+		}
+		return nil, p.mirrorDeleteItem(ctx, "Delete", req.Path, parsed)
+	})
+	return err
+}
 
-	if p.closed {
+// deleteSecret removes a secret, once past the middleware.
+func (p *Provider) deleteSecret(ctx context.Context, path string) error {
+	if p.closed.Load() {
 		return vault.NewVaultError("Delete", path, ProviderName, vault.ErrClosed)
 	}
 
-	parsed, err := ParsePath(path, p.getDefaultVault())
+	parsed, err := p.parsePath(path)
 	if err != nil {
 		return vault.NewVaultError("Delete", path, ProviderName, err)
 	}
 
+	unlock := p.lockItem(parsed)
+	defer unlock()
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Write)
+	defer cancel()
+
+	p.invalidateDiskCache(ctx, parsed)
+
 	// Resolve vault
 	vaultID, err := p.resolveVaultID(ctx, parsed.Vault)
 	if err != nil {
@@ -290,7 +665,14 @@ func (p *Provider) Delete(ctx context.Context, path string) error {
 		return mapError("Delete", path, err)
 	}
 
-	err = p.client.Items.Delete(ctx, vaultID, itemID)
+	if plan := p.planFor(ctx); plan != nil {
+		planDelete(plan, path, func(ctx context.Context, p *Provider) error {
+			return p.Delete(ctx, path)
+		})
+		return nil
+	}
+
+	err = p.items.Delete(ctx, vaultID, itemID)
 	if err != nil {
 		// Ignore not found errors
 		if isNotFoundError(err) {
@@ -302,80 +684,106 @@ func (p *Provider) Delete(ctx context.Context, path string) error {
 	return nil
 }
 
-// Exists checks if a secret exists in 1Password.
+// Exists checks if a secret exists in 1Password. For a field path, the
+// field must exist on the item, in the given section if there is one.
 func (p *Provider) Exists(ctx context.Context, path string) (bool, error) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-
-	if p.closed {
+	if p.closed.Load() {
 		return false, vault.NewVaultError("Exists", path, ProviderName, vault.ErrClosed)
 	}
 
-	parsed, err := ParsePath(path, p.getDefaultVault())
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
 	if err != nil {
 		return false, vault.NewVaultError("Exists", path, ProviderName, err)
 	}
 
-	// Resolve vault
-	vaultID, err := p.resolveVaultID(ctx, parsed.Vault)
+	level, err := p.probe(ctx, "Exists", parsed)
 	if err != nil {
-		if isNotFoundError(err) {
-			return false, nil
-		}
-		return false, mapError("Exists", path, err)
+		return false, err
 	}
-
-	// Resolve item
-	_, err = p.resolveItemID(ctx, vaultID, parsed.Item)
-	if err != nil {
-		if isNotFoundError(err) {
-			return false, nil
-		}
-		return false, mapError("Exists", path, err)
+	if parsed.Field == "" {
+		return level >= LevelItem, nil
 	}
-
-	return true, nil
+	return level == LevelField, nil
 }
 
-// List returns all secret paths matching the prefix.
+// List returns all secret paths matching the prefix, as "vault/item" paths
+// or, with Config.StrictPaths, op:// references. The prefix is matched
+// literally; use ListGlob for patterns.
 func (p *Provider) List(ctx context.Context, prefix string) ([]string, error) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	resp, err := p.intercept(ctx, &OpRequest{Op: OpList, Path: prefix}, func(ctx context.Context, req *OpRequest) (*OpResponse, error) {
+		paths, err := p.listSecrets(ctx, req.Path)
+		return &OpResponse{Paths: paths}, err
+	})
+	return resp.Paths, err
+}
 
-	if p.closed {
+// listSecrets lists secret paths, once past the middleware.
+func (p *Provider) listSecrets(ctx context.Context, prefix string) ([]string, error) {
+	if p.closed.Load() {
 		return nil, vault.NewVaultError("List", prefix, ProviderName, vault.ErrClosed)
 	}
 
-	var results []string
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.List)
+	defer cancel()
 
-	// Get all vaults
-	vaultsIter, err := p.client.Vaults.ListAll(ctx)
+	wantVault, wantItem, err := listMatcher(prefix, false)
+	if err != nil {
+		return nil, vault.NewVaultError("List", prefix, ProviderName, err)
+	}
+
+	var results []string
+	err = p.walkItems(ctx, wantVault, func(v op.VaultOverview, item op.ItemOverview) bool {
+		if wantItem(v, item) {
+			results = append(results, p.listedPath(BuildPath(v.Title, item.Title)))
+		}
+		return true
+	})
 	if err != nil {
 		return nil, mapError("List", prefix, err)
 	}
+	return results, nil
+}
+
+// walkItems calls fn for each item of each vault that wantVault accepts,
+// in listing order, until fn returns false. Vaults and items that can't be
+// listed are skipped. The IDs of the vaults walked are cached.
+func (p *Provider) walkItems(ctx context.Context, wantVault func(op.VaultOverview) bool, fn func(op.VaultOverview, op.ItemOverview) bool) error {
+	vaultsIter, err := p.vaults.ListAll(ctx)
+	if err != nil {
+		return err
+	}
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		v, err := vaultsIter.Next()
 		if err == op.ErrorIteratorDone {
-			break
+			return nil
 		}
 		if err != nil {
-			return nil, mapError("List", prefix, err)
+			return err
 		}
-
-		// Filter by prefix if it specifies a vault
-		if prefix != "" && !strings.HasPrefix(v.Title, prefix) && !strings.HasPrefix(prefix, v.Title+"/") {
+		if !wantVault(*v) {
 			continue
 		}
 
 		// List items in vault
-		itemsIter, err := p.client.Items.ListAll(ctx, v.ID)
+		itemsIter, err := p.items.ListAll(ctx, v.ID)
 		if err != nil {
 			// Skip vaults we can't access
 			continue
 		}
 
 		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
 			item, err := itemsIter.Next()
 			if err == op.ErrorIteratorDone {
 				break
@@ -384,18 +792,15 @@ func (p *Provider) List(ctx context.Context, prefix string) ([]string, error) {
 				// Skip items we can't iterate
 				break
 			}
-
-			path := fmt.Sprintf("%s/%s", v.Title, item.Title)
-			if prefix == "" || strings.HasPrefix(path, prefix) {
-				results = append(results, path)
+			if !fn(*v, *item) {
+				p.cacheVaultID(v.Title, v.ID)
+				return nil
 			}
 		}
 
 		// Cache vault ID
 		p.cacheVaultID(v.Title, v.ID)
 	}
-
-	return results, nil
 }
 
 // Name returns the provider name.
@@ -407,22 +812,26 @@ func (p *Provider) Name() string {
 func (p *Provider) Capabilities() vault.Capabilities {
 	return vault.Capabilities{
 		Read:       true,
-		Write:      true,
-		Delete:     true,
+		Write:      !p.config.ReadOnly,
+		Delete:     !p.config.ReadOnly,
 		List:       true,
 		Versioning: false, // SDK doesn't expose version history
 		Rotation:   false, // No rotation API in SDK
 		Binary:     true,  // Via file attachments
 		MultiField: true,  // Items have multiple fields
 		Batch:      true,  // ResolveAll() for reads
+		Watch:      true,  // Polling via Watch()
 	}
 }
 
-// Close releases resources held by the provider.
+// Close releases resources held by the provider. Operations started after
+// Close fail with vault.ErrClosed; operations already in flight complete.
 func (p *Provider) Close() error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-	p.closed = true
+	p.closed.Store(true)
+	p.mirror.flush()
+	if p.config.ZeroizeSecrets && !p.view {
+		return p.Wipe()
+	}
 	// The 1Password client uses a runtime finalizer, no explicit close needed
 	return nil
 }
@@ -435,6 +844,38 @@ func (p *Provider) getDefaultVault() string {
 	return p.config.DefaultVaultName
 }
 
+// parsePath parses a path using the configured default vault and strictness.
+func (p *Provider) parsePath(path string) (*ParsedPath, error) {
+	if p.config.StrictPaths {
+		return ParsePathStrict(path)
+	}
+	return ParsePath(path, p.getDefaultVault())
+}
+
+// listedPath returns the path List reports for the "vault/item" path of an
+// item: the path itself or, with StrictPaths, which rejects that form, the
+// op:// reference, so that listed paths can be passed to Get.
+func (p *Provider) listedPath(path string) string {
+	if p.config.StrictPaths {
+		return "op://" + path
+	}
+	return path
+}
+
+// noCacheKey marks a context whose lookups bypass the vault cache.
+type noCacheKey struct{}
+
+// withoutCache returns a context whose vault lookups bypass the cache.
+func withoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// cacheBypassed reports whether ctx was marked by withoutCache.
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(noCacheKey{}).(bool)
+	return bypass
+}
+
 // resolveVaultID resolves a vault name or ID to its ID.
 func (p *Provider) resolveVaultID(ctx context.Context, nameOrID string) (string, error) {
 	if nameOrID == "" {
@@ -442,53 +883,48 @@ func (p *Provider) resolveVaultID(ctx context.Context, nameOrID string) (string,
 	}
 
 	// Check cache first
-	p.vaultMu.RLock()
-	if id, ok := p.vaultCache[nameOrID]; ok {
-		p.vaultMu.RUnlock()
-		return id, nil
+	if !cacheBypassed(ctx) {
+		id, found, missing := p.vaultCache.get(nameOrID)
+		if found {
+			return id, nil
+		}
+		if missing {
+			return "", fmt.Errorf("%w: %s", ErrVaultNotFound, nameOrID)
+		}
 	}
-	p.vaultMu.RUnlock()
 
 	// List vaults to find the match
-	vaultsIter, err := p.client.Vaults.ListAll(ctx)
+	ids, err := p.vaultCache.load(ctx, p.listVaultIDs)
 	if err != nil {
 		return "", err
 	}
-
-	for {
-		v, err := vaultsIter.Next()
-		if err == op.ErrorIteratorDone {
-			break
-		}
-		if err != nil {
-			return "", err
-		}
-
-		// Cache all vault IDs while we're at it
-		p.cacheVaultID(v.Title, v.ID)
-
-		// Check for match by ID or title
-		if v.ID == nameOrID || v.Title == nameOrID {
-			return v.ID, nil
-		}
+	if id, ok := ids[nameOrID]; ok {
+		return id, nil
 	}
 
-	return "", fmt.Errorf("vault not found: %s", nameOrID)
+	p.vaultCache.miss(nameOrID)
+	return "", fmt.Errorf("%w: %s", ErrVaultNotFound, nameOrID)
 }
 
 // resolveItemID resolves an item name or ID to its ID.
+// A title matching several items is resolved according to Config.OnAmbiguous.
 func (p *Provider) resolveItemID(ctx context.Context, vaultID, nameOrID string) (string, error) {
 	if nameOrID == "" {
 		return "", fmt.Errorf("item name or ID is required")
 	}
 
 	// List items to find the match
-	itemsIter, err := p.client.Items.ListAll(ctx, vaultID)
+	itemsIter, err := p.items.ListAll(ctx, vaultID)
 	if err != nil {
 		return "", err
 	}
 
+	var matches []string
 	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
 		item, err := itemsIter.Next()
 		if err == op.ErrorIteratorDone {
 			break
@@ -497,21 +933,50 @@ func (p *Provider) resolveItemID(ctx context.Context, vaultID, nameOrID string)
 			return "", err
 		}
 
-		if item.ID == nameOrID || item.Title == nameOrID {
+		// An exact ID match is never ambiguous
+		if item.ID == nameOrID {
 			return item.ID, nil
 		}
+		if item.Title == nameOrID {
+			matches = append(matches, item.ID)
+		}
 	}
 
-	return "", fmt.Errorf("item not found: %s", nameOrID)
+	return selectItemMatch(nameOrID, vaultID, matches, p.config.OnAmbiguous)
+}
+
+// selectItemMatch picks an item ID from the items whose title matched.
+func selectItemMatch(title, vaultID string, matches []string, policy AmbiguityPolicy) (string, error) {
+	switch {
+	case len(matches) == 0:
+		return "", fmt.Errorf("%w: %s", ErrItemNotFound, title)
+	case len(matches) == 1 || policy == AmbiguityFirst:
+		return matches[0], nil
+	default:
+		return "", &AmbiguousItemError{Title: title, VaultID: vaultID, Candidates: matches}
+	}
 }
 
 // cacheVaultID caches a vault name -> ID mapping.
 func (p *Provider) cacheVaultID(name, id string) {
-	p.vaultMu.Lock()
-	p.vaultCache[name] = id
-	p.vaultCache[id] = id // Also cache ID -> ID for direct lookups
-	p.vaultMu.Unlock()
+	p.vaultCache.add(name, id)
 }
 
 // Ensure Provider implements vault.Vault.
 var _ vault.Vault = (*Provider)(nil)
+
+// logDebug logs to Config.Logger, if set, identifying the caller set on
+// ctx (see WithRequestor).
+func (p *Provider) logDebug(ctx context.Context, msg string, args ...any) {
+	if p.config.Logger != nil {
+		p.config.Logger.DebugContext(ctx, msg, append(args, RequestInfoFrom(ctx).logAttrs()...)...)
+	}
+}
+
+// logWarn logs to Config.Logger, if set, identifying the caller set on
+// ctx (see WithRequestor).
+func (p *Provider) logWarn(ctx context.Context, msg string, args ...any) {
+	if p.config.Logger != nil {
+		p.config.Logger.WarnContext(ctx, msg, append(args, RequestInfoFrom(ctx).logAttrs()...)...)
+	}
+}
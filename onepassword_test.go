@@ -1,6 +1,7 @@
 package onepassword
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -63,6 +64,7 @@ func TestProvider_Capabilities(t *testing.T) {
 		{"Binary", caps.Binary, true},
 		{"MultiField", caps.MultiField, true},
 		{"Batch", caps.Batch, true},
+		{"Watch", caps.Watch, true},
 	}
 
 	for _, tt := range tests {
@@ -75,7 +77,7 @@ func TestProvider_Capabilities(t *testing.T) {
 func TestProvider_Close(t *testing.T) {
 	p := &Provider{}
 
-	if p.closed {
+	if p.closed.Load() {
 		t.Error("Provider should not be closed initially")
 	}
 
@@ -84,7 +86,7 @@ func TestProvider_Close(t *testing.T) {
 		t.Errorf("Close() returned error: %v", err)
 	}
 
-	if !p.closed {
+	if !p.closed.Load() {
 		t.Error("Provider should be closed after Close()")
 	}
 }
@@ -133,3 +135,66 @@ func TestNewWithoutToken(t *testing.T) {
 		t.Error("New() should return error when no token provided")
 	}
 }
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		actual   uint32
+		wantErr  bool
+	}{
+		{"no expectation", "", 7, false},
+		{"matching version", "7", 7, false},
+		{"stale version", "6", 7, true},
+		{"non-numeric version", "abc", 7, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkVersion(tt.expected, tt.actual)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkVersion(%q, %d) error = %v, wantErr %v", tt.expected, tt.actual, err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrConflict) {
+				t.Errorf("checkVersion() error = %v, want ErrConflict", err)
+			}
+		})
+	}
+}
+
+func TestSelectItemMatch(t *testing.T) {
+	t.Run("no match", func(t *testing.T) {
+		_, err := selectItemMatch("db", "v1", nil, AmbiguityError)
+		if !isNotFoundError(err) {
+			t.Errorf("Expected not-found error, got %v", err)
+		}
+	})
+
+	t.Run("single match", func(t *testing.T) {
+		id, err := selectItemMatch("db", "v1", []string{"a"}, AmbiguityError)
+		if err != nil || id != "a" {
+			t.Errorf("selectItemMatch() = %q, %v; want 'a', nil", id, err)
+		}
+	})
+
+	t.Run("ambiguous with error policy", func(t *testing.T) {
+		_, err := selectItemMatch("db", "v1", []string{"a", "b"}, AmbiguityError)
+		if !errors.Is(err, ErrAmbiguousItem) {
+			t.Fatalf("Expected ErrAmbiguousItem, got %v", err)
+		}
+		var ambiguous *AmbiguousItemError
+		if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 {
+			t.Errorf("Expected candidates [a b], got %v", err)
+		}
+		if isNotFoundError(err) {
+			t.Error("Ambiguous error must not be treated as not found")
+		}
+	})
+
+	t.Run("ambiguous with first policy", func(t *testing.T) {
+		id, err := selectItemMatch("db", "v1", []string{"a", "b"}, AmbiguityFirst)
+		if err != nil || id != "a" {
+			t.Errorf("selectItemMatch() = %q, %v; want 'a', nil", id, err)
+		}
+	})
+}
@@ -0,0 +1,404 @@
+// Package onepasswordtest provides an in-memory 1Password backend for unit
+// testing code that uses the onepassword provider, without a service account
+// token or network access.
+//
+// The backend stands in for the 1Password SDK, so the provider on top of it
+// is the real one: path parsing, field conversion, tags, and error mapping
+// behave as they do against 1Password.
+//
+//	provider, backend := onepasswordtest.New(onepassword.Config{})
+//	backend.AddFields("Private", "Database", map[string]string{"password": "hunter2"})
+//
+//	secret, err := provider.Get(ctx, "Private/Database/password")
+package onepasswordtest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	op "github.com/1password/onepassword-sdk-go"
+
+	onepassword "github.com/agentplexus/omnivault-onepassword"
+)
+
+// Methods that can be made to fail with Backend.Fail.
+const (
+	MethodResolve     = "Secrets.Resolve"
+	MethodItemsCreate = "Items.Create"
+	MethodItemsGet    = "Items.Get"
+	MethodItemsPut    = "Items.Put"
+	MethodItemsDelete = "Items.Delete"
+	MethodItemsList   = "Items.ListAll"
+	MethodVaultsList  = "Vaults.ListAll"
+)
+
+// New returns a provider backed by a new, empty Backend.
+func New(config onepassword.Config) (*onepassword.Provider, *Backend) {
+	backend := NewBackend()
+	return onepassword.NewWithClient(backend.Client(), config), backend
+}
+
+// Backend is an in-memory store of 1Password vaults and items implementing
+// the SDK's Secrets, Items, and Vaults APIs. It is safe for concurrent use.
+type Backend struct {
+	mu       sync.Mutex
+	vaults   []*vaultData
+	failures map[string]error
+	nextID   int
+}
+
+type vaultData struct {
+	overview op.VaultOverview
+	items    []op.Item
+}
+
+// NewBackend returns an empty backend.
+func NewBackend() *Backend {
+	return &Backend{failures: make(map[string]error)}
+}
+
+// Client returns an SDK client whose APIs are served by the backend.
+func (b *Backend) Client() *op.Client {
+	return &op.Client{
+		Secrets: secretsAPI{b},
+		Items:   itemsAPI{b},
+		Vaults:  vaultsAPI{b},
+	}
+}
+
+// AddVault creates a vault with the given title, if it does not exist, and
+// returns its ID.
+func (b *Backend) AddVault(title string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.vault(title).overview.ID
+}
+
+// AddItem stores item in the vault with the given title, creating the vault
+// if needed. The item's ID and version are assigned unless set. It returns
+// the stored item.
+func (b *Backend) AddItem(vaultTitle string, item op.Item) op.Item {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	v := b.vault(vaultTitle)
+	if item.ID == "" {
+		item.ID = b.newID("item")
+	}
+	if item.Version == 0 {
+		item.Version = 1
+	}
+	if item.Category == "" {
+		item.Category = op.ItemCategorySecureNote
+	}
+	item.VaultID = v.overview.ID
+	v.items = append(v.items, copyItem(item))
+	return copyItem(item)
+}
+
+// AddFields stores an item with the given text fields. Fields whose names
+// suggest a secret ("password", "token", "key", ...) are concealed.
+func (b *Backend) AddFields(vaultTitle, title string, fields map[string]string) op.Item {
+	item := op.Item{Title: title}
+	for name, value := range fields {
+		fieldType := op.ItemFieldTypeText
+		lower := strings.ToLower(name)
+		for _, hint := range []string{"password", "secret", "token", "key", "credential"} {
+			if strings.Contains(lower, hint) {
+				fieldType = op.ItemFieldTypeConcealed
+				break
+			}
+		}
+		item.Fields = append(item.Fields, op.ItemField{ID: name, Title: name, FieldType: fieldType, Value: value})
+	}
+	return b.AddItem(vaultTitle, item)
+}
+
+// Item returns the first item with the given title in the vault with the
+// given title.
+func (b *Backend) Item(vaultTitle, title string) (op.Item, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, v := range b.vaults {
+		if v.overview.Title != vaultTitle {
+			continue
+		}
+		for _, item := range v.items {
+			if item.Title == title {
+				return copyItem(item), true
+			}
+		}
+	}
+	return op.Item{}, false
+}
+
+// Fail makes every call to method (one of the Method constants) return err
+// until Fail is called again with a nil error. Use it to exercise error
+// handling, e.g. errors.New("unauthorized") for access denied.
+func (b *Backend) Fail(method string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		delete(b.failures, method)
+		return
+	}
+	b.failures[method] = err
+}
+
+// vault returns the vault with the given title, creating it if needed.
+// b.mu must be held.
+func (b *Backend) vault(title string) *vaultData {
+	for _, v := range b.vaults {
+		if v.overview.Title == title {
+			return v
+		}
+	}
+	v := &vaultData{overview: op.VaultOverview{ID: b.newID("vault"), Title: title}}
+	b.vaults = append(b.vaults, v)
+	return v
+}
+
+// lookupVault returns the vault with the given ID or title. b.mu must be held.
+func (b *Backend) lookupVault(idOrTitle string) (*vaultData, error) {
+	for _, v := range b.vaults {
+		if v.overview.ID == idOrTitle || v.overview.Title == idOrTitle {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("vaultNotFound: %s", idOrTitle)
+}
+
+// lookupItem returns the index of the item with the given ID or, if byTitle
+// is set, title. b.mu must be held.
+func (v *vaultData) lookupItem(idOrTitle string, byTitle bool) (int, error) {
+	for i, item := range v.items {
+		if item.ID == idOrTitle || (byTitle && item.Title == idOrTitle) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("itemNotFound: %s", idOrTitle)
+}
+
+// failure returns the error configured for method. b.mu must be held.
+func (b *Backend) failure(method string) error {
+	return b.failures[method]
+}
+
+// newID returns a unique ID. b.mu must be held.
+func (b *Backend) newID(kind string) string {
+	b.nextID++
+	return kind + "-" + strconv.Itoa(b.nextID)
+}
+
+// copyItem returns a copy of item that shares no slices with it.
+func copyItem(item op.Item) op.Item {
+	item.Fields = append([]op.ItemField(nil), item.Fields...)
+	item.Sections = append([]op.ItemSection(nil), item.Sections...)
+	item.Tags = append([]string(nil), item.Tags...)
+	item.Websites = append([]op.Website(nil), item.Websites...)
+	return item
+}
+
+type secretsAPI struct{ b *Backend }
+
+// Resolve returns the field value a secret reference points to.
+func (s secretsAPI) Resolve(_ context.Context, reference string) (string, error) {
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.failure(MethodResolve); err != nil {
+		return "", err
+	}
+
+	parsed, err := onepassword.ParsePath(reference, "")
+	if err != nil || parsed.Field == "" {
+		return "", fmt.Errorf("invalid secret reference: %s", reference)
+	}
+
+	v, err := b.lookupVault(parsed.Vault)
+	if err != nil {
+		return "", err
+	}
+	i, err := v.lookupItem(parsed.Item, true)
+	if err != nil {
+		return "", err
+	}
+
+	item := v.items[i]
+	for _, field := range item.Fields {
+		if field.Title != parsed.Field && field.ID != parsed.Field {
+			continue
+		}
+		if parsed.Section != "" && !inSection(item, field, parsed.Section) {
+			continue
+		}
+		return field.Value, nil
+	}
+	return "", fmt.Errorf("fieldNotFound: %s", parsed.Field)
+}
+
+// inSection reports whether field belongs to the section with the given
+// title or ID.
+func inSection(item op.Item, field op.ItemField, section string) bool {
+	if field.SectionID == nil {
+		return false
+	}
+	for _, s := range item.Sections {
+		if s.ID == *field.SectionID && (s.Title == section || s.ID == section) {
+			return true
+		}
+	}
+	return false
+}
+
+type itemsAPI struct{ b *Backend }
+
+// Create stores a new item.
+func (s itemsAPI) Create(_ context.Context, params op.ItemCreateParams) (op.Item, error) {
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.failure(MethodItemsCreate); err != nil {
+		return op.Item{}, err
+	}
+
+	v, err := b.lookupVault(params.VaultID)
+	if err != nil {
+		return op.Item{}, err
+	}
+
+	item := op.Item{
+		ID:       b.newID("item"),
+		Title:    params.Title,
+		Category: params.Category,
+		VaultID:  v.overview.ID,
+		Fields:   params.Fields,
+		Sections: params.Sections,
+		Tags:     params.Tags,
+		Websites: params.Websites,
+		Version:  1,
+	}
+	v.items = append(v.items, copyItem(item))
+	return copyItem(item), nil
+}
+
+// Get returns an item by vault and item ID.
+func (s itemsAPI) Get(_ context.Context, vaultID, itemID string) (op.Item, error) {
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.failure(MethodItemsGet); err != nil {
+		return op.Item{}, err
+	}
+
+	v, err := b.lookupVault(vaultID)
+	if err != nil {
+		return op.Item{}, err
+	}
+	i, err := v.lookupItem(itemID, false)
+	if err != nil {
+		return op.Item{}, err
+	}
+	return copyItem(v.items[i]), nil
+}
+
+// Put replaces an existing item and bumps its version.
+func (s itemsAPI) Put(_ context.Context, item op.Item) (op.Item, error) {
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.failure(MethodItemsPut); err != nil {
+		return op.Item{}, err
+	}
+
+	v, err := b.lookupVault(item.VaultID)
+	if err != nil {
+		return op.Item{}, err
+	}
+	i, err := v.lookupItem(item.ID, false)
+	if err != nil {
+		return op.Item{}, err
+	}
+
+	item.Version = v.items[i].Version + 1
+	v.items[i] = copyItem(item)
+	return copyItem(item), nil
+}
+
+// Delete removes an item.
+func (s itemsAPI) Delete(_ context.Context, vaultID, itemID string) error {
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.failure(MethodItemsDelete); err != nil {
+		return err
+	}
+
+	v, err := b.lookupVault(vaultID)
+	if err != nil {
+		return err
+	}
+	i, err := v.lookupItem(itemID, false)
+	if err != nil {
+		return err
+	}
+	v.items = append(v.items[:i], v.items[i+1:]...)
+	return nil
+}
+
+// ListAll lists the items of a vault.
+func (s itemsAPI) ListAll(_ context.Context, vaultID string) (*op.Iterator[op.ItemOverview], error) {
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.failure(MethodItemsList); err != nil {
+		return nil, err
+	}
+
+	v, err := b.lookupVault(vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	overviews := make([]op.ItemOverview, 0, len(v.items))
+	for _, item := range v.items {
+		overviews = append(overviews, op.ItemOverview{
+			ID:       item.ID,
+			Title:    item.Title,
+			Category: item.Category,
+			VaultID:  item.VaultID,
+			Websites: item.Websites,
+		})
+	}
+	return op.NewIterator(overviews), nil
+}
+
+type vaultsAPI struct{ b *Backend }
+
+// ListAll lists all vaults.
+func (s vaultsAPI) ListAll(context.Context) (*op.Iterator[op.VaultOverview], error) {
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.failure(MethodVaultsList); err != nil {
+		return nil, err
+	}
+
+	overviews := make([]op.VaultOverview, 0, len(b.vaults))
+	for _, v := range b.vaults {
+		overviews = append(overviews, v.overview)
+	}
+	return op.NewIterator(overviews), nil
+}
@@ -0,0 +1,100 @@
+package onepasswordtest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+
+	onepassword "github.com/agentplexus/omnivault-onepassword"
+)
+
+func TestProvider_Get(t *testing.T) {
+	ctx := context.Background()
+	provider, backend := New(onepassword.Config{})
+	backend.AddFields("Private", "Database", map[string]string{"username": "admin", "password": "hunter2"})
+
+	secret, err := provider.Get(ctx, "Private/Database")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if secret.Value != "hunter2" || secret.Fields["username"] != "admin" {
+		t.Errorf("Get() = %+v", secret)
+	}
+
+	field, err := provider.Get(ctx, "op://Private/Database/username")
+	if err != nil || field.Value != "admin" {
+		t.Errorf("Get(field) = %+v, %v", field, err)
+	}
+
+	tests := []string{"Private/Missing", "Nope/Database", "Private/Database/missing"}
+	for _, path := range tests {
+		if _, err := provider.Get(ctx, path); !errors.Is(err, vault.ErrSecretNotFound) {
+			t.Errorf("Get(%q) error = %v, want ErrSecretNotFound", path, err)
+		}
+	}
+}
+
+func TestProvider_SetListDelete(t *testing.T) {
+	ctx := context.Background()
+	provider, backend := New(onepassword.Config{})
+	backend.AddVault("Work")
+
+	err := provider.Set(ctx, "Work/API", &vault.Secret{
+		Fields:   map[string]string{"token": "abc"},
+		Metadata: vault.Metadata{Tags: map[string]string{"env": "prod"}},
+	})
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	item, ok := backend.Item("Work", "API")
+	if !ok {
+		t.Fatal("item not created")
+	}
+	if item.Category != onepassword.CategorySecureNote || !reflect.DeepEqual(item.Tags, []string{"env:prod"}) {
+		t.Errorf("created item = %+v", item)
+	}
+	if item.Fields[0].FieldType != op.ItemFieldTypeConcealed {
+		t.Errorf("token field type = %s, want concealed", item.Fields[0].FieldType)
+	}
+
+	if err := provider.Set(ctx, "Work/API/user", &vault.Secret{Value: "bot"}); err != nil {
+		t.Fatalf("Set(field) error = %v", err)
+	}
+	item, _ = backend.Item("Work", "API")
+	if item.Version != 2 || len(item.Fields) != 2 {
+		t.Errorf("updated item = %+v", item)
+	}
+
+	paths, err := provider.List(ctx, "Work/")
+	if err != nil || !reflect.DeepEqual(paths, []string{"Work/API"}) {
+		t.Errorf("List() = %v, %v", paths, err)
+	}
+
+	if err := provider.Delete(ctx, "Work/API"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok := backend.Item("Work", "API"); ok {
+		t.Error("item still exists after Delete()")
+	}
+}
+
+func TestBackend_Fail(t *testing.T) {
+	ctx := context.Background()
+	provider, backend := New(onepassword.Config{})
+	backend.AddFields("Private", "Database", map[string]string{"password": "hunter2"})
+
+	backend.Fail(MethodItemsGet, errors.New("unauthorized"))
+	if _, err := provider.Get(ctx, "Private/Database"); !errors.Is(err, vault.ErrAccessDenied) {
+		t.Errorf("Get() error = %v, want ErrAccessDenied", err)
+	}
+
+	backend.Fail(MethodItemsGet, nil)
+	if _, err := provider.Get(ctx, "Private/Database"); err != nil {
+		t.Errorf("Get() after clearing failure error = %v", err)
+	}
+}
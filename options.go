@@ -0,0 +1,84 @@
+package onepassword
+
+import op "github.com/1password/onepassword-sdk-go"
+
+// WriteMode controls how Set applies fields to an existing item.
+type WriteMode int
+
+const (
+	// WriteModeMerge upserts the provided fields and preserves all other
+	// fields already on the item. This is the default.
+	WriteModeMerge WriteMode = iota
+
+	// WriteModeReplace replaces all fields on the item with the provided ones,
+	// dropping any field not present in the new secret.
+	WriteModeReplace
+)
+
+// TagsMode controls how Set applies Secret.Metadata.Tags to an existing item.
+// Tags added by hand, e.g. in the 1Password app, are kept by default.
+type TagsMode int
+
+const (
+	// TagsMerge adds the secret's tags to the item's tags. A "key:value" tag
+	// replaces an existing tag with the same key. This is the default.
+	TagsMerge TagsMode = iota
+
+	// TagsAddOnly adds the secret's tags whose keys the item doesn't have
+	// yet. Existing tags are never changed or removed.
+	TagsAddOnly
+
+	// TagsReplace replaces the item's tags with the secret's tags when the
+	// secret has any tags map, even an empty one, removing all others.
+	TagsReplace
+)
+
+// SetOptions controls the behavior of SetWithOptions.
+type SetOptions struct {
+	// Mode selects merge or replace semantics when writing a multi-field
+	// secret to an existing item.
+	// Default: WriteModeMerge
+	Mode WriteMode
+
+	// FieldTypes sets the type of the named fields, overriding type
+	// inference and the types of existing fields. It takes precedence over
+	// Secret.Metadata.Extra[FieldTypesKey].
+	FieldTypes map[string]FieldType
+
+	// Tags selects how the secret's tags are applied to an existing item.
+	// Default: TagsMerge
+	Tags TagsMode
+
+	// ExpectedVersion refuses the update with ErrConflict unless the
+	// existing item is at this version. It takes precedence over
+	// Secret.Metadata.Version. Creating a new item is unconditional.
+	ExpectedVersion string
+
+	// Section writes the secret's fields into the item section with this
+	// title or ID, creating the section if needed. Fields are matched within
+	// the section only, and WriteModeReplace replaces only that section's
+	// fields. It takes precedence over a section in the path.
+	Section string
+
+	// Category is the category of a new item. It takes precedence over
+	// Secret.Metadata.Extra[CategoryKey] and category inference, and is
+	// ignored when the item already exists.
+	Category op.ItemCategory
+
+	// SkipUnchanged compares the content of the existing item before and
+	// after the write (see ContentHash) and skips the update when they
+	// match, so the item keeps its version. Config.SkipUnchanged enables
+	// it for every write.
+	SkipUnchanged bool
+}
+
+// GetOptions controls the behavior of GetWithOptions.
+type GetOptions struct {
+	// Section restricts the read to the item section with this title or ID.
+	// For an item path, only that section's fields are returned. It takes
+	// precedence over a section in the path.
+	Section string
+
+	// NoCache bypasses cached vault name lookups and refreshes them.
+	NoCache bool
+}
@@ -0,0 +1,318 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// sectionedMockAPI returns a mock whose Database item has a "prod" section.
+func sectionedMockAPI() *mockAPI {
+	m := testMockAPI()
+	prod := "prod"
+	item := &m.items["v1"][0]
+	item.Sections = []op.ItemSection{{ID: prod, Title: "Production"}}
+	item.Fields = append(item.Fields,
+		op.ItemField{ID: "host", Title: "host", SectionID: &prod, Value: "db.prod"},
+		op.ItemField{ID: "password2", Title: "password", SectionID: &prod, FieldType: op.ItemFieldTypeConcealed, Value: "prodpass"},
+	)
+	m.resolved["op://Private/Database/prod/password"] = "prodpass"
+	return m
+}
+
+func TestGetWithOptions_Section(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(sectionedMockAPI(), Config{})
+
+	secret, err := p.GetWithOptions(ctx, "Private/Database", GetOptions{Section: "Production"})
+	if err != nil {
+		t.Fatalf("GetWithOptions() error = %v", err)
+	}
+	want := map[string]string{"host": "db.prod", "password": "prodpass"}
+	if !reflect.DeepEqual(secret.Fields, want) {
+		t.Errorf("Fields = %v, want %v", secret.Fields, want)
+	}
+	if secret.Metadata.Path != "Private/Database" {
+		t.Errorf("Metadata.Path = %q, want item path", secret.Metadata.Path)
+	}
+
+	field, err := p.GetWithOptions(ctx, "Private/Database/password", GetOptions{Section: "prod"})
+	if err != nil || field.Value != "prodpass" {
+		t.Errorf("GetWithOptions(field) = %+v, %v; want prodpass", field, err)
+	}
+}
+
+func TestGetWithOptions_NoCache(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	if _, err := p.Get(ctx, "Private/Database"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// "Private" now names another vault; the cached ID is stale
+	m.vaults = []op.VaultOverview{{ID: "v2", Title: "Private"}}
+
+	if _, err := p.Get(ctx, "Private/API"); !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("Get() with stale cache error = %v, want ErrItemNotFound", err)
+	}
+	if _, err := p.GetWithOptions(ctx, "Private/API", GetOptions{NoCache: true}); err != nil {
+		t.Errorf("GetWithOptions(NoCache) error = %v", err)
+	}
+	if _, err := p.Get(ctx, "Private/API"); err != nil {
+		t.Errorf("Get() after refresh error = %v", err)
+	}
+}
+
+func TestSetWithOptions_Section(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create", func(t *testing.T) {
+		m := testMockAPI()
+		p := newMockProvider(m, Config{})
+
+		err := p.SetWithOptions(ctx, "Private/Cache", &vault.Secret{
+			Fields: map[string]string{"host": "redis", "notes": "shared"},
+		}, SetOptions{Section: "Staging"})
+		if err != nil {
+			t.Fatalf("SetWithOptions() error = %v", err)
+		}
+
+		created := m.created[0]
+		if !reflect.DeepEqual(created.Sections, []op.ItemSection{{ID: "staging", Title: "Staging"}}) {
+			t.Errorf("Sections = %+v", created.Sections)
+		}
+		for _, f := range created.Fields {
+			inStaging := f.SectionID != nil && *f.SectionID == "staging"
+			if inStaging == isNotesField(f) {
+				t.Errorf("field %q section = %v", f.Title, f.SectionID)
+			}
+		}
+	})
+
+	t.Run("merge within section", func(t *testing.T) {
+		m := sectionedMockAPI()
+		p := newMockProvider(m, Config{})
+
+		err := p.SetWithOptions(ctx, "Private/Database", &vault.Secret{
+			Fields: map[string]string{"password": "rotated"},
+		}, SetOptions{Section: "Production"})
+		if err != nil {
+			t.Fatalf("SetWithOptions() error = %v", err)
+		}
+
+		got := make(map[string]string)
+		for _, f := range m.put[0].Fields {
+			got[f.ID] = f.Value
+		}
+		want := map[string]string{"username": "admin", "password": "hunter2", "host": "db.prod", "password2": "rotated"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("fields = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("replace section from path", func(t *testing.T) {
+		m := sectionedMockAPI()
+		p := newMockProvider(m, Config{})
+
+		err := p.SetWithOptions(ctx, "Private/Database", &vault.Secret{
+			Fields: map[string]string{"port": "5432"},
+		}, SetOptions{Mode: WriteModeReplace, Section: "prod"})
+		if err != nil {
+			t.Fatalf("SetWithOptions() error = %v", err)
+		}
+
+		var titles []string
+		for _, f := range m.put[0].Fields {
+			titles = append(titles, f.Title)
+		}
+		sort.Strings(titles)
+		if want := []string{"password", "port", "username"}; !reflect.DeepEqual(titles, want) {
+			t.Errorf("fields = %v, want %v", titles, want)
+		}
+		if len(m.put[0].Sections) != 1 {
+			t.Errorf("Sections = %+v, want the existing section reused", m.put[0].Sections)
+		}
+	})
+
+	t.Run("section path", func(t *testing.T) {
+		m := sectionedMockAPI()
+		p := newMockProvider(m, Config{})
+
+		err := p.Set(ctx, "Private/Database/Production", &vault.Secret{
+			Fields: map[string]string{"password": "rotated", "port": "5432"},
+		})
+		if err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+
+		got := make(map[string]string)
+		for _, f := range m.put[0].Fields {
+			section := ""
+			if f.SectionID != nil {
+				section = *f.SectionID
+			}
+			got[section+"."+f.Title] = f.Value
+		}
+		want := map[string]string{
+			".username": "admin", ".password": "hunter2",
+			"prod.host": "db.prod", "prod.password": "rotated", "prod.port": "5432",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("fields = %v, want %v", got, want)
+		}
+
+		// A single value still names a field
+		if err := p.Set(ctx, "Private/Database/Production", &vault.Secret{Value: "x"}); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if f, ok := findField(m.put[1], "", "Production"); !ok || f.Value != "x" {
+			t.Errorf("field Production not written: %+v", m.put[1].Fields)
+		}
+	})
+
+	t.Run("new section from path", func(t *testing.T) {
+		m := testMockAPI()
+		p := newMockProvider(m, Config{})
+
+		err := p.Set(ctx, "Private/Cache/staging", &vault.Secret{Fields: map[string]string{"host": "redis"}})
+		if err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		created := m.created[0]
+		if created.Title != "Cache" || len(created.Sections) != 1 || created.Sections[0].Title != "staging" {
+			t.Errorf("created %q with sections %+v", created.Title, created.Sections)
+		}
+		if f := created.Fields[0]; f.SectionID == nil || *f.SectionID != created.Sections[0].ID {
+			t.Errorf("field %q section = %v", f.Title, f.SectionID)
+		}
+	})
+}
+
+func TestSetWithOptions_Tags(t *testing.T) {
+	ctx := context.Background()
+	secret := &vault.Secret{
+		Fields:   map[string]string{"password": "x"},
+		Metadata: vault.Metadata{Tags: map[string]string{"env": "dev", "team": "infra"}},
+	}
+
+	tests := []struct {
+		mode TagsMode
+		want []string
+	}{
+		{TagsMerge, []string{"manual", "env:dev", "team:infra"}},
+		{TagsAddOnly, []string{"env:prod", "manual", "team:infra"}},
+		{TagsReplace, []string{"env:dev", "team:infra"}},
+	}
+
+	for _, tt := range tests {
+		m := testMockAPI()
+		m.items["v1"][0].Tags = []string{"env:prod", "manual"}
+		p := newMockProvider(m, Config{})
+		if err := p.SetWithOptions(ctx, "Private/Database", secret, SetOptions{Tags: tt.mode}); err != nil {
+			t.Fatalf("SetWithOptions() error = %v", err)
+		}
+		if got := m.put[0].Tags; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("mode %d: tags = %v, want %v", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestSetWithOptions_ExpectedVersion(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(testMockAPI(), Config{})
+	secret := &vault.Secret{Value: "x", Metadata: vault.Metadata{Version: "3"}}
+
+	err := p.SetWithOptions(ctx, "Private/Database/password", secret, SetOptions{ExpectedVersion: "2"})
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("SetWithOptions() error = %v, want ErrConflict", err)
+	}
+	if err := p.SetWithOptions(ctx, "Private/Database/password", secret, SetOptions{ExpectedVersion: "3"}); err != nil {
+		t.Errorf("SetWithOptions() error = %v", err)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	got := mergeTags([]string{"env:prod", "manual", "team:web"}, map[string]string{"team": "infra"}, DefaultTagSeparator)
+	want := []string{"env:prod", "manual", "team:infra"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeTags() = %v, want %v", got, want)
+	}
+}
+
+func TestAddTags(t *testing.T) {
+	got := addTags([]string{"env:prod", "manual"}, map[string]string{"env": "dev", "manual": "", "team": "infra"}, DefaultTagSeparator)
+	want := []string{"env:prod", "manual", "team:infra"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("addTags() = %v, want %v", got, want)
+	}
+}
+
+func TestDefaultSection(t *testing.T) {
+	ctx := context.Background()
+	secret := &vault.Secret{Fields: map[string]string{"password": "new", "api_key": "k", NotesField: "n"}}
+
+	sectionOf := func(item op.Item, name string) string {
+		for _, field := range item.Fields {
+			if field.Title == name {
+				if field.SectionID == nil {
+					return ""
+				}
+				for _, s := range item.Sections {
+					if s.ID == *field.SectionID {
+						return s.Title
+					}
+				}
+			}
+		}
+		return "?"
+	}
+
+	t.Run("create", func(t *testing.T) {
+		m := testMockAPI()
+		p := newMockProvider(m, Config{DefaultSection: "omnivault"})
+		if err := p.Set(ctx, "Private/New", secret); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		created := op.Item{Fields: m.created[0].Fields, Sections: m.created[0].Sections}
+		for name, want := range map[string]string{"password": "omnivault", "api_key": "omnivault", NotesField: ""} {
+			if got := sectionOf(created, name); got != want {
+				t.Errorf("field %q in section %q, want %q", name, got, want)
+			}
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		m := testMockAPI()
+		p := newMockProvider(m, Config{DefaultSection: "omnivault"})
+		if err := p.Set(ctx, "Private/Database", secret); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		// The existing password is updated in place; the new key is sectioned
+		put := m.put[0]
+		if got := sectionOf(put, "password"); got != "" || findValue(put, "password") != "new" {
+			t.Errorf("password in section %q with value %q, want updated at the top level", got, findValue(put, "password"))
+		}
+		if got := sectionOf(put, "api_key"); got != "omnivault" {
+			t.Errorf("api_key in section %q, want omnivault", got)
+		}
+	})
+
+	t.Run("explicit section wins", func(t *testing.T) {
+		m := testMockAPI()
+		p := newMockProvider(m, Config{DefaultSection: "omnivault"})
+		if err := p.SetWithOptions(ctx, "Private/New", secret, SetOptions{Section: "Staging"}); err != nil {
+			t.Fatalf("SetWithOptions() error = %v", err)
+		}
+		created := op.Item{Fields: m.created[0].Fields, Sections: m.created[0].Sections}
+		if got := sectionOf(created, "api_key"); got != "Staging" {
+			t.Errorf("api_key in section %q, want Staging", got)
+		}
+	})
+}
@@ -1,13 +1,28 @@
 package onepassword
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"net/url"
 	"strings"
+
+	"github.com/agentplexus/omnivault/vault"
 )
 
 // ErrInvalidPath is returned when a path cannot be parsed.
-var ErrInvalidPath = errors.New("invalid path format")
+// It wraps vault.ErrInvalidPath.
+var ErrInvalidPath error = &sentinelError{"invalid path format", []error{vault.ErrInvalidPath}}
+
+// Reference attributes supported in "?attribute=" queries.
+const (
+	// AttributeTOTP resolves a one-time password field to its current code.
+	// "otp" is accepted as an alias.
+	AttributeTOTP = "totp"
+
+	// AttributeOTPAuthURI resolves a one-time password field to its stored
+	// value (usually an otpauth:// URI) instead of the computed code.
+	AttributeOTPAuthURI = "otpauth-uri"
+)
 
 // ParsedPath represents a parsed 1Password secret path.
 type ParsedPath struct {
@@ -22,10 +37,40 @@ type ParsedPath struct {
 
 	// Field is the field name (optional).
 	Field string
+
+	// Attribute is the reference attribute from an "?attribute=" query
+	// (optional), such as AttributeTOTP.
+	Attribute string
+}
+
+// Path identifies a 1Password item or field by its raw, unescaped names.
+// Building a Path avoids string concatenation and escaping mistakes:
+//
+//	path := onepassword.NewPath("Private", "prod/db").WithField("password")
+//	secret, err := provider.GetPath(ctx, path)
+type Path = ParsedPath
+
+// NewPath returns a Path to an item. An empty vault uses the provider's
+// default vault.
+func NewPath(vault, item string) Path {
+	return Path{Vault: vault, Item: item}
+}
+
+// WithSection returns a copy of the path with the section set.
+func (p ParsedPath) WithSection(section string) Path {
+	p.Section = section
+	return p
+}
+
+// WithField returns a copy of the path with the field set.
+func (p ParsedPath) WithField(field string) Path {
+	p.Field = field
+	return p
 }
 
 // String returns the path in canonical format.
-func (p *ParsedPath) String() string {
+// Slashes and backslashes inside components are escaped.
+func (p ParsedPath) String() string {
 	var parts []string
 	if p.Vault != "" {
 		parts = append(parts, p.Vault)
@@ -39,20 +84,91 @@ func (p *ParsedPath) String() string {
 	if p.Field != "" {
 		parts = append(parts, p.Field)
 	}
-	return strings.Join(parts, "/")
+	return BuildPath(parts...) + p.attributeQuery()
+}
+
+// attributeQuery returns the "?attribute=" suffix for the path, if any.
+func (p ParsedPath) attributeQuery() string {
+	if p.Attribute == "" {
+		return ""
+	}
+	return "?attribute=" + url.QueryEscape(p.Attribute)
+}
+
+// referenceSafe reports whether the path can be expressed as a 1Password
+// secret reference, which has no escape syntax for slashes.
+func (p ParsedPath) referenceSafe() bool {
+	return !strings.Contains(p.Vault+p.Item+p.Section+p.Field, "/")
 }
 
 // SecretReference returns the path as a 1Password secret reference URI.
-func (p *ParsedPath) SecretReference() string {
+func (p ParsedPath) SecretReference() string {
 	if p.Field != "" {
 		if p.Section != "" {
-			return fmt.Sprintf("op://%s/%s/%s/%s", p.Vault, p.Item, p.Section, p.Field)
+			return fmt.Sprintf("op://%s/%s/%s/%s", p.Vault, p.Item, p.Section, p.Field) + p.attributeQuery()
 		}
-		return fmt.Sprintf("op://%s/%s/%s", p.Vault, p.Item, p.Field)
+		return fmt.Sprintf("op://%s/%s/%s", p.Vault, p.Item, p.Field) + p.attributeQuery()
 	}
 	return fmt.Sprintf("op://%s/%s", p.Vault, p.Item)
 }
 
+// EscapePathComponent escapes a raw vault, item, section, or field name so it
+// can be used as a single path component. A backslash becomes `\\` and a
+// slash becomes `\/`.
+func EscapePathComponent(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "/", `\/`)
+}
+
+// BuildPath joins raw path components into a path, escaping each one.
+//
+//	BuildPath("Private", "prod/db password", "password")
+//	// "Private/prod\/db password/password"
+func BuildPath(components ...string) string {
+	escaped := make([]string, len(components))
+	for i, c := range components {
+		escaped[i] = EscapePathComponent(c)
+	}
+	return strings.Join(escaped, "/")
+}
+
+// joinField appends an escaped field name to an item path.
+func joinField(itemPath, field string) string {
+	return strings.TrimSuffix(itemPath, "/") + "/" + EscapePathComponent(field)
+}
+
+// splitPath splits a path on unescaped slashes, unescapes each component,
+// and drops empty components.
+func splitPath(path string) []string {
+	var parts []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range path {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '/':
+			if current.Len() > 0 {
+				parts = append(parts, current.String())
+			}
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if escaped {
+		// Keep a trailing lone backslash literally
+		current.WriteRune('\\')
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+	return parts
+}
+
 // ParsePath parses a path string into components.
 //
 // Supported formats:
@@ -62,7 +178,50 @@ func (p *ParsedPath) SecretReference() string {
 //   - "item" - item only (uses defaultVault, returns all fields)
 //   - "vault/item/section/field" - full path with section
 //   - "op://vault/item/field" - native 1Password secret reference
+//
+// Any form may end in an "?attribute=" query such as "?attribute=totp",
+// which is kept in ParsedPath.Attribute and requires a field.
+//
+// A slash that is part of a name must be escaped as `\/` (and a backslash
+// as `\\`); see EscapePathComponent and BuildPath.
 func ParsePath(path string, defaultVault string) (*ParsedPath, error) {
+	path, attribute := splitAttribute(path)
+
+	parsed, err := parseComponents(path, defaultVault)
+	if err != nil {
+		return nil, err
+	}
+
+	if attribute != "" {
+		if parsed.Field == "" {
+			return nil, fmt.Errorf("%w: attribute %q requires a field", ErrInvalidPath, attribute)
+		}
+		parsed.Attribute = attribute
+	}
+	return parsed, nil
+}
+
+// splitAttribute separates a trailing "?attribute=" query from a path.
+// A "?" that doesn't start such a query is left as part of the path.
+func splitAttribute(path string) (string, string) {
+	i := strings.LastIndex(path, "?")
+	if i < 0 {
+		return path, ""
+	}
+	query, err := url.ParseQuery(path[i+1:])
+	if err != nil || !query.Has("attribute") {
+		return path, ""
+	}
+
+	attribute := strings.ToLower(query.Get("attribute"))
+	if attribute == "otp" {
+		attribute = AttributeTOTP
+	}
+	return path[:i], attribute
+}
+
+// parseComponents parses a path without its attribute query.
+func parseComponents(path string, defaultVault string) (*ParsedPath, error) {
 	if path == "" {
 		return nil, ErrInvalidPath
 	}
@@ -72,17 +231,8 @@ func ParsePath(path string, defaultVault string) (*ParsedPath, error) {
 		return parseSecretReference(path)
 	}
 
-	// Split path into components
-	parts := strings.Split(path, "/")
-
-	// Filter out empty parts (handles double slashes)
-	var filtered []string
-	for _, p := range parts {
-		if p != "" {
-			filtered = append(filtered, p)
-		}
-	}
-	parts = filtered
+	// Split path into components, dropping empty parts (handles double slashes)
+	parts := splitPath(path)
 
 	if len(parts) == 0 {
 		return nil, ErrInvalidPath
@@ -137,25 +287,35 @@ func ParsePath(path string, defaultVault string) (*ParsedPath, error) {
 	}
 }
 
+// ParsePathStrict parses a path without default-vault heuristics. Only
+// op:// references and fully qualified "vault/item/field" or
+// "vault/item/section/field" paths are accepted; the one- and two-component
+// forms, whose meaning depends on the default vault, are rejected.
+func ParsePathStrict(path string) (*ParsedPath, error) {
+	if path == "" {
+		return nil, ErrInvalidPath
+	}
+
+	if !strings.HasPrefix(path, "op://") {
+		components, _ := splitAttribute(path)
+		if len(splitPath(components)) < 3 {
+			return nil, fmt.Errorf("%w: %q is ambiguous in strict mode; use op://vault/item or vault/item/field", ErrInvalidPath, path)
+		}
+	}
+	return ParsePath(path, "")
+}
+
 // parseSecretReference parses a native 1Password secret reference.
 // Format: op://vault/item[/section]/field
 func parseSecretReference(ref string) (*ParsedPath, error) {
 	// Remove op:// prefix
 	ref = strings.TrimPrefix(ref, "op://")
 
-	// Handle query parameters (e.g., ?attribute=totp)
+	// Drop any other query parameters; attributes were split off by ParsePath
 	ref = strings.Split(ref, "?")[0]
 
-	parts := strings.Split(ref, "/")
-
-	// Filter out empty parts
-	var filtered []string
-	for _, p := range parts {
-		if p != "" {
-			filtered = append(filtered, p)
-		}
-	}
-	parts = filtered
+	// Split into components, dropping empty parts
+	parts := splitPath(ref)
 
 	switch len(parts) {
 	case 2:
@@ -186,3 +346,70 @@ func parseSecretReference(ref string) (*ParsedPath, error) {
 		return nil, fmt.Errorf("%w: invalid secret reference format", ErrInvalidPath)
 	}
 }
+
+// GetPath retrieves the secret identified by path.
+func (p *Provider) GetPath(ctx context.Context, path Path) (*vault.Secret, error) {
+	qualified, err := p.qualifyPath("Get", path)
+	if err != nil {
+		return nil, err
+	}
+	return p.Get(ctx, qualified)
+}
+
+// SetPath stores a secret at the location identified by path.
+func (p *Provider) SetPath(ctx context.Context, path Path, secret *vault.Secret) error {
+	qualified, err := p.qualifyPath("Set", path)
+	if err != nil {
+		return err
+	}
+	return p.Set(ctx, qualified, secret)
+}
+
+// DeletePath removes the item identified by path.
+func (p *Provider) DeletePath(ctx context.Context, path Path) error {
+	qualified, err := p.qualifyPath("Delete", path)
+	if err != nil {
+		return err
+	}
+	return p.Delete(ctx, qualified)
+}
+
+// ExistsPath checks if the secret identified by path exists.
+func (p *Provider) ExistsPath(ctx context.Context, path Path) (bool, error) {
+	qualified, err := p.qualifyPath("Exists", path)
+	if err != nil {
+		return false, err
+	}
+	return p.Exists(ctx, qualified)
+}
+
+// GetField returns the value of a single field. The names are used as is,
+// without path parsing or escaping, so they may contain slashes. An empty
+// vault name uses the default vault.
+func (p *Provider) GetField(ctx context.Context, vaultName, item, field string) (string, error) {
+	secret, err := p.GetPath(ctx, NewPath(vaultName, item).WithField(field))
+	if err != nil {
+		return "", err
+	}
+	return secret.Value, nil
+}
+
+// SetField stores value in a single field, creating the item if needed. The
+// names are used as in GetField.
+func (p *Provider) SetField(ctx context.Context, vaultName, item, field, value string) error {
+	return p.SetPath(ctx, NewPath(vaultName, item).WithField(field), &vault.Secret{Value: value})
+}
+
+// qualifyPath fills in the default vault and returns the path as an escaped
+// op:// reference, which always parses back as vault/item[/section]/field
+// regardless of the default vault.
+func (p *Provider) qualifyPath(operation string, path Path) (string, error) {
+	if path.Vault == "" {
+		path.Vault = p.getDefaultVault()
+	}
+	if path.Vault == "" || path.Item == "" || (path.Section != "" && path.Field == "") {
+		return "", vault.NewVaultError(operation, path.String(), ProviderName,
+			fmt.Errorf("%w: path requires a vault (or default vault), an item, and a field when a section is set", ErrInvalidPath))
+	}
+	return "op://" + path.String(), nil
+}
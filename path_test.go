@@ -1,7 +1,14 @@
 package onepassword
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
 	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
 )
 
 func TestParsePath(t *testing.T) {
@@ -78,14 +85,48 @@ func TestParsePath(t *testing.T) {
 			},
 		},
 		{
-			name: "op:// with query params (stripped)",
-			path: "op://Private/Login/totp?attribute=totp",
+			name: "op:// with attribute",
+			path: "op://Private/Login/one-time password?attribute=totp",
+			want: &ParsedPath{
+				Vault:     "Private",
+				Item:      "Login",
+				Field:     "one-time password",
+				Attribute: AttributeTOTP,
+			},
+		},
+		{
+			name: "otp attribute alias without op://",
+			path: "Private/Login/otp?attribute=otp",
+			want: &ParsedPath{
+				Vault:     "Private",
+				Item:      "Login",
+				Field:     "otp",
+				Attribute: AttributeTOTP,
+			},
+		},
+		{
+			name: "op:// with other query params (stripped)",
+			path: "op://Private/Login/key?ssh-format=openssh",
 			want: &ParsedPath{
 				Vault: "Private",
 				Item:  "Login",
-				Field: "totp",
+				Field: "key",
+			},
+		},
+		{
+			name: "question mark in item title",
+			path: "Private/Why?/token",
+			want: &ParsedPath{
+				Vault: "Private",
+				Item:  "Why?",
+				Field: "token",
 			},
 		},
+		{
+			name:    "attribute without field",
+			path:    "op://Private/Login?attribute=totp",
+			wantErr: true,
+		},
 		{
 			name:    "empty path",
 			path:    "",
@@ -101,6 +142,33 @@ func TestParsePath(t *testing.T) {
 			path:    "a/b/c/d/e",
 			wantErr: true,
 		},
+		{
+			name: "escaped slash in item title",
+			path: `Private/prod\/db password/password`,
+			want: &ParsedPath{
+				Vault: "Private",
+				Item:  "prod/db password",
+				Field: "password",
+			},
+		},
+		{
+			name: "escaped backslash",
+			path: `Private/C:\\temp/key`,
+			want: &ParsedPath{
+				Vault: "Private",
+				Item:  `C:\temp`,
+				Field: "key",
+			},
+		},
+		{
+			name: "op:// with escaped slash",
+			path: `op://Private/a\/b/token`,
+			want: &ParsedPath{
+				Vault: "Private",
+				Item:  "a/b",
+				Field: "token",
+			},
+		},
 		{
 			name: "handles double slashes",
 			path: "Private//API Keys//token",
@@ -134,6 +202,9 @@ func TestParsePath(t *testing.T) {
 			if got.Field != tt.want.Field {
 				t.Errorf("ParsePath() Field = %v, want %v", got.Field, tt.want.Field)
 			}
+			if got.Attribute != tt.want.Attribute {
+				t.Errorf("ParsePath() Attribute = %v, want %v", got.Attribute, tt.want.Attribute)
+			}
 		})
 	}
 }
@@ -159,6 +230,11 @@ func TestParsedPath_String(t *testing.T) {
 			path: ParsedPath{Vault: "Private", Item: "Login", Section: "Security", Field: "totp"},
 			want: "Private/Login/Security/totp",
 		},
+		{
+			name: "escapes slashes",
+			path: ParsedPath{Vault: "Private", Item: "prod/db password", Field: "password"},
+			want: `Private/prod\/db password/password`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -191,6 +267,11 @@ func TestParsedPath_SecretReference(t *testing.T) {
 			path: ParsedPath{Vault: "Private", Item: "Login", Section: "Security", Field: "totp"},
 			want: "op://Private/Login/Security/totp",
 		},
+		{
+			name: "with attribute",
+			path: ParsedPath{Vault: "Private", Item: "Login", Field: "otp", Attribute: AttributeTOTP},
+			want: "op://Private/Login/otp?attribute=totp",
+		},
 	}
 
 	for _, tt := range tests {
@@ -201,3 +282,192 @@ func TestParsedPath_SecretReference(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildPath_RoundTrip(t *testing.T) {
+	tests := []struct {
+		vault, item, field string
+	}{
+		{"Private", "API Keys", "token"},
+		{"Private", "prod/db password", "password"},
+		{"Team/Ops", `back\slash`, "a/b"},
+	}
+
+	for _, tt := range tests {
+		path := BuildPath(tt.vault, tt.item, tt.field)
+		got, err := ParsePath(path, "")
+		if err != nil {
+			t.Fatalf("ParsePath(%q) error = %v", path, err)
+		}
+		if got.Vault != tt.vault || got.Item != tt.item || got.Field != tt.field {
+			t.Errorf("ParsePath(%q) = %+v, want %s/%s/%s", path, got, tt.vault, tt.item, tt.field)
+		}
+		if got.String() != path {
+			t.Errorf("String() = %q, want %q", got.String(), path)
+		}
+	}
+}
+
+func TestPath_Builder(t *testing.T) {
+	item := NewPath("Private", "prod/db")
+	field := item.WithSection("Primary").WithField("password")
+
+	if item.Field != "" || item.Section != "" {
+		t.Error("WithSection/WithField should not modify the original path")
+	}
+	if got, want := field.String(), `Private/prod\/db/Primary/password`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestProvider_qualifyPath(t *testing.T) {
+	p := &Provider{config: Config{DefaultVaultName: "Default"}}
+
+	tests := []struct {
+		name    string
+		path    Path
+		want    *ParsedPath
+		wantErr bool
+	}{
+		{
+			name: "explicit vault and item",
+			path: NewPath("Private", "API Keys"),
+			want: &ParsedPath{Vault: "Private", Item: "API Keys"},
+		},
+		{
+			name: "default vault with field",
+			path: NewPath("", "API Keys").WithField("token"),
+			want: &ParsedPath{Vault: "Default", Item: "API Keys", Field: "token"},
+		},
+		{
+			name:    "missing item",
+			path:    NewPath("Private", ""),
+			wantErr: true,
+		},
+		{
+			name:    "section without field",
+			path:    NewPath("Private", "Login").WithSection("Security"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qualified, err := p.qualifyPath("Get", tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("qualifyPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := ParsePath(qualified, p.getDefaultVault())
+			if err != nil {
+				t.Fatalf("ParsePath(%q) error = %v", qualified, err)
+			}
+			if *got != *tt.want {
+				t.Errorf("qualifyPath() parsed back as %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvider_GetField_SetField(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	m.items["v1"] = append(m.items["v1"], op.Item{
+		ID: "i3", Title: "prod/db", VaultID: "v1",
+		Fields: []op.ItemField{{ID: "url", Title: "url", Value: "postgres://prod"}},
+	})
+	p := newMockProvider(m, Config{DefaultVaultName: "Private"})
+
+	value, err := p.GetField(ctx, "", "prod/db", "url")
+	if err != nil || value != "postgres://prod" {
+		t.Errorf("GetField() = %q, %v; want postgres://prod", value, err)
+	}
+	if _, err := p.GetField(ctx, "Private", "prod/db", "missing"); !errors.Is(err, vault.ErrSecretNotFound) {
+		t.Errorf("GetField(missing) error = %v, want ErrSecretNotFound", err)
+	}
+
+	if err := p.SetField(ctx, "Private", "prod/db", "url", "postgres://new"); err != nil {
+		t.Fatalf("SetField() error = %v", err)
+	}
+	if len(m.put) != 1 || m.put[0].Fields[0].Value != "postgres://new" {
+		t.Errorf("put = %+v, want url updated", m.put)
+	}
+}
+
+func TestParsePathStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    *ParsedPath
+		wantErr bool
+	}{
+		{
+			name: "vault/item/field",
+			path: "Private/API Keys/token",
+			want: &ParsedPath{Vault: "Private", Item: "API Keys", Field: "token"},
+		},
+		{
+			name: "vault/item/section/field",
+			path: "Private/Login/Security/totp",
+			want: &ParsedPath{Vault: "Private", Item: "Login", Section: "Security", Field: "totp"},
+		},
+		{
+			name: "op:// item reference",
+			path: "op://Private/API Keys",
+			want: &ParsedPath{Vault: "Private", Item: "API Keys"},
+		},
+		{name: "two components", path: "Private/API Keys", wantErr: true},
+		{name: "one component", path: "API Keys", wantErr: true},
+		{name: "empty", path: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePathStrict(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePathStrict() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && *got != *tt.want {
+				t.Errorf("ParsePathStrict() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvider_StrictPaths_List(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(testMockAPI(), Config{StrictPaths: true})
+
+	// Listed paths are references, which strict mode accepts
+	paths, err := p.List(ctx, "Private/")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if want := []string{"op://Private/Database"}; !reflect.DeepEqual(paths, want) {
+		t.Fatalf("List() = %v, want %v", paths, want)
+	}
+	if secret, err := p.Get(ctx, paths[0]); err != nil || secret.Fields["username"] != "admin" {
+		t.Errorf("Get(%s) = %+v, %v", paths[0], secret, err)
+	}
+	if page, _, err := p.ListPage(ctx, "op://Private/", "", 0); err != nil || !reflect.DeepEqual(page, paths) {
+		t.Errorf("ListPage() = %v, %v; want %v", page, err, paths)
+	}
+
+	var buf bytes.Buffer
+	if err := p.Export(ctx, "Private/", &buf, ExportOptions{}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"Database"`)) {
+		t.Errorf("Export() = %s, want the Database item", buf.String())
+	}
+
+	prefixed := WithPrefix(p, "Data")
+	paths, err = prefixed.List(ctx, "Private/")
+	if err != nil || !reflect.DeepEqual(paths, []string{"op://Private/base"}) {
+		t.Fatalf("Prefixed.List() = %v, %v", paths, err)
+	}
+	if _, err := prefixed.Get(ctx, paths[0]); err != nil {
+		t.Errorf("Prefixed.Get(%s) error = %v", paths[0], err)
+	}
+}
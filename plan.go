@@ -0,0 +1,277 @@
+package onepassword
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ErrDryRun is returned by writes that can't be planned, such as SetBytes
+// or Move, when called in a dry run. It wraps vault.ErrNotSupported.
+var ErrDryRun error = &sentinelError{"write not supported in a dry run", []error{vault.ErrNotSupported}}
+
+// PlannedChange is a write computed in a dry run but not made.
+type PlannedChange struct {
+	// Path is the path written.
+	Path string
+
+	// Action is ChangeCreate, ChangeUpdate, ChangeDelete, or, for Ensure,
+	// ChangeReplace.
+	Action ChangeAction
+
+	// Fields are the fields that would be added, updated, or removed,
+	// sorted by name. Values are never included.
+	Fields []FieldChange
+
+	// TagsAdded and TagsRemoved are the tags that would be added and
+	// removed, sorted.
+	TagsAdded   []string
+	TagsRemoved []string
+
+	// Version is the version of the item a Set update was planned
+	// against. Applying the change fails with ErrConflict if the item has
+	// changed since. It is empty for other changes; those planned by
+	// Ensure are worked out again when applied.
+	Version string
+
+	apply func(ctx context.Context, p *Provider) error
+}
+
+// String describes the change in one line, like "~ Prod/app (~password,
+// +host; tags +env:prod)".
+func (c PlannedChange) String() string {
+	var b strings.Builder
+	switch c.Action {
+	case ChangeCreate:
+		b.WriteString("+ ")
+	case ChangeDelete:
+		b.WriteString("- ")
+	case ChangeReplace:
+		b.WriteString("-/+ ")
+	default:
+		b.WriteString("~ ")
+	}
+	b.WriteString(c.Path)
+
+	var details []string
+	for _, f := range c.Fields {
+		details = append(details, changeSign(f.Action)+f.Name)
+	}
+	var tags []string
+	for _, tag := range c.TagsAdded {
+		tags = append(tags, "+"+tag)
+	}
+	for _, tag := range c.TagsRemoved {
+		tags = append(tags, "-"+tag)
+	}
+	detail := strings.Join(details, ", ")
+	if len(tags) > 0 {
+		if detail != "" {
+			detail += "; "
+		}
+		detail += "tags " + strings.Join(tags, " ")
+	}
+	if detail != "" {
+		b.WriteString(" (" + detail + ")")
+	}
+	return b.String()
+}
+
+// changeSign returns the sign String uses for a field change.
+func changeSign(action ChangeAction) string {
+	switch action {
+	case ChangeCreate:
+		return "+"
+	case ChangeDelete:
+		return "-"
+	}
+	return "~"
+}
+
+// ChangeSet collects the writes planned in a dry run, so they can be
+// reviewed and later applied. The zero value is an empty change set ready
+// to use, and it is safe for concurrent use.
+type ChangeSet struct {
+	mu      sync.Mutex
+	changes []PlannedChange
+}
+
+// Changes returns the planned changes in the order they were made.
+func (c *ChangeSet) Changes() []PlannedChange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.changes)
+}
+
+// Len returns the number of planned changes.
+func (c *ChangeSet) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.changes)
+}
+
+// String describes the planned changes, one per line.
+func (c *ChangeSet) String() string {
+	var lines []string
+	for _, change := range c.Changes() {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Apply makes the planned changes through p, in the order they were
+// planned, even if p is configured with Config.DryRun. Updates are made
+// only if the item is still at the planned version. Apply stops at the
+// first failure; the changes before it remain made.
+func (c *ChangeSet) Apply(ctx context.Context, p *Provider) error {
+	ctx = context.WithValue(ctx, dryRunKey{}, (*ChangeSet)(nil))
+	for _, change := range c.Changes() {
+		if err := change.apply(ctx, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add records a planned change.
+func (c *ChangeSet) add(change PlannedChange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changes = append(c.changes, change)
+}
+
+// dryRunKey holds the change set writes through a context are planned into.
+type dryRunKey struct{}
+
+// WithDryRun returns a context under which Set, SetWithOptions, Delete,
+// Ensure, and the batch and bulk writes built on them record their changes
+// in plan instead of making them.
+//
+//	var plan onepassword.ChangeSet
+//	err := provider.SetBatch(onepassword.WithDryRun(ctx, &plan), secrets)
+//	fmt.Println(plan.String())
+//	err = plan.Apply(ctx, provider)
+func WithDryRun(ctx context.Context, plan *ChangeSet) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, plan)
+}
+
+// PlannedChanges returns the changes planned by a provider configured with
+// Config.DryRun since the last call, and starts a new change set.
+func (p *Provider) PlannedChanges() *ChangeSet {
+	p.dryRun.CompareAndSwap(nil, &ChangeSet{})
+	return p.dryRun.Swap(&ChangeSet{})
+}
+
+// planFor returns the change set writes through ctx are planned into, or
+// nil if they are made.
+func (p *Provider) planFor(ctx context.Context) *ChangeSet {
+	if plan, ok := ctx.Value(dryRunKey{}).(*ChangeSet); ok {
+		return plan
+	}
+	if !p.config.DryRun {
+		return nil
+	}
+	p.dryRun.CompareAndSwap(nil, &ChangeSet{})
+	return p.dryRun.Load()
+}
+
+// refuseDryRun returns ErrDryRun if writes through ctx are planned.
+func (p *Provider) refuseDryRun(ctx context.Context, operation, path string) error {
+	if p.planFor(ctx) != nil {
+		return vault.NewVaultError(operation, path, ProviderName, ErrDryRun)
+	}
+	return nil
+}
+
+// planSet records a Set that would create or update the item at path, or
+// nothing if the write wouldn't change the item. before is nil for a new
+// item.
+func planSet(plan *ChangeSet, path string, before *op.Item, after op.Item, secret *vault.Secret, opts SetOptions) {
+	change := PlannedChange{Path: path, Action: ChangeCreate}
+	var beforeFields []op.ItemField
+	var beforeTags []string
+	if before != nil {
+		change.Action = ChangeUpdate
+		change.Version = strconv.FormatUint(uint64(before.Version), 10)
+		beforeFields, beforeTags = before.Fields, before.Tags
+	}
+	change.Fields = diffItemFields(beforeFields, after.Fields)
+	change.TagsAdded, change.TagsRemoved = diffTags(beforeTags, after.Tags)
+	if before != nil && len(change.Fields) == 0 && len(change.TagsAdded) == 0 && len(change.TagsRemoved) == 0 {
+		return
+	}
+
+	secret = copySecret(secret)
+	if change.Version != "" && opts.ExpectedVersion == "" {
+		opts.ExpectedVersion = change.Version
+	}
+	change.apply = func(ctx context.Context, p *Provider) error {
+		return p.SetWithOptions(ctx, path, secret, opts)
+	}
+	plan.add(change)
+}
+
+// copySecret copies the secret of a planned change, as the caller may
+// reuse it before the change is applied.
+func copySecret(secret *vault.Secret) *vault.Secret {
+	clone := *secret
+	clone.Fields = maps.Clone(secret.Fields)
+	clone.Metadata.Tags = maps.Clone(secret.Metadata.Tags)
+	clone.Metadata.Extra = maps.Clone(secret.Metadata.Extra)
+	return &clone
+}
+
+// diffItemFields returns the changes turning the before fields into the
+// after fields, matching fields by their Secret.Fields key.
+func diffItemFields(before, after []op.ItemField) []FieldChange {
+	old := make(map[string]op.ItemField, len(before))
+	for i, key := range fieldKeys(before) {
+		old[key] = before[i]
+	}
+
+	var changes []FieldChange
+	for i, key := range fieldKeys(after) {
+		prev, ok := old[key]
+		delete(old, key)
+		switch {
+		case !ok:
+			changes = append(changes, FieldChange{Name: key, Action: ChangeCreate})
+		case prev.Value != after[i].Value || prev.FieldType != after[i].FieldType || !sameSection(prev, after[i]):
+			changes = append(changes, FieldChange{Name: key, Action: ChangeUpdate})
+		}
+	}
+	for key := range old {
+		changes = append(changes, FieldChange{Name: key, Action: ChangeDelete})
+	}
+	sortFieldChanges(changes)
+	return changes
+}
+
+// diffTags returns the tags in after but not before, and in before but not
+// after, each sorted.
+func diffTags(before, after []string) (added, removed []string) {
+	for _, tag := range after {
+		if !slices.Contains(before, tag) {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range before {
+		if !slices.Contains(after, tag) {
+			removed = append(removed, tag)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
+
+// planDelete records a Delete of the item at path.
+func planDelete(plan *ChangeSet, path string, apply func(ctx context.Context, p *Provider) error) {
+	plan.add(PlannedChange{Path: path, Action: ChangeDelete, apply: apply})
+}
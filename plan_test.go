@@ -0,0 +1,130 @@
+package onepassword
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestWithDryRun(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	var plan ChangeSet
+	ctx := WithDryRun(t.Context(), &plan)
+	err := p.SetBatch(ctx, map[string]*vault.Secret{
+		"Private/Database/password": {Value: "rotated"},
+		"Private/Database/username": {Value: "admin"}, // unchanged
+	})
+	if err != nil {
+		t.Fatalf("SetBatch() error = %v", err)
+	}
+	if err := p.Set(ctx, "Work/New", &vault.Secret{
+		Fields:   map[string]string{"token": "t"},
+		Metadata: vault.Metadata{Tags: map[string]string{"env": "dev"}},
+	}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := p.Delete(ctx, "Work/API"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := p.Delete(ctx, "Work/Missing"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if len(m.put)+len(m.created)+len(m.deleted) != 0 {
+		t.Fatalf("dry run wrote: put %d, created %d, deleted %d", len(m.put), len(m.created), len(m.deleted))
+	}
+
+	want := []string{
+		"~ Private/Database/password (~password)",
+		"+ Work/New (+token; tags +env:dev)",
+		"- Work/API",
+	}
+	if got := strings.Split(plan.String(), "\n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("plan = %q, want %q", got, want)
+	}
+	if v := plan.Changes()[0].Version; v != "3" {
+		t.Errorf("planned Version = %q, want 3", v)
+	}
+
+	if err := plan.Apply(t.Context(), p); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(m.put) != 1 || len(m.created) != 1 || len(m.deleted) != 1 {
+		t.Errorf("Apply() wrote: put %d, created %d, deleted %d; want 1 each", len(m.put), len(m.created), len(m.deleted))
+	}
+}
+
+func TestConfigDryRun(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{DryRun: true})
+	ctx := t.Context()
+
+	if err := p.Set(ctx, "Private/Database/password", &vault.Secret{Value: "rotated"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := p.DeleteByPrefix(ctx, "Work/", BulkDeleteOptions{Confirm: true}); err != nil {
+		t.Fatalf("DeleteByPrefix() error = %v", err)
+	}
+	if err := p.SetBytes(ctx, "Private/Blob", []byte("x")); !errors.Is(err, ErrDryRun) || !errors.Is(err, vault.ErrNotSupported) {
+		t.Errorf("SetBytes() error = %v, want ErrDryRun", err)
+	}
+	if len(m.put)+len(m.created)+len(m.deleted) != 0 {
+		t.Fatal("dry run wrote")
+	}
+
+	plan := p.PlannedChanges()
+	if plan.Len() != 2 {
+		t.Fatalf("planned %d changes, want 2:\n%s", plan.Len(), plan)
+	}
+	if p.PlannedChanges().Len() != 0 {
+		t.Error("PlannedChanges() didn't start a new change set")
+	}
+
+	// Applying refuses an update to an item changed since planning
+	m.items["v1"][0].Version = 4
+	err := plan.Apply(ctx, p)
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("Apply() error = %v, want ErrConflict", err)
+	}
+	if len(m.put)+len(m.deleted) != 0 {
+		t.Error("Apply() wrote after a conflict")
+	}
+
+	m.items["v1"][0].Version = 3
+	if err := plan.Apply(ctx, p); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(m.put) != 1 || !reflect.DeepEqual(m.deleted, []string{"i2"}) {
+		t.Errorf("Apply() put %d, deleted %v", len(m.put), m.deleted)
+	}
+	if p.PlannedChanges().Len() != 0 {
+		t.Error("Apply() was planned instead of made")
+	}
+}
+
+func TestEnsure_DryRunContext(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	var plan ChangeSet
+	desired := &vault.Secret{Fields: map[string]string{"password": "rotated"}}
+	result, err := p.Ensure(WithDryRun(t.Context(), &plan), "Private/Database", desired, EnsureOptions{})
+	if err != nil || !result.Changed() {
+		t.Fatalf("Ensure() = %+v, %v", result, err)
+	}
+	if len(m.put) != 0 || plan.Len() != 1 {
+		t.Fatalf("put %d, planned %d; want 0 and 1", len(m.put), plan.Len())
+	}
+	desired.Fields["password"] = "changed later"
+	if err := plan.Apply(t.Context(), p); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(m.put) != 1 || findValue(m.put[0], "password") != "rotated" {
+		t.Errorf("Apply() put %+v", m.put)
+	}
+}
@@ -0,0 +1,152 @@
+package onepassword
+
+import (
+	"context"
+	"strings"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// Prefixed is a vault.Vault that prefixes the title of every item it
+// addresses, so that several applications can share a vault without their
+// titles colliding. Paths given to it name items without the prefix, and
+// List returns them the same way, leaving out items without the prefix.
+// Items must be addressed by title, not ID.
+type Prefixed struct {
+	provider *Provider
+	prefix   string
+}
+
+// WithPrefix returns a vault.Vault writing and reading the items of p
+// whose titles start with prefix:
+//
+//	payments := onepassword.WithPrefix(provider, "svc-payments/")
+//	err := payments.Set(ctx, "Shared/db/password", secret) // item "svc-payments/db"
+func WithPrefix(p *Provider, prefix string) *Prefixed {
+	return &Prefixed{provider: p, prefix: prefix}
+}
+
+// WithNamespace is WithPrefix with the prefix namespace + "/".
+func WithNamespace(p *Provider, namespace string) *Prefixed {
+	return WithPrefix(p, strings.TrimSuffix(namespace, "/")+"/")
+}
+
+// Provider returns the underlying provider.
+func (v *Prefixed) Provider() *Provider {
+	return v.provider
+}
+
+// Prefix returns the prefix added to item titles.
+func (v *Prefixed) Prefix() string {
+	return v.prefix
+}
+
+// Get retrieves the secret at path, under the prefix.
+func (v *Prefixed) Get(ctx context.Context, path string) (*vault.Secret, error) {
+	prefixed, err := v.prefixPath("Get", path)
+	if err != nil {
+		return nil, err
+	}
+	return v.provider.Get(ctx, prefixed)
+}
+
+// Set stores the secret at path, under the prefix.
+func (v *Prefixed) Set(ctx context.Context, path string, secret *vault.Secret) error {
+	prefixed, err := v.prefixPath("Set", path)
+	if err != nil {
+		return err
+	}
+	return v.provider.Set(ctx, prefixed, secret)
+}
+
+// Delete removes the secret at path, under the prefix.
+func (v *Prefixed) Delete(ctx context.Context, path string) error {
+	prefixed, err := v.prefixPath("Delete", path)
+	if err != nil {
+		return err
+	}
+	return v.provider.Delete(ctx, prefixed)
+}
+
+// Exists checks if a secret exists at path, under the prefix.
+func (v *Prefixed) Exists(ctx context.Context, path string) (bool, error) {
+	prefixed, err := v.prefixPath("Exists", path)
+	if err != nil {
+		return false, err
+	}
+	return v.provider.Exists(ctx, prefixed)
+}
+
+// List returns the paths of items with the prefix matching prefix, which
+// is given and returned without the item prefix, as in Provider.List.
+func (v *Prefixed) List(ctx context.Context, prefix string) ([]string, error) {
+	// Prefix the item part of the listing prefix, if it has one
+	prefix = strings.TrimPrefix(prefix, "op://")
+	listPrefix := prefix
+	if i := unescapedSlash(prefix); i >= 0 {
+		listPrefix = prefix[:i+1] + EscapePathComponent(v.prefix) + prefix[i+1:]
+	}
+
+	paths, err := v.provider.List(ctx, listPrefix)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]string, 0, len(paths))
+	for _, path := range paths {
+		parts := splitPath(strings.TrimPrefix(path, "op://"))
+		if len(parts) != 2 || !strings.HasPrefix(parts[1], v.prefix) || parts[1] == v.prefix {
+			continue
+		}
+		results = append(results, v.provider.listedPath(BuildPath(parts[0], strings.TrimPrefix(parts[1], v.prefix))))
+	}
+	return results, nil
+}
+
+// Name returns the provider name.
+func (v *Prefixed) Name() string {
+	return v.provider.Name()
+}
+
+// Capabilities returns the capabilities of the provider.
+func (v *Prefixed) Capabilities() vault.Capabilities {
+	return v.provider.Capabilities()
+}
+
+// Close closes the underlying provider.
+func (v *Prefixed) Close() error {
+	return v.provider.Close()
+}
+
+// prefixPath returns path with the prefix added to its item title.
+func (v *Prefixed) prefixPath(operation, path string) (string, error) {
+	parsed, err := v.provider.parsePath(path)
+	if err != nil {
+		return "", vault.NewVaultError(operation, path, ProviderName, err)
+	}
+	parsed.Item = v.prefix + parsed.Item
+	if v.provider.config.StrictPaths {
+		// "vault/item" is rejected in strict mode
+		return "op://" + parsed.String(), nil
+	}
+	return parsed.String(), nil
+}
+
+// unescapedSlash returns the index of the first slash in path not escaped
+// with a backslash, or -1.
+func unescapedSlash(path string) int {
+	escaped := false
+	for i, r := range path {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '/':
+			return i
+		}
+	}
+	return -1
+}
+
+// Ensure Prefixed implements vault.Vault.
+var _ vault.Vault = (*Prefixed)(nil)
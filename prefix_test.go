@@ -0,0 +1,62 @@
+package onepassword
+
+import (
+	"reflect"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestWithPrefix(t *testing.T) {
+	m := testMockAPI()
+	m.items["v1"] = append(m.items["v1"],
+		op.Item{ID: "i3", Title: "svc-payments/db", VaultID: "v1", Fields: []op.ItemField{{ID: "password", Title: "password", Value: "paypass", FieldType: op.ItemFieldTypeConcealed}}},
+		op.Item{ID: "i4", Title: "svc-payments/queue", VaultID: "v1"},
+	)
+	v := WithNamespace(newMockProvider(m, Config{}), "svc-payments")
+
+	secret, err := v.Get(t.Context(), "Private/db")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if secret.Fields["password"] != "paypass" {
+		t.Errorf("Get() fields = %v, want the prefixed item's", secret.Fields)
+	}
+	if ok, err := v.Exists(t.Context(), "Private/Database"); err != nil || ok {
+		t.Errorf("Exists(unprefixed item) = %v, %v, want false", ok, err)
+	}
+
+	if err := v.Set(t.Context(), "Private/cache/password", &vault.Secret{Value: "x"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got := m.created[0].Title; got != "svc-payments/cache" {
+		t.Errorf("created title = %q, want svc-payments/cache", got)
+	}
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"", []string{"Private/db", "Private/queue"}},
+		{"Private/", []string{"Private/db", "Private/queue"}},
+		{"Private/q", []string{"Private/queue"}},
+		{"Work", []string{}},
+	}
+	for _, tt := range tests {
+		got, err := v.List(t.Context(), tt.prefix)
+		if err != nil {
+			t.Fatalf("List(%q) error = %v", tt.prefix, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("List(%q) = %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+
+	if err := v.Delete(t.Context(), "Private/queue"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if !reflect.DeepEqual(m.deleted, []string{"i4"}) {
+		t.Errorf("deleted %v, want [i4]", m.deleted)
+	}
+}
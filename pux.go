@@ -0,0 +1,364 @@
+package onepassword
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// puxDataFile is the archive member of a 1PUX export holding the items.
+const puxDataFile = "export.data"
+
+// puxCategories maps 1PUX category UUIDs to item categories.
+var puxCategories = map[string]op.ItemCategory{
+	"001": op.ItemCategoryLogin,
+	"002": op.ItemCategoryCreditCard,
+	"003": op.ItemCategorySecureNote,
+	"004": op.ItemCategoryIdentity,
+	"005": op.ItemCategoryPassword,
+	"006": op.ItemCategoryDocument,
+	"100": op.ItemCategorySoftwareLicense,
+	"101": op.ItemCategoryBankAccount,
+	"102": op.ItemCategoryDatabase,
+	"103": op.ItemCategoryDriverLicense,
+	"104": op.ItemCategoryOutdoorLicense,
+	"105": op.ItemCategoryMembership,
+	"106": op.ItemCategoryPassport,
+	"107": op.ItemCategoryRewards,
+	"108": op.ItemCategorySocialSecurityNumber,
+	"109": op.ItemCategoryRouter,
+	"110": op.ItemCategoryServer,
+	"111": op.ItemCategoryEmail,
+	"112": op.ItemCategoryAPICredentials,
+	"113": op.ItemCategoryMedicalRecord,
+	"114": op.ItemCategorySSHKey,
+	"115": op.ItemCategoryCryptoWallet,
+}
+
+// PUXExport is the content of a 1Password (1PUX) export file.
+type PUXExport struct {
+	Items []PUXItem
+}
+
+// PUXItem is one item of a 1PUX export, converted to the Secret model used
+// by Get.
+type PUXItem struct {
+	// Vault is the name of the vault the item was exported from.
+	Vault string
+
+	// Title is the item title.
+	Title string
+
+	// Category is the item category, or ItemCategoryUnsupported for
+	// categories this package does not know.
+	Category op.ItemCategory
+
+	// Archived reports whether the item was in the archive.
+	Archived bool
+
+	// Secret holds the item's fields, notes (as "notes"), tags, and
+	// timestamps.
+	Secret *vault.Secret
+}
+
+// Path returns the item's "vault/item" path.
+func (i PUXItem) Path() string {
+	return BuildPath(i.Vault, i.Title)
+}
+
+// OpenPUX reads the 1PUX export file at name.
+//
+//	export, err := onepassword.OpenPUX("1PasswordExport.1pux")
+//	for _, item := range export.Items {
+//	    fmt.Println(item.Path(), item.Category)
+//	}
+func OpenPUX(name string) (*PUXExport, error) {
+	f, err := os.Open(name) //nolint:gosec // G304: reading a user-chosen export file is the point
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return ReadPUX(f, info.Size())
+}
+
+// ReadPUX reads a 1PUX export, a zip archive of size bytes.
+func ReadPUX(r io.ReaderAt, size int64) (*PUXExport, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 1pux archive: %w", err)
+	}
+
+	data, err := archive.Open(puxDataFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 1pux archive: %w", err)
+	}
+	defer data.Close()
+
+	var export puxData
+	if err := json.NewDecoder(data).Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid 1pux %s: %w", puxDataFile, err)
+	}
+
+	result := &PUXExport{}
+	for _, account := range export.Accounts {
+		for _, v := range account.Vaults {
+			for _, item := range v.Items {
+				result.Items = append(result.Items, item.toPUXItem(v.Attrs.Name))
+			}
+		}
+	}
+	return result, nil
+}
+
+// ReplayOptions configures PUXExport.Replay.
+type ReplayOptions struct {
+	// MapPath converts an item's path to its destination path.
+	// Default: the same path
+	MapPath func(path string) string
+
+	// IncludeArchived also replays archived items.
+	IncludeArchived bool
+
+	// Overwrite writes items whose destination already exists. Otherwise
+	// they are skipped.
+	Overwrite bool
+}
+
+// Replay writes the export's items to dst with Set. Each secret carries its
+// category in Metadata.Extra[CategoryKey], so a 1Password destination
+// creates items with the original category; other vaults ignore it. Replay
+// stops at the first failure; the result lists what was done.
+//
+//	result, err := export.Replay(ctx, provider, onepassword.ReplayOptions{
+//	    MapPath: func(path string) string { return "Restored/" + path },
+//	})
+func (e *PUXExport) Replay(ctx context.Context, dst vault.Vault, opts ReplayOptions) (*ImportResult, error) {
+	result := &ImportResult{}
+	for _, item := range e.Items {
+		if item.Archived && !opts.IncludeArchived {
+			continue
+		}
+
+		path := item.Path()
+		if opts.MapPath != nil {
+			path = opts.MapPath(path)
+		}
+
+		exists, err := dst.Exists(ctx, path)
+		if err != nil {
+			return result, err
+		}
+		if exists && !opts.Overwrite {
+			result.Skipped = append(result.Skipped, path)
+			continue
+		}
+
+		secret := *item.Secret
+		if err := dst.Set(ctx, path, &secret); err != nil {
+			return result, err
+		}
+		if exists {
+			result.Updated = append(result.Updated, path)
+		} else {
+			result.Created = append(result.Created, path)
+		}
+	}
+	return result, nil
+}
+
+// puxData is the subset of export.data read by ReadPUX.
+type puxData struct {
+	Accounts []struct {
+		Vaults []struct {
+			Attrs struct {
+				Name string `json:"name"`
+			} `json:"attrs"`
+			Items []puxItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type puxItem struct {
+	UUID         string `json:"uuid"`
+	CreatedAt    int64  `json:"createdAt"`
+	UpdatedAt    int64  `json:"updatedAt"`
+	State        string `json:"state"`
+	CategoryUUID string `json:"categoryUuid"`
+	Details      struct {
+		LoginFields []struct {
+			Value       string `json:"value"`
+			Name        string `json:"name"`
+			FieldType   string `json:"fieldType"`
+			Designation string `json:"designation"`
+		} `json:"loginFields"`
+		NotesPlain string `json:"notesPlain"`
+		Password   string `json:"password"`
+		Sections   []struct {
+			Title  string `json:"title"`
+			Fields []struct {
+				Title string                     `json:"title"`
+				ID    string                     `json:"id"`
+				Value map[string]json.RawMessage `json:"value"`
+			} `json:"fields"`
+		} `json:"sections"`
+	} `json:"details"`
+	Overview struct {
+		Title string   `json:"title"`
+		URL   string   `json:"url"`
+		Tags  []string `json:"tags"`
+	} `json:"overview"`
+}
+
+// toPUXItem converts an exported item to a PUXItem.
+func (i puxItem) toPUXItem(vaultName string) PUXItem {
+	category, ok := puxCategories[i.CategoryUUID]
+	if !ok {
+		category = op.ItemCategoryUnsupported
+	}
+
+	item := PUXItem{
+		Vault:    vaultName,
+		Title:    i.Overview.Title,
+		Category: category,
+		Archived: i.State == "archived",
+	}
+
+	secret := &vault.Secret{
+		Fields: make(map[string]string),
+		Metadata: vault.Metadata{
+			Path: item.Path(),
+			Extra: map[string]any{
+				"itemId":    i.UUID,
+				CategoryKey: string(category),
+			},
+		},
+	}
+	if i.CreatedAt > 0 {
+		secret.Metadata.CreatedAt = vault.NewTimestamp(time.Unix(i.CreatedAt, 0))
+	}
+	if i.UpdatedAt > 0 {
+		secret.Metadata.ModifiedAt = vault.NewTimestamp(time.Unix(i.UpdatedAt, 0))
+	}
+	if len(i.Overview.Tags) > 0 {
+		secret.Metadata.Tags = make(map[string]string)
+		for _, tag := range i.Overview.Tags {
+			key, value, _ := strings.Cut(tag, ":")
+			secret.Metadata.Tags[key] = value
+		}
+	}
+
+	var firstConcealed string
+	for _, f := range i.Details.LoginFields {
+		name := f.Designation
+		if name == "" {
+			name = f.Name
+		}
+		if name == "" || f.Value == "" {
+			continue
+		}
+		secret.Fields[name] = f.Value
+		if f.FieldType == "P" && firstConcealed == "" {
+			firstConcealed = f.Value
+		}
+	}
+	if i.Details.Password != "" {
+		secret.Fields["password"] = i.Details.Password
+	}
+	if i.Overview.URL != "" {
+		secret.Fields["website"] = i.Overview.URL
+	}
+	for _, section := range i.Details.Sections {
+		for _, f := range section.Fields {
+			name := f.Title
+			if name == "" {
+				name = f.ID
+			}
+			value, concealed := puxFieldValue(f.Value)
+			if name == "" || value == "" {
+				continue
+			}
+			secret.Fields[name] = value
+			if concealed && firstConcealed == "" {
+				firstConcealed = value
+			}
+		}
+	}
+	if i.Details.NotesPlain != "" {
+		secret.Fields["notes"] = i.Details.NotesPlain
+	}
+
+	// Primary value, as in itemToSecret
+	switch {
+	case secret.Fields["password"] != "":
+		secret.Value = secret.Fields["password"]
+	case firstConcealed != "":
+		secret.Value = firstConcealed
+	}
+
+	item.Secret = secret
+	return item
+}
+
+// puxFieldValue returns the text of a section field value, which 1PUX
+// stores as a single-key object such as {"concealed": "..."} or
+// {"date": 1700000000}, and whether the value is concealed.
+func puxFieldValue(value map[string]json.RawMessage) (string, bool) {
+	kinds := make([]string, 0, len(value))
+	for kind := range value {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		raw := value[kind]
+
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			return s, kind == "concealed" || kind == "totp"
+		}
+
+		var n json.Number
+		if json.Unmarshal(raw, &n) == nil {
+			if kind == "date" {
+				if secs, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+					return time.Unix(secs, 0).UTC().Format("2006-01-02"), false
+				}
+			}
+			return n.String(), false
+		}
+
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(raw, &obj) == nil {
+			switch kind {
+			case "email":
+				var email struct {
+					Address string `json:"email_address"`
+				}
+				_ = json.Unmarshal(raw, &email)
+				return email.Address, false
+			case "sshKey":
+				var key struct {
+					PrivateKey string `json:"privateKey"`
+				}
+				_ = json.Unmarshal(raw, &key)
+				return key.PrivateKey, true
+			}
+		}
+	}
+	return "", false
+}
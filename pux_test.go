@@ -0,0 +1,158 @@
+package onepassword
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/providers/memory"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+const testPUXData = `{
+  "accounts": [{
+    "attrs": {"name": "Example"},
+    "vaults": [{
+      "attrs": {"uuid": "v1", "name": "Private"},
+      "items": [
+        {
+          "uuid": "i1",
+          "createdAt": 1700000000,
+          "updatedAt": 1700000500,
+          "state": "active",
+          "categoryUuid": "001",
+          "details": {
+            "loginFields": [
+              {"value": "octocat", "name": "username", "fieldType": "T", "designation": "username"},
+              {"value": "hunter2", "name": "password", "fieldType": "P", "designation": "password"}
+            ],
+            "notesPlain": "recovery codes in the safe",
+            "sections": [{
+              "title": "Extra",
+              "fields": [
+                {"title": "pin", "id": "pin", "value": {"concealed": "1234"}},
+                {"title": "email", "id": "email", "value": {"email": {"email_address": "me@example.com"}}},
+                {"title": "expires", "id": "exp", "value": {"date": 1704067200}}
+              ]
+            }]
+          },
+          "overview": {"title": "GitHub", "url": "https://github.com", "tags": ["dev", "env:prod"]}
+        },
+        {
+          "uuid": "i2",
+          "state": "archived",
+          "categoryUuid": "112",
+          "details": {
+            "sections": [{"fields": [{"title": "credential", "id": "credential", "value": {"concealed": "sk_x"}}]}]
+          },
+          "overview": {"title": "Old API"}
+        }
+      ]
+    }]
+  }]
+}`
+
+func testPUXArchive(t *testing.T) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(puxDataFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(testPUXData)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestReadPUX(t *testing.T) {
+	r := testPUXArchive(t)
+	export, err := ReadPUX(r, r.Size())
+	if err != nil {
+		t.Fatalf("ReadPUX() error = %v", err)
+	}
+	if len(export.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(export.Items))
+	}
+
+	login := export.Items[0]
+	if login.Path() != "Private/GitHub" || login.Category != op.ItemCategoryLogin || login.Archived {
+		t.Errorf("item = %s %s archived=%v", login.Path(), login.Category, login.Archived)
+	}
+	wantFields := map[string]string{
+		"username": "octocat",
+		"password": "hunter2",
+		"website":  "https://github.com",
+		"pin":      "1234",
+		"email":    "me@example.com",
+		"expires":  "2024-01-01",
+		"notes":    "recovery codes in the safe",
+	}
+	if !reflect.DeepEqual(login.Secret.Fields, wantFields) {
+		t.Errorf("Fields = %v, want %v", login.Secret.Fields, wantFields)
+	}
+	if login.Secret.Value != "hunter2" {
+		t.Errorf("Value = %q, want hunter2", login.Secret.Value)
+	}
+	if !reflect.DeepEqual(login.Secret.Metadata.Tags, map[string]string{"dev": "", "env": "prod"}) {
+		t.Errorf("Tags = %v", login.Secret.Metadata.Tags)
+	}
+	if login.Secret.Metadata.ModifiedAt == nil || login.Secret.Metadata.ModifiedAt.Unix() != 1700000500 {
+		t.Errorf("ModifiedAt = %v", login.Secret.Metadata.ModifiedAt)
+	}
+
+	api := export.Items[1]
+	if api.Category != op.ItemCategoryAPICredentials || !api.Archived || api.Secret.Value != "sk_x" {
+		t.Errorf("archived item = %+v", api)
+	}
+}
+
+func TestReadPUX_Invalid(t *testing.T) {
+	r := bytes.NewReader([]byte("not a zip"))
+	if _, err := ReadPUX(r, r.Size()); err == nil {
+		t.Error("ReadPUX() error = nil, want error")
+	}
+}
+
+func TestPUXExport_Replay(t *testing.T) {
+	ctx := context.Background()
+	r := testPUXArchive(t)
+	export, err := ReadPUX(r, r.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := memory.New()
+	_ = dst.Set(ctx, "Restored/Private/Old API", &vault.Secret{Value: "keep"})
+
+	opts := ReplayOptions{
+		MapPath:         func(path string) string { return "Restored/" + path },
+		IncludeArchived: true,
+	}
+	result, err := export.Replay(ctx, dst, opts)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Created, []string{"Restored/Private/GitHub"}) {
+		t.Errorf("Created = %v", result.Created)
+	}
+	if !reflect.DeepEqual(result.Skipped, []string{"Restored/Private/Old API"}) {
+		t.Errorf("Skipped = %v", result.Skipped)
+	}
+
+	got, err := dst.Get(ctx, "Restored/Private/GitHub")
+	if err != nil || got.Fields["username"] != "octocat" {
+		t.Errorf("replayed secret = %+v, %v", got, err)
+	}
+	kept, _ := dst.Get(ctx, "Restored/Private/Old API")
+	if kept.Value != "keep" {
+		t.Errorf("existing secret overwritten: %q", kept.Value)
+	}
+}
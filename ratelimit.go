@@ -0,0 +1,92 @@
+package onepassword
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit smooths a provider's calls to 1Password, so that bursts of
+// operations stay under the service account's quotas instead of being
+// rejected. Calls over the limit wait for their turn, or until their
+// context is done. A zero rate leaves that class of calls unlimited.
+type RateLimit struct {
+	// ReadsPerSecond limits calls reading secrets, items, and listings.
+	ReadsPerSecond float64
+
+	// WritesPerSecond limits calls creating, updating, and deleting items.
+	WritesPerSecond float64
+
+	// Burst is how many calls of each class may be made at once after a
+	// quiet period.
+	// Default: the per-second rate, rounded up
+	Burst int
+}
+
+// tokenBucket is a token bucket rate limiter. Each call takes a token;
+// tokens refill at rate per second up to burst. A call finding no token
+// reserves the next one and waits for it.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a bucket allowing rate calls per second, or nil,
+// which allows every call, if rate isn't positive.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	b := &tokenBucket{rate: rate, burst: float64(burst), now: time.Now}
+	b.tokens, b.last = b.burst, b.now()
+	return b
+}
+
+// wait takes a token, waiting for one if needed. It reports whether the
+// call was delayed. If ctx is done first, the token is given back and the
+// context's error returned.
+func (b *tokenBucket) wait(ctx context.Context) (bool, error) {
+	if b == nil {
+		return false, nil
+	}
+	delay := b.reserve()
+	if delay <= 0 {
+		return false, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true, nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return true, ctx.Err()
+	}
+}
+
+// reserve takes a token, possibly one not yet refilled, and returns how
+// long to wait until it is.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
@@ -0,0 +1,74 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newTokenBucket(2, 3)
+	b.now = func() time.Time { return now }
+	b.last = now
+
+	// The burst is served at once, then calls are spaced at the rate
+	for i := range 3 {
+		if d := b.reserve(); d != 0 {
+			t.Fatalf("reserve() #%d = %v, want no wait", i, d)
+		}
+	}
+	if d := b.reserve(); d != 500*time.Millisecond {
+		t.Errorf("reserve() over burst = %v, want 500ms", d)
+	}
+	if d := b.reserve(); d != time.Second {
+		t.Errorf("reserve() queued = %v, want 1s", d)
+	}
+
+	// Tokens refill over time, up to the burst
+	now = now.Add(time.Hour)
+	for range 3 {
+		if d := b.reserve(); d != 0 {
+			t.Fatalf("reserve() after refill = %v, want no wait", d)
+		}
+	}
+
+	if newTokenBucket(0, 5) != nil {
+		t.Error("newTokenBucket(0) should allow every call")
+	}
+	if b := newTokenBucket(2.5, 0); b.burst != 3 {
+		t.Errorf("default burst = %v, want 3", b.burst)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	p := newMockProvider(testMockAPI(), Config{
+		RateLimit: RateLimit{ReadsPerSecond: 200, Burst: 1},
+	})
+
+	for range 3 {
+		if _, err := p.Get(t.Context(), "Private/Database"); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+	if p.Stats().Throttled == 0 {
+		t.Error("Stats().Throttled = 0, want delayed calls counted")
+	}
+}
+
+func TestTokenBucket_Cancel(t *testing.T) {
+	b := newTokenBucket(0.001, 1)
+	if delayed, err := b.wait(t.Context()); delayed || err != nil {
+		t.Fatalf("wait() = %v, %v, want the burst token", delayed, err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := b.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait() with a cancelled context error = %v, want context.Canceled", err)
+	}
+	if b.tokens > 0.01 || b.tokens < -0.01 {
+		t.Errorf("tokens after cancel = %v, want the reserved token returned", b.tokens)
+	}
+}
@@ -0,0 +1,315 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ChangeAction describes what a write does to an item or field.
+type ChangeAction string
+
+const (
+	// ChangeNone leaves the item as it is.
+	ChangeNone ChangeAction = "none"
+
+	// ChangeCreate creates the item or adds the field.
+	ChangeCreate ChangeAction = "create"
+
+	// ChangeUpdate modifies the item or the field's value or type.
+	ChangeUpdate ChangeAction = "update"
+
+	// ChangeDelete deletes the item or removes the field.
+	ChangeDelete ChangeAction = "delete"
+
+	// ChangeReplace deletes the item and creates it again, as needed to
+	// change its category.
+	ChangeReplace ChangeAction = "replace"
+)
+
+// EnsureOptions controls the behavior of Ensure.
+type EnsureOptions struct {
+	// Prune removes fields of an existing item that the desired secret
+	// doesn't have; for a section path, only fields of that section. It is
+	// ignored for field paths.
+	Prune bool
+
+	// Category is the desired category. It takes precedence over
+	// Secret.Metadata.Extra[CategoryKey]. With neither set, the category
+	// of an existing item is left alone, and a new item gets the category
+	// Set would give it.
+	Category op.ItemCategory
+
+	// RecreateOnCategoryChange replaces an item whose category differs from
+	// the desired one with a new item holding the same content, as
+	// 1Password can't change the category of an item. The new item has a
+	// new ID. Without it, the difference is only reported.
+	RecreateOnCategoryChange bool
+
+	// DryRun computes the changes without making them.
+	DryRun bool
+}
+
+// EnsureResult reports the changes Ensure made, or would make in a dry run.
+// It lists field and tag names but never values.
+type EnsureResult struct {
+	// Path is the path passed to Ensure.
+	Path string
+
+	// Action is what happened to the item as a whole.
+	Action ChangeAction
+
+	// Fields are the fields added, updated, or removed, sorted by name.
+	Fields []FieldChange
+
+	// TagsAdded and TagsRemoved are the tags added and removed, sorted.
+	TagsAdded   []string
+	TagsRemoved []string
+
+	// Category is set when the item's category differs from the desired
+	// one. It is only changed with EnsureOptions.RecreateOnCategoryChange.
+	Category *CategoryChange
+}
+
+// Changed reports whether the item was, or would be, written.
+func (r *EnsureResult) Changed() bool {
+	return r.Action != ChangeNone
+}
+
+// FieldChange describes a change to one field.
+type FieldChange struct {
+	// Name is the field's title.
+	Name string
+
+	// Action is ChangeCreate, ChangeUpdate, or ChangeDelete.
+	Action ChangeAction
+}
+
+// CategoryChange describes a difference in item category.
+type CategoryChange struct {
+	From op.ItemCategory
+	To   op.ItemCategory
+}
+
+// Ensure makes the item at path hold the desired secret, diffing its
+// fields, tags, and category against the item and applying only what
+// differs. Fields not in the desired secret are kept unless
+// EnsureOptions.Prune is set; tags are replaced by the desired tags when
+// Secret.Metadata.Tags is non-nil and left alone otherwise. An item already
+// in the desired state isn't written, so it keeps its version.
+//
+//	result, err := provider.Ensure(ctx, "Prod/app", desired, onepassword.EnsureOptions{Prune: true})
+//	if result.Changed() {
+//	    log.Printf("%s: %s %v", result.Path, result.Action, result.Fields)
+//	}
+func (p *Provider) Ensure(ctx context.Context, path string, desired *vault.Secret, opts EnsureOptions) (*EnsureResult, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("Ensure", path, ProviderName, vault.ErrClosed)
+	}
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return nil, vault.NewVaultError("Ensure", path, ProviderName, err)
+	}
+	sectionWrite(parsed, desired)
+
+	unlock := p.lockItem(parsed)
+	defer unlock()
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Write)
+	defer cancel()
+
+	types, err := fieldTypeOverrides(desired, SetOptions{})
+	if err != nil {
+		return nil, vault.NewVaultError("Ensure", path, ProviderName, err)
+	}
+	setOpts := SetOptions{Section: parsed.Section, Category: opts.Category}
+
+	vaultID, err := p.resolveVaultID(ctx, parsed.Vault)
+	if err != nil {
+		return nil, mapError("Ensure", path, err)
+	}
+
+	itemID, err := p.resolveItemID(ctx, vaultID, parsed.Item)
+	if isNotFoundError(err) {
+		return p.ensureCreated(ctx, vaultID, parsed, path, desired, setOpts, types, opts.DryRun)
+	}
+	if err != nil {
+		return nil, mapError("Ensure", path, err)
+	}
+
+	item, err := p.items.Get(ctx, vaultID, itemID)
+	if err != nil {
+		return nil, mapError("Ensure", path, err)
+	}
+
+	result := &EnsureResult{Path: path, Action: ChangeNone}
+	wanted := p.buildFields(desired, parsed.Field, types)
+	useBuiltinFields(item.Category, wanted)
+	if parsed.Section != "" {
+		placeInSection(wanted, ensureSection(&item.Sections, parsed.Section))
+	} else {
+		p.placeNewFields(item.Fields, &item.Sections, wanted, item.Category)
+	}
+	var prune func(op.ItemField) bool
+	if opts.Prune && parsed.Field == "" {
+		prune = func(field op.ItemField) bool {
+			return parsed.Section == "" || inSection(item, field, parsed.Section)
+		}
+	}
+	item.Fields, result.Fields = reconcileFields(item.Fields, wanted, types, prune)
+
+	if desired.Metadata.Tags != nil {
+		tags := tagsToStrings(desired.Metadata.Tags, p.tagSeparator())
+		tags = p.keepManagedTag(item.Tags, tags)
+		result.TagsAdded, result.TagsRemoved = diffTags(item.Tags, tags)
+		item.Tags = tags
+	}
+
+	category, err := explicitCategory(desired, opts.Category)
+	if err != nil {
+		return nil, vault.NewVaultError("Ensure", path, ProviderName, err)
+	}
+	if category != "" && category != item.Category {
+		result.Category = &CategoryChange{From: item.Category, To: category}
+	}
+
+	switch {
+	case result.Category != nil && opts.RecreateOnCategoryChange:
+		result.Action = ChangeReplace
+	case len(result.Fields) > 0 || len(result.TagsAdded) > 0 || len(result.TagsRemoved) > 0:
+		result.Action = ChangeUpdate
+	}
+	if opts.DryRun || result.Action == ChangeNone {
+		return result, nil
+	}
+	if plan := p.planFor(ctx); plan != nil {
+		planEnsure(plan, result, desired, opts)
+		return result, nil
+	}
+
+	p.invalidateDiskCache(ctx, parsed)
+	if result.Action == ChangeReplace {
+		if err := p.checkManaged(item); err != nil {
+			return nil, vault.NewVaultError("Ensure", path, ProviderName, err)
+		}
+		// Create the replacement first, so a failure loses nothing
+		_, err = p.items.Create(ctx, op.ItemCreateParams{
+			VaultID:  vaultID,
+			Title:    item.Title,
+			Category: category,
+			Sections: item.Sections,
+			Fields:   item.Fields,
+			Tags:     item.Tags,
+			Websites: item.Websites,
+		})
+		if err != nil {
+			return nil, mapError("Ensure", path, err)
+		}
+		if err := p.items.Delete(ctx, vaultID, item.ID); err != nil && !isNotFoundError(err) {
+			return nil, mapError("Ensure", path, err)
+		}
+		return result, p.mirrorSet(ctx, "Ensure", path, desired)
+	}
+
+	if _, err := p.items.Put(ctx, item); err != nil {
+		return nil, mapError("Ensure", path, err)
+	}
+	return result, p.mirrorSet(ctx, "Ensure", path, desired)
+}
+
+// ensureCreated creates the desired item, reporting all of it as new.
+func (p *Provider) ensureCreated(ctx context.Context, vaultID string, parsed *ParsedPath, path string, desired *vault.Secret, opts SetOptions, types map[string]op.ItemFieldType, dryRun bool) (*EnsureResult, error) {
+	result := &EnsureResult{Path: path, Action: ChangeCreate}
+	for _, field := range p.buildFields(desired, parsed.Field, types) {
+		result.Fields = append(result.Fields, FieldChange{Name: field.Title, Action: ChangeCreate})
+	}
+	sortFieldChanges(result.Fields)
+	result.TagsAdded = tagsToStrings(desired.Metadata.Tags, p.tagSeparator())
+
+	if dryRun {
+		return result, nil
+	}
+	if plan := p.planFor(ctx); plan != nil {
+		planEnsure(plan, result, desired, EnsureOptions{})
+		return result, nil
+	}
+	p.invalidateDiskCache(ctx, parsed)
+	if err := p.createItem(ctx, vaultID, parsed, desired, opts, types); err != nil {
+		return nil, err
+	}
+	return result, p.mirrorSet(ctx, "Ensure", path, desired)
+}
+
+// planEnsure records the changes of an Ensure. Applying them runs Ensure
+// again, which makes whatever changes are needed by then.
+func planEnsure(plan *ChangeSet, result *EnsureResult, desired *vault.Secret, opts EnsureOptions) {
+	desired = copySecret(desired)
+	change := PlannedChange{
+		Path:        result.Path,
+		Action:      result.Action,
+		Fields:      result.Fields,
+		TagsAdded:   result.TagsAdded,
+		TagsRemoved: result.TagsRemoved,
+	}
+	change.apply = func(ctx context.Context, p *Provider) error {
+		_, err := p.Ensure(ctx, result.Path, desired, opts)
+		return err
+	}
+	plan.add(change)
+}
+
+// reconcileFields applies the wanted fields to existing, returning the new
+// fields and the changes made. A field's type changes only when types sets
+// it explicitly. Fields not wanted are removed if prune, when not nil,
+// reports true for them.
+func reconcileFields(existing, wanted []op.ItemField, types map[string]op.ItemFieldType, prune func(op.ItemField) bool) ([]op.ItemField, []FieldChange) {
+	fields := slices.Clone(existing)
+	keep := make([]bool, len(fields))
+
+	var changes []FieldChange
+	for _, w := range wanted {
+		i := mergeIndex(fields, w)
+		if i < 0 {
+			fields = append(fields, w)
+			keep = append(keep, true)
+			changes = append(changes, FieldChange{Name: w.Title, Action: ChangeCreate})
+			continue
+		}
+		keep[i] = true
+
+		_, retype := types[w.Title]
+		if fields[i].Value != w.Value || (retype && fields[i].FieldType != w.FieldType) {
+			fields[i].Value = w.Value
+			if retype {
+				fields[i].FieldType = w.FieldType
+			}
+			changes = append(changes, FieldChange{Name: fieldKey(fields[i]), Action: ChangeUpdate})
+		}
+	}
+
+	if prune != nil {
+		kept := fields[:0]
+		for i, field := range fields {
+			if keep[i] || !prune(field) {
+				kept = append(kept, field)
+			} else {
+				changes = append(changes, FieldChange{Name: fieldKey(field), Action: ChangeDelete})
+			}
+		}
+		fields = kept
+	}
+
+	sortFieldChanges(changes)
+	return fields, changes
+}
+
+// sortFieldChanges sorts changes by field name.
+func sortFieldChanges(changes []FieldChange) {
+	slices.SortStableFunc(changes, func(a, b FieldChange) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
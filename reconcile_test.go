@@ -0,0 +1,170 @@
+package onepassword
+
+import (
+	"reflect"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestProvider_Ensure(t *testing.T) {
+	ctx := t.Context()
+
+	tests := []struct {
+		name        string
+		path        string
+		desired     *vault.Secret
+		opts        EnsureOptions
+		wantAction  ChangeAction
+		wantFields  []FieldChange
+		wantAdded   []string
+		wantRemoved []string
+		wantCalls   int // Puts, Creates, and Deletes
+	}{
+		{
+			name:       "in sync",
+			path:       "Private/Database",
+			desired:    &vault.Secret{Fields: map[string]string{"username": "admin", "password": "hunter2"}},
+			wantAction: ChangeNone,
+		},
+		{
+			name:       "field value",
+			path:       "Private/Database/password",
+			desired:    &vault.Secret{Value: "rotated"},
+			wantAction: ChangeUpdate,
+			wantFields: []FieldChange{{Name: "password", Action: ChangeUpdate}},
+			wantCalls:  1,
+		},
+		{
+			name:       "new field kept alongside",
+			path:       "Private/Database",
+			desired:    &vault.Secret{Fields: map[string]string{"host": "db", "password": "hunter2"}},
+			wantAction: ChangeUpdate,
+			wantFields: []FieldChange{{Name: "host", Action: ChangeCreate}},
+			wantCalls:  1,
+		},
+		{
+			name:    "prune",
+			path:    "Private/Database",
+			desired: &vault.Secret{Fields: map[string]string{"host": "db", "password": "hunter2"}},
+			opts:    EnsureOptions{Prune: true},
+			wantFields: []FieldChange{
+				{Name: "host", Action: ChangeCreate},
+				{Name: "username", Action: ChangeDelete},
+			},
+			wantAction: ChangeUpdate,
+			wantCalls:  1,
+		},
+		{
+			name: "tags",
+			path: "Private/Database/password",
+			desired: &vault.Secret{Value: "hunter2", Metadata: vault.Metadata{
+				Tags: map[string]string{"team": "core"},
+			}},
+			wantAction:  ChangeUpdate,
+			wantAdded:   []string{"team:core"},
+			wantRemoved: []string{"env:prod"},
+			wantCalls:   1,
+		},
+		{
+			name:       "dry run",
+			path:       "Private/Database/password",
+			desired:    &vault.Secret{Value: "rotated"},
+			opts:       EnsureOptions{DryRun: true},
+			wantAction: ChangeUpdate,
+			wantFields: []FieldChange{{Name: "password", Action: ChangeUpdate}},
+		},
+		{
+			name:       "create",
+			path:       "Work/New",
+			desired:    &vault.Secret{Fields: map[string]string{"token": "t"}, Metadata: vault.Metadata{Tags: map[string]string{"env": "dev"}}},
+			wantAction: ChangeCreate,
+			wantFields: []FieldChange{{Name: "token", Action: ChangeCreate}},
+			wantAdded:  []string{"env:dev"},
+			wantCalls:  1,
+		},
+		{
+			name:       "category reported",
+			path:       "Private/Database/password",
+			desired:    &vault.Secret{Value: "hunter2"},
+			opts:       EnsureOptions{Category: op.ItemCategoryDatabase},
+			wantAction: ChangeNone,
+		},
+		{
+			name:       "category recreated",
+			path:       "Private/Database/password",
+			desired:    &vault.Secret{Value: "hunter2"},
+			opts:       EnsureOptions{Category: op.ItemCategoryDatabase, RecreateOnCategoryChange: true},
+			wantAction: ChangeReplace,
+			wantCalls:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testMockAPI()
+			p := newMockProvider(m, Config{})
+
+			result, err := p.Ensure(ctx, tt.path, tt.desired, tt.opts)
+			if err != nil {
+				t.Fatalf("Ensure() error = %v", err)
+			}
+			if result.Action != tt.wantAction || result.Changed() != (tt.wantAction != ChangeNone) {
+				t.Errorf("Action = %s, want %s", result.Action, tt.wantAction)
+			}
+			if !reflect.DeepEqual(result.Fields, tt.wantFields) {
+				t.Errorf("Fields = %v, want %v", result.Fields, tt.wantFields)
+			}
+			if !reflect.DeepEqual(result.TagsAdded, tt.wantAdded) || !reflect.DeepEqual(result.TagsRemoved, tt.wantRemoved) {
+				t.Errorf("tags added %v removed %v, want %v and %v", result.TagsAdded, result.TagsRemoved, tt.wantAdded, tt.wantRemoved)
+			}
+			if (tt.opts.Category != "") != (result.Category != nil) {
+				t.Errorf("Category = %v", result.Category)
+			}
+			if calls := len(m.put) + len(m.created) + len(m.deleted); calls != tt.wantCalls {
+				t.Errorf("made %d writes, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestProvider_Ensure_Applies(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	_, err := p.Ensure(t.Context(), "Private/Database", &vault.Secret{
+		Fields: map[string]string{"password": "rotated"},
+	}, EnsureOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	if len(m.put) != 1 {
+		t.Fatalf("Put called %d times, want 1", len(m.put))
+	}
+	item := m.put[0]
+	if len(item.Fields) != 1 || findValue(item, "password") != "rotated" || item.Fields[0].FieldType != op.ItemFieldTypeConcealed {
+		t.Errorf("Put fields = %+v, want only the rotated password", item.Fields)
+	}
+	if !reflect.DeepEqual(item.Tags, []string{"env:prod"}) {
+		t.Errorf("Put tags = %v, want them unchanged", item.Tags)
+	}
+}
+
+func TestProvider_Ensure_SectionPath(t *testing.T) {
+	m := sectionedMockAPI()
+	p := newMockProvider(m, Config{})
+
+	result, err := p.Ensure(t.Context(), "Private/Database/Production", &vault.Secret{
+		Fields: map[string]string{"password": "prodpass"},
+	}, EnsureOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	want := []FieldChange{{Name: "host", Action: ChangeDelete}}
+	if !reflect.DeepEqual(result.Fields, want) {
+		t.Errorf("Fields = %v, want only the section's host removed", result.Fields)
+	}
+	if len(m.put) != 1 || len(m.put[0].Fields) != 3 {
+		t.Errorf("Put %+v, want the fields outside the section kept", m.put)
+	}
+}
@@ -0,0 +1,146 @@
+package onepassword
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// RedactedText replaces secret values in redacted output.
+const RedactedText = "<redacted>"
+
+// maskMinLength is the shortest value Mask shows any characters of.
+const maskMinLength = 12
+
+// Mask hides a secret value for display. Values of at least 12 characters
+// keep their first and last two characters ("gh****9f") so they can be told
+// apart; shorter ones become "****".
+func Mask(value string) string {
+	runes := []rune(value)
+	if len(runes) < maskMinLength {
+		return "****"
+	}
+	return string(runes[:2]) + "****" + string(runes[len(runes)-2:])
+}
+
+// SecretValue is a secret value that prints as RedactedText in every fmt
+// verb, in slog output, and when marshaled to JSON or text. Use Reveal to
+// get the value.
+type SecretValue string
+
+// Reveal returns the secret value.
+func (v SecretValue) Reveal() string {
+	return string(v)
+}
+
+// String returns RedactedText.
+func (v SecretValue) String() string {
+	return RedactedText
+}
+
+// GoString returns RedactedText, quoted.
+func (v SecretValue) GoString() string {
+	return fmt.Sprintf("%q", RedactedText)
+}
+
+// Format writes RedactedText for every verb, including %x and %q.
+func (v SecretValue) Format(f fmt.State, verb rune) {
+	if verb == 'q' || (verb == 'v' && f.Flag('#')) {
+		fmt.Fprintf(f, "%q", RedactedText)
+		return
+	}
+	fmt.Fprint(f, RedactedText)
+}
+
+// MarshalText returns RedactedText, which also covers JSON.
+func (v SecretValue) MarshalText() ([]byte, error) {
+	return []byte(RedactedText), nil
+}
+
+// LogValue returns RedactedText for slog.
+func (v SecretValue) LogValue() slog.Value {
+	return slog.StringValue(RedactedText)
+}
+
+// RedactedSecret wraps a resolved secret so printing or logging it shows
+// its reference but never its values:
+//
+//	secret, err := provider.Get(ctx, "Private/Database/password")
+//	log.Printf("loaded %v", onepassword.Redact(secret))
+//	// loaded op://Private/Database/password:<redacted>
+type RedactedSecret struct {
+	// Value is the secret's primary value.
+	Value SecretValue
+
+	// Fields are the secret's fields.
+	Fields map[string]SecretValue
+
+	// Metadata is the secret's metadata, which holds no values.
+	Metadata vault.Metadata
+}
+
+// Redact wraps secret in a RedactedSecret. It returns nil for nil.
+func Redact(secret *vault.Secret) *RedactedSecret {
+	if secret == nil {
+		return nil
+	}
+	r := &RedactedSecret{Value: SecretValue(secret.Value), Metadata: secret.Metadata}
+	if secret.Fields != nil {
+		r.Fields = make(map[string]SecretValue, len(secret.Fields))
+		for name, value := range secret.Fields {
+			r.Fields[name] = SecretValue(value)
+		}
+	}
+	return r
+}
+
+// Reveal returns the wrapped secret.
+func (s *RedactedSecret) Reveal() *vault.Secret {
+	secret := &vault.Secret{Value: string(s.Value), Metadata: s.Metadata}
+	if s.Fields != nil {
+		secret.Fields = make(map[string]string, len(s.Fields))
+		for name, value := range s.Fields {
+			secret.Fields[name] = string(value)
+		}
+	}
+	return secret
+}
+
+// String returns the secret's reference followed by ":<redacted>", and the
+// names of its fields, if any.
+func (s *RedactedSecret) String() string {
+	if s == nil {
+		return "<nil>"
+	}
+	ref := s.Metadata.Path
+	if !strings.HasPrefix(ref, "op://") {
+		ref = "op://" + ref
+	}
+	if len(s.Fields) == 0 {
+		return ref + ":" + RedactedText
+	}
+
+	names := make([]string, 0, len(s.Fields))
+	for name := range s.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return ref + ":" + RedactedText + " [" + strings.Join(names, " ") + "]"
+}
+
+// Format writes String for every verb, so %+v and %#v don't expose fields.
+func (s *RedactedSecret) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		fmt.Fprintf(f, "%q", s.String())
+		return
+	}
+	fmt.Fprint(f, s.String())
+}
+
+// LogValue returns String for slog.
+func (s *RedactedSecret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
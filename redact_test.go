@@ -0,0 +1,100 @@
+package onepassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", "****"},
+		{"hunter2", "****"},
+		{"ghp_0123456789", "gh****89"},
+		{"pässwörtchen", "pä****en"},
+	}
+	for _, tt := range tests {
+		if got := Mask(tt.value); got != tt.want {
+			t.Errorf("Mask(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSecretValue(t *testing.T) {
+	v := SecretValue("hunter2")
+	for _, format := range []string{"%v", "%s", "%+v", "%#v", "%q", "%x", "%d"} {
+		if got := fmt.Sprintf(format, v); strings.Contains(got, "hunter2") || !strings.Contains(got, RedactedText) {
+			t.Errorf("Sprintf(%q) = %q", format, got)
+		}
+	}
+
+	data, err := json.Marshal(map[string]SecretValue{"password": v})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"password":"\u003credacted\u003e"}`; string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+
+	if v.Reveal() != "hunter2" {
+		t.Errorf("Reveal() = %q", v.Reveal())
+	}
+}
+
+func TestRedact(t *testing.T) {
+	secret := &vault.Secret{
+		Value:    "hunter2",
+		Fields:   map[string]string{"username": "admin", "password": "hunter2"},
+		Metadata: vault.Metadata{Path: "Private/Database"},
+	}
+	r := Redact(secret)
+
+	want := "op://Private/Database:<redacted> [password username]"
+	for _, format := range []string{"%v", "%s", "%+v", "%#v"} {
+		if got := fmt.Sprintf(format, r); got != want {
+			t.Errorf("Sprintf(%q) = %q, want %q", format, got, want)
+		}
+	}
+
+	field := Redact(&vault.Secret{Value: "admin", Metadata: vault.Metadata{Path: "Private/Database/username"}})
+	if got := field.String(); got != "op://Private/Database/username:<redacted>" {
+		t.Errorf("String() = %q", got)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("loaded", "secret", r, "value", r.Value)
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("slog output exposes the value: %s", buf.String())
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "admin") {
+		t.Errorf("json exposes values: %s", data)
+	}
+
+	if got := r.Reveal(); got.Value != "hunter2" || got.Fields["username"] != "admin" {
+		t.Errorf("Reveal() = %+v", got)
+	}
+	if Redact(nil) != nil {
+		t.Error("Redact(nil) != nil")
+	}
+}
+
+func TestSecretBuffer_Format(t *testing.T) {
+	b := NewSecretBuffer([]byte("hunter2"))
+	defer b.Destroy()
+	if got := fmt.Sprintf("%v %s %+v", b, b, b); strings.Contains(got, "hunter2") {
+		t.Errorf("Sprintf() = %q", got)
+	}
+}
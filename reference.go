@@ -0,0 +1,122 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ValidateReference checks the syntax of a 1Password secret reference,
+// "op://vault/item[/section]/field" with an optional "?attribute=" query,
+// without resolving it. It rejects references without a field, with empty
+// components, or with an attribute other than AttributeTOTP, "otp", or
+// AttributeOTPAuthURI. The error wraps ErrInvalidPath.
+func ValidateReference(ref string) error {
+	if !strings.HasPrefix(ref, "op://") {
+		return fmt.Errorf("%w: %q is not an op:// reference", ErrInvalidPath, ref)
+	}
+
+	components, attribute := splitAttribute(ref)
+	components, _, _ = strings.Cut(strings.TrimPrefix(components, "op://"), "?")
+	parts := strings.Split(components, "/")
+	if len(parts) < 3 || len(parts) > 4 {
+		return fmt.Errorf("%w: %q must be op://vault/item/field or op://vault/item/section/field", ErrInvalidPath, ref)
+	}
+	for _, part := range parts {
+		if strings.TrimSpace(part) == "" {
+			return fmt.Errorf("%w: %q has an empty component", ErrInvalidPath, ref)
+		}
+	}
+
+	switch attribute {
+	case "", AttributeTOTP, AttributeOTPAuthURI:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q has unsupported attribute %q", ErrInvalidPath, ref, attribute)
+	}
+}
+
+// ExtractReferences returns the distinct op:// references in the text read
+// from r, such as a config file, manifest, or env file, in the order they
+// first appear. References are found as Inject finds them: bare, ending at
+// whitespace, a quote, or a brace, less any trailing punctuation, or in
+// "{{ op://... }}" placeholders, which may contain spaces. Feed them to ValidateReferences to check a
+// deployment's secrets before it runs:
+//
+//	refs, err := onepassword.ExtractReferences(manifest)
+//	err = provider.ValidateReferences(ctx, refs, true)
+func ExtractReferences(r io.Reader) ([]string, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read references: %w", err)
+	}
+
+	refs := []string{}
+	seen := make(map[string]bool)
+	for _, m := range referencePattern.FindAllSubmatchIndex(text, -1) {
+		if ref := matchedReference(text, m); !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+// ValidateReferences checks the syntax of each reference with
+// ValidateReference and, if checkExistence is set, that the field it names
+// exists, without reading any values. The errors are joined, one per
+// failing reference, each naming it. A missing vault, item, or field is
+// reported with ErrVaultNotFound, ErrItemNotFound, or ErrFieldNotFound:
+//
+//	if err := provider.ValidateReferences(ctx, refs, true); err != nil {
+//	    log.Fatalf("bad references:\n%v", err)
+//	}
+func (p *Provider) ValidateReferences(ctx context.Context, refs []string, checkExistence bool) error {
+	if p.closed.Load() {
+		return vault.NewVaultError("ValidateReferences", "", ProviderName, vault.ErrClosed)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	var errs []error
+	for _, ref := range refs {
+		if err := ValidateReference(ref); err != nil {
+			errs = append(errs, vault.NewVaultError("ValidateReferences", ref, ProviderName, err))
+			continue
+		}
+		if !checkExistence {
+			continue
+		}
+
+		parsed, err := ParsePath(ref, "")
+		if err != nil {
+			errs = append(errs, vault.NewVaultError("ValidateReferences", ref, ProviderName, err))
+			continue
+		}
+		level, err := p.probe(ctx, "ValidateReferences", parsed)
+		if err == nil && level < LevelField {
+			err = vault.NewVaultError("ValidateReferences", ref, ProviderName, missingLevel(level))
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// missingLevel returns the error for a path that exists only down to level.
+func missingLevel(level PathLevel) error {
+	switch level {
+	case LevelNone:
+		return ErrVaultNotFound
+	case LevelVault:
+		return ErrItemNotFound
+	default:
+		return ErrFieldNotFound
+	}
+}
@@ -0,0 +1,95 @@
+package onepassword
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateReference(t *testing.T) {
+	tests := []struct {
+		ref   string
+		valid bool
+	}{
+		{"op://Private/Database/password", true},
+		{"op://Private/Database/admin/password", true},
+		{"op://Private/GitHub/one-time password?attribute=otp", true},
+		{"op://Private/GitHub/one-time password?attribute=otpauth-uri", true},
+		{"op://Private/Database", false},
+		{"op://Private//password", false},
+		{"op://Private/Database/a/b/c", false},
+		{"op://Private/Database/password?attribute=type", false},
+		{"Private/Database/password", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		err := ValidateReference(tt.ref)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateReference(%q) error = %v, want valid %v", tt.ref, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("ValidateReference(%q) error = %v, want ErrInvalidPath", tt.ref, err)
+		}
+	}
+}
+
+func TestValidateReferences(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+	refs := []string{
+		"op://Private/Database/password",
+		"op://Private/Database/missing",
+		"op://Private/Missing/password",
+		"op://Nowhere/Database/password",
+		"op://Private",
+	}
+
+	// Syntax only
+	err := p.ValidateReferences(t.Context(), refs, false)
+	if err == nil || strings.Count(err.Error(), "\n") != 0 || !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("ValidateReferences() error = %v, want only the malformed reference", err)
+	}
+	if len(m.calls) != 0 {
+		t.Errorf("syntax check made calls %v", m.calls)
+	}
+
+	err = p.ValidateReferences(t.Context(), refs, true)
+	for _, want := range []error{ErrInvalidPath, ErrFieldNotFound, ErrItemNotFound, ErrVaultNotFound} {
+		if !errors.Is(err, want) {
+			t.Errorf("ValidateReferences() error = %v, want %v", err, want)
+		}
+	}
+	if n := strings.Count(err.Error(), "\n") + 1; n != 4 {
+		t.Errorf("ValidateReferences() reported %d errors, want 4:\n%v", n, err)
+	}
+	if err := p.ValidateReferences(t.Context(), refs[:1], true); err != nil {
+		t.Errorf("ValidateReferences(existing) error = %v", err)
+	}
+}
+
+func TestExtractReferences(t *testing.T) {
+	text := `DATABASE_URL=op://Prod/Database/url
+password: "{{ op://Prod/Database/admin password }}"
+token: 'op://Prod/GitHub/token'
+again: op://Prod/Database/url
+totp: op://Prod/GitHub/one-time-password?attribute=otp
+`
+	got, err := ExtractReferences(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("ExtractReferences() error = %v", err)
+	}
+	want := []string{
+		"op://Prod/Database/url",
+		"op://Prod/Database/admin password",
+		"op://Prod/GitHub/token",
+		"op://Prod/GitHub/one-time-password?attribute=otp",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractReferences() = %q, want %q", got, want)
+	}
+
+	if got, _ := ExtractReferences(strings.NewReader("no references")); got == nil || len(got) != 0 {
+		t.Errorf("ExtractReferences(none) = %#v, want empty", got)
+	}
+}
@@ -0,0 +1,56 @@
+package onepassword
+
+import "context"
+
+// RequestInfo identifies the upstream caller an operation is made for, so
+// that a service sharing one provider between callers can attribute secret
+// access to them.
+type RequestInfo struct {
+	// Requestor names the calling application or user, such as
+	// "payments-api".
+	Requestor string
+
+	// Tenant names the tenant the call is made for, if any.
+	Tenant string
+}
+
+// requestInfoKey holds the RequestInfo of a context.
+type requestInfoKey struct{}
+
+// WithRequestor returns a context attributing the operations made with it
+// to requestor. The requestor is passed to middleware in
+// OpRequest.Requestor, added to log records, and counted in
+// Stats.Requestors; write hooks can read it with RequestInfoFrom.
+//
+//	secret, err := provider.Get(onepassword.WithRequestor(ctx, "payments-api"), path)
+func WithRequestor(ctx context.Context, requestor string) context.Context {
+	info := RequestInfoFrom(ctx)
+	info.Requestor = requestor
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// WithTenant returns a context attributing the operations made with it to
+// tenant, as WithRequestor does for the requestor.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	info := RequestInfoFrom(ctx)
+	info.Tenant = tenant
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the requestor and tenant set on ctx.
+func RequestInfoFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+// logAttrs returns the log attributes identifying the caller.
+func (i RequestInfo) logAttrs() []any {
+	var attrs []any
+	if i.Requestor != "" {
+		attrs = append(attrs, "requestor", i.Requestor)
+	}
+	if i.Tenant != "" {
+		attrs = append(attrs, "tenant", i.Tenant)
+	}
+	return attrs
+}
@@ -0,0 +1,80 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestWithRequestor(t *testing.T) {
+	var logs bytes.Buffer
+	var hooked RequestInfo
+	m := testMockAPI()
+	p := newMockProvider(m, Config{
+		SkipUnchanged: true,
+		Logger:        slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		BeforeUpdate: func(ctx context.Context, _ *op.Item) error {
+			hooked = RequestInfoFrom(ctx)
+			return nil
+		},
+	})
+
+	var seen []OpRequest
+	p.Use(func(next OpFunc) OpFunc {
+		return func(ctx context.Context, req *OpRequest) (*OpResponse, error) {
+			seen = append(seen, *req)
+			return next(ctx, req)
+		}
+	})
+
+	ctx := WithTenant(WithRequestor(t.Context(), "payments-api"), "acme")
+	if _, err := p.Get(ctx, "Private/Database"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if err := p.Set(ctx, "Private/Database/password", &vault.Secret{Value: "hunter2"}); err != nil {
+		t.Fatalf("Set() unchanged error = %v", err)
+	}
+	if err := p.Set(ctx, "Private/Database/password", &vault.Secret{Value: "new"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := p.Get(t.Context(), "Private/Database"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if seen[0].Requestor != "payments-api" || seen[0].Tenant != "acme" || seen[3].Requestor != "" {
+		t.Errorf("middleware saw %+v", seen)
+	}
+	if want := (RequestInfo{Requestor: "payments-api", Tenant: "acme"}); hooked != want {
+		t.Errorf("hook saw %+v, want %+v", hooked, want)
+	}
+	if got := p.Stats().Requestors; !reflect.DeepEqual(got, map[string]uint64{"payments-api": 3}) {
+		t.Errorf("Stats().Requestors = %v", got)
+	}
+	if line := logs.String(); !strings.Contains(line, "skipping unchanged write") ||
+		!strings.Contains(line, "requestor=payments-api") || !strings.Contains(line, "tenant=acme") {
+		t.Errorf("log = %q, want the requestor and tenant", line)
+	}
+}
+
+func TestStats_RequestorsCapped(t *testing.T) {
+	p := newMockProvider(testMockAPI(), Config{})
+	for i := range MaxRequestors + 5 {
+		p.stats.countRequest(fmt.Sprintf("caller-%d", i))
+	}
+	p.stats.countRequest("caller-0")
+
+	got := p.Stats().Requestors
+	if len(got) != MaxRequestors+1 {
+		t.Errorf("tracked %d requestors, want %d and %s", len(got), MaxRequestors, OtherRequestors)
+	}
+	if got["caller-0"] != 2 || got[OtherRequestors] != 5 {
+		t.Errorf("caller-0 = %d, %s = %d; want 2 and 5", got["caller-0"], OtherRequestors, got[OtherRequestors])
+	}
+}
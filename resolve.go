@@ -0,0 +1,99 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// SchemeOP is the scheme of 1Password's secret references, under which
+// RegisterSchemes registers providers by default.
+const SchemeOP = "op"
+
+// Registrar registers a vault under a URI scheme. *omnivault.Resolver
+// implements it.
+type Registrar interface {
+	Register(scheme string, v vault.Vault)
+}
+
+// Resolve returns the value of a single secret reference, such as
+// "op://Private/API Keys/github-token", without setting up an OmniVault
+// resolver. Any path accepted by Get works.
+func Resolve(ctx context.Context, p *Provider, ref string) (string, error) {
+	return p.resolveValue(ctx, ref)
+}
+
+// MustResolve is like Resolve but panics if the reference can't be
+// resolved. It is meant for scripts and program initialization:
+//
+//	var token = onepassword.MustResolve(ctx, provider, "op://CI/GitHub/token")
+func MustResolve(ctx context.Context, p *Provider, ref string) string {
+	value, err := Resolve(ctx, p, ref)
+	if err != nil {
+		panic(fmt.Sprintf("onepassword: resolve %s: %v", ref, err))
+	}
+	return value
+}
+
+// RegisterSchemes registers p with r under each scheme, or SchemeOP if none
+// are given. Unlike registering p directly, reads through r treat the path
+// of every reference as in an op:// reference, whatever the scheme:
+// "scheme://vault/item" names an item even with a default vault set, and
+// "?attribute=" queries apply. Writes through the registered vault take
+// reference paths too. Register a provider per account under schemes of
+// your choosing to route references by scheme:
+//
+//	resolver := omnivault.NewResolver()
+//	personal.RegisterSchemes(resolver)       // op://
+//	work.RegisterSchemes(resolver, "opwork") // opwork://
+//	value, err := resolver.Resolve(ctx, "opwork://Infra/Database/password")
+func (p *Provider) RegisterSchemes(r Registrar, schemes ...string) {
+	if len(schemes) == 0 {
+		schemes = []string{SchemeOP}
+	}
+	for _, scheme := range schemes {
+		r.Register(scheme, referenceVault{p})
+	}
+}
+
+// referenceVault reads paths as the part of op:// references after the
+// scheme.
+type referenceVault struct {
+	*Provider
+}
+
+// Get retrieves the secret the reference path names.
+func (v referenceVault) Get(ctx context.Context, path string) (*vault.Secret, error) {
+	return v.Provider.Get(ctx, asReference(path))
+}
+
+// Exists checks if the secret the reference path names exists.
+func (v referenceVault) Exists(ctx context.Context, path string) (bool, error) {
+	return v.Provider.Exists(ctx, asReference(path))
+}
+
+// Set stores the secret at the reference path.
+func (v referenceVault) Set(ctx context.Context, path string, secret *vault.Secret) error {
+	return v.Provider.Set(ctx, asReference(path), secret)
+}
+
+// Delete removes the secret at the reference path.
+func (v referenceVault) Delete(ctx context.Context, path string) error {
+	return v.Provider.Delete(ctx, asReference(path))
+}
+
+// List lists the items under prefix, which may carry the op:// scheme.
+// The paths returned are "vault/item", as in references.
+func (v referenceVault) List(ctx context.Context, prefix string) ([]string, error) {
+	return v.Provider.List(ctx, strings.TrimPrefix(prefix, "op://"))
+}
+
+// asReference returns path as an op:// reference.
+func asReference(path string) string {
+	if strings.HasPrefix(path, "op://") {
+		return path
+	}
+	return "op://" + path
+}
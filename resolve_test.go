@@ -0,0 +1,99 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agentplexus/omnivault"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(testMockAPI(), Config{})
+
+	value, err := Resolve(ctx, p, "op://Private/Database/username")
+	if err != nil || value != "admin" {
+		t.Errorf("Resolve() = %q, %v; want admin", value, err)
+	}
+
+	if _, err := Resolve(ctx, p, "op://Private/Missing/password"); !errors.Is(err, vault.ErrSecretNotFound) {
+		t.Errorf("Resolve(missing) error = %v, want ErrSecretNotFound", err)
+	}
+}
+
+func TestMustResolve(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(testMockAPI(), Config{})
+
+	if got := MustResolve(ctx, p, "op://Private/Database/username"); got != "admin" {
+		t.Errorf("MustResolve() = %q, want admin", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustResolve() did not panic for a missing secret")
+		}
+	}()
+	MustResolve(ctx, p, "op://Private/Missing/password")
+}
+
+func TestRegisterSchemes(t *testing.T) {
+	ctx := context.Background()
+	saas := newMockProvider(testMockAPI(), Config{DefaultVaultName: "Work"})
+	work := testMockAPI()
+	work.resolved["op://Private/Database/password"] = "work"
+
+	resolver := omnivault.NewResolver()
+	saas.RegisterSchemes(resolver)
+	newMockProvider(work, Config{}).RegisterSchemes(resolver, "opwork", "opwork2")
+
+	if got := resolver.Schemes(); len(got) != 3 {
+		t.Errorf("Schemes() = %v, want 3", got)
+	}
+	if value, err := resolver.Resolve(ctx, "opwork://Private/Database/password"); err != nil || value != "work" {
+		t.Errorf("Resolve(opwork) = %q, %v; want work", value, err)
+	}
+
+	// Two components name an item, as in an op:// reference, despite the
+	// default vault
+	secret, err := resolver.ResolveSecret(ctx, "op://Private/Database")
+	if err != nil {
+		t.Fatalf("ResolveSecret(item) error = %v", err)
+	}
+	if secret.Fields["username"] != "admin" {
+		t.Errorf("ResolveSecret(item) fields = %v", secret.Fields)
+	}
+	if value, err := resolver.Resolve(ctx, "op://Private/Database#username"); err != nil || value != "admin" {
+		t.Errorf("Resolve(fragment) = %q, %v; want admin", value, err)
+	}
+}
+
+func TestReferenceVault_Writes(t *testing.T) {
+	ctx := context.Background()
+	m := testMockAPI()
+	v := referenceVault{newMockProvider(m, Config{DefaultVaultName: "Work"})}
+
+	// Two components name an item, not a field of the default vault
+	if err := v.Set(ctx, "Private/Database", &vault.Secret{Fields: map[string]string{"password": "newer"}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if len(m.put) != 1 || m.put[0].ID != "i1" {
+		t.Errorf("put = %v, want item i1", m.put)
+	}
+	if err := v.Delete(ctx, "Private/Database"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(m.deleted) != 1 || m.deleted[0] != "i1" {
+		t.Errorf("deleted = %v, want i1", m.deleted)
+	}
+
+	paths, err := v.List(ctx, "op://Private/")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "Private/Database" {
+		t.Errorf("List() = %v, want [Private/Database]", paths)
+	}
+}
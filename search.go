@@ -0,0 +1,142 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ItemInfo describes an item found by Search, without its fields.
+type ItemInfo struct {
+	// ID is the item ID.
+	ID string
+
+	// Title is the item title.
+	Title string
+
+	// VaultID is the ID of the item's vault.
+	VaultID string
+
+	// VaultTitle is the name of the item's vault.
+	VaultTitle string
+
+	// Category is the item category.
+	Category op.ItemCategory
+
+	// Path is the item's "vault/item" path.
+	Path string
+}
+
+// newItemInfo describes a listed item.
+func newItemInfo(v op.VaultOverview, item op.ItemOverview) ItemInfo {
+	return ItemInfo{
+		ID:         item.ID,
+		Title:      item.Title,
+		VaultID:    v.ID,
+		VaultTitle: v.Title,
+		Category:   item.Category,
+		Path:       BuildPath(v.Title, item.Title),
+	}
+}
+
+// SearchOptions narrows a Search. All set criteria must match.
+type SearchOptions struct {
+	// Vaults are the names or IDs of the vaults to search.
+	// Default: all accessible vaults
+	Vaults []string
+
+	// Category only matches items of this category.
+	Category op.ItemCategory
+
+	// Tags only matches items having every tag. A "key" tag also matches
+	// "key:value" tags. Checking tags fetches each item that matches the
+	// other criteria, as item listings don't include tags.
+	Tags []string
+
+	// Limit stops the search after this many results. Zero means no limit.
+	Limit int
+}
+
+// Search finds items whose title contains query, ignoring case, and which
+// match opts. An empty query matches every title.
+//
+// The 1Password SDK has no server-side filtering, so Search lists each
+// vault's items once and filters the listings, which carry titles and
+// categories. Items are only fetched to check tags. With a positive
+// Config.CacheTTL, repeated searches reuse the listings.
+//
+//	items, err := provider.Search(ctx, "postgres", onepassword.SearchOptions{
+//	    Vaults:   []string{"Production"},
+//	    Category: onepassword.CategoryDatabase,
+//	    Tags:     []string{"env:prod"},
+//	})
+func (p *Provider) Search(ctx context.Context, query string, opts SearchOptions) ([]ItemInfo, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("Search", query, ProviderName, vault.ErrClosed)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.List)
+	defer cancel()
+
+	needle := strings.ToLower(query)
+	var results []ItemInfo
+	var fetchErr error
+	err := p.walkItems(ctx,
+		func(v op.VaultOverview) bool {
+			return len(opts.Vaults) == 0 || slices.Contains(opts.Vaults, v.Title) || slices.Contains(opts.Vaults, v.ID)
+		},
+		func(v op.VaultOverview, item op.ItemOverview) bool {
+			if !strings.Contains(strings.ToLower(item.Title), needle) {
+				return true
+			}
+			if opts.Category != "" && item.Category != opts.Category {
+				return true
+			}
+			if len(opts.Tags) > 0 {
+				full, err := p.items.Get(ctx, v.ID, item.ID)
+				if err != nil {
+					if isNotFoundError(err) {
+						return true
+					}
+					fetchErr = err
+					return false
+				}
+				if !hasTags(full.Tags, opts.Tags, p.tagSeparator()) {
+					return true
+				}
+			}
+
+			results = append(results, newItemInfo(v, item))
+			return opts.Limit <= 0 || len(results) < opts.Limit
+		})
+	if err == nil {
+		err = fetchErr
+	}
+	if err != nil {
+		return nil, mapError("Search", query, err)
+	}
+	return results, nil
+}
+
+// hasTags reports whether tags include every wanted tag. A wanted tag
+// without a value also matches tags with that key (see splitTag).
+func hasTags(tags, wanted []string, sep string) bool {
+	for _, want := range wanted {
+		_, wantValue := splitTag(want, sep)
+		found := false
+		for _, tag := range tags {
+			key, _ := splitTag(tag, sep)
+			if tag == want || (wantValue == "" && key == want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package onepassword
+
+import (
+	"reflect"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func searchMockAPI() *mockAPI {
+	m := testMockAPI()
+	m.items["v1"] = append(m.items["v1"],
+		op.Item{ID: "i3", Title: "Postgres Staging", VaultID: "v1", Category: op.ItemCategoryDatabase, Tags: []string{"env:staging"}},
+		op.Item{ID: "i4", Title: "postgres-prod", VaultID: "v1", Category: op.ItemCategoryDatabase, Tags: []string{"env:prod", "team"}},
+		op.Item{ID: "i5", Title: "Postgres Login", VaultID: "v1", Category: op.ItemCategoryLogin},
+	)
+	m.items["v2"] = append(m.items["v2"],
+		op.Item{ID: "i6", Title: "Postgres", VaultID: "v2", Category: op.ItemCategoryDatabase, Tags: []string{"env:prod"}},
+	)
+	return m
+}
+
+func TestProvider_Search(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		opts  SearchOptions
+		want  []string
+	}{
+		{"title", "postgres", SearchOptions{}, []string{"Private/Postgres Staging", "Private/postgres-prod", "Private/Postgres Login", "Work/Postgres"}},
+		{"vault", "POSTGRES", SearchOptions{Vaults: []string{"v2"}}, []string{"Work/Postgres"}},
+		{"category", "postgres", SearchOptions{Category: op.ItemCategoryDatabase}, []string{"Private/Postgres Staging", "Private/postgres-prod", "Work/Postgres"}},
+		{"tag", "", SearchOptions{Tags: []string{"env:prod"}}, []string{"Private/Database", "Private/postgres-prod", "Work/Postgres"}},
+		{"tag key", "", SearchOptions{Tags: []string{"env", "team"}}, []string{"Private/postgres-prod"}},
+		{"limit", "postgres", SearchOptions{Limit: 2}, []string{"Private/Postgres Staging", "Private/postgres-prod"}},
+		{"none", "redis", SearchOptions{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newMockProvider(searchMockAPI(), Config{})
+			items, err := p.Search(t.Context(), tt.query, tt.opts)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			var got []string
+			for _, item := range items {
+				got = append(got, item.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvider_Search_FetchesOnlyForTags(t *testing.T) {
+	m := searchMockAPI()
+	p := newMockProvider(m, Config{})
+
+	items, err := p.Search(t.Context(), "postgres", SearchOptions{Category: op.ItemCategoryDatabase})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || items[0].VaultTitle != "Private" || items[0].ID != "i3" {
+		t.Errorf("Search() = %+v", items)
+	}
+	if len(m.calls) != 0 {
+		t.Errorf("Search() without tags fetched items: %v", m.calls)
+	}
+
+	if _, err := p.Search(t.Context(), "postgres", SearchOptions{Category: op.ItemCategoryDatabase, Tags: []string{"env"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.calls) != 3 {
+		t.Errorf("Search() with tags made %d calls, want one per candidate: %v", len(m.calls), m.calls)
+	}
+}
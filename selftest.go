@@ -0,0 +1,128 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// DefaultCanaryTitle is the title prefix of the item SelfTest creates to
+// check writes.
+const DefaultCanaryTitle = "omnivault-selftest"
+
+// SelfTestOptions controls SelfTest.
+type SelfTestOptions struct {
+	// CheckWrite also creates an item in the default vault and deletes it
+	// again, checking that the token may write there.
+	CheckWrite bool
+
+	// CanaryTitle is the title prefix of the item created by CheckWrite,
+	// followed by a random suffix. Default: DefaultCanaryTitle.
+	CanaryTitle string
+}
+
+// SelfTest checks the provider's configuration against 1Password, so a
+// misconfigured deployment fails at startup rather than on its first
+// secret: that the token authenticates, that the default vault, if one is
+// configured, exists and its items can be listed, and, with
+// opts.CheckWrite, that an item can be created in it and deleted. A
+// rejected token fails with ErrAuth and a missing default vault with
+// ErrVaultNotFound. CheckWrite requires a default vault and fails with
+// vault.ErrReadOnly for a read-only provider.
+//
+//	if err := provider.SelfTest(ctx, onepassword.SelfTestOptions{CheckWrite: true}); err != nil {
+//	    log.Fatalf("1Password is misconfigured: %v", err)
+//	}
+func (p *Provider) SelfTest(ctx context.Context, opts SelfTestOptions) error {
+	if p.closed.Load() {
+		return vault.NewVaultError("SelfTest", "", ProviderName, vault.ErrClosed)
+	}
+
+	readCtx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	// Listing vaults bypasses the cache, so the token is really used
+	ids, err := p.listVaultIDs(readCtx)
+	if err != nil {
+		return mapError("SelfTest", "", err)
+	}
+
+	name := p.getDefaultVault()
+	if name == "" {
+		if opts.CheckWrite {
+			return vault.NewVaultError("SelfTest", "", ProviderName,
+				fmt.Errorf("%w: checking writes requires a default vault", ErrInvalidPath))
+		}
+		return nil
+	}
+	vaultID, ok := ids[name]
+	if !ok {
+		return vault.NewVaultError("SelfTest", name, ProviderName, fmt.Errorf("%w: %s", ErrVaultNotFound, name))
+	}
+	if err := p.listFirstItem(readCtx, vaultID); err != nil {
+		return mapError("SelfTest", name, err)
+	}
+
+	if !opts.CheckWrite {
+		return nil
+	}
+	if err := p.refuseDryRun(ctx, "SelfTest", name); err != nil {
+		return err
+	}
+	return p.writeCanary(ctx, vaultID, name, opts.CanaryTitle)
+}
+
+// listFirstItem lists the items of a vault, reading no further than the
+// first, to check that they can be listed.
+func (p *Provider) listFirstItem(ctx context.Context, vaultID string) error {
+	itemsIter, err := p.items.ListAll(ctx, vaultID)
+	if err != nil {
+		return err
+	}
+	if _, err := itemsIter.Next(); err != nil && err != op.ErrorIteratorDone {
+		return err
+	}
+	return nil
+}
+
+// writeCanary creates an item titled with prefix and a random suffix in
+// the vault and deletes it. If it can't be deleted, the error names it so
+// it can be removed by hand.
+func (p *Provider) writeCanary(ctx context.Context, vaultID, vaultName, prefix string) error {
+	if prefix == "" {
+		prefix = DefaultCanaryTitle
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return vault.NewVaultError("SelfTest", vaultName, ProviderName, fmt.Errorf("failed to generate canary title: %w", err))
+	}
+	title := prefix + "-" + hex.EncodeToString(suffix)
+	path := BuildPath(vaultName, title)
+
+	writeCtx, cancel := withTimeout(ctx, p.config.Timeouts.Write)
+	defer cancel()
+
+	item, err := p.items.Create(writeCtx, op.ItemCreateParams{
+		VaultID:  vaultID,
+		Title:    title,
+		Category: p.config.DefaultCategory,
+		Fields: []op.ItemField{{
+			ID:        "canary",
+			Title:     "canary",
+			Value:     "created by SelfTest to check writes; safe to delete",
+			FieldType: op.ItemFieldTypeText,
+		}},
+	})
+	if err != nil {
+		return mapError("SelfTest", path, err)
+	}
+
+	if err := p.items.Delete(writeCtx, vaultID, item.ID); err != nil {
+		return mapError("SelfTest", path, fmt.Errorf("failed to delete canary item, remove it by hand: %w", err))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestSelfTest(t *testing.T) {
+	ctx := context.Background()
+
+	m := testMockAPI()
+	p := newMockProvider(m, Config{DefaultVaultName: "Private"})
+	if err := p.SelfTest(ctx, SelfTestOptions{CheckWrite: true}); err != nil {
+		t.Fatalf("SelfTest() error = %v", err)
+	}
+	if len(m.created) != 1 || len(m.deleted) != 1 {
+		t.Fatalf("created %d and deleted %d items, want 1 each", len(m.created), len(m.deleted))
+	}
+	if created := m.created[0]; created.VaultID != "v1" || !strings.HasPrefix(created.Title, DefaultCanaryTitle+"-") {
+		t.Errorf("canary = %s in %s, want %s-* in v1", created.Title, created.VaultID, DefaultCanaryTitle)
+	}
+
+	// Without CheckWrite nothing is written
+	m = testMockAPI()
+	p = newMockProvider(m, Config{DefaultVaultName: "Private"})
+	if err := p.SelfTest(ctx, SelfTestOptions{}); err != nil {
+		t.Fatalf("SelfTest() error = %v", err)
+	}
+	if len(m.created) != 0 {
+		t.Errorf("created %d items without CheckWrite", len(m.created))
+	}
+
+	tests := []struct {
+		name   string
+		config Config
+		opts   SelfTestOptions
+		err    error
+		want   error
+	}{
+		{"rejected token", Config{DefaultVaultName: "Private"}, SelfTestOptions{}, errors.New("unauthorized"), ErrAuth},
+		{"missing default vault", Config{DefaultVaultName: "Nope"}, SelfTestOptions{}, nil, ErrVaultNotFound},
+		{"write without default vault", Config{}, SelfTestOptions{CheckWrite: true}, nil, ErrInvalidPath},
+		{"read-only", Config{DefaultVaultName: "Private", ReadOnly: true}, SelfTestOptions{CheckWrite: true}, nil, vault.ErrReadOnly},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testMockAPI()
+			m.err = tt.err
+			p := newMockProvider(m, tt.config)
+			if err := p.SelfTest(ctx, tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("SelfTest() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
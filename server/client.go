@@ -0,0 +1,156 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/agentplexus/omnivault-onepassword/internal/wire"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ProviderName is the name returned by Client.Name().
+const ProviderName = "onepassword-server"
+
+// ClientConfig configures a Client.
+type ClientConfig struct {
+	// Token is sent as a bearer token when set.
+	Token string
+
+	// HTTPClient sends the requests. Configure its transport's TLS client
+	// certificates for mTLS.
+	// Default: http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// Client talks to a Server over the REST API. It implements vault.Vault, so it can be
+// registered with an omnivault resolver in place of the provider itself.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient returns a client for the server at baseURL, e.g.
+// "https://localhost:8200".
+func NewClient(baseURL string, config ClientConfig) *Client {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   config.Token,
+		http:    httpClient,
+	}
+}
+
+// Get returns the secret at path.
+func (c *Client) Get(ctx context.Context, path string) (*vault.Secret, error) {
+	var secret vault.Secret
+	if err := c.do(ctx, http.MethodGet, "/v1/secret", url.Values{"path": {path}}, nil, &secret); err != nil {
+		return nil, vault.NewVaultError("Get", path, ProviderName, err)
+	}
+	return &secret, nil
+}
+
+// Set stores secret at path.
+func (c *Client) Set(ctx context.Context, path string, secret *vault.Secret) error {
+	if err := c.do(ctx, http.MethodPut, "/v1/secret", url.Values{"path": {path}}, secret, nil); err != nil {
+		return vault.NewVaultError("Set", path, ProviderName, err)
+	}
+	return nil
+}
+
+// Delete removes the secret at path.
+func (c *Client) Delete(ctx context.Context, path string) error {
+	if err := c.do(ctx, http.MethodDelete, "/v1/secret", url.Values{"path": {path}}, nil, nil); err != nil {
+		return vault.NewVaultError("Delete", path, ProviderName, err)
+	}
+	return nil
+}
+
+// Exists reports whether the secret at path exists.
+func (c *Client) Exists(ctx context.Context, path string) (bool, error) {
+	var resp existsResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/exists", url.Values{"path": {path}}, nil, &resp); err != nil {
+		return false, vault.NewVaultError("Exists", path, ProviderName, err)
+	}
+	return resp.Exists, nil
+}
+
+// List returns the secret paths matching prefix.
+func (c *Client) List(ctx context.Context, prefix string) ([]string, error) {
+	var resp listResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/list", url.Values{"prefix": {prefix}}, nil, &resp); err != nil {
+		return nil, vault.NewVaultError("List", prefix, ProviderName, err)
+	}
+	return resp.Paths, nil
+}
+
+// Name returns the provider name.
+func (c *Client) Name() string {
+	return ProviderName
+}
+
+// Capabilities returns the client capabilities.
+func (c *Client) Capabilities() vault.Capabilities {
+	return vault.Capabilities{
+		Read:       true,
+		Write:      true,
+		Delete:     true,
+		List:       true,
+		MultiField: true,
+	}
+}
+
+// Close releases idle connections.
+func (c *Client) Close() error {
+	c.http.CloseIdleConnections()
+	return nil
+}
+
+// do sends a request and decodes a successful response into out.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path+"?"+query.Encode(), body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", vault.ErrConnectionFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return wire.ReadError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Ensure Client implements vault.Vault.
+var _ vault.Vault = (*Client)(nil)
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/agentplexus/omnivault-onepassword/internal/wire"
+	"github.com/agentplexus/omnivault-onepassword/server/vaultpb"
+	"github.com/agentplexus/omnivault/vault"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// errorDomain is the domain of the google.rpc.ErrorInfo details of gRPC
+// errors.
+const errorDomain = "omnivault-onepassword"
+
+// grpcCodes maps error codes to gRPC status codes.
+var grpcCodes = map[string]codes.Code{
+	wire.CodeNotFound:      codes.NotFound,
+	wire.CodeAccessDenied:  codes.PermissionDenied,
+	wire.CodeInvalidPath:   codes.InvalidArgument,
+	wire.CodeAlreadyExists: codes.AlreadyExists,
+	wire.CodeReadOnly:      codes.FailedPrecondition,
+	wire.CodeNotSupported:  codes.Unimplemented,
+	wire.CodeClosed:        codes.Unavailable,
+	wire.CodeBadRequest:    codes.InvalidArgument,
+	wire.CodeInternal:      codes.Internal,
+}
+
+// GRPCServer returns a gRPC server serving the API, including token
+// authentication when a token is configured and TLS when TLSConfig is set.
+// Serve it on a listener of your own, or set Config.GRPCAddr to have
+// ListenAndServe serve it.
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	if s.config.Token != "" {
+		opts = append(opts, grpc.ChainUnaryInterceptor(s.requireGRPCToken))
+	}
+	if s.config.TLSConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.config.TLSConfig)))
+	}
+	srv := grpc.NewServer(opts...)
+	vaultpb.RegisterVaultServer(srv, &grpcService{server: s})
+	return srv
+}
+
+// requireGRPCToken rejects calls without the configured bearer token.
+func (s *Server) requireGRPCToken(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	want := []byte("Bearer " + s.config.Token)
+	var got []byte
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) == 1 {
+			got = []byte(values[0])
+		}
+	}
+	if subtle.ConstantTimeCompare(got, want) != 1 {
+		return nil, grpcError(codes.Unauthenticated, wire.CodeAccessDenied, "missing or invalid bearer token")
+	}
+	return handler(ctx, req)
+}
+
+// grpcService implements the gRPC API over the server's vault.
+type grpcService struct {
+	vaultpb.UnimplementedVaultServer
+	server *Server
+}
+
+func (g *grpcService) Get(ctx context.Context, req *vaultpb.GetRequest) (*vaultpb.Secret, error) {
+	if err := requirePath(req.GetPath()); err != nil {
+		return nil, g.error(err)
+	}
+	secret, err := g.server.vault.Get(ctx, req.GetPath())
+	if err != nil {
+		return nil, g.error(err)
+	}
+	pb, err := secretToProto(secret)
+	if err != nil {
+		return nil, g.error(err)
+	}
+	return pb, nil
+}
+
+func (g *grpcService) Set(ctx context.Context, req *vaultpb.SetRequest) (*vaultpb.SetResponse, error) {
+	if g.server.config.ReadOnly {
+		return nil, g.error(vault.ErrReadOnly)
+	}
+	if err := requirePath(req.GetPath()); err != nil {
+		return nil, g.error(err)
+	}
+	if req.GetSecret() == nil {
+		return nil, grpcError(codes.InvalidArgument, wire.CodeBadRequest, "invalid secret: secret is required")
+	}
+	if err := g.server.vault.Set(ctx, req.GetPath(), secretFromProto(req.GetSecret())); err != nil {
+		return nil, g.error(err)
+	}
+	return &vaultpb.SetResponse{}, nil
+}
+
+func (g *grpcService) Delete(ctx context.Context, req *vaultpb.DeleteRequest) (*vaultpb.DeleteResponse, error) {
+	if g.server.config.ReadOnly {
+		return nil, g.error(vault.ErrReadOnly)
+	}
+	if err := requirePath(req.GetPath()); err != nil {
+		return nil, g.error(err)
+	}
+	if err := g.server.vault.Delete(ctx, req.GetPath()); err != nil {
+		return nil, g.error(err)
+	}
+	return &vaultpb.DeleteResponse{}, nil
+}
+
+func (g *grpcService) Exists(ctx context.Context, req *vaultpb.ExistsRequest) (*vaultpb.ExistsResponse, error) {
+	if err := requirePath(req.GetPath()); err != nil {
+		return nil, g.error(err)
+	}
+	exists, err := g.server.vault.Exists(ctx, req.GetPath())
+	if err != nil {
+		return nil, g.error(err)
+	}
+	return &vaultpb.ExistsResponse{Exists: exists}, nil
+}
+
+func (g *grpcService) List(ctx context.Context, req *vaultpb.ListRequest) (*vaultpb.ListResponse, error) {
+	paths, err := g.server.vault.List(ctx, req.GetPrefix())
+	if err != nil {
+		return nil, g.error(err)
+	}
+	return &vaultpb.ListResponse{Paths: paths}, nil
+}
+
+// error converts err into a gRPC status error carrying its error code.
+func (g *grpcService) error(err error) error {
+	g.server.config.Logger.Debug("request failed", "error", err)
+	code := wire.Code(err)
+	return grpcError(grpcCodes[code], code, err.Error())
+}
+
+// requirePath returns an error if the required path is missing.
+func requirePath(path string) error {
+	if path == "" {
+		return fmt.Errorf("%w: path is required", vault.ErrInvalidPath)
+	}
+	return nil
+}
+
+// grpcError returns a gRPC status error with an ErrorInfo detail whose
+// reason is the error code.
+func grpcError(c codes.Code, code, message string) error {
+	st, err := status.New(c, message).WithDetails(&errdetails.ErrorInfo{Reason: code, Domain: errorDomain})
+	if err != nil {
+		return status.Error(c, message)
+	}
+	return st.Err()
+}
+
+// readGRPCError converts a gRPC error into an error wrapping the matching
+// sentinel error.
+func readGRPCError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == errorDomain {
+			return wire.FromCode(info.GetReason(), st.Message())
+		}
+	}
+	switch st.Code() {
+	case codes.Unavailable:
+		return fmt.Errorf("%w: %s", vault.ErrConnectionFailed, st.Message())
+	case codes.Canceled:
+		return fmt.Errorf("%w: %s", context.Canceled, st.Message())
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", context.DeadlineExceeded, st.Message())
+	}
+	return errors.New(st.Message())
+}
+
+// secretToProto converts a secret to its gRPC message. Metadata.Extra is
+// sent as JSON values.
+func secretToProto(secret *vault.Secret) (*vaultpb.Secret, error) {
+	m := secret.Metadata
+	pb := &vaultpb.Secret{
+		Value:      secret.Value,
+		ValueBytes: secret.ValueBytes,
+		Fields:     secret.Fields,
+		Metadata: &vaultpb.Metadata{
+			CreatedAt:  timestampToProto(m.CreatedAt),
+			ModifiedAt: timestampToProto(m.ModifiedAt),
+			ExpiresAt:  timestampToProto(m.ExpiresAt),
+			Version:    m.Version,
+			Tags:       m.Tags,
+			Labels:     m.Labels,
+			Provider:   m.Provider,
+			Path:       m.Path,
+		},
+	}
+	if m.Extra != nil {
+		data, err := json.Marshal(m.Extra)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode metadata: %w", err)
+		}
+		pb.Metadata.Extra = &structpb.Struct{}
+		if err := pb.Metadata.Extra.UnmarshalJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to encode metadata: %w", err)
+		}
+	}
+	return pb, nil
+}
+
+// secretFromProto converts a gRPC message to a secret.
+func secretFromProto(pb *vaultpb.Secret) *vault.Secret {
+	secret := &vault.Secret{
+		Value:      pb.GetValue(),
+		ValueBytes: pb.GetValueBytes(),
+		Fields:     pb.GetFields(),
+	}
+	if m := pb.GetMetadata(); m != nil {
+		secret.Metadata = vault.Metadata{
+			CreatedAt:  timestampFromProto(m.GetCreatedAt()),
+			ModifiedAt: timestampFromProto(m.GetModifiedAt()),
+			ExpiresAt:  timestampFromProto(m.GetExpiresAt()),
+			Version:    m.GetVersion(),
+			Tags:       m.GetTags(),
+			Labels:     m.GetLabels(),
+			Provider:   m.GetProvider(),
+			Path:       m.GetPath(),
+		}
+		if m.GetExtra() != nil {
+			secret.Metadata.Extra = m.GetExtra().AsMap()
+		}
+	}
+	return secret
+}
+
+func timestampToProto(t *vault.Timestamp) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
+
+func timestampFromProto(t *timestamppb.Timestamp) *vault.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return vault.NewTimestamp(t.AsTime())
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/agentplexus/omnivault-onepassword/server/vaultpb"
+	"github.com/agentplexus/omnivault/vault"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// GRPCClientConfig configures a GRPCClient.
+type GRPCClientConfig struct {
+	// Token is sent as a bearer token when set.
+	Token string
+
+	// TLSConfig enables TLS. Set Certificates for mTLS. Without it the
+	// connection is unencrypted.
+	TLSConfig *tls.Config
+
+	// DialOptions are passed to grpc.NewClient. Optional.
+	DialOptions []grpc.DialOption
+}
+
+// GRPCClient talks to a Server over gRPC. Like Client, it implements
+// vault.Vault.
+type GRPCClient struct {
+	conn  *grpc.ClientConn
+	api   vaultpb.VaultClient
+	token string
+}
+
+// NewGRPCClient returns a client for the gRPC server at target, e.g.
+// "localhost:8201". It connects lazily, on the first call.
+func NewGRPCClient(target string, config GRPCClientConfig) (*GRPCClient, error) {
+	creds := insecure.NewCredentials()
+	if config.TLSConfig != nil {
+		creds = credentials.NewTLS(config.TLSConfig)
+	}
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, config.DialOptions...)
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, vault.NewVaultError("NewGRPCClient", "", ProviderName, err)
+	}
+	return &GRPCClient{conn: conn, api: vaultpb.NewVaultClient(conn), token: config.Token}, nil
+}
+
+// Get returns the secret at path.
+func (c *GRPCClient) Get(ctx context.Context, path string) (*vault.Secret, error) {
+	pb, err := c.api.Get(c.outgoing(ctx), &vaultpb.GetRequest{Path: path})
+	if err != nil {
+		return nil, vault.NewVaultError("Get", path, ProviderName, readGRPCError(err))
+	}
+	return secretFromProto(pb), nil
+}
+
+// Set stores secret at path.
+func (c *GRPCClient) Set(ctx context.Context, path string, secret *vault.Secret) error {
+	pb, err := secretToProto(secret)
+	if err != nil {
+		return vault.NewVaultError("Set", path, ProviderName, err)
+	}
+	if _, err := c.api.Set(c.outgoing(ctx), &vaultpb.SetRequest{Path: path, Secret: pb}); err != nil {
+		return vault.NewVaultError("Set", path, ProviderName, readGRPCError(err))
+	}
+	return nil
+}
+
+// Delete removes the secret at path.
+func (c *GRPCClient) Delete(ctx context.Context, path string) error {
+	if _, err := c.api.Delete(c.outgoing(ctx), &vaultpb.DeleteRequest{Path: path}); err != nil {
+		return vault.NewVaultError("Delete", path, ProviderName, readGRPCError(err))
+	}
+	return nil
+}
+
+// Exists reports whether the secret at path exists.
+func (c *GRPCClient) Exists(ctx context.Context, path string) (bool, error) {
+	resp, err := c.api.Exists(c.outgoing(ctx), &vaultpb.ExistsRequest{Path: path})
+	if err != nil {
+		return false, vault.NewVaultError("Exists", path, ProviderName, readGRPCError(err))
+	}
+	return resp.GetExists(), nil
+}
+
+// List returns the secret paths matching prefix.
+func (c *GRPCClient) List(ctx context.Context, prefix string) ([]string, error) {
+	resp, err := c.api.List(c.outgoing(ctx), &vaultpb.ListRequest{Prefix: prefix})
+	if err != nil {
+		return nil, vault.NewVaultError("List", prefix, ProviderName, readGRPCError(err))
+	}
+	if resp.GetPaths() == nil {
+		return []string{}, nil
+	}
+	return resp.GetPaths(), nil
+}
+
+// Name returns the provider name.
+func (c *GRPCClient) Name() string {
+	return ProviderName
+}
+
+// Capabilities returns the client capabilities.
+func (c *GRPCClient) Capabilities() vault.Capabilities {
+	return vault.Capabilities{
+		Read:       true,
+		Write:      true,
+		Delete:     true,
+		List:       true,
+		MultiField: true,
+	}
+}
+
+// Close closes the connection.
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// outgoing returns ctx carrying the bearer token, if any.
+func (c *GRPCClient) outgoing(ctx context.Context) context.Context {
+	if c.token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.token)
+}
+
+// Ensure GRPCClient implements vault.Vault.
+var _ vault.Vault = (*GRPCClient)(nil)
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/omnivault/providers/memory"
+	"github.com/agentplexus/omnivault/vault"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestGRPCClient serves v with config over an in-memory gRPC connection
+// and returns a client authenticated with token.
+func newTestGRPCClient(t *testing.T, v vault.Vault, config Config, token string) *GRPCClient {
+	t.Helper()
+	l := bufconn.Listen(1 << 20)
+	srv := New(v, config).GRPCServer()
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(srv.Stop)
+
+	client, err := NewGRPCClient("passthrough:///bufconn", GRPCClientConfig{
+		Token: token,
+		DialOptions: []grpc.DialOption{grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return l.DialContext(ctx)
+		})},
+	})
+	if err != nil {
+		t.Fatalf("NewGRPCClient() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestGRPC_CRUD(t *testing.T) {
+	mem := memory.New()
+	client := newTestGRPCClient(t, mem, Config{Token: "t0ken"}, "t0ken")
+	ctx := context.Background()
+
+	secret := &vault.Secret{
+		Value:  "s3cret",
+		Fields: map[string]string{"user": "admin"},
+		Metadata: vault.Metadata{
+			Tags:      map[string]string{"env": "prod"},
+			ExpiresAt: vault.NewTimestamp(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)),
+			Extra:     map[string]any{"category": "LOGIN", "urls": []any{"https://example.com"}},
+		},
+	}
+	if err := client.Set(ctx, "Work/DB", secret); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	stored, err := mem.Get(ctx, "Work/DB")
+	if err != nil {
+		t.Fatalf("memory Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(stored.Metadata.Extra, secret.Metadata.Extra) || !stored.Metadata.ExpiresAt.Equal(secret.Metadata.ExpiresAt.Time) {
+		t.Errorf("stored metadata = %+v, want %+v", stored.Metadata, secret.Metadata)
+	}
+
+	got, err := client.Get(ctx, "Work/DB")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Value != "s3cret" || !reflect.DeepEqual(got.Fields, secret.Fields) || got.Metadata.Tags["env"] != "prod" {
+		t.Errorf("Get() = %+v", got)
+	}
+
+	exists, err := client.Exists(ctx, "Work/DB")
+	if err != nil || !exists {
+		t.Errorf("Exists() = %v, %v; want true", exists, err)
+	}
+
+	paths, err := client.List(ctx, "Work/")
+	if err != nil || !reflect.DeepEqual(paths, []string{"Work/DB"}) {
+		t.Errorf("List() = %v, %v", paths, err)
+	}
+
+	if err := client.Delete(ctx, "Work/DB"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := client.Get(ctx, "Work/DB"); !errors.Is(err, vault.ErrSecretNotFound) {
+		t.Errorf("Get() after Delete error = %v, want ErrSecretNotFound", err)
+	}
+	if _, err := client.Get(ctx, ""); !errors.Is(err, vault.ErrInvalidPath) {
+		t.Errorf("Get() without a path error = %v, want ErrInvalidPath", err)
+	}
+}
+
+func TestGRPC_Token(t *testing.T) {
+	mem := memory.NewWithSecrets(map[string]string{"V/I": "v"})
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"valid token", "t0ken", true},
+		{"wrong token", "guess", false},
+		{"no token", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestGRPCClient(t, mem, Config{Token: "t0ken"}, tt.token)
+			_, err := client.Get(context.Background(), "V/I")
+			if tt.ok && err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+			if !tt.ok && !errors.Is(err, vault.ErrAccessDenied) {
+				t.Errorf("Get() error = %v, want ErrAccessDenied", err)
+			}
+		})
+	}
+}
+
+func TestGRPC_ReadOnly(t *testing.T) {
+	mem := memory.NewWithSecrets(map[string]string{"V/I": "v"})
+	client := newTestGRPCClient(t, mem, Config{ReadOnly: true}, "")
+	ctx := context.Background()
+
+	if err := client.Set(ctx, "V/I", &vault.Secret{Value: "x"}); !errors.Is(err, vault.ErrReadOnly) {
+		t.Errorf("Set() error = %v, want ErrReadOnly", err)
+	}
+	if err := client.Delete(ctx, "V/I"); !errors.Is(err, vault.ErrReadOnly) {
+		t.Errorf("Delete() error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestGRPC_ListenAndServe(t *testing.T) {
+	if err := New(memory.New(), Config{Token: "t0ken", DisableREST: true}).ListenAndServe(context.Background()); !errors.Is(err, ErrNoAPI) {
+		t.Errorf("ListenAndServe() without any API error = %v, want ErrNoAPI", err)
+	}
+
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- New(memory.NewWithSecrets(map[string]string{"V/I": "v"}), Config{
+			Token:       "t0ken",
+			GRPCAddr:    addr,
+			DisableREST: true,
+		}).ListenAndServe(ctx)
+	}()
+
+	client, err := NewGRPCClient(addr, GRPCClientConfig{Token: "t0ken"})
+	if err != nil {
+		t.Fatalf("NewGRPCClient() error = %v", err)
+	}
+	defer client.Close()
+	for range 50 {
+		if _, err = client.Get(ctx, "V/I"); !errors.Is(err, vault.ErrConnectionFailed) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("ListenAndServe() error = %v", err)
+	}
+}
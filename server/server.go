@@ -0,0 +1,340 @@
+// Package server exposes a vault over gRPC and HTTP so services that can't
+// link the 1Password SDK can use it as a sidecar.
+//
+// The gRPC API is the Vault service defined in vaultpb/vault.proto, served
+// on Config.GRPCAddr. Generate clients for other languages from that file;
+// Go programs can use GRPCClient. The REST API, served on Config.Addr
+// unless Config.DisableREST is set, is:
+//
+//	GET    /v1/secret?path=PATH   the secret as JSON (vault.Secret)
+//	PUT    /v1/secret?path=PATH   store the JSON secret in the request body
+//	DELETE /v1/secret?path=PATH   delete the secret
+//	GET    /v1/exists?path=PATH   {"exists": true|false}
+//	GET    /v1/list?prefix=PREFIX {"paths": [...]}
+//
+// Errors are returned as {"error": "...", "code": "not_found"} with a
+// matching status code; gRPC errors carry the same code as the reason of a
+// google.rpc.ErrorInfo detail. Requests to either API are authenticated
+// with a bearer token, TLS client certificates (mTLS), or both.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/agentplexus/omnivault-onepassword/internal/wire"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// EnvToken is the environment variable the CLI reads the bearer token from.
+const EnvToken = "OMNIVAULT_OP_SERVER_TOKEN" //nolint:gosec // G101: this is an env var name, not a credential
+
+// maxBodySize limits the size of a PUT request body.
+const maxBodySize = 1 << 20
+
+// ErrNoAuth is returned by ListenAndServe when neither a token nor TLS
+// client certificates, required and verified, are configured.
+var ErrNoAuth = errors.New("server requires a token or TLS client certificate verification")
+
+// ErrNoAPI is returned by ListenAndServe when DisableREST is set without
+// GRPCAddr.
+var ErrNoAPI = errors.New("server has no API to serve: DisableREST is set without GRPCAddr")
+
+// Config configures a Server.
+type Config struct {
+	// Addr is the TCP address to serve the REST API on.
+	// Default: "127.0.0.1:8200"
+	Addr string
+
+	// Token, when set, must be sent as "Authorization: Bearer <token>".
+	Token string
+
+	// GRPCAddr, when set, is the TCP address to serve the gRPC API on.
+	GRPCAddr string
+
+	// DisableREST serves only the gRPC API; GRPCAddr must be set.
+	DisableREST bool
+
+	// TLSConfig enables TLS for both APIs. Set ClientAuth to
+	// tls.RequireAndVerifyClientCert and ClientCAs for mTLS.
+	TLSConfig *tls.Config
+
+	// ReadOnly rejects PUT and DELETE requests.
+	ReadOnly bool
+
+	// Logger for request logging. Optional.
+	Logger *slog.Logger
+}
+
+// withDefaults returns a copy of the config with default values applied.
+func (c Config) withDefaults() Config {
+	if c.Addr == "" {
+		c.Addr = "127.0.0.1:8200"
+	}
+	if c.Logger == nil {
+		c.Logger = slog.New(slog.DiscardHandler)
+	}
+	return c
+}
+
+// Server serves a vault over HTTP.
+type Server struct {
+	vault  vault.Vault
+	config Config
+}
+
+// listResponse is the body of a successful list request.
+type listResponse struct {
+	Paths []string `json:"paths"`
+}
+
+// existsResponse is the body of a successful exists request.
+type existsResponse struct {
+	Exists bool `json:"exists"`
+}
+
+// New returns a server for v.
+func New(v vault.Vault, config Config) *Server {
+	return &Server{vault: v, config: config.withDefaults()}
+}
+
+// ListenAndServe serves requests until ctx is cancelled: the REST API on
+// Addr, unless DisableREST is set, and the gRPC API on GRPCAddr, if set. It
+// refuses to start without authentication.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	tlsConfig := s.config.TLSConfig
+	// Only RequireAndVerifyClientCert authenticates every client; the other
+	// modes let clients without a certificate through
+	if s.config.Token == "" && (tlsConfig == nil || tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert) {
+		return ErrNoAuth
+	}
+	if s.config.DisableREST && s.config.GRPCAddr == "" {
+		return ErrNoAPI
+	}
+
+	var serves []func(context.Context) error
+	if !s.config.DisableREST {
+		l, err := net.Listen("tcp", s.config.Addr)
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+		serves = append(serves, func(ctx context.Context) error { return s.serveREST(ctx, l) })
+	}
+	if s.config.GRPCAddr != "" {
+		l, err := net.Listen("tcp", s.config.GRPCAddr)
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+		serves = append(serves, func(ctx context.Context) error { return s.serveGRPC(ctx, l) })
+	}
+
+	// The first API to stop, by error or cancellation, stops the other
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errc := make(chan error, len(serves))
+	for _, serve := range serves {
+		go func() { errc <- serve(ctx) }()
+	}
+	var err error
+	for range serves {
+		if serveErr := <-errc; serveErr != nil && err == nil {
+			err = serveErr
+		}
+		cancel()
+	}
+	return err
+}
+
+// serveREST serves the REST API on l until ctx is cancelled.
+func (s *Server) serveREST(ctx context.Context, l net.Listener) error {
+	if s.config.TLSConfig != nil {
+		l = tls.NewListener(l, s.config.TLSConfig)
+	}
+
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	s.config.Logger.Info("server listening", "api", "rest", "addr", l.Addr().String(), "tls", s.config.TLSConfig != nil)
+	err := srv.Serve(l)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// serveGRPC serves the gRPC API on l until ctx is cancelled.
+func (s *Server) serveGRPC(ctx context.Context, l net.Listener) error {
+	srv := s.GRPCServer()
+	go func() {
+		<-ctx.Done()
+		stopped := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			srv.Stop()
+		}
+	}()
+
+	s.config.Logger.Info("server listening", "api", "grpc", "addr", l.Addr().String(), "tls", s.config.TLSConfig != nil)
+	return srv.Serve(l)
+}
+
+// Handler returns the HTTP handler serving the REST API, including token
+// authentication when a token is configured.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/secret", s.handleGet)
+	mux.HandleFunc("PUT /v1/secret", s.handleSet)
+	mux.HandleFunc("DELETE /v1/secret", s.handleDelete)
+	mux.HandleFunc("GET /v1/exists", s.handleExists)
+	mux.HandleFunc("GET /v1/list", s.handleList)
+
+	if s.config.Token == "" {
+		return mux
+	}
+	return s.requireToken(mux)
+}
+
+// requireToken rejects requests without the configured bearer token.
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	want := []byte("Bearer " + s.config.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			s.writeJSON(w, http.StatusUnauthorized, wire.ErrorResponse{
+				Error: "missing or invalid bearer token",
+				Code:  wire.CodeAccessDenied,
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	path, ok := s.pathParam(w, r)
+	if !ok {
+		return
+	}
+	secret, err := s.vault.Get(r.Context(), path)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, secret)
+}
+
+func (s *Server) handleSet(w http.ResponseWriter, r *http.Request) {
+	if s.config.ReadOnly {
+		s.writeError(w, vault.ErrReadOnly)
+		return
+	}
+	path, ok := s.pathParam(w, r)
+	if !ok {
+		return
+	}
+
+	var secret vault.Secret
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&secret); err != nil {
+		s.writeJSON(w, http.StatusBadRequest, wire.ErrorResponse{
+			Error: "invalid secret: " + err.Error(),
+			Code:  wire.CodeBadRequest,
+		})
+		return
+	}
+	if err := s.vault.Set(r.Context(), path, &secret); err != nil {
+		s.writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if s.config.ReadOnly {
+		s.writeError(w, vault.ErrReadOnly)
+		return
+	}
+	path, ok := s.pathParam(w, r)
+	if !ok {
+		return
+	}
+	if err := s.vault.Delete(r.Context(), path); err != nil {
+		s.writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleExists(w http.ResponseWriter, r *http.Request) {
+	path, ok := s.pathParam(w, r)
+	if !ok {
+		return
+	}
+	exists, err := s.vault.Exists(r.Context(), path)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, existsResponse{Exists: exists})
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	paths, err := s.vault.List(r.Context(), r.URL.Query().Get("prefix"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	if paths == nil {
+		paths = []string{}
+	}
+	s.writeJSON(w, http.StatusOK, listResponse{Paths: paths})
+}
+
+// pathParam returns the required "path" query parameter, writing an error
+// response if it is missing.
+func (s *Server) pathParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		s.writeError(w, fmt.Errorf("%w: path is required", vault.ErrInvalidPath))
+		return "", false
+	}
+	return path, true
+}
+
+// writeJSON writes v as a JSON response.
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	if err := wire.WriteJSON(w, status, v); err != nil {
+		s.config.Logger.Debug("failed to write response", "error", err)
+	}
+}
+
+// writeError writes err as an error response.
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	s.config.Logger.Debug("request failed", "error", err)
+	if err := wire.WriteError(w, err); err != nil {
+		s.config.Logger.Debug("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,245 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/omnivault/providers/memory"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// newTestClient serves v with config over httptest and returns a client
+// authenticated with token.
+func newTestClient(t *testing.T, v vault.Vault, config Config, token string) *Client {
+	t.Helper()
+	ts := httptest.NewServer(New(v, config).Handler())
+	t.Cleanup(ts.Close)
+	return NewClient(ts.URL, ClientConfig{Token: token})
+}
+
+func TestServer_CRUD(t *testing.T) {
+	mem := memory.New()
+	client := newTestClient(t, mem, Config{Token: "t0ken"}, "t0ken")
+	ctx := context.Background()
+
+	secret := &vault.Secret{Value: "s3cret", Fields: map[string]string{"user": "admin"}}
+	if err := client.Set(ctx, "Work/DB", secret); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	got, err := client.Get(ctx, "Work/DB")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Value != "s3cret" || !reflect.DeepEqual(got.Fields, secret.Fields) {
+		t.Errorf("Get() = %+v", got)
+	}
+
+	exists, err := client.Exists(ctx, "Work/DB")
+	if err != nil || !exists {
+		t.Errorf("Exists() = %v, %v; want true", exists, err)
+	}
+
+	paths, err := client.List(ctx, "Work/")
+	if err != nil || !reflect.DeepEqual(paths, []string{"Work/DB"}) {
+		t.Errorf("List() = %v, %v", paths, err)
+	}
+
+	if err := client.Delete(ctx, "Work/DB"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := client.Get(ctx, "Work/DB"); !errors.Is(err, vault.ErrSecretNotFound) {
+		t.Errorf("Get() after Delete error = %v, want ErrSecretNotFound", err)
+	}
+}
+
+func TestServer_Token(t *testing.T) {
+	mem := memory.NewWithSecrets(map[string]string{"V/I": "v"})
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"valid token", "t0ken", true},
+		{"wrong token", "guess", false},
+		{"no token", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, mem, Config{Token: "t0ken"}, tt.token)
+			_, err := client.Get(context.Background(), "V/I")
+			if tt.ok && err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+			if !tt.ok && !errors.Is(err, vault.ErrAccessDenied) {
+				t.Errorf("Get() error = %v, want ErrAccessDenied", err)
+			}
+		})
+	}
+}
+
+func TestServer_ReadOnly(t *testing.T) {
+	mem := memory.NewWithSecrets(map[string]string{"V/I": "v"})
+	client := newTestClient(t, mem, Config{ReadOnly: true}, "")
+	ctx := context.Background()
+
+	if err := client.Set(ctx, "V/I", &vault.Secret{Value: "x"}); !errors.Is(err, vault.ErrReadOnly) {
+		t.Errorf("Set() error = %v, want ErrReadOnly", err)
+	}
+	if err := client.Delete(ctx, "V/I"); !errors.Is(err, vault.ErrReadOnly) {
+		t.Errorf("Delete() error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestServer_ListenAndServeRequiresAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		clientAuth tls.ClientAuthType
+	}{
+		{"no TLS", -1},
+		{"no client certificates", tls.NoClientCert},
+		{"optional client certificates", tls.VerifyClientCertIfGiven},
+		{"unverified client certificates", tls.RequireAnyClientCert},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{Addr: "127.0.0.1:0"}
+			if tt.clientAuth >= 0 {
+				config.TLSConfig = &tls.Config{ClientAuth: tt.clientAuth}
+			}
+			err := New(memory.New(), config).ListenAndServe(context.Background())
+			if !errors.Is(err, ErrNoAuth) {
+				t.Errorf("ListenAndServe() error = %v, want ErrNoAuth", err)
+			}
+		})
+	}
+}
+
+func TestServer_MTLS(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	serverCert := newTestCert(t, ca, caKey, x509.ExtKeyUsageServerAuth)
+	clientCert := newTestCert(t, ca, caKey, x509.ExtKeyUsageClientAuth)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- New(memory.NewWithSecrets(map[string]string{"V/I": "v"}), Config{
+			Addr: addr,
+			TLSConfig: &tls.Config{
+				Certificates: []tls.Certificate{serverCert},
+				ClientCAs:    pool,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				MinVersion:   tls.VersionTLS12,
+			},
+		}).ListenAndServe(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("ListenAndServe() error = %v", err)
+		}
+	})
+
+	newClient := func(certs ...tls.Certificate) *Client {
+		transport := &http.Transport{TLSClientConfig: &tls.Config{
+			Certificates: certs,
+			RootCAs:      pool,
+			MinVersion:   tls.VersionTLS12,
+		}}
+		return NewClient("https://"+addr, ClientConfig{HTTPClient: &http.Client{Transport: transport}})
+	}
+
+	var err error
+	for range 50 {
+		if _, err = newClient(clientCert).Get(ctx, "V/I"); !errors.Is(err, vault.ErrConnectionFailed) || !strings.Contains(err.Error(), "connection refused") {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("Get() with a client certificate error = %v", err)
+	}
+	if _, err := newClient().Get(ctx, "V/I"); err == nil {
+		t.Error("Get() without a client certificate succeeded")
+	}
+}
+
+// freeAddr returns a loopback address that was free when called.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// newTestCA returns a self-signed CA certificate and its key.
+func newTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ca, key
+}
+
+// newTestCert returns a certificate for 127.0.0.1 signed by ca.
+func newTestCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
@@ -0,0 +1,3 @@
+// Package vaultpb holds the generated code of the sidecar server's gRPC API,
+// defined in vault.proto.
+package vaultpb
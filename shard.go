@@ -0,0 +1,236 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+// DefaultShardCooldown is how long a failing shard is skipped.
+const DefaultShardCooldown = 30 * time.Second
+
+// ShardStrategy selects how reads are spread across service accounts.
+type ShardStrategy int
+
+const (
+	// ShardRoundRobin sends reads to each healthy shard in turn. This is
+	// the default.
+	ShardRoundRobin ShardStrategy = iota
+
+	// ShardLeastLoaded sends reads to the healthy shard with the fewest
+	// calls in flight.
+	ShardLeastLoaded
+)
+
+// ShardStats describes one service account of a sharded provider.
+type ShardStats struct {
+	// Calls is the number of calls made through the shard.
+	Calls uint64
+
+	// Failures is the number of calls that failed with a rate limit,
+	// authentication, or connection error.
+	Failures uint64
+
+	// InFlight is the number of calls currently running.
+	InFlight int64
+
+	// Healthy reports whether the shard is receiving reads. A shard is
+	// skipped for Config.ShardCooldown after a failure.
+	Healthy bool
+}
+
+// shard is one service account's APIs and health.
+type shard struct {
+	secrets secretsAPI
+	items   itemsAPI
+	vaults  vaultsAPI
+
+	calls     atomic.Uint64
+	failures  atomic.Uint64
+	inFlight  atomic.Int64
+	coolUntil atomic.Int64 // unix nanoseconds; zero when healthy
+}
+
+// shardSet spreads reads across several service accounts. Writes always
+// go to the first shard, so a read following a write through the same
+// shard set sees the same account's view.
+type shardSet struct {
+	shards   []*shard
+	strategy ShardStrategy
+	cooldown time.Duration
+	now      func() time.Time
+	next     atomic.Uint64
+}
+
+func newShardSet(shards []*shard, strategy ShardStrategy, cooldown time.Duration) *shardSet {
+	return &shardSet{shards: shards, strategy: strategy, cooldown: cooldown, now: time.Now}
+}
+
+// healthy reports whether s isn't cooling down after a failure.
+func (set *shardSet) healthy(s *shard) bool {
+	return set.now().UnixNano() >= s.coolUntil.Load()
+}
+
+// pick returns the shard for the next read, skipping those in exclude.
+// Shards cooling down are only used when no healthy shard is left.
+func (set *shardSet) pick(exclude map[*shard]bool) *shard {
+	n := len(set.shards)
+	start := int(set.next.Add(1)-1) % n
+
+	var best, fallback *shard
+	for i := range n {
+		s := set.shards[(start+i)%n]
+		if exclude[s] {
+			continue
+		}
+		if !set.healthy(s) {
+			if fallback == nil {
+				fallback = s
+			}
+			continue
+		}
+		if set.strategy == ShardRoundRobin {
+			return s
+		}
+		if best == nil || s.inFlight.Load() < best.inFlight.Load() {
+			best = s
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return fallback
+}
+
+// read calls fn on a shard, moving on to another shard while fn fails with
+// an error that marks its shard unhealthy.
+func (set *shardSet) read(ctx context.Context, fn func(*shard) error) error {
+	tried := make(map[*shard]bool)
+	for {
+		s := set.pick(tried)
+		err := set.call(s, fn)
+		if err == nil || !shardFailure(err) || ctx.Err() != nil {
+			return err
+		}
+		tried[s] = true
+		if len(tried) == len(set.shards) {
+			return err
+		}
+	}
+}
+
+// write calls fn on the first shard.
+func (set *shardSet) write(fn func(*shard) error) error {
+	return set.call(set.shards[0], fn)
+}
+
+// call calls fn on s, tracking load and health.
+func (set *shardSet) call(s *shard, fn func(*shard) error) error {
+	s.calls.Add(1)
+	s.inFlight.Add(1)
+	err := fn(s)
+	s.inFlight.Add(-1)
+
+	if shardFailure(err) {
+		s.failures.Add(1)
+		s.coolUntil.Store(set.now().Add(set.cooldown).UnixNano())
+	}
+	return err
+}
+
+// stats returns a snapshot of each shard's counters.
+func (set *shardSet) stats() []ShardStats {
+	stats := make([]ShardStats, len(set.shards))
+	for i, s := range set.shards {
+		stats[i] = ShardStats{
+			Calls:    s.calls.Load(),
+			Failures: s.failures.Load(),
+			InFlight: s.inFlight.Load(),
+			Healthy:  set.healthy(s),
+		}
+	}
+	return stats
+}
+
+// shardFailure reports whether err says something about the shard rather
+// than the request: rate limiting, a rejected token, or a failure to reach
+// 1Password. Answers such as not found and the caller's cancellation don't
+// count.
+func shardFailure(err error) bool {
+	if err == nil || isContextError(err) || isNotFoundError(err) {
+		return false
+	}
+	return !errors.Is(classifyError(err), ErrAmbiguous)
+}
+
+// shardedSecrets implements secretsAPI on a shard set.
+type shardedSecrets struct{ set *shardSet }
+
+func (s shardedSecrets) Resolve(ctx context.Context, secretReference string) (string, error) {
+	var value string
+	err := s.set.read(ctx, func(sh *shard) (err error) {
+		value, err = sh.secrets.Resolve(ctx, secretReference)
+		return err
+	})
+	return value, err
+}
+
+// shardedItems implements itemsAPI on a shard set.
+type shardedItems struct{ set *shardSet }
+
+func (s shardedItems) Create(ctx context.Context, params op.ItemCreateParams) (op.Item, error) {
+	var item op.Item
+	err := s.set.write(func(sh *shard) (err error) {
+		item, err = sh.items.Create(ctx, params)
+		return err
+	})
+	return item, err
+}
+
+func (s shardedItems) Get(ctx context.Context, vaultID, itemID string) (op.Item, error) {
+	var item op.Item
+	err := s.set.read(ctx, func(sh *shard) (err error) {
+		item, err = sh.items.Get(ctx, vaultID, itemID)
+		return err
+	})
+	return item, err
+}
+
+func (s shardedItems) Put(ctx context.Context, item op.Item) (op.Item, error) {
+	var updated op.Item
+	err := s.set.write(func(sh *shard) (err error) {
+		updated, err = sh.items.Put(ctx, item)
+		return err
+	})
+	return updated, err
+}
+
+func (s shardedItems) Delete(ctx context.Context, vaultID, itemID string) error {
+	return s.set.write(func(sh *shard) error {
+		return sh.items.Delete(ctx, vaultID, itemID)
+	})
+}
+
+func (s shardedItems) ListAll(ctx context.Context, vaultID string) (*op.Iterator[op.ItemOverview], error) {
+	var it *op.Iterator[op.ItemOverview]
+	err := s.set.read(ctx, func(sh *shard) (err error) {
+		it, err = sh.items.ListAll(ctx, vaultID)
+		return err
+	})
+	return it, err
+}
+
+// shardedVaults implements vaultsAPI on a shard set.
+type shardedVaults struct{ set *shardSet }
+
+func (s shardedVaults) ListAll(ctx context.Context) (*op.Iterator[op.VaultOverview], error) {
+	var it *op.Iterator[op.VaultOverview]
+	err := s.set.read(ctx, func(sh *shard) (err error) {
+		it, err = sh.vaults.ListAll(ctx)
+		return err
+	})
+	return it, err
+}
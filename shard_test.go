@@ -0,0 +1,129 @@
+package onepassword
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func newShardedMockProvider(config Config, apis ...*mockAPI) (*Provider, *shardSet) {
+	var shards []*shard
+	for _, m := range apis {
+		shards = append(shards, &shard{secrets: m, items: m, vaults: mockVaults{m}})
+	}
+	config = config.withDefaults()
+	set := newShardSet(shards, config.ShardStrategy, config.ShardCooldown)
+	p := newWithAPIs(shardedSecrets{set}, shardedItems{set}, shardedVaults{set}, config)
+	p.shards = set
+	return p, set
+}
+
+func resolveCalls(m *mockAPI) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, call := range m.calls {
+		if len(call) > 8 && call[:8] == "Resolve " {
+			n++
+		}
+	}
+	return n
+}
+
+func TestShards_RoundRobin(t *testing.T) {
+	a, b := testMockAPI(), testMockAPI()
+	p, _ := newShardedMockProvider(Config{}, a, b)
+
+	for range 4 {
+		if _, err := p.Get(t.Context(), "Private/Database/username"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if resolveCalls(a) != 2 || resolveCalls(b) != 2 {
+		t.Errorf("Resolve calls = %d, %d; want 2, 2", resolveCalls(a), resolveCalls(b))
+	}
+}
+
+func TestShards_Failover(t *testing.T) {
+	a, b := testMockAPI(), testMockAPI()
+	a.err = errors.New("too many requests")
+	p, set := newShardedMockProvider(Config{ShardCooldown: time.Minute}, a, b)
+
+	now := time.Now()
+	set.now = func() time.Time { return now }
+
+	for range 3 {
+		secret, err := p.Get(t.Context(), "Private/Database/username")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if secret.Value != "admin" {
+			t.Errorf("Value = %q", secret.Value)
+		}
+	}
+	if got := resolveCalls(a); got != 1 {
+		t.Errorf("rate-limited shard got %d calls, want 1 before cooling down", got)
+	}
+
+	stats := p.Stats().Shards
+	if len(stats) != 2 || stats[0].Healthy || stats[0].Failures != 1 || !stats[1].Healthy {
+		t.Errorf("Stats().Shards = %+v", stats)
+	}
+
+	// Back in rotation after the cooldown
+	a.err = nil
+	now = now.Add(2 * time.Minute)
+	for range 2 {
+		if _, err := p.Get(t.Context(), "Private/Database/username"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := resolveCalls(a); got != 2 {
+		t.Errorf("recovered shard got %d calls in total, want 2", got)
+	}
+}
+
+func TestShards_AnswersDontFailOver(t *testing.T) {
+	a, b := testMockAPI(), testMockAPI()
+	p, _ := newShardedMockProvider(Config{}, a, b)
+
+	if _, err := p.Get(t.Context(), "Private/Database/missing"); err == nil {
+		t.Fatal("Get() of a missing field succeeded")
+	}
+	if total := resolveCalls(a) + resolveCalls(b); total != 1 {
+		t.Errorf("Resolve calls = %d, want 1", total)
+	}
+	for i, s := range p.Stats().Shards {
+		if !s.Healthy {
+			t.Errorf("shard %d unhealthy after a not found answer", i)
+		}
+	}
+}
+
+func TestShards_WritesUsePrimary(t *testing.T) {
+	a, b := testMockAPI(), testMockAPI()
+	p, _ := newShardedMockProvider(Config{}, a, b)
+
+	for range 3 {
+		if err := p.Set(t.Context(), "Private/Database/password", &vault.Secret{Value: "x"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(a.put) != 3 || len(b.put) != 0 {
+		t.Errorf("puts = %d, %d; want 3, 0", len(a.put), len(b.put))
+	}
+}
+
+func TestShards_LeastLoaded(t *testing.T) {
+	a, b := testMockAPI(), testMockAPI()
+	_, set := newShardedMockProvider(Config{ShardStrategy: ShardLeastLoaded}, a, b)
+
+	set.shards[0].inFlight.Add(2)
+	for range 3 {
+		if got := set.pick(nil); got != set.shards[1] {
+			t.Fatal("pick() chose the busier shard")
+		}
+	}
+}
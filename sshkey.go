@@ -0,0 +1,239 @@
+package onepassword
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+	"golang.org/x/crypto/ssh"
+)
+
+// Titles of the fields of SSH Key items.
+const (
+	// SSHPrivateKeyField is the title of the private key field.
+	SSHPrivateKeyField = "private key"
+
+	// SSHPublicKeyField is the title of the public key field, which holds
+	// the key in authorized_keys format.
+	SSHPublicKeyField = "public key"
+
+	// SSHFingerprintField is the title of the fingerprint field.
+	SSHFingerprintField = "fingerprint"
+)
+
+// SSHKeyType is the type of key pair CreateSSHKey generates.
+type SSHKeyType string
+
+// SSH key types.
+const (
+	SSHKeyEd25519 SSHKeyType = "ed25519"
+	SSHKeyRSA     SSHKeyType = "rsa"
+)
+
+// DefaultRSABits is the size of RSA keys CreateSSHKey generates unless
+// SSHKeyOptions.Bits is set.
+const DefaultRSABits = 4096
+
+// SSHKeyOptions controls CreateSSHKey.
+type SSHKeyOptions struct {
+	// PrivateKey is a PEM-encoded private key to store. If empty, a new key
+	// pair is generated.
+	PrivateKey string
+
+	// Type is the type of key pair to generate.
+	// Default: SSHKeyEd25519
+	Type SSHKeyType
+
+	// Bits is the size of generated RSA keys, at least 2048.
+	// Default: DefaultRSABits
+	Bits int
+}
+
+// SSHKey is an SSH key pair read from a 1Password SSH Key item.
+type SSHKey struct {
+	// PrivateKey is the parsed private key (*ecdsa.PrivateKey,
+	// ed25519.PrivateKey, or *rsa.PrivateKey).
+	PrivateKey crypto.PrivateKey
+
+	// Signer signs with the private key, for use with ssh.PublicKeys.
+	Signer ssh.Signer
+
+	// PublicKey is the public half of the key pair.
+	PublicKey ssh.PublicKey
+
+	// AuthorizedKey is the public key in authorized_keys format,
+	// e.g. "ssh-ed25519 AAAA...".
+	AuthorizedKey string
+
+	// Fingerprint is the SHA256 fingerprint of the public key,
+	// e.g. "SHA256:...".
+	Fingerprint string
+
+	// PrivateKeyOpenSSH is the private key as an OpenSSH PEM block.
+	PrivateKeyOpenSSH string
+}
+
+// GetSSHKey reads the SSH key stored in the item at path. The path may name
+// the item ("vault/item"), in which case the "private key" field is used, or
+// a specific field holding a PEM-encoded private key.
+//
+//	key, err := provider.GetSSHKey(ctx, "Private/deploy key")
+//	config := &ssh.ClientConfig{Auth: []ssh.AuthMethod{ssh.PublicKeys(key.Signer)}}
+func (p *Provider) GetSSHKey(ctx context.Context, path string) (*SSHKey, error) {
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return nil, vault.NewVaultError("GetSSHKey", path, ProviderName, err)
+	}
+	if parsed.Field == "" {
+		parsed.Field = SSHPrivateKeyField
+	}
+
+	secret, err := p.Get(ctx, "op://"+parsed.String())
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parseSSHKey(secret.Value)
+	if err != nil {
+		return nil, vault.NewVaultError("GetSSHKey", path, ProviderName, err)
+	}
+	return key, nil
+}
+
+// CreateSSHKey creates an SSH Key item at path ("vault/item") holding the
+// private key in opts, or a newly generated key pair, and returns the key.
+// The item gets the private key in OpenSSH format, the public key in
+// authorized_keys format, and the fingerprint. It fails with
+// vault.ErrAlreadyExists if the item exists, and with ErrDryRun under a dry
+// run. Like Set, it is mirrored to Config.MirrorTo.
+//
+//	key, err := provider.CreateSSHKey(ctx, "Private/web-01", onepassword.SSHKeyOptions{})
+//	fmt.Println(key.AuthorizedKey) // for the machine's authorized_keys
+func (p *Provider) CreateSSHKey(ctx context.Context, path string, opts SSHKeyOptions) (*SSHKey, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("CreateSSHKey", path, ProviderName, vault.ErrClosed)
+	}
+
+	if err := p.refuseDryRun(ctx, "CreateSSHKey", path); err != nil {
+		return nil, err
+	}
+
+	parsed, err := p.parsePath(path)
+	if err == nil && parsed.Field != "" {
+		err = fmt.Errorf("%w: CreateSSHKey requires an item path", ErrInvalidPath)
+	}
+	if err != nil {
+		return nil, vault.NewVaultError("CreateSSHKey", path, ProviderName, err)
+	}
+
+	key, err := opts.key()
+	if err != nil {
+		return nil, vault.NewVaultError("CreateSSHKey", path, ProviderName, err)
+	}
+
+	unlock := p.lockItem(parsed)
+	defer unlock()
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Write)
+	defer cancel()
+
+	p.invalidateDiskCache(ctx, parsed)
+
+	vaultID, err := p.resolveVaultID(ctx, parsed.Vault)
+	if err != nil {
+		return nil, mapError("CreateSSHKey", path, err)
+	}
+	if _, err := p.resolveItemID(ctx, vaultID, parsed.Item); err == nil {
+		return nil, vault.NewVaultError("CreateSSHKey", path, ProviderName, vault.ErrAlreadyExists)
+	} else if !isNotFoundError(err) {
+		return nil, mapError("CreateSSHKey", path, err)
+	}
+
+	_, err = p.items.Create(ctx, op.ItemCreateParams{
+		VaultID:  vaultID,
+		Title:    parsed.Item,
+		Category: op.ItemCategorySSHKey,
+		Fields: []op.ItemField{
+			{ID: "private_key", Title: SSHPrivateKeyField, Value: key.PrivateKeyOpenSSH, FieldType: op.ItemFieldTypeConcealed},
+			{ID: "public_key", Title: SSHPublicKeyField, Value: key.AuthorizedKey, FieldType: op.ItemFieldTypeText},
+			{ID: "fingerprint", Title: SSHFingerprintField, Value: key.Fingerprint, FieldType: op.ItemFieldTypeText},
+		},
+	})
+	if err != nil {
+		return nil, mapError("CreateSSHKey", path, err)
+	}
+
+	if err := p.mirrorItem(ctx, "CreateSSHKey", parsed.String(), parsed); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// key parses the private key in opts, or generates one.
+func (opts SSHKeyOptions) key() (*SSHKey, error) {
+	if opts.PrivateKey != "" {
+		return parseSSHKey(opts.PrivateKey)
+	}
+
+	var privateKey crypto.PrivateKey
+	var err error
+	switch opts.Type {
+	case "", SSHKeyEd25519:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	case SSHKeyRSA:
+		bits := opts.Bits
+		if bits == 0 {
+			bits = DefaultRSABits
+		}
+		if bits < 2048 {
+			return nil, fmt.Errorf("RSA keys must have at least 2048 bits, got %d", bits)
+		}
+		privateKey, err = rsa.GenerateKey(rand.Reader, bits)
+	default:
+		return nil, fmt.Errorf("unsupported SSH key type %q", opts.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate SSH key: %w", err)
+	}
+	return newSSHKey(privateKey)
+}
+
+// parseSSHKey parses a PEM-encoded private key (PKCS#1, PKCS#8, SEC 1, or
+// OpenSSH format) into an SSHKey.
+func parseSSHKey(privateKeyPEM string) (*SSHKey, error) {
+	privateKey, err := ssh.ParseRawPrivateKey([]byte(privateKeyPEM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH private key: %w", err)
+	}
+	return newSSHKey(privateKey)
+}
+
+// newSSHKey returns privateKey as an SSHKey.
+func newSSHKey(privateKey crypto.PrivateKey) (*SSHKey, error) {
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSH signer: %w", err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenSSH private key: %w", err)
+	}
+
+	publicKey := signer.PublicKey()
+	return &SSHKey{
+		PrivateKey:        privateKey,
+		Signer:            signer,
+		PublicKey:         publicKey,
+		AuthorizedKey:     strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))),
+		Fingerprint:       ssh.FingerprintSHA256(publicKey),
+		PrivateKeyOpenSSH: string(pem.EncodeToMemory(block)),
+	}, nil
+}
@@ -0,0 +1,138 @@
+package onepassword
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/providers/memory"
+	"github.com/agentplexus/omnivault/vault"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestParseSSHKey(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	pkcs8 := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	key, err := parseSSHKey(pkcs8)
+	if err != nil {
+		t.Fatalf("parseSSHKey() error = %v", err)
+	}
+
+	if !strings.HasPrefix(key.AuthorizedKey, "ssh-ed25519 ") {
+		t.Errorf("AuthorizedKey = %q, want ssh-ed25519 prefix", key.AuthorizedKey)
+	}
+	if !strings.HasPrefix(key.Fingerprint, "SHA256:") {
+		t.Errorf("Fingerprint = %q, want SHA256: prefix", key.Fingerprint)
+	}
+	if key.Signer.PublicKey().Type() != ssh.KeyAlgoED25519 {
+		t.Errorf("Signer key type = %q, want %q", key.Signer.PublicKey().Type(), ssh.KeyAlgoED25519)
+	}
+
+	// The OpenSSH encoding must parse back to the same key
+	roundTrip, err := parseSSHKey(key.PrivateKeyOpenSSH)
+	if err != nil {
+		t.Fatalf("parseSSHKey(OpenSSH) error = %v", err)
+	}
+	if roundTrip.Fingerprint != key.Fingerprint {
+		t.Errorf("OpenSSH round trip fingerprint = %q, want %q", roundTrip.Fingerprint, key.Fingerprint)
+	}
+}
+
+func TestParseSSHKey_Invalid(t *testing.T) {
+	if _, err := parseSSHKey("not a key"); err == nil {
+		t.Error("parseSSHKey() should fail for invalid input")
+	}
+}
+
+func TestCreateSSHKey(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	key, err := p.CreateSSHKey(t.Context(), "Private/web-01", SSHKeyOptions{})
+	if err != nil {
+		t.Fatalf("CreateSSHKey() error = %v", err)
+	}
+	if !strings.HasPrefix(key.AuthorizedKey, "ssh-ed25519 ") {
+		t.Errorf("AuthorizedKey = %q, want an ed25519 key", key.AuthorizedKey)
+	}
+	created := m.created[0]
+	if created.Title != "web-01" || created.Category != op.ItemCategorySSHKey {
+		t.Errorf("created %q with category %s", created.Title, created.Category)
+	}
+	fields := make(map[string]string)
+	for _, field := range created.Fields {
+		fields[field.Title] = field.Value
+	}
+	if fields[SSHPublicKeyField] != key.AuthorizedKey || fields[SSHFingerprintField] != key.Fingerprint {
+		t.Errorf("created fields %v, want the public key and fingerprint", fields)
+	}
+	stored, err := parseSSHKey(fields[SSHPrivateKeyField])
+	if err != nil || stored.Fingerprint != key.Fingerprint {
+		t.Errorf("stored private key = %v, %v, want the returned key", stored, err)
+	}
+
+	// A supplied key is stored instead of a generated one
+	supplied, err := p.CreateSSHKey(t.Context(), "Private/web-02", SSHKeyOptions{PrivateKey: key.PrivateKeyOpenSSH})
+	if err != nil {
+		t.Fatalf("CreateSSHKey(supplied) error = %v", err)
+	}
+	if supplied.Fingerprint != key.Fingerprint {
+		t.Errorf("supplied key fingerprint = %q, want %q", supplied.Fingerprint, key.Fingerprint)
+	}
+
+	rsaKey, err := p.CreateSSHKey(t.Context(), "Private/web-03", SSHKeyOptions{Type: SSHKeyRSA, Bits: 2048})
+	if err != nil {
+		t.Fatalf("CreateSSHKey(rsa) error = %v", err)
+	}
+	if rsaKey.PublicKey.Type() != ssh.KeyAlgoRSA {
+		t.Errorf("RSA key type = %q", rsaKey.PublicKey.Type())
+	}
+
+	if _, err := p.CreateSSHKey(t.Context(), "Private/Database", SSHKeyOptions{}); !errors.Is(err, vault.ErrAlreadyExists) {
+		t.Errorf("CreateSSHKey(existing) error = %v, want vault.ErrAlreadyExists", err)
+	}
+	for _, opts := range []SSHKeyOptions{{Type: "dsa"}, {Type: SSHKeyRSA, Bits: 1024}} {
+		if _, err := p.CreateSSHKey(t.Context(), "Private/web-04", opts); err == nil {
+			t.Errorf("CreateSSHKey(%+v) should fail", opts)
+		}
+	}
+	if len(m.created) != 3 {
+		t.Errorf("created %d items, want 3", len(m.created))
+	}
+}
+
+func TestCreateSSHKey_DryRunAndMirror(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{DryRun: true})
+	if _, err := p.CreateSSHKey(t.Context(), "Private/web-01", SSHKeyOptions{}); !errors.Is(err, ErrDryRun) {
+		t.Errorf("CreateSSHKey() under DryRun error = %v, want ErrDryRun", err)
+	}
+	if len(m.created) != 0 {
+		t.Errorf("created %d items under DryRun", len(m.created))
+	}
+
+	secondary := memory.New()
+	m = testMockAPI()
+	p = newWithAPIs(m, storingItems{mockAPI: m}, mockVaults{m}, Config{MirrorTo: secondary})
+	key, err := p.CreateSSHKey(t.Context(), "Private/web-01", SSHKeyOptions{})
+	if err != nil {
+		t.Fatalf("CreateSSHKey() error = %v", err)
+	}
+	mirrored, err := secondary.Get(t.Context(), "Private/web-01")
+	if err != nil || mirrored.Fields[SSHPublicKeyField] != key.AuthorizedKey {
+		t.Errorf("mirror Get() = %+v, %v; want the key", mirrored, err)
+	}
+}
@@ -0,0 +1,86 @@
+package onepassword
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+// Stats.Requestors counts the calls of at most MaxRequestors requestors,
+// taken in order of first use. Calls made for any other requestor are
+// counted under OtherRequestors, so that requestors taken from request
+// data can't grow the counters without bound.
+const (
+	MaxRequestors   = 100
+	OtherRequestors = "(other)"
+)
+
+// Stats are counters describing a provider's calls to 1Password.
+type Stats struct {
+	// RateLimited is the number of calls 1Password rejected because of
+	// rate limiting.
+	RateLimited uint64
+
+	// Throttled is the number of calls delayed by Config.RateLimit.
+	Throttled uint64
+
+	// SkippedWrites is the number of Set calls that left an item alone
+	// because it already held the content written (see
+	// Config.SkipUnchanged).
+	SkippedWrites uint64
+
+	// MirrorFailures is the number of writes to Config.MirrorTo that
+	// failed.
+	MirrorFailures uint64
+
+	// Requestors is the number of Get, Set, Delete, and List calls made
+	// for each requestor set with WithRequestor, beyond MaxRequestors of
+	// them under OtherRequestors. It is nil if there were none.
+	Requestors map[string]uint64
+
+	// Shards describes each service account of a provider configured with
+	// Config.ShardTokens, starting with the primary one. It is nil
+	// otherwise.
+	Shards []ShardStats
+}
+
+// providerStats holds the live counters behind Stats.
+type providerStats struct {
+	rateLimited    atomic.Uint64
+	throttled      atomic.Uint64
+	skippedWrites  atomic.Uint64
+	mirrorFailures atomic.Uint64
+
+	mu         sync.Mutex
+	requestors map[string]uint64
+}
+
+// countRequest counts an operation made for requestor.
+func (s *providerStats) countRequest(requestor string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.requestors == nil {
+		s.requestors = make(map[string]uint64)
+	}
+	if _, ok := s.requestors[requestor]; !ok && len(s.requestors) >= MaxRequestors {
+		requestor = OtherRequestors
+	}
+	s.requestors[requestor]++
+}
+
+// Stats returns a snapshot of the provider's counters.
+func (p *Provider) Stats() Stats {
+	stats := Stats{
+		RateLimited:    p.stats.rateLimited.Load(),
+		Throttled:      p.stats.throttled.Load(),
+		SkippedWrites:  p.stats.skippedWrites.Load(),
+		MirrorFailures: p.stats.mirrorFailures.Load(),
+	}
+	p.stats.mu.Lock()
+	stats.Requestors = maps.Clone(p.stats.requestors)
+	p.stats.mu.Unlock()
+	if p.shards != nil {
+		stats.Shards = p.shards.stats()
+	}
+	return stats
+}
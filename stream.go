@@ -0,0 +1,138 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// FileOptions controls PutFileFrom and GetFileReader.
+type FileOptions struct {
+	// Progress, if set, is called with the number of bytes read so far
+	// after each chunk.
+	Progress func(bytes int64)
+
+	// Filename, if set, is stored by PutFileFrom in DocumentFilenameField,
+	// as for documents.
+	Filename string
+}
+
+// PutFileFrom stores the data read from r as SetBytes does, reading and
+// encoding it one chunk at a time rather than reading it whole first.
+// The SDK writes items whole, so the encoded chunks are held in memory
+// until the item is written.
+//
+//	f, err := os.Open("keystore.p12")
+//	err = provider.PutFileFrom(ctx, "Private/keystore", f, onepassword.FileOptions{Filename: "keystore.p12"})
+func (p *Provider) PutFileFrom(ctx context.Context, path string, r io.Reader, opts FileOptions) error {
+	return p.writeBytes(ctx, "PutFileFrom", path, r, opts.Progress, documentFields(opts.Filename), anyItem)
+}
+
+// GetFileReader returns a reader of the data stored by SetBytes,
+// PutFileFrom, or CreateDocument, decoding one chunk at a time rather
+// than all at once. The SDK reads items whole, so the encoded chunks are
+// held in memory until the reader is dropped. The reader checks the data
+// against its recorded length and checksum at the end, returning an error
+// wrapping ErrChecksumMismatch in place of io.EOF on a difference.
+func (p *Provider) GetFileReader(ctx context.Context, path string, opts FileOptions) (io.Reader, error) {
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("GetFileReader", path, ProviderName, vault.ErrClosed)
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.Get)
+	defer cancel()
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return nil, vault.NewVaultError("GetFileReader", path, ProviderName, err)
+	}
+	name := bytesFieldName(parsed)
+
+	item, err := p.fetchItem(ctx, parsed)
+	if err != nil {
+		return nil, mapError("GetFileReader", path, err)
+	}
+
+	first, ok := findField(item, parsed.Section, name)
+	if !ok {
+		return nil, vault.NewVaultError("GetFileReader", path, ProviderName, ErrFieldNotFound)
+	}
+	chunks := []io.Reader{strings.NewReader(first.Value)}
+	for n := 2; ; n++ {
+		chunk, ok := findField(item, parsed.Section, bytesChunkName(name, n))
+		if !ok {
+			break
+		}
+		chunks = append(chunks, strings.NewReader(chunk.Value))
+	}
+
+	r := &fileReader{
+		path:     path,
+		decoder:  base64.NewDecoder(base64.StdEncoding, io.MultiReader(chunks...)),
+		hash:     sha256.New(),
+		progress: opts.Progress,
+	}
+	if size, ok := findField(item, parsed.Section, name+".size"); ok {
+		r.size = size.Value
+	}
+	if sum, ok := findField(item, parsed.Section, name+".sha256"); ok {
+		r.sum = sum.Value
+	}
+	return r, nil
+}
+
+// fileReader decodes the chunks of stored data, checking them against the
+// recorded length and checksum, if any, at the end.
+type fileReader struct {
+	path      string
+	decoder   io.Reader
+	hash      hash.Hash
+	read      int64
+	size, sum string
+	progress  func(int64)
+	err       error
+}
+
+func (r *fileReader) Read(b []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.decoder.Read(b)
+	if n > 0 {
+		r.hash.Write(b[:n])
+		r.read += int64(n)
+		if r.progress != nil {
+			r.progress(r.read)
+		}
+	}
+	switch {
+	case err == io.EOF:
+		err = r.verify()
+	case err != nil:
+		err = vault.NewVaultError("GetFileReader", r.path, ProviderName, fmt.Errorf("invalid base64 data: %w", err))
+	}
+	r.err = err
+	return n, err
+}
+
+// verify returns io.EOF if the data read matches the recorded length and
+// checksum, or an error wrapping ErrChecksumMismatch.
+func (r *fileReader) verify() error {
+	if r.size != "" && r.size != strconv.FormatInt(r.read, 10) {
+		return vault.NewVaultError("GetFileReader", r.path, ProviderName,
+			fmt.Errorf("%w: read %d bytes, expected %s", ErrChecksumMismatch, r.read, r.size))
+	}
+	if r.sum != "" && r.sum != hex.EncodeToString(r.hash.Sum(nil)) {
+		return vault.NewVaultError("GetFileReader", r.path, ProviderName,
+			fmt.Errorf("%w: SHA-256 differs from %s", ErrChecksumMismatch, r.sum))
+	}
+	return io.EOF
+}
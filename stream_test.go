@@ -0,0 +1,65 @@
+package onepassword
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func TestPutFileFrom_GetFileReader(t *testing.T) {
+	defer func(size int) { bytesChunkSize = size }(bytesChunkSize)
+	bytesChunkSize = 8
+
+	data := []byte("\x00\x01binary\xff payload of several chunks")
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+
+	var written []int64
+	err := p.PutFileFrom(t.Context(), "Private/Keystore", bytes.NewReader(data), FileOptions{
+		Filename: "keystore.p12",
+		Progress: func(n int64) { written = append(written, n) },
+	})
+	if err != nil {
+		t.Fatalf("PutFileFrom() error = %v", err)
+	}
+	if len(written) != 6 || written[len(written)-1] != int64(len(data)) {
+		t.Errorf("progress = %v, want 6 chunks ending at %d", written, len(data))
+	}
+	// The chunks match what SetBytes writes
+	fields := m.created[0].Fields
+	if want := bytesFields(BytesField, data); len(fields) != len(want)+1 || fields[0] != want[0] || fields[5] != want[5] {
+		t.Errorf("fields = %+v, want SetBytes' and a filename", fields)
+	}
+
+	m.items["v1"] = append(m.items["v1"], op.Item{ID: "i3", Title: "Keystore", VaultID: "v1", Fields: fields})
+	var read int64
+	r, err := p.GetFileReader(t.Context(), "Private/Keystore", FileOptions{Progress: func(n int64) { read = n }})
+	if err != nil {
+		t.Fatalf("GetFileReader() error = %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("read %q, %v; want %q", got, err, data)
+	}
+	if read != int64(len(data)) {
+		t.Errorf("progress = %d, want %d", read, len(data))
+	}
+	if doc, err := p.GetDocument(t.Context(), "Private/Keystore"); err != nil || doc.Filename != "keystore.p12" {
+		t.Errorf("GetDocument() = %+v, %v", doc, err)
+	}
+
+	// Tampering is reported at the end of the data
+	tampered := bytesFields("cert", []byte("original"))
+	tampered[0].Value = "dGFtcGVyZWQ=" // "tampered"
+	m.items["v1"] = append(m.items["v1"], op.Item{ID: "i4", Title: "Tampered", VaultID: "v1", Fields: tampered})
+	r, err = p.GetFileReader(t.Context(), "Private/Tampered/cert", FileOptions{})
+	if err != nil {
+		t.Fatalf("GetFileReader() error = %v", err)
+	}
+	if _, err := io.ReadAll(r); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("reading tampered data error = %v, want ErrChecksumMismatch", err)
+	}
+}
@@ -0,0 +1,279 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// ConflictPolicy selects what Sync does when a destination secret exists and
+// differs from the source.
+type ConflictPolicy int
+
+const (
+	// ConflictSourceWins overwrites the destination with the source.
+	ConflictSourceWins ConflictPolicy = iota
+
+	// ConflictNewestWins overwrites the destination only if the source was
+	// modified more recently. When either side has no modification time
+	// (1Password items never do, as the SDK does not expose them) the
+	// secret is reported as a conflict and left alone.
+	ConflictNewestWins
+
+	// ConflictSkip never overwrites existing destination secrets.
+	ConflictSkip
+)
+
+// SyncAction is what Sync did, or would do in a dry run, with one path.
+type SyncAction string
+
+// Sync actions.
+const (
+	SyncCreated   SyncAction = "created"
+	SyncUpdated   SyncAction = "updated"
+	SyncDeleted   SyncAction = "deleted"
+	SyncUnchanged SyncAction = "unchanged"
+	SyncSkipped   SyncAction = "skipped"
+	SyncConflict  SyncAction = "conflict"
+	SyncFailed    SyncAction = "failed"
+)
+
+// SyncOptions configures Sync.
+type SyncOptions struct {
+	// Prefix selects the source secrets to sync (see vault.Vault.List).
+	Prefix string
+
+	// Tag, when set, limits the sync to source secrets carrying the tag.
+	// "key" matches any value; "key:value" matches exactly.
+	Tag string
+
+	// MapPath converts a source path to its destination path.
+	// Default: the same path
+	MapPath func(srcPath string) string
+
+	// Conflict selects how differing destination secrets are handled.
+	// Default: ConflictSourceWins
+	Conflict ConflictPolicy
+
+	// Delete removes destination secrets under MapPath(Prefix) that have no
+	// matching source secret. Copies of sources that failed to read or
+	// were filtered out by Tag are kept.
+	Delete bool
+
+	// DryRun reports what would change without writing to the destination.
+	DryRun bool
+
+	// Progress, when set, is called after each path is processed.
+	Progress func(SyncResult)
+}
+
+// SyncResult is the outcome for one path.
+type SyncResult struct {
+	// Source is the source path; empty for deletions.
+	Source string
+
+	// Destination is the destination path.
+	Destination string
+
+	// Action is what was done (or would be done in a dry run).
+	Action SyncAction
+
+	// Err is the failure for SyncFailed, or the reason for SyncConflict.
+	Err error
+}
+
+// SyncReport summarizes a sync.
+type SyncReport struct {
+	// DryRun reports whether the destination was left untouched.
+	DryRun bool
+
+	// Results has one entry per processed path, in processing order.
+	Results []SyncResult
+}
+
+// Count returns the number of results with the given action.
+func (r *SyncReport) Count(action SyncAction) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Err joins the errors of all failed results, or returns nil.
+func (r *SyncReport) Err() error {
+	var errs []error
+	for _, res := range r.Results {
+		if res.Action == SyncFailed {
+			errs = append(errs, res.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Sync mirrors secrets from src into dst. Either side may be a 1Password
+// provider or any other vault.Vault, so it serves both for migrating into
+// 1Password and for replicating out of it:
+//
+//	report, err := onepassword.Sync(ctx, provider, awsProvider, onepassword.SyncOptions{
+//	    Prefix:  "Prod/",
+//	    MapPath: func(p string) string { return "prod/" + strings.TrimPrefix(p, "Prod/") },
+//	    DryRun:  true,
+//	})
+//
+// A destination secret is unchanged when it has the source's value (if any)
+// and every source field with the same value; extra destination fields are
+// ignored. Failures for individual paths are recorded in the report (see
+// SyncReport.Err) and do not stop the sync. The returned error is set only
+// when the secrets can't be listed.
+func Sync(ctx context.Context, src, dst vault.Vault, opts SyncOptions) (*SyncReport, error) {
+	mapPath := opts.MapPath
+	if mapPath == nil {
+		mapPath = func(p string) string { return p }
+	}
+
+	srcPaths, err := src.List(ctx, opts.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(srcPaths)
+
+	report := &SyncReport{DryRun: opts.DryRun}
+	record := func(res SyncResult) {
+		report.Results = append(report.Results, res)
+		if opts.Progress != nil {
+			opts.Progress(res)
+		}
+	}
+
+	synced := make(map[string]bool, len(srcPaths))
+	for _, srcPath := range srcPaths {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		// The destination copy is kept even if the source can't be read or
+		// is filtered out: only missing sources are deleted
+		dstPath := mapPath(srcPath)
+		synced[dstPath] = true
+		res := SyncResult{Source: srcPath, Destination: dstPath}
+
+		secret, err := src.Get(ctx, srcPath)
+		if err != nil {
+			res.Action, res.Err = SyncFailed, err
+			record(res)
+			continue
+		}
+		if opts.Tag != "" && !hasTag(secret, opts.Tag) {
+			continue
+		}
+
+		res.Action, res.Err = syncOne(ctx, dst, dstPath, secret, opts)
+		record(res)
+	}
+
+	if opts.Delete {
+		dstPaths, err := dst.List(ctx, mapPath(opts.Prefix))
+		if err != nil {
+			return report, err
+		}
+		sort.Strings(dstPaths)
+
+		for _, dstPath := range dstPaths {
+			if synced[dstPath] {
+				continue
+			}
+			res := SyncResult{Destination: dstPath, Action: SyncDeleted}
+			if !opts.DryRun {
+				if err := dst.Delete(ctx, dstPath); err != nil {
+					res.Action, res.Err = SyncFailed, err
+				}
+			}
+			record(res)
+		}
+	}
+
+	return report, nil
+}
+
+// syncOne copies secret to dstPath according to opts.
+func syncOne(ctx context.Context, dst vault.Vault, dstPath string, secret *vault.Secret, opts SyncOptions) (SyncAction, error) {
+	existing, err := dst.Get(ctx, dstPath)
+	switch {
+	case errors.Is(err, vault.ErrSecretNotFound):
+		if !opts.DryRun {
+			if err := dst.Set(ctx, dstPath, syncCopy(secret)); err != nil {
+				return SyncFailed, err
+			}
+		}
+		return SyncCreated, nil
+	case err != nil:
+		return SyncFailed, err
+	}
+
+	if secretInSync(secret, existing) {
+		return SyncUnchanged, nil
+	}
+
+	switch opts.Conflict {
+	case ConflictSkip:
+		return SyncSkipped, nil
+	case ConflictNewestWins:
+		srcTime, dstTime := secret.Metadata.ModifiedAt, existing.Metadata.ModifiedAt
+		if srcTime == nil || dstTime == nil {
+			return SyncConflict, fmt.Errorf("modification time unavailable for %s", dstPath)
+		}
+		if !srcTime.After(dstTime.Time) {
+			return SyncSkipped, nil
+		}
+	}
+
+	if !opts.DryRun {
+		if err := dst.Set(ctx, dstPath, syncCopy(secret)); err != nil {
+			return SyncFailed, err
+		}
+	}
+	return SyncUpdated, nil
+}
+
+// syncCopy returns the parts of secret written to the destination. Metadata
+// describing the source (provider, path, version, IDs) is dropped; tags are
+// kept.
+func syncCopy(secret *vault.Secret) *vault.Secret {
+	return &vault.Secret{
+		Value:      secret.Value,
+		ValueBytes: secret.ValueBytes,
+		Fields:     secret.Fields,
+		Metadata:   vault.Metadata{Tags: secret.Metadata.Tags},
+	}
+}
+
+// secretInSync reports whether dst has src's value (if any) and all of its
+// fields.
+func secretInSync(src, dst *vault.Secret) bool {
+	if src.Value != "" && src.Value != dst.Value {
+		return false
+	}
+	for name, value := range src.Fields {
+		if dstValue, ok := dst.Fields[name]; !ok || dstValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTag reports whether secret carries tag, given as "key" or "key:value".
+func hasTag(secret *vault.Secret, tag string) bool {
+	key, value, hasValue := strings.Cut(tag, ":")
+	got, ok := secret.Metadata.Tags[key]
+	if !ok {
+		return false
+	}
+	return !hasValue || got == value
+}
@@ -0,0 +1,196 @@
+package onepassword
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/omnivault/providers/memory"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	src := memory.New()
+	dst := memory.New()
+
+	_ = src.Set(ctx, "Prod/new", &vault.Secret{Value: "n"})
+	_ = src.Set(ctx, "Prod/same", &vault.Secret{Fields: map[string]string{"user": "admin"}})
+	_ = src.Set(ctx, "Prod/changed", &vault.Secret{Value: "v2"})
+	_ = src.Set(ctx, "Dev/other", &vault.Secret{Value: "x"})
+
+	_ = dst.Set(ctx, "prod/same", &vault.Secret{Fields: map[string]string{"user": "admin", "extra": "kept"}})
+	_ = dst.Set(ctx, "prod/changed", &vault.Secret{Value: "v1"})
+	_ = dst.Set(ctx, "prod/stale", &vault.Secret{Value: "old"})
+
+	opts := SyncOptions{
+		Prefix:  "Prod/",
+		MapPath: func(p string) string { return "prod/" + strings.TrimPrefix(p, "Prod/") },
+		Delete:  true,
+	}
+
+	// Dry run reports without writing
+	dry := opts
+	dry.DryRun = true
+	report, err := Sync(ctx, src, dst, dry)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	want := map[string]SyncAction{
+		"prod/changed": SyncUpdated,
+		"prod/new":     SyncCreated,
+		"prod/same":    SyncUnchanged,
+		"prod/stale":   SyncDeleted,
+	}
+	checkSyncResults(t, report, want)
+	if exists, _ := dst.Exists(ctx, "prod/new"); exists {
+		t.Error("dry run created a secret")
+	}
+
+	var progress int
+	opts.Progress = func(SyncResult) { progress++ }
+	report, err = Sync(ctx, src, dst, opts)
+	if err != nil || report.Err() != nil {
+		t.Fatalf("Sync() error = %v, %v", err, report.Err())
+	}
+	checkSyncResults(t, report, want)
+	if progress != len(want) {
+		t.Errorf("Progress called %d times, want %d", progress, len(want))
+	}
+
+	if s, _ := dst.Get(ctx, "prod/changed"); s.Value != "v2" {
+		t.Errorf("prod/changed = %q, want 'v2'", s.Value)
+	}
+	if exists, _ := dst.Exists(ctx, "prod/stale"); exists {
+		t.Error("prod/stale was not deleted")
+	}
+
+	// A second run has nothing to do
+	report, _ = Sync(ctx, src, dst, opts)
+	if n := report.Count(SyncUnchanged); n != 3 {
+		t.Errorf("second sync unchanged = %d, want 3", n)
+	}
+}
+
+// failingGets fails Get for the paths in fail.
+type failingGets struct {
+	vault.Vault
+	fail map[string]bool
+}
+
+func (f failingGets) Get(ctx context.Context, path string) (*vault.Secret, error) {
+	if f.fail[path] {
+		return nil, vault.ErrConnectionFailed
+	}
+	return f.Vault.Get(ctx, path)
+}
+
+func TestSync_DeleteKeepsUnreadSources(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.New()
+	dst := memory.New()
+
+	_ = mem.Set(ctx, "Prod/flaky", &vault.Secret{Value: "f"})
+	_ = mem.Set(ctx, "Prod/untagged", &vault.Secret{Value: "u"})
+	_ = mem.Set(ctx, "Prod/tagged", &vault.Secret{Value: "t", Metadata: vault.Metadata{Tags: map[string]string{"sync": ""}}})
+	for _, path := range []string{"Prod/flaky", "Prod/untagged", "Prod/gone"} {
+		_ = dst.Set(ctx, path, &vault.Secret{Value: "old"})
+	}
+
+	src := failingGets{Vault: mem, fail: map[string]bool{"Prod/flaky": true}}
+	report, err := Sync(ctx, src, dst, SyncOptions{Prefix: "Prod/", Tag: "sync", Delete: true})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	checkSyncResults(t, report, map[string]SyncAction{
+		"Prod/flaky":  SyncFailed,
+		"Prod/tagged": SyncCreated,
+		"Prod/gone":   SyncDeleted,
+	})
+	for _, path := range []string{"Prod/flaky", "Prod/untagged"} {
+		if exists, _ := dst.Exists(ctx, path); !exists {
+			t.Errorf("%s was deleted from the destination", path)
+		}
+	}
+}
+
+func checkSyncResults(t *testing.T, report *SyncReport, want map[string]SyncAction) {
+	t.Helper()
+	if len(report.Results) != len(want) {
+		t.Fatalf("Sync() returned %d results, want %d: %+v", len(report.Results), len(want), report.Results)
+	}
+	for _, res := range report.Results {
+		if want[res.Destination] != res.Action {
+			t.Errorf("%s: action = %s, want %s", res.Destination, res.Action, want[res.Destination])
+		}
+	}
+}
+
+func TestSyncOne_Conflicts(t *testing.T) {
+	ctx := context.Background()
+	older := vault.NewTimestamp(time.Unix(1000, 0))
+	newer := vault.NewTimestamp(time.Unix(2000, 0))
+
+	tests := []struct {
+		name   string
+		policy ConflictPolicy
+		src    *vault.Secret
+		want   SyncAction
+	}{
+		{"source wins", ConflictSourceWins, &vault.Secret{Value: "s"}, SyncUpdated},
+		{"skip", ConflictSkip, &vault.Secret{Value: "s"}, SyncSkipped},
+		{"newest wins, source newer", ConflictNewestWins,
+			&vault.Secret{Value: "s", Metadata: vault.Metadata{ModifiedAt: newer}}, SyncUpdated},
+		{"newest wins, source older", ConflictNewestWins,
+			&vault.Secret{Value: "s", Metadata: vault.Metadata{ModifiedAt: older}}, SyncSkipped},
+		{"newest wins, no time", ConflictNewestWins, &vault.Secret{Value: "s"}, SyncConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := &fixedTimeVault{Provider: memory.New(), modified: vault.NewTimestamp(time.Unix(1500, 0))}
+			_ = dst.Set(ctx, "p", &vault.Secret{Value: "d"})
+
+			got, _ := syncOne(ctx, dst, "p", tt.src, SyncOptions{Conflict: tt.policy})
+			if got != tt.want {
+				t.Errorf("syncOne() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// fixedTimeVault reports a fixed modification time for every secret.
+type fixedTimeVault struct {
+	*memory.Provider
+	modified *vault.Timestamp
+}
+
+func (v *fixedTimeVault) Get(ctx context.Context, path string) (*vault.Secret, error) {
+	secret, err := v.Provider.Get(ctx, path)
+	if err == nil {
+		secret.Metadata.ModifiedAt = v.modified
+	}
+	return secret, err
+}
+
+func TestHasTag(t *testing.T) {
+	secret := &vault.Secret{Metadata: vault.Metadata{Tags: map[string]string{"env": "prod", "sync": ""}}}
+
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{"env", true},
+		{"env:prod", true},
+		{"env:dev", false},
+		{"sync", true},
+		{"missing", false},
+	}
+
+	for _, tt := range tests {
+		if got := hasTag(secret, tt.tag); got != tt.want {
+			t.Errorf("hasTag(%q) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+}
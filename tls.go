@@ -0,0 +1,298 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// Field titles searched, in order, by LoadTLSCertificate and
+// GetCertificateBundle.
+var (
+	tlsCertificateFields = []string{"certificate", "cert", "tls.crt", "certificate.pem"}
+	tlsPrivateKeyFields  = []string{"private key", "private_key", "key", "tls.key", "key.pem"}
+	tlsChainFields       = []string{"chain", "ca", "ca.crt", "chain.pem"}
+)
+
+// GetTLSCertificate builds a tls.Certificate from a PEM certificate (chain)
+// at certPath and a PEM private key at keyPath.
+//
+//	cert, err := provider.GetTLSCertificate(ctx, "Infra/api.example.com/certificate", "Infra/api.example.com/private key")
+func (p *Provider) GetTLSCertificate(ctx context.Context, certPath, keyPath string) (tls.Certificate, error) {
+	certSecret, err := p.Get(ctx, certPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keySecret, err := p.Get(ctx, keyPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	cert, err := tls.X509KeyPair([]byte(certSecret.Value), []byte(keySecret.Value))
+	if err != nil {
+		return tls.Certificate{}, vault.NewVaultError("GetTLSCertificate", certPath, ProviderName, err)
+	}
+	return cert, nil
+}
+
+// LoadTLSCertificate builds a tls.Certificate from an item that stores the
+// PEM certificate and private key as fields. The certificate is read from the
+// first field titled "certificate", "cert", "tls.crt", or "certificate.pem"
+// and the key from "private key", "private_key", "key", "tls.key", or
+// "key.pem" (case-insensitive).
+func (p *Provider) LoadTLSCertificate(ctx context.Context, itemPath string) (tls.Certificate, error) {
+	secret, err := p.Get(ctx, itemPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	certPEM, ok := lookupField(secret.Fields, tlsCertificateFields)
+	if !ok {
+		return tls.Certificate{}, vault.NewVaultError("LoadTLSCertificate", itemPath, ProviderName,
+			fmt.Errorf("%w: no certificate field (tried %s)", vault.ErrSecretNotFound, strings.Join(tlsCertificateFields, ", ")))
+	}
+	keyPEM, ok := lookupField(secret.Fields, tlsPrivateKeyFields)
+	if !ok {
+		return tls.Certificate{}, vault.NewVaultError("LoadTLSCertificate", itemPath, ProviderName,
+			fmt.Errorf("%w: no private key field (tried %s)", vault.ErrSecretNotFound, strings.Join(tlsPrivateKeyFields, ", ")))
+	}
+
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		return tls.Certificate{}, vault.NewVaultError("LoadTLSCertificate", itemPath, ProviderName, err)
+	}
+	return cert, nil
+}
+
+// CertificateBundle is a certificate, its private key, and the chain of
+// intermediate certificates, as stored by StoreCertificate.
+type CertificateBundle struct {
+	// Certificate is the parsed leaf certificate.
+	Certificate *x509.Certificate
+
+	// Chain holds the parsed intermediate certificates, in the order
+	// stored, normally starting with the issuer of Certificate.
+	Chain []*x509.Certificate
+
+	// CertificatePEM, PrivateKeyPEM, and ChainPEM are the PEM blocks as
+	// stored. PrivateKeyPEM and ChainPEM may be empty.
+	CertificatePEM string
+	PrivateKeyPEM  string
+	ChainPEM       string
+
+	// Metadata is the metadata of the item. Its ExpiresAt is the
+	// certificate's NotAfter for items written by StoreCertificate.
+	Metadata vault.Metadata
+}
+
+// TLSCertificate returns the bundle as a tls.Certificate presenting the
+// certificate and chain.
+func (b *CertificateBundle) TLSCertificate() (tls.Certificate, error) {
+	return tls.X509KeyPair([]byte(b.CertificatePEM+"\n"+b.ChainPEM), []byte(b.PrivateKeyPEM))
+}
+
+// StoreCertificate writes a PEM certificate, its private key, and the chain
+// of intermediate certificates to the item at path ("vault/item") in the
+// fields "certificate", "private key", and "chain", creating a Secure Note
+// if needed. keyPEM and chainPEM may be empty; an empty one isn't written,
+// so in WriteModeMerge the stored key or chain is kept. The certificate's
+// NotAfter is stored in the "expires" field, so Get reports it as
+// Metadata.ExpiresAt and Config.EnforceExpiry applies. The key must match
+// the certificate.
+//
+//	err := provider.StoreCertificate(ctx, "Infra/api.example.com", certPEM, keyPEM, chainPEM)
+func (p *Provider) StoreCertificate(ctx context.Context, path, certPEM, keyPEM, chainPEM string) error {
+	certs, err := parseCertificates(certPEM)
+	if err == nil && chainPEM != "" {
+		_, err = parseCertificates(chainPEM)
+	}
+	if err == nil && keyPEM != "" {
+		_, err = tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	}
+	if err != nil {
+		return vault.NewVaultError("StoreCertificate", path, ProviderName, err)
+	}
+
+	fields := map[string]string{
+		"certificate": certPEM,
+		ExpiresKey:    certs[0].NotAfter.UTC().Format(time.RFC3339),
+	}
+	types := map[string]FieldType{
+		"certificate": op.ItemFieldTypeText,
+		ExpiresKey:    op.ItemFieldTypeText,
+	}
+	// An empty key or chain is left out, so a merge keeps the stored one
+	if keyPEM != "" {
+		fields["private key"] = keyPEM
+		types["private key"] = op.ItemFieldTypeConcealed
+	}
+	if chainPEM != "" {
+		fields["chain"] = chainPEM
+		types["chain"] = op.ItemFieldTypeText
+	}
+
+	return p.SetWithOptions(ctx, path, &vault.Secret{Fields: fields}, SetOptions{
+		Category:   op.ItemCategorySecureNote,
+		FieldTypes: types,
+	})
+}
+
+// GetCertificateBundle reads the certificate bundle stored in the item at
+// path. The certificate, private key, and chain are read from the fields
+// LoadTLSCertificate uses and the first field titled "chain", "ca",
+// "ca.crt", or "chain.pem"; only the certificate is required. A
+// certificate field holding several certificates supplies the chain when
+// there is no chain field.
+func (p *Provider) GetCertificateBundle(ctx context.Context, path string) (*CertificateBundle, error) {
+	secret, err := p.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	certPEM, ok := lookupField(secret.Fields, tlsCertificateFields)
+	if !ok {
+		return nil, vault.NewVaultError("GetCertificateBundle", path, ProviderName,
+			fmt.Errorf("%w: no certificate field (tried %s)", vault.ErrSecretNotFound, strings.Join(tlsCertificateFields, ", ")))
+	}
+	keyPEM, _ := lookupField(secret.Fields, tlsPrivateKeyFields)
+	chainPEM, _ := lookupField(secret.Fields, tlsChainFields)
+
+	certs, err := parseCertificates(certPEM)
+	if err != nil {
+		return nil, vault.NewVaultError("GetCertificateBundle", path, ProviderName, err)
+	}
+	chain := certs[1:]
+	if chainPEM != "" {
+		if chain, err = parseCertificates(chainPEM); err != nil {
+			return nil, vault.NewVaultError("GetCertificateBundle", path, ProviderName, err)
+		}
+	}
+
+	return &CertificateBundle{
+		Certificate:    certs[0],
+		Chain:          chain,
+		CertificatePEM: certPEM,
+		PrivateKeyPEM:  keyPEM,
+		ChainPEM:       chainPEM,
+		Metadata:       secret.Metadata,
+	}, nil
+}
+
+// parseCertificates parses the PEM certificates in data, of which there
+// must be at least one.
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM certificate found")
+	}
+	return certs, nil
+}
+
+// lookupField returns the value of the first non-empty field whose name
+// matches one of names, in order. A field named exactly is preferred, then
+// the first in sorted order whose name matches ignoring case, so the result
+// doesn't depend on map order.
+func lookupField(fields map[string]string, names []string) (string, bool) {
+	var keys []string
+	for _, name := range names {
+		if value := fields[name]; value != "" {
+			return value, true
+		}
+		if keys == nil {
+			keys = slices.Sorted(maps.Keys(fields))
+		}
+		for _, key := range keys {
+			if value := fields[key]; strings.EqualFold(key, name) && value != "" {
+				return value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// TLSReloader holds a certificate loaded from 1Password and swaps it in
+// place on Reload. Its GetCertificate and GetClientCertificate methods plug
+// into tls.Config so servers and clients pick up rotated certificates
+// without restarting. It is safe for concurrent use.
+type TLSReloader struct {
+	load func(ctx context.Context) (tls.Certificate, error)
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewTLSReloader loads the certificate from the item at itemPath (see
+// LoadTLSCertificate) and returns a reloader serving it.
+//
+//	reloader, err := provider.NewTLSReloader(ctx, "Infra/api.example.com")
+//	server.TLSConfig = &tls.Config{GetCertificate: reloader.GetCertificate}
+func (p *Provider) NewTLSReloader(ctx context.Context, itemPath string) (*TLSReloader, error) {
+	r := &TLSReloader{
+		load: func(ctx context.Context) (tls.Certificate, error) {
+			return p.LoadTLSCertificate(ctx, itemPath)
+		},
+	}
+	if err := r.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload fetches the certificate again. On failure the previous certificate
+// stays in use.
+func (r *TLSReloader) Reload(ctx context.Context) error {
+	cert, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+// Certificate returns the current certificate.
+func (r *TLSReloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// GetCertificate returns the current certificate; use it as tls.Config.GetCertificate.
+func (r *TLSReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// GetClientCertificate returns the current certificate; use it as
+// tls.Config.GetClientCertificate.
+func (r *TLSReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
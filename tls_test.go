@@ -0,0 +1,182 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func TestLookupField(t *testing.T) {
+	fields := map[string]string{
+		"Certificate": "CERT",
+		"key":         "",
+		"tls.key":     "KEY",
+	}
+
+	if got, ok := lookupField(fields, tlsCertificateFields); !ok || got != "CERT" {
+		t.Errorf("lookupField(cert) = %q, %v; want CERT", got, ok)
+	}
+	// Empty fields are skipped in favor of later names
+	if got, ok := lookupField(fields, tlsPrivateKeyFields); !ok || got != "KEY" {
+		t.Errorf("lookupField(key) = %q, %v; want KEY", got, ok)
+	}
+	if _, ok := lookupField(fields, []string{"missing"}); ok {
+		t.Error("lookupField() should report missing fields")
+	}
+
+	// An exact match wins over other cases, then sorted order decides
+	fields = map[string]string{"CERT": "upper", "Cert": "title", "cert": "exact"}
+	for range 20 {
+		if got, _ := lookupField(fields, []string{"cert"}); got != "exact" {
+			t.Fatalf("lookupField(exact) = %q, want exact", got)
+		}
+	}
+	delete(fields, "cert")
+	for range 20 {
+		if got, _ := lookupField(fields, []string{"cert"}); got != "upper" {
+			t.Fatalf("lookupField(folded) = %q, want upper", got)
+		}
+	}
+}
+
+func TestTLSReloader(t *testing.T) {
+	first := tls.Certificate{Certificate: [][]byte{[]byte("first")}}
+	second := tls.Certificate{Certificate: [][]byte{[]byte("second")}}
+
+	next := first
+	var loadErr error
+	r := &TLSReloader{
+		load: func(ctx context.Context) (tls.Certificate, error) {
+			return next, loadErr
+		},
+	}
+	ctx := context.Background()
+
+	if err := r.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got, _ := r.GetCertificate(nil); string(got.Certificate[0]) != "first" {
+		t.Errorf("GetCertificate() = %q, want first", got.Certificate[0])
+	}
+
+	next = second
+	if err := r.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got, _ := r.GetClientCertificate(nil); string(got.Certificate[0]) != "second" {
+		t.Errorf("GetClientCertificate() = %q, want second", got.Certificate[0])
+	}
+
+	// A failed reload keeps serving the previous certificate
+	loadErr = errors.New("unavailable")
+	if err := r.Reload(ctx); err == nil {
+		t.Error("Reload() should return the load error")
+	}
+	if got := r.Certificate(); string(got.Certificate[0]) != "second" {
+		t.Errorf("Certificate() after failed reload = %q, want second", got.Certificate[0])
+	}
+}
+
+// testCertificate issues a certificate for name, signed by parent (or self
+// signed if nil), returning it and its key in PEM.
+func testCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return cert, key,
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestCertificateBundle(t *testing.T) {
+	ca, caKey, caPEM, _ := testCertificate(t, "Test CA", nil, nil)
+	_, _, certPEM, keyPEM := testCertificate(t, "api.example.com", ca, caKey)
+	_, _, _, otherKeyPEM := testCertificate(t, "other", nil, nil)
+
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+	if err := p.StoreCertificate(t.Context(), "Private/api.example.com", certPEM, otherKeyPEM, caPEM); err == nil {
+		t.Error("StoreCertificate() with a mismatched key should fail")
+	}
+	if err := p.StoreCertificate(t.Context(), "Private/api.example.com", certPEM, keyPEM, caPEM); err != nil {
+		t.Fatalf("StoreCertificate() error = %v", err)
+	}
+	created := m.created[0]
+	if created.Category != op.ItemCategorySecureNote {
+		t.Errorf("created category = %s, want SecureNote", created.Category)
+	}
+
+	m.items["v1"] = append(m.items["v1"], op.Item{ID: "i3", Title: created.Title, VaultID: "v1", Fields: created.Fields})
+	bundle, err := p.GetCertificateBundle(t.Context(), "Private/api.example.com")
+	if err != nil {
+		t.Fatalf("GetCertificateBundle() error = %v", err)
+	}
+	if bundle.Certificate.Subject.CommonName != "api.example.com" || len(bundle.Chain) != 1 || bundle.Chain[0].Subject.CommonName != "Test CA" {
+		t.Errorf("bundle = %v with chain %v", bundle.Certificate.Subject, bundle.Chain)
+	}
+	if want := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC); bundle.Metadata.ExpiresAt == nil || !bundle.Metadata.ExpiresAt.Time.Equal(want) {
+		t.Errorf("Metadata.ExpiresAt = %v, want %v", bundle.Metadata.ExpiresAt, want)
+	}
+	cert, err := bundle.TLSCertificate()
+	if err != nil {
+		t.Fatalf("TLSCertificate() error = %v", err)
+	}
+	if len(cert.Certificate) != 2 {
+		t.Errorf("TLSCertificate() has %d certificates, want the leaf and CA", len(cert.Certificate))
+	}
+
+	// Storing a renewed certificate without a key or chain keeps them
+	if err := p.StoreCertificate(t.Context(), "Private/api.example.com", certPEM, "", ""); err != nil {
+		t.Fatalf("StoreCertificate() without key error = %v", err)
+	}
+	bundle, err = p.GetCertificateBundle(t.Context(), "Private/api.example.com")
+	if err != nil {
+		t.Fatalf("GetCertificateBundle() error = %v", err)
+	}
+	if bundle.PrivateKeyPEM != keyPEM || bundle.ChainPEM != caPEM {
+		t.Errorf("after storing without key and chain, key %q, chain %q", bundle.PrivateKeyPEM, bundle.ChainPEM)
+	}
+
+	// Without a chain field, a certificate field holding several
+	// certificates supplies the chain
+	m.items["v1"] = append(m.items["v1"], op.Item{ID: "i4", Title: "fullchain", VaultID: "v1", Fields: []op.ItemField{
+		{ID: "cert", Title: "tls.crt", Value: certPEM + caPEM},
+	}})
+	bundle, err = p.GetCertificateBundle(t.Context(), "Private/fullchain")
+	if err != nil {
+		t.Fatalf("GetCertificateBundle(fullchain) error = %v", err)
+	}
+	if len(bundle.Chain) != 1 || bundle.PrivateKeyPEM != "" {
+		t.Errorf("fullchain bundle chain %d, key %q", len(bundle.Chain), bundle.PrivateKeyPEM)
+	}
+}
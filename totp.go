@@ -0,0 +1,141 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// DefaultTOTPPeriod is the TOTP time step used when the otpauth URI doesn't specify one.
+const DefaultTOTPPeriod = 30 * time.Second
+
+// GetTOTP returns the current one-time password code for the TOTP field at
+// path, along with the time at which the code expires.
+//
+//	code, expiresAt, err := provider.GetTOTP(ctx, "Private/GitHub/one-time password")
+func (p *Provider) GetTOTP(ctx context.Context, path string) (string, time.Time, error) {
+	if p.closed.Load() {
+		return "", time.Time{}, vault.NewVaultError("GetTOTP", path, ProviderName, vault.ErrClosed)
+	}
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return "", time.Time{}, vault.NewVaultError("GetTOTP", path, ProviderName, err)
+	}
+	if parsed.Field == "" {
+		return "", time.Time{}, vault.NewVaultError("GetTOTP", path, ProviderName, ErrInvalidPath)
+	}
+
+	// Take the time before fetching so a window boundary crossed during the
+	// request yields an earlier, not later, expiry
+	now := time.Now()
+
+	item, err := p.fetchItem(ctx, parsed)
+	if err != nil {
+		return "", time.Time{}, mapError("GetTOTP", path, err)
+	}
+
+	field, ok := findField(item, parsed.Section, parsed.Field)
+	if !ok {
+		return "", time.Time{}, vault.NewVaultError("GetTOTP", path, ProviderName, vault.ErrSecretNotFound)
+	}
+
+	code, err := totpCode(field)
+	if err != nil {
+		return "", time.Time{}, vault.NewVaultError("GetTOTP", path, ProviderName, err)
+	}
+
+	return code, totpExpiry(field.Value, now), nil
+}
+
+// TOTPSource caches the one-time password for a TOTP field and fetches a new
+// code only when the current time window has ended. It is safe for
+// concurrent use.
+type TOTPSource struct {
+	fetch func(ctx context.Context) (string, time.Time, error)
+	now   func() time.Time
+
+	mu        sync.Mutex
+	code      string
+	expiresAt time.Time
+}
+
+// TOTPSource returns a TOTPSource for the TOTP field at path.
+//
+//	otp := provider.TOTPSource("Private/GitHub/one-time password")
+//	code, err := otp.Code(ctx)
+func (p *Provider) TOTPSource(path string) *TOTPSource {
+	return &TOTPSource{
+		fetch: func(ctx context.Context) (string, time.Time, error) {
+			return p.GetTOTP(ctx, path)
+		},
+		now: time.Now,
+	}
+}
+
+// Code returns the current one-time password, refreshing it from 1Password
+// when the cached code has expired.
+func (s *TOTPSource) Code(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.code != "" && s.now().Before(s.expiresAt) {
+		return s.code, nil
+	}
+
+	code, expiresAt, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.code, s.expiresAt = code, expiresAt
+	return code, nil
+}
+
+// ExpiresAt returns when the cached code expires. It is the zero time
+// before the first call to Code.
+func (s *TOTPSource) ExpiresAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.expiresAt
+}
+
+// totpCode extracts the computed code from a TOTP field.
+func totpCode(field op.ItemField) (string, error) {
+	if field.FieldType != op.ItemFieldTypeTOTP {
+		return "", errors.New("field is not a one-time password")
+	}
+	if field.Details != nil {
+		if otp := field.Details.OTP(); otp != nil {
+			if otp.Code != nil {
+				return *otp.Code, nil
+			}
+			if otp.ErrorMessage != nil {
+				return "", errors.New(*otp.ErrorMessage)
+			}
+		}
+	}
+	return "", errors.New("one-time password code not available")
+}
+
+// totpExpiry returns the end of the TOTP window containing now, using the
+// period from the otpauth URI when present.
+func totpExpiry(otpauth string, now time.Time) time.Time {
+	period := DefaultTOTPPeriod
+	if strings.HasPrefix(otpauth, "otpauth://") {
+		if u, err := url.Parse(otpauth); err == nil {
+			if secs, err := strconv.Atoi(u.Query().Get("period")); err == nil && secs > 0 {
+				period = time.Duration(secs) * time.Second
+			}
+		}
+	}
+	// TOTP windows are aligned to the Unix epoch
+	step := int64(period / time.Second)
+	return time.Unix((now.Unix()/step+1)*step, 0)
+}
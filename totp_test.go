@@ -0,0 +1,97 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func TestTOTPExpiry(t *testing.T) {
+	now := time.Unix(1700000010, 0)
+
+	tests := []struct {
+		name    string
+		otpauth string
+		want    int64
+	}{
+		{"default period", "JBSWY3DPEHPK3PXP", 1700000010 - 1700000010%30 + 30},
+		{"uri without period", "otpauth://totp/x?secret=abc", 1700000010 - 1700000010%30 + 30},
+		{"custom period", "otpauth://totp/x?secret=abc&period=60", 1700000010 - 1700000010%60 + 60},
+		{"invalid period", "otpauth://totp/x?secret=abc&period=zero", 1700000010 - 1700000010%30 + 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := totpExpiry(tt.otpauth, now); got.Unix() != tt.want {
+				t.Errorf("totpExpiry() = %d, want %d", got.Unix(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTOTPCode(t *testing.T) {
+	code := "123456"
+	message := "invalid secret"
+	withCode := op.NewItemFieldDetailsTypeVariantOTP(&op.OTPFieldDetails{Code: &code})
+	withError := op.NewItemFieldDetailsTypeVariantOTP(&op.OTPFieldDetails{ErrorMessage: &message})
+
+	got, err := totpCode(op.ItemField{FieldType: op.ItemFieldTypeTOTP, Details: &withCode})
+	if err != nil || got != code {
+		t.Errorf("totpCode() = %q, %v; want %q", got, err, code)
+	}
+
+	if _, err := totpCode(op.ItemField{FieldType: op.ItemFieldTypeTOTP, Details: &withError}); err == nil || err.Error() != message {
+		t.Errorf("totpCode() error = %v, want %q", err, message)
+	}
+
+	if _, err := totpCode(op.ItemField{FieldType: op.ItemFieldTypeConcealed, Value: "x"}); err == nil {
+		t.Error("totpCode() should fail for non-TOTP fields")
+	}
+}
+
+func TestTOTPSource_Code(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	fetches := 0
+	src := &TOTPSource{
+		fetch: func(ctx context.Context) (string, time.Time, error) {
+			fetches++
+			return fmt.Sprintf("code-%d", fetches), totpExpiry("", now), nil
+		},
+		now: func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	first, err := src.Code(ctx)
+	if err != nil {
+		t.Fatalf("Code() error = %v", err)
+	}
+	if second, _ := src.Code(ctx); second != first || fetches != 1 {
+		t.Errorf("Expected cached code %q with 1 fetch, got %q with %d fetches", first, second, fetches)
+	}
+
+	// Move into the next window
+	now = now.Add(DefaultTOTPPeriod)
+	if third, _ := src.Code(ctx); third == first || fetches != 2 {
+		t.Errorf("Expected refreshed code after expiry, got %q with %d fetches", third, fetches)
+	}
+	if !src.ExpiresAt().After(now) {
+		t.Errorf("ExpiresAt() = %v, want after %v", src.ExpiresAt(), now)
+	}
+}
+
+func TestTOTPSource_CodeError(t *testing.T) {
+	src := &TOTPSource{
+		fetch: func(ctx context.Context) (string, time.Time, error) {
+			return "", time.Time{}, errors.New("boom")
+		},
+		now: time.Now,
+	}
+
+	if _, err := src.Code(context.Background()); err == nil {
+		t.Error("Code() should return the fetch error")
+	}
+}
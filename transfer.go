@@ -0,0 +1,199 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// Copy copies the item at srcPath to dstPath, which may be in another vault.
+// The new item keeps the source category, fields, sections, tags, and
+// websites. Both paths must refer to items, not fields.
+//
+// Returns vault.ErrAlreadyExists if an item already exists at dstPath.
+func (p *Provider) Copy(ctx context.Context, srcPath, dstPath string) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	if p.closed.Load() {
+		return vault.NewVaultError("Copy", srcPath, ProviderName, vault.ErrClosed)
+	}
+
+	if err := p.refuseDryRun(ctx, "Copy", srcPath); err != nil {
+		return err
+	}
+
+	_, _, dst, err := p.transferItem(ctx, "Copy", srcPath, dstPath)
+	if err != nil {
+		return err
+	}
+	return p.mirrorItem(ctx, "Copy", dstPath, dst)
+}
+
+// Move moves the item at srcPath to dstPath, which may be in another vault.
+// It is implemented as a copy followed by deletion of the source, so the
+// moved item gets a new ID.
+//
+// Returns vault.ErrAlreadyExists if an item already exists at dstPath.
+func (p *Provider) Move(ctx context.Context, srcPath, dstPath string) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	if p.closed.Load() {
+		return vault.NewVaultError("Move", srcPath, ProviderName, vault.ErrClosed)
+	}
+
+	if err := p.refuseDryRun(ctx, "Move", srcPath); err != nil {
+		return err
+	}
+
+	item, src, dst, err := p.transferItem(ctx, "Move", srcPath, dstPath)
+	if err != nil {
+		return err
+	}
+
+	if err := p.items.Delete(ctx, item.VaultID, item.ID); err != nil {
+		return mapError("Move", srcPath, fmt.Errorf("item copied to %s but source not deleted: %w", dstPath, err))
+	}
+
+	if err := p.mirrorItem(ctx, "Move", dstPath, dst); err != nil {
+		return err
+	}
+	return p.mirrorDeleteItem(ctx, "Move", srcPath, src)
+}
+
+// Rename changes the title of the item at path to newTitle. Unlike Move, the
+// item keeps its ID, fields, and tags, so references by ID keep working.
+//
+// Returns vault.ErrAlreadyExists if another item in the vault has newTitle.
+func (p *Provider) Rename(ctx context.Context, path, newTitle string) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	if p.closed.Load() {
+		return vault.NewVaultError("Rename", path, ProviderName, vault.ErrClosed)
+	}
+
+	if err := p.refuseDryRun(ctx, "Rename", path); err != nil {
+		return err
+	}
+
+	parsed, err := p.parsePath(path)
+	if err != nil {
+		return vault.NewVaultError("Rename", path, ProviderName, err)
+	}
+	if parsed.Field != "" || newTitle == "" {
+		return vault.NewVaultError("Rename", path, ProviderName,
+			fmt.Errorf("%w: rename requires an item path and a non-empty title", ErrInvalidPath))
+	}
+
+	vaultID, err := p.resolveVaultID(ctx, parsed.Vault)
+	if err != nil {
+		return mapError("Rename", path, err)
+	}
+	itemID, err := p.resolveItemID(ctx, vaultID, parsed.Item)
+	if err != nil {
+		return mapError("Rename", path, err)
+	}
+
+	// Refuse to create a duplicate title
+	if existingID, err := p.resolveItemID(ctx, vaultID, newTitle); err == nil {
+		if existingID != itemID {
+			return vault.NewVaultError("Rename", path, ProviderName, vault.ErrAlreadyExists)
+		}
+	} else if !isNotFoundError(err) {
+		return mapError("Rename", path, err)
+	}
+
+	item, err := p.items.Get(ctx, vaultID, itemID)
+	if err != nil {
+		return mapError("Rename", path, err)
+	}
+	item.Title = newTitle
+
+	if _, err := p.items.Put(ctx, item); err != nil {
+		return mapError("Rename", path, err)
+	}
+
+	renamed := &ParsedPath{Vault: parsed.Vault, Item: newTitle}
+	if err := p.mirrorItem(ctx, "Rename", renamed.String(), renamed); err != nil {
+		return err
+	}
+	return p.mirrorDeleteItem(ctx, "Rename", path, parsed)
+}
+
+// transferItem copies the item at srcPath to dstPath and returns the source
+// item and both paths, parsed.
+func (p *Provider) transferItem(ctx context.Context, operation, srcPath, dstPath string) (*op.Item, *ParsedPath, *ParsedPath, error) {
+	src, err := p.parsePath(srcPath)
+	if err != nil {
+		return nil, nil, nil, vault.NewVaultError(operation, srcPath, ProviderName, err)
+	}
+	dst, err := p.parsePath(dstPath)
+	if err != nil {
+		return nil, nil, nil, vault.NewVaultError(operation, dstPath, ProviderName, err)
+	}
+	if src.Field != "" || dst.Field != "" {
+		return nil, nil, nil, vault.NewVaultError(operation, srcPath, ProviderName,
+			fmt.Errorf("%w: source and destination must be items, not fields", ErrInvalidPath))
+	}
+
+	// Fetch the source item
+	srcVaultID, err := p.resolveVaultID(ctx, src.Vault)
+	if err != nil {
+		return nil, nil, nil, mapError(operation, srcPath, err)
+	}
+	srcItemID, err := p.resolveItemID(ctx, srcVaultID, src.Item)
+	if err != nil {
+		return nil, nil, nil, mapError(operation, srcPath, err)
+	}
+	item, err := p.items.Get(ctx, srcVaultID, srcItemID)
+	if err != nil {
+		return nil, nil, nil, mapError(operation, srcPath, err)
+	}
+	if operation == "Move" {
+		// Refuse before copying, as the source can't be deleted
+		if err := p.checkManaged(item); err != nil {
+			return nil, nil, nil, vault.NewVaultError(operation, srcPath, ProviderName, err)
+		}
+	}
+
+	// Refuse to overwrite an existing destination item
+	dstVaultID, err := p.resolveVaultID(ctx, dst.Vault)
+	if err != nil {
+		return nil, nil, nil, mapError(operation, dstPath, err)
+	}
+	if _, err := p.resolveItemID(ctx, dstVaultID, dst.Item); err == nil {
+		return nil, nil, nil, vault.NewVaultError(operation, dstPath, ProviderName, vault.ErrAlreadyExists)
+	} else if !isNotFoundError(err) {
+		return nil, nil, nil, mapError(operation, dstPath, err)
+	}
+
+	if _, err := p.items.Create(ctx, itemToCreateParams(item, dstVaultID, dst.Item)); err != nil {
+		return nil, nil, nil, mapError(operation, dstPath, err)
+	}
+
+	return &item, src, dst, nil
+}
+
+// itemToCreateParams builds parameters that recreate item under a new vault and title.
+func itemToCreateParams(item op.Item, vaultID, title string) op.ItemCreateParams {
+	fields := make([]op.ItemField, len(item.Fields))
+	for i, field := range item.Fields {
+		// Computed details such as TOTP codes are regenerated by 1Password
+		field.Details = nil
+		fields[i] = field
+	}
+
+	return op.ItemCreateParams{
+		Category: item.Category,
+		VaultID:  vaultID,
+		Title:    title,
+		Fields:   fields,
+		Sections: append([]op.ItemSection(nil), item.Sections...),
+		Tags:     append([]string(nil), item.Tags...),
+		Websites: append([]op.Website(nil), item.Websites...),
+	}
+}
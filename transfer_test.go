@@ -0,0 +1,50 @@
+package onepassword
+
+import (
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+func TestItemToCreateParams(t *testing.T) {
+	code := "123456"
+	sectionID := "sec1"
+	details := op.NewItemFieldDetailsTypeVariantOTP(&op.OTPFieldDetails{Code: &code})
+	item := op.Item{
+		ID:       "item123",
+		Title:    "Staging DB",
+		Category: op.ItemCategoryDatabase,
+		VaultID:  "staging",
+		Fields: []op.ItemField{
+			{ID: "password", Title: "password", Value: "secret", FieldType: op.ItemFieldTypeConcealed, SectionID: &sectionID},
+			{ID: "otp", Title: "otp", Value: "otpauth://totp/x?secret=abc", FieldType: op.ItemFieldTypeTOTP, Details: &details},
+		},
+		Sections: []op.ItemSection{{ID: sectionID, Title: "Credentials"}},
+		Tags:     []string{"env:staging"},
+		Version:  3,
+	}
+
+	params := itemToCreateParams(item, "prod", "Prod DB")
+
+	if params.VaultID != "prod" || params.Title != "Prod DB" {
+		t.Errorf("Expected prod/Prod DB, got %s/%s", params.VaultID, params.Title)
+	}
+	if params.Category != op.ItemCategoryDatabase {
+		t.Errorf("Expected Category = Database, got %v", params.Category)
+	}
+	if len(params.Fields) != 2 || params.Fields[0].Value != "secret" {
+		t.Fatalf("Expected fields to be copied, got %+v", params.Fields)
+	}
+	if params.Fields[0].SectionID == nil || *params.Fields[0].SectionID != sectionID {
+		t.Error("Expected field section to be preserved")
+	}
+	if params.Fields[1].Details != nil {
+		t.Error("Expected computed field details to be dropped")
+	}
+	if item.Fields[1].Details == nil {
+		t.Error("itemToCreateParams should not modify the source item")
+	}
+	if len(params.Sections) != 1 || len(params.Tags) != 1 {
+		t.Errorf("Expected sections and tags to be copied, got %v %v", params.Sections, params.Tags)
+	}
+}
@@ -0,0 +1,182 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTransportCacheTTL is how long Transport reuses a resolved secret
+// before fetching it again.
+const DefaultTransportCacheTTL = 5 * time.Minute
+
+// TransportConfig configures a Transport.
+type TransportConfig struct {
+	// Headers maps header names to templates. Each "{{op://...}}" placeholder
+	// in a template is replaced with the referenced secret, e.g.
+	// "Authorization": "Bearer {{op://Work/API/token}}".
+	Headers map[string]string
+
+	// Base is the underlying transport. Default: http.DefaultTransport
+	Base http.RoundTripper
+
+	// CacheTTL is how long resolved secrets are reused.
+	// Default: 5 minutes
+	CacheTTL time.Duration
+}
+
+// Transport is an http.RoundTripper that resolves secrets from 1Password and
+// sets them as request headers, so HTTP clients never hold raw secrets in
+// their own configuration. It is safe for concurrent use.
+type Transport struct {
+	base    http.RoundTripper
+	headers map[string][]templateSegment
+	ttl     time.Duration
+	resolve resolveFunc
+	now     func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedValue
+}
+
+// cachedValue is a resolved secret and when it stops being reused.
+type cachedValue struct {
+	value     string
+	expiresAt time.Time
+}
+
+// templateSegment is literal text or, when ref is set, a secret reference.
+type templateSegment struct {
+	text string
+	ref  string
+}
+
+// Transport returns a Transport injecting the configured header templates.
+//
+//	transport, err := provider.Transport(onepassword.TransportConfig{
+//	    Headers: map[string]string{"Authorization": "Bearer {{op://Work/API/token}}"},
+//	})
+//	client := &http.Client{Transport: transport}
+func (p *Provider) Transport(config TransportConfig) (*Transport, error) {
+	headers := make(map[string][]templateSegment, len(config.Headers))
+	for name, tmpl := range config.Headers {
+		segments, err := parseTemplate(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("header %q: %w", name, err)
+		}
+		headers[http.CanonicalHeaderKey(name)] = segments
+	}
+
+	base := config.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ttl := config.CacheTTL
+	if ttl <= 0 {
+		ttl = DefaultTransportCacheTTL
+	}
+
+	return &Transport{
+		base:    base,
+		headers: headers,
+		ttl:     ttl,
+		resolve: p.resolveValue,
+		now:     time.Now,
+		cache:   make(map[string]cachedValue),
+	}, nil
+}
+
+// RoundTrip sets the configured headers on a copy of req and sends it with
+// the base transport. If a secret can't be resolved the request is not sent.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	for name, segments := range t.headers {
+		value, err := t.render(req.Context(), segments)
+		if err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+		clone.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(clone)
+}
+
+// Invalidate drops all cached secrets so the next request fetches them again.
+func (t *Transport) Invalidate() {
+	t.mu.Lock()
+	t.cache = make(map[string]cachedValue)
+	t.mu.Unlock()
+}
+
+// render joins the segments, resolving references through the cache.
+func (t *Transport) render(ctx context.Context, segments []templateSegment) (string, error) {
+	var b strings.Builder
+	for _, seg := range segments {
+		if seg.ref == "" {
+			b.WriteString(seg.text)
+			continue
+		}
+		value, err := t.lookup(ctx, seg.ref)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value)
+	}
+	return b.String(), nil
+}
+
+// lookup returns the cached value of ref, resolving it when missing or stale.
+func (t *Transport) lookup(ctx context.Context, ref string) (string, error) {
+	t.mu.Lock()
+	cached, ok := t.cache[ref]
+	t.mu.Unlock()
+	if ok && t.now().Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+
+	value, err := t.resolve(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	t.mu.Lock()
+	t.cache[ref] = cachedValue{value: value, expiresAt: t.now().Add(t.ttl)}
+	t.mu.Unlock()
+	return value, nil
+}
+
+// parseTemplate splits tmpl into literal text and "{{op://...}}" references.
+// Whitespace inside the braces is ignored.
+func parseTemplate(tmpl string) ([]templateSegment, error) {
+	var segments []templateSegment
+	for {
+		start := strings.Index(tmpl, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(tmpl[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed placeholder in %q", tmpl)
+		}
+		end += start
+
+		ref := strings.TrimSpace(tmpl[start+2 : end])
+		if !strings.HasPrefix(ref, "op://") {
+			return nil, fmt.Errorf("placeholder %q is not an op:// reference", ref)
+		}
+		if start > 0 {
+			segments = append(segments, templateSegment{text: tmpl[:start]})
+		}
+		segments = append(segments, templateSegment{ref: ref})
+		tmpl = tmpl[end+2:]
+	}
+	if tmpl != "" {
+		segments = append(segments, templateSegment{text: tmpl})
+	}
+	return segments, nil
+}
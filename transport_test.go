@@ -0,0 +1,131 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    []templateSegment
+		wantErr bool
+	}{
+		{
+			name: "literal only",
+			tmpl: "static",
+			want: []templateSegment{{text: "static"}},
+		},
+		{
+			name: "prefix and reference",
+			tmpl: "Bearer {{op://Work/API/token}}",
+			want: []templateSegment{{text: "Bearer "}, {ref: "op://Work/API/token"}},
+		},
+		{
+			name: "spaces and multiple references",
+			tmpl: "{{ op://V/I/user }}:{{op://V/I/pass}}",
+			want: []templateSegment{{ref: "op://V/I/user"}, {text: ":"}, {ref: "op://V/I/pass"}},
+		},
+		{
+			name:    "unclosed",
+			tmpl:    "Bearer {{op://Work/API/token",
+			wantErr: true,
+		},
+		{
+			name:    "not a reference",
+			tmpl:    "{{ .Token }}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTemplate(tt.tmpl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTemplate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransport_RoundTrip(t *testing.T) {
+	now := time.Unix(1000, 0)
+	calls := 0
+	var sent *http.Request
+
+	segments, _ := parseTemplate("Bearer {{op://Work/API/token}}")
+	transport := &Transport{
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+		headers: map[string][]templateSegment{"Authorization": segments},
+		ttl:     time.Minute,
+		resolve: func(_ context.Context, ref string) (string, error) {
+			calls++
+			return fmt.Sprintf("t%d", calls), nil
+		},
+		now:   func() time.Time { return now },
+		cache: make(map[string]cachedValue),
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if got := sent.Header.Get("Authorization"); got != "Bearer t1" {
+		t.Errorf("Authorization = %q, want 'Bearer t1'", got)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("RoundTrip() modified the caller's request")
+	}
+
+	// Cached within the TTL
+	transport.RoundTrip(req)
+	if calls != 1 {
+		t.Errorf("resolve called %d times, want 1", calls)
+	}
+
+	// Fetched again once stale
+	now = now.Add(2 * time.Minute)
+	transport.RoundTrip(req)
+	if got := sent.Header.Get("Authorization"); got != "Bearer t2" {
+		t.Errorf("Authorization = %q, want 'Bearer t2'", got)
+	}
+}
+
+func TestTransport_RoundTripResolveError(t *testing.T) {
+	segments, _ := parseTemplate("{{op://Work/API/token}}")
+	transport := &Transport{
+		base: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			t.Fatal("request should not be sent")
+			return nil, nil
+		}),
+		headers: map[string][]templateSegment{"X-Api-Key": segments},
+		ttl:     time.Minute,
+		resolve: func(context.Context, string) (string, error) {
+			return "", errors.New("not found")
+		},
+		now:   time.Now,
+		cache: make(map[string]cachedValue),
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Error("RoundTrip() should fail when a secret can't be resolved")
+	}
+}
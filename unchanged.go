@@ -0,0 +1,50 @@
+package onepassword
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+
+	op "github.com/1password/onepassword-sdk-go"
+)
+
+// ContentHash returns a hex SHA-256 hash of the content Set writes to an
+// item: its fields with their types and sections, its sections, and its
+// tags. Order is ignored, so two items holding the same fields in another
+// order hash the same. The version, title, and category are not included.
+func ContentHash(item op.Item) string {
+	entries := make([]string, 0, len(item.Fields)+len(item.Sections)+len(item.Tags))
+	for _, f := range item.Fields {
+		section := ""
+		if f.SectionID != nil {
+			section = *f.SectionID
+		}
+		entries = append(entries, hashEntry("field", f.ID, f.Title, section, string(f.FieldType), f.Value))
+	}
+	for _, s := range item.Sections {
+		entries = append(entries, hashEntry("section", s.ID, s.Title))
+	}
+	for _, tag := range item.Tags {
+		entries = append(entries, hashEntry("tag", tag))
+	}
+	slices.Sort(entries)
+
+	h := sha256.New()
+	for _, entry := range entries {
+		h.Write([]byte(entry))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashEntry encodes parts length-prefixed, so no two lists of parts encode
+// alike.
+func hashEntry(parts ...string) string {
+	var b []byte
+	for _, part := range parts {
+		b = strconv.AppendInt(b, int64(len(part)), 10)
+		b = append(b, ':')
+		b = append(b, part...)
+	}
+	return string(append(b, '\n'))
+}
@@ -0,0 +1,86 @@
+package onepassword
+
+import (
+	"testing"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestContentHash(t *testing.T) {
+	section := "s1"
+	item := op.Item{
+		Fields: []op.ItemField{
+			{ID: "username", Title: "username", FieldType: op.ItemFieldTypeText, Value: "admin"},
+			{ID: "password", Title: "password", FieldType: op.ItemFieldTypeConcealed, Value: "hunter2", SectionID: &section},
+		},
+		Sections: []op.ItemSection{{ID: "s1", Title: "Login"}},
+		Tags:     []string{"env:prod", "team"},
+		Version:  3,
+	}
+	base := ContentHash(item)
+
+	reordered := item
+	reordered.Fields = []op.ItemField{item.Fields[1], item.Fields[0]}
+	reordered.Tags = []string{"team", "env:prod"}
+	reordered.Version = 4
+	if ContentHash(reordered) != base {
+		t.Error("ContentHash() differs for reordered fields and tags")
+	}
+
+	changes := map[string]func(*op.Item){
+		"value":         func(i *op.Item) { i.Fields[0].Value = "root" },
+		"type":          func(i *op.Item) { i.Fields[0].FieldType = op.ItemFieldTypeConcealed },
+		"section":       func(i *op.Item) { i.Fields[0].SectionID = &section },
+		"tag":           func(i *op.Item) { i.Tags = []string{"env:prod"} },
+		"section title": func(i *op.Item) { i.Sections = []op.ItemSection{{ID: "s1", Title: "Other"}} },
+	}
+	for name, change := range changes {
+		changed := item
+		changed.Fields = append([]op.ItemField(nil), item.Fields...)
+		change(&changed)
+		if ContentHash(changed) == base {
+			t.Errorf("ContentHash() unchanged after changing %s", name)
+		}
+	}
+}
+
+func TestSetWithOptions_SkipUnchanged(t *testing.T) {
+	ctx := t.Context()
+
+	tests := []struct {
+		name     string
+		config   Config
+		opts     SetOptions
+		path     string
+		secret   *vault.Secret
+		wantPuts int
+	}{
+		{"same value", Config{}, SetOptions{SkipUnchanged: true}, "Private/Database/password", &vault.Secret{Value: "hunter2"}, 0},
+		{"same fields", Config{SkipUnchanged: true}, SetOptions{}, "Private/Database",
+			&vault.Secret{Fields: map[string]string{"username": "admin", "password": "hunter2"}}, 0},
+		{"merged tag already set", Config{SkipUnchanged: true}, SetOptions{Tags: TagsMerge}, "Private/Database/password",
+			&vault.Secret{Value: "hunter2", Metadata: vault.Metadata{Tags: map[string]string{"env": "prod"}}}, 0},
+		{"new value", Config{SkipUnchanged: true}, SetOptions{}, "Private/Database/password", &vault.Secret{Value: "changed"}, 1},
+		{"new field", Config{SkipUnchanged: true}, SetOptions{}, "Private/Database/host", &vault.Secret{Value: "db"}, 1},
+		{"new tag", Config{SkipUnchanged: true}, SetOptions{Tags: TagsMerge}, "Private/Database/password",
+			&vault.Secret{Value: "hunter2", Metadata: vault.Metadata{Tags: map[string]string{"team": "core"}}}, 1},
+		{"disabled", Config{}, SetOptions{}, "Private/Database/password", &vault.Secret{Value: "hunter2"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testMockAPI()
+			p := newMockProvider(m, tt.config)
+
+			if err := p.SetWithOptions(ctx, tt.path, tt.secret, tt.opts); err != nil {
+				t.Fatalf("SetWithOptions() error = %v", err)
+			}
+			if len(m.put) != tt.wantPuts {
+				t.Errorf("Put called %d times, want %d", len(m.put), tt.wantPuts)
+			}
+			if skipped := p.Stats().SkippedWrites; skipped != uint64(1-tt.wantPuts) {
+				t.Errorf("Stats().SkippedWrites = %d, want %d", skipped, 1-tt.wantPuts)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// VaultInfo describes a vault.
+type VaultInfo struct {
+	// ID is the vault ID.
+	ID string
+
+	// Title is the vault name.
+	Title string
+
+	// ItemCount is the number of active items in the vault.
+	ItemCount int
+}
+
+// GetVaultInfo describes the vault with the given name or ID. An empty
+// name uses the default vault. It lists the vault's items to count them.
+func (p *Provider) GetVaultInfo(ctx context.Context, nameOrID string) (*VaultInfo, error) {
+	if nameOrID == "" {
+		nameOrID = p.getDefaultVault()
+	}
+	if p.closed.Load() {
+		return nil, vault.NewVaultError("GetVaultInfo", nameOrID, ProviderName, vault.ErrClosed)
+	}
+	if nameOrID == "" {
+		return nil, vault.NewVaultError("GetVaultInfo", nameOrID, ProviderName,
+			fmt.Errorf("%w: vault name or default vault is required", ErrInvalidPath))
+	}
+
+	ctx, cancel := withTimeout(ctx, p.config.Timeouts.List)
+	defer cancel()
+
+	id, err := p.resolveVaultID(ctx, nameOrID)
+	if err != nil {
+		return nil, mapError("GetVaultInfo", nameOrID, err)
+	}
+	info := &VaultInfo{ID: id}
+
+	vaults, err := p.vaults.ListAll(ctx)
+	if err != nil {
+		return nil, mapError("GetVaultInfo", nameOrID, err)
+	}
+	for {
+		v, err := vaults.Next()
+		if err == op.ErrorIteratorDone {
+			break
+		}
+		if err != nil {
+			return nil, mapError("GetVaultInfo", nameOrID, err)
+		}
+		if v.ID == id {
+			info.Title = v.Title
+			break
+		}
+	}
+
+	items, err := p.items.ListAll(ctx, id)
+	if err != nil {
+		return nil, mapError("GetVaultInfo", nameOrID, err)
+	}
+	for {
+		if _, err := items.Next(); err == op.ErrorIteratorDone {
+			break
+		} else if err != nil {
+			return nil, mapError("GetVaultInfo", nameOrID, err)
+		}
+		info.ItemCount++
+	}
+	return info, nil
+}
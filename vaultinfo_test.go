@@ -0,0 +1,27 @@
+package onepassword
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestProvider_GetVaultInfo(t *testing.T) {
+	ctx := t.Context()
+	p := newMockProvider(testMockAPI(), Config{DefaultVaultName: "Private"})
+
+	for _, nameOrID := range []string{"Private", "v1", ""} {
+		info, err := p.GetVaultInfo(ctx, nameOrID)
+		if err != nil {
+			t.Fatalf("GetVaultInfo(%q) error = %v", nameOrID, err)
+		}
+		if info.ID != "v1" || info.Title != "Private" || info.ItemCount != 1 {
+			t.Errorf("GetVaultInfo(%q) = %+v", nameOrID, info)
+		}
+	}
+
+	if _, err := p.GetVaultInfo(ctx, "Missing"); !errors.Is(err, vault.ErrSecretNotFound) {
+		t.Errorf("GetVaultInfo(missing) error = %v, want not found", err)
+	}
+}
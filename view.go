@@ -0,0 +1,74 @@
+package onepassword
+
+import (
+	"context"
+
+	op "github.com/1password/onepassword-sdk-go"
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// With returns a view of p using the default vault, default category, or
+// read-only flag set in overrides, and p's configuration otherwise. Views
+// are cheap: they share p's SDK client, caches, write locks, and
+// statistics, and start with the middleware added to p so far. A
+// multi-tenant service can keep one authenticated provider and hand out a
+// view per environment:
+//
+//	staging := provider.With(onepassword.Config{DefaultVaultName: "Staging", ReadOnly: true})
+//
+// Setting DefaultVaultID or DefaultVaultName replaces both. ReadOnly can
+// only be turned on. Other fields of overrides are ignored. Closing a view
+// doesn't close p, nor the other way around, and doesn't wipe the caches
+// they share, even with Config.ZeroizeSecrets.
+func (p *Provider) With(overrides Config) *Provider {
+	config := p.config
+	if overrides.DefaultVaultID != "" || overrides.DefaultVaultName != "" {
+		config.DefaultVaultID, config.DefaultVaultName = overrides.DefaultVaultID, overrides.DefaultVaultName
+	}
+	if overrides.DefaultCategory != "" {
+		config.DefaultCategory = overrides.DefaultCategory
+	}
+
+	items := p.items
+	if overrides.ReadOnly && !config.ReadOnly {
+		config.ReadOnly = true
+		items = readOnlyItems{items}
+	}
+
+	view := &Provider{
+		secrets:    p.secrets,
+		items:      items,
+		vaults:     p.vaults,
+		config:     config,
+		vaultCache: p.vaultCache,
+		itemCache:  p.itemCache,
+		diskCache:  p.diskCache,
+		writeMu:    p.writeMu,
+		itemLocks:  p.itemLocks,
+		shards:     p.shards,
+		mirror:     p.mirror,
+		stats:      p.stats,
+		view:       true,
+	}
+	if chain := p.loadMiddleware(); chain != nil {
+		view.middleware.Store(&chain)
+	}
+	return view
+}
+
+// readOnlyItems refuses writes with vault.ErrReadOnly.
+type readOnlyItems struct {
+	itemsAPI
+}
+
+func (readOnlyItems) Create(context.Context, op.ItemCreateParams) (op.Item, error) {
+	return op.Item{}, vault.ErrReadOnly
+}
+
+func (readOnlyItems) Put(context.Context, op.Item) (op.Item, error) {
+	return op.Item{}, vault.ErrReadOnly
+}
+
+func (readOnlyItems) Delete(context.Context, string, string) error {
+	return vault.ErrReadOnly
+}
@@ -0,0 +1,89 @@
+package onepassword
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+func TestWith(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{DefaultVaultName: "Private"})
+	work := p.With(Config{DefaultVaultName: "Work", DefaultCategory: CategoryAPICredentials})
+
+	if _, err := work.Get(t.Context(), "API"); err != nil {
+		t.Errorf("view Get() in its default vault error = %v", err)
+	}
+	if _, err := p.Get(t.Context(), "Database"); err != nil {
+		t.Errorf("Get() in the original default vault error = %v", err)
+	}
+
+	if err := work.Set(t.Context(), "New/token", &vault.Secret{Value: "x"}); err != nil {
+		t.Fatalf("view Set() error = %v", err)
+	}
+	if created := m.created[0]; created.VaultID != "v2" || created.Category != CategoryAPICredentials {
+		t.Errorf("view created item in %s with category %s", created.VaultID, created.Category)
+	}
+
+	// The view shares the vault cache: Work was resolved once
+	lists := m.vaultLists
+	if ok, err := p.ItemExists(t.Context(), "Work", "API"); err != nil || !ok {
+		t.Fatalf("ItemExists() = %v, %v", ok, err)
+	}
+	if m.vaultLists != lists {
+		t.Errorf("vaults listed %d more times, want the view's lookup reused", m.vaultLists-lists)
+	}
+}
+
+func TestWith_ReadOnly(t *testing.T) {
+	m := testMockAPI()
+	p := newMockProvider(m, Config{})
+	ro := p.With(Config{ReadOnly: true})
+
+	if _, err := ro.Get(t.Context(), "Private/Database"); err != nil {
+		t.Errorf("read-only Get() error = %v", err)
+	}
+	if err := ro.Set(t.Context(), "Private/Database/password", &vault.Secret{Value: "x"}); !errors.Is(err, vault.ErrReadOnly) {
+		t.Errorf("read-only Set() error = %v, want vault.ErrReadOnly", err)
+	}
+	if err := ro.Delete(t.Context(), "Private/Database"); !errors.Is(err, vault.ErrReadOnly) {
+		t.Errorf("read-only Delete() error = %v, want vault.ErrReadOnly", err)
+	}
+	if caps := ro.Capabilities(); caps.Write || caps.Delete {
+		t.Errorf("read-only Capabilities() = %+v", caps)
+	}
+	if len(m.put) != 0 || len(m.deleted) != 0 {
+		t.Errorf("read-only view wrote: put %d, deleted %v", len(m.put), m.deleted)
+	}
+
+	// The original provider can still write
+	if err := p.Set(t.Context(), "Private/Database/password", &vault.Secret{Value: "x"}); err != nil {
+		t.Errorf("Set() error = %v", err)
+	}
+}
+
+func TestWith_CloseKeepsCaches(t *testing.T) {
+	p := newMockProvider(testMockAPI(), Config{ZeroizeSecrets: true})
+	view := p.With(Config{DefaultVaultName: "Private"})
+
+	if _, err := view.Get(t.Context(), "Database"); err != nil {
+		t.Fatalf("view Get() error = %v", err)
+	}
+	if err := view.Close(); err != nil {
+		t.Fatalf("view Close() error = %v", err)
+	}
+	if _, found, _ := p.vaultCache.get("Private"); !found {
+		t.Error("closing the view wiped the shared vault cache")
+	}
+	if _, err := p.Get(t.Context(), "Private/Database"); err != nil {
+		t.Errorf("Get() after closing the view error = %v", err)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, found, _ := p.vaultCache.get("Private"); found {
+		t.Error("Close() didn't wipe the vault cache")
+	}
+}
@@ -0,0 +1,184 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"math/rand/v2"
+	"time"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// MinWatchInterval is the shortest polling interval used by Watch; shorter
+// intervals are raised to it to stay within service account rate limits.
+const MinWatchInterval = time.Second
+
+// ChangeType describes what happened to a watched secret.
+type ChangeType int
+
+const (
+	// ChangeUpdated means the secret's value, fields, or version changed.
+	ChangeUpdated ChangeType = iota
+
+	// ChangeDeleted means the secret no longer exists.
+	ChangeDeleted
+
+	// ChangeCreated means a previously deleted secret exists again.
+	ChangeCreated
+
+	// ChangeError means a poll failed. The watch keeps polling.
+	ChangeError
+)
+
+// String returns the change type name.
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeUpdated:
+		return "updated"
+	case ChangeDeleted:
+		return "deleted"
+	case ChangeCreated:
+		return "created"
+	case ChangeError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// SecretChange is an event emitted by Watch.
+type SecretChange struct {
+	// Path is the watched path.
+	Path string
+
+	// Type is the kind of change.
+	Type ChangeType
+
+	// Secret is the current secret; nil for ChangeDeleted and ChangeError.
+	Secret *vault.Secret
+
+	// Previous is the last secret seen before the change; nil if the secret
+	// did not exist.
+	Previous *vault.Secret
+
+	// Err is the poll failure for ChangeError.
+	Err error
+}
+
+// Watch polls the secret at path every interval (with ±10% jitter so many
+// watchers don't poll in lockstep) and emits an event whenever its value,
+// fields, or version change. The secret is read once before Watch returns,
+// so an invalid or inaccessible path fails immediately.
+//
+// The channel is closed when ctx is cancelled; cancel it to stop the watch.
+// Events are not dropped, so a slow reader delays the next poll.
+//
+//	changes, err := provider.Watch(ctx, "Prod/Database/password", time.Minute)
+//	for change := range changes {
+//	    if change.Type == onepassword.ChangeUpdated {
+//	        reconnect(change.Secret.Value)
+//	    }
+//	}
+func (p *Provider) Watch(ctx context.Context, path string, interval time.Duration) (<-chan SecretChange, error) {
+	_, changes, err := p.watch(ctx, path, interval)
+	return changes, err
+}
+
+// watch implements Watch, also returning the secret as first read.
+func (p *Provider) watch(ctx context.Context, path string, interval time.Duration) (*vault.Secret, <-chan SecretChange, error) {
+	current, err := p.Get(ctx, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w := &watcher{
+		path:     path,
+		interval: max(interval, MinWatchInterval),
+		get:      p.Get,
+		jitter:   defaultJitter,
+	}
+	changes := make(chan SecretChange, 1)
+	go w.run(ctx, current, changes)
+	return current, changes, nil
+}
+
+// defaultJitter returns a random number in [0, n).
+func defaultJitter(n int64) int64 {
+	return rand.Int64N(n) //nolint:gosec // G404: jitter does not need a secure source
+}
+
+// watcher polls one path for Watch.
+type watcher struct {
+	path     string
+	interval time.Duration
+	get      func(ctx context.Context, path string) (*vault.Secret, error)
+	jitter   func(n int64) int64
+}
+
+// run polls until ctx is cancelled, then closes changes.
+func (w *watcher) run(ctx context.Context, current *vault.Secret, changes chan<- SecretChange) {
+	defer close(changes)
+
+	timer := time.NewTimer(w.nextDelay())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		change, ok := w.poll(ctx, current)
+		if ok {
+			if change.Type != ChangeError {
+				current = change.Secret
+			}
+			select {
+			case changes <- change:
+			case <-ctx.Done():
+				return
+			}
+		}
+		timer.Reset(w.nextDelay())
+	}
+}
+
+// poll reads the secret and reports the change from current, if any.
+func (w *watcher) poll(ctx context.Context, current *vault.Secret) (SecretChange, bool) {
+	secret, err := w.get(ctx, w.path)
+	switch {
+	case errors.Is(err, vault.ErrSecretNotFound):
+		if current == nil {
+			return SecretChange{}, false
+		}
+		return SecretChange{Path: w.path, Type: ChangeDeleted, Previous: current}, true
+	case err != nil:
+		if ctx.Err() != nil {
+			return SecretChange{}, false
+		}
+		return SecretChange{Path: w.path, Type: ChangeError, Previous: current, Err: err}, true
+	case current == nil:
+		return SecretChange{Path: w.path, Type: ChangeCreated, Secret: secret}, true
+	case secretChanged(current, secret):
+		return SecretChange{Path: w.path, Type: ChangeUpdated, Secret: secret, Previous: current}, true
+	}
+	return SecretChange{}, false
+}
+
+// nextDelay returns the interval with ±10% jitter.
+func (w *watcher) nextDelay() time.Duration {
+	spread := int64(w.interval) / 5
+	if spread <= 0 {
+		return w.interval
+	}
+	return w.interval - time.Duration(spread/2) + time.Duration(w.jitter(spread))
+}
+
+// secretChanged reports whether b differs from a in version, value, or fields.
+func secretChanged(a, b *vault.Secret) bool {
+	return a.Metadata.Version != b.Metadata.Version ||
+		a.Value != b.Value ||
+		!maps.Equal(a.Fields, b.Fields)
+}
@@ -0,0 +1,129 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/omnivault/vault"
+)
+
+// fakeSecretStore is a mutable secret for watch tests.
+type fakeSecretStore struct {
+	mu     sync.Mutex
+	secret *vault.Secret
+	err    error
+}
+
+func (f *fakeSecretStore) set(secret *vault.Secret, err error) {
+	f.mu.Lock()
+	f.secret, f.err = secret, err
+	f.mu.Unlock()
+}
+
+func (f *fakeSecretStore) get(context.Context, string) (*vault.Secret, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.secret == nil {
+		return nil, vault.ErrSecretNotFound
+	}
+	return f.secret, nil
+}
+
+func secretVersion(value, version string) *vault.Secret {
+	return &vault.Secret{Value: value, Metadata: vault.Metadata{Version: version}}
+}
+
+func TestWatcher_Events(t *testing.T) {
+	store := &fakeSecretStore{}
+	initial := secretVersion("v1", "1")
+	store.set(initial, nil)
+
+	w := &watcher{
+		path:     "V/I/f",
+		interval: time.Millisecond,
+		get:      store.get,
+		jitter:   func(int64) int64 { return 0 },
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan SecretChange)
+	go w.run(ctx, initial, changes)
+
+	next := func() SecretChange {
+		t.Helper()
+		select {
+		case c := <-changes:
+			return c
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for change")
+			return SecretChange{}
+		}
+	}
+
+	store.set(secretVersion("v2", "2"), nil)
+	if c := next(); c.Type != ChangeUpdated || c.Secret.Value != "v2" || c.Previous.Value != "v1" {
+		t.Errorf("change = %+v, want update v1 -> v2", c)
+	}
+
+	store.set(nil, errors.New("rate limited"))
+	if c := next(); c.Type != ChangeError || c.Err == nil {
+		t.Errorf("change = %+v, want error", c)
+	}
+
+	store.set(nil, nil)
+	if c := next(); c.Type != ChangeDeleted || c.Previous.Value != "v2" {
+		t.Errorf("change = %+v, want delete", c)
+	}
+
+	store.set(secretVersion("v3", "1"), nil)
+	if c := next(); c.Type != ChangeCreated || c.Secret.Value != "v3" {
+		t.Errorf("change = %+v, want create", c)
+	}
+
+	cancel()
+	for range changes {
+	}
+}
+
+func TestSecretChanged(t *testing.T) {
+	base := &vault.Secret{Value: "v", Fields: map[string]string{"a": "1"}, Metadata: vault.Metadata{Version: "1"}}
+
+	tests := []struct {
+		name  string
+		other *vault.Secret
+		want  bool
+	}{
+		{"same", &vault.Secret{Value: "v", Fields: map[string]string{"a": "1"}, Metadata: vault.Metadata{Version: "1"}}, false},
+		{"version", &vault.Secret{Value: "v", Fields: map[string]string{"a": "1"}, Metadata: vault.Metadata{Version: "2"}}, true},
+		{"value", &vault.Secret{Value: "w", Fields: map[string]string{"a": "1"}, Metadata: vault.Metadata{Version: "1"}}, true},
+		{"field", &vault.Secret{Value: "v", Fields: map[string]string{"a": "2"}, Metadata: vault.Metadata{Version: "1"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := secretChanged(base, tt.other); got != tt.want {
+				t.Errorf("secretChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatcher_NextDelay(t *testing.T) {
+	w := &watcher{interval: 10 * time.Second}
+
+	w.jitter = func(int64) int64 { return 0 }
+	if got := w.nextDelay(); got != 9*time.Second {
+		t.Errorf("nextDelay() min = %v, want 9s", got)
+	}
+
+	w.jitter = func(n int64) int64 { return n - 1 }
+	if got := w.nextDelay(); got >= 11*time.Second || got < 10*time.Second {
+		t.Errorf("nextDelay() max = %v, want just under 11s", got)
+	}
+}